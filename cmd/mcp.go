@@ -1,12 +1,14 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	infraUserManagement "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/usermanagement"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/mcp"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/helpers"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/usecase"
@@ -37,11 +39,17 @@ func mcpServer(_ *cobra.Command, _ []string) {
 		return
 	}
 
-	userManagementUsecase := usecase.NewUserManagementUsecase(userManagementRepo, chatStorageRepo)
+	userManagementUsecase := usecase.NewUserManagementUsecase(userManagementRepo, chatStorageRepo, auditRepo)
 	// Set auto reconnect to whatsapp server after booting with user management support
 	go helpers.SetAutoConnectAfterBootingWithUserManagement(appUsecase, userManagementUsecase, chatStorageRepo)
 	// Set auto reconnect checking for all user sessions
 	go helpers.SetAutoReconnectCheckingForAllUsers()
+	// Deliver queued webhook events, including those left over from a previous run
+	go whatsapp.RunWebhookOutboxWorker(context.Background())
+	// Send queued messages, including those left over from a previous run
+	go queueUsecase.RunWorker(context.Background())
+	// Send due scheduled messages, the schedules survive restarts
+	go scheduleUsecase.RunScheduler(context.Background())
 
 	// Create MCP server with capabilities
 	mcpServer := server.NewMCPServer(
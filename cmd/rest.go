@@ -1,11 +1,14 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
 	infraUserManagement "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/usermanagement"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/helpers"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
@@ -38,7 +41,10 @@ func restServer(_ *cobra.Command, _ []string) {
 	if err != nil {
 		logrus.Fatalf("Failed to initialize user management repository: %v", err)
 	}
-	userManagementUsecase := usecase.NewUserManagementUsecase(userManagementRepo, chatStorageRepo)
+	userManagementUsecase := usecase.NewUserManagementUsecase(userManagementRepo, chatStorageRepo, auditRepo)
+	if err := userManagementUsecase.BootstrapAdmin(config.AdminUsername, config.AdminPassword); err != nil {
+		logrus.Fatalf("Failed to bootstrap admin account: %v", err)
+	}
 
 	engine := html.NewFileSystem(http.FS(EmbedIndex), ".html")
 	engine.AddFunc("isEnableBasicAuth", func(token any) bool {
@@ -67,8 +73,9 @@ func restServer(_ *cobra.Command, _ []string) {
 		app.Use(logger.New())
 	}
 	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowHeaders: "Origin, Content-Type, Accept",
+		AllowOrigins:  "*",
+		AllowHeaders:  "Origin, Content-Type, Accept, " + middleware.IdempotencyKeyHeader,
+		ExposeHeaders: middleware.IdempotentReplayedHeader,
 	}))
 
 	// Create base path group or use app directly
@@ -78,11 +85,20 @@ func restServer(_ *cobra.Command, _ []string) {
 	}
 
 	// Admin routes with admin authentication
-	adminGroup := apiGroup.Group("/admin", middleware.AdminBasicAuth())
+	adminGroup := apiGroup.Group("/admin", middleware.AdminBasicAuth(userManagementUsecase))
 	rest.InitRestUserManagement(adminGroup, userManagementUsecase)
+	rest.InitRestAdminWebhook(adminGroup, webhookUsecase)
+	rest.InitRestAdminAutoReply(adminGroup, autoReplyUsecase)
+	rest.InitRestAdminAPIKey(adminGroup, apiKeyUsecase)
+	rest.InitRestAdminAudit(adminGroup, auditUsecase)
+	rest.InitRestAdminQuota(adminGroup, quotaUsecase)
+	rest.InitRestAdminQueue(adminGroup, queueUsecase)
+	rest.InitRestAdminBulk(adminGroup, bulkUsecase)
+	rest.InitRestAdminSchedule(adminGroup, scheduleUsecase)
+	rest.InitRestAdminTemplate(adminGroup, templateUsecase)
 
 	// Homepage route (protected with basic user authentication but not session middleware)
-	apiGroup.Get("/", middleware.UserBasicAuth(userManagementUsecase), func(c *fiber.Ctx) error {
+	apiGroup.Get("/", middleware.UserBasicAuth(userManagementUsecase, apiKeyUsecase), func(c *fiber.Ctx) error {
 		// Extract user information for display
 		var userID int
 		var username string
@@ -112,11 +128,17 @@ func restServer(_ *cobra.Command, _ []string) {
 		})
 	})
 
+	// Signed media URLs from webhooks carry their own authorization, so they go before the authenticated groups
+	rest.InitRestMedia(apiGroup, messageUsecase, userManagementUsecase)
+
 	// Routes with basic user authentication only (for login, status, etc.)
-	basicUserRoutes := apiGroup.Group("/", middleware.UserBasicAuth(userManagementUsecase))
+	basicUserRoutes := apiGroup.Group("/", middleware.UserBasicAuth(userManagementUsecase, apiKeyUsecase))
 
 	// App routes that need session middleware (for operations requiring active WhatsApp session)
-	sessionUserRoutes := apiGroup.Group("/", middleware.UserSessionMiddleware(userManagementUsecase, chatStorageRepo))
+	sessionUserRoutes := apiGroup.Group("/", middleware.UserSessionMiddleware(userManagementUsecase, apiKeyUsecase, chatStorageRepo))
+
+	// Retried sends with the same Idempotency-Key replay the first response instead of sending again
+	apiGroup.Use("/send", middleware.Idempotency(idempotencyUsecase))
 
 	// Initialize REST routes with appropriate middleware
 	rest.InitRestApp(basicUserRoutes, appUsecase)                 // Login doesn't need session
@@ -126,7 +148,15 @@ func restServer(_ *cobra.Command, _ []string) {
 	rest.InitRestMessage(sessionUserRoutes, messageUsecase)       // Message operations need session
 	rest.InitRestGroup(sessionUserRoutes, groupUsecase)           // Group operations need session
 	rest.InitRestNewsletter(sessionUserRoutes, newsletterUsecase) // Newsletter operations need session
-
+	rest.InitRestWebhook(basicUserRoutes, webhookUsecase)         // Webhook endpoints don't need session
+	rest.InitRestAutoReply(basicUserRoutes, autoReplyUsecase)     // Auto-reply rules don't need session
+	rest.InitRestAPIKey(basicUserRoutes, apiKeyUsecase)           // API keys don't need session
+	rest.InitRestQueue(basicUserRoutes, queueUsecase)             // Send queue inspection doesn't need session
+	rest.InitRestBulk(basicUserRoutes, bulkUsecase)               // Bulk jobs go through the send queue
+	rest.InitRestSchedule(basicUserRoutes, scheduleUsecase)       // Schedules don't need session until they run
+	rest.InitRestTemplate(basicUserRoutes, templateUsecase)       // Templates don't need session
+
+	apiGroup.Use("/ws", middleware.RequireScope(permission.EventsRead))
 	websocket.RegisterRoutes(basicUserRoutes, appUsecase)
 	go websocket.RunHub()
 
@@ -134,6 +164,12 @@ func restServer(_ *cobra.Command, _ []string) {
 	go helpers.SetAutoConnectAfterBootingWithUserManagement(appUsecase, userManagementUsecase, chatStorageRepo)
 	// Set auto reconnect checking for all user sessions
 	go helpers.SetAutoReconnectCheckingForAllUsers()
+	// Deliver queued webhook events, including those left over from a previous run
+	go whatsapp.RunWebhookOutboxWorker(context.Background())
+	// Send queued messages, including those left over from a previous run
+	go queueUsecase.RunWorker(context.Background())
+	// Send due scheduled messages, the schedules survive restarts
+	go scheduleUsecase.RunScheduler(context.Background())
 
 	if err := app.Listen(":" + config.AppPort); err != nil {
 		logrus.Fatalln("Failed to start: ", err.Error())
@@ -2,8 +2,10 @@ package cmd
 
 import (
 	"context"
+	"crypto/rand"
 	"database/sql"
 	"embed"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"strings"
@@ -12,15 +14,35 @@ import (
 	"go.mau.fi/whatsmeow/store/sqlstore"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
 	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	domainAudit "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/audit"
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	domainBulk "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/bulk"
 	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
+	domainIdempotency "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/idempotency"
 	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
 	domainNewsletter "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/newsletter"
+	domainQueue "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/queue"
+	domainQuota "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/quota"
+	domainSchedule "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/schedule"
 	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
 	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	infraAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/apikey"
+	infraAudit "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/audit"
+	infraAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/autoreply"
+	infraBulk "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/bulk"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/chatstorage"
+	infraIdempotency "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/idempotency"
+	infraQueue "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/queue"
+	infraQuota "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/quota"
+	infraSchedule "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/schedule"
+	infraTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/template"
+	infraWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/webhook"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/usecase"
@@ -39,14 +61,55 @@ var (
 	chatStorageDB   *sql.DB
 	chatStorageRepo domainChatStorage.IChatStorageRepository
 
+	// Webhooks
+	webhookRepo   domainWebhook.IWebhookRepository
+	webhookOutbox domainWebhook.IWebhookOutboxRepository
+
+	// Auto-reply
+	autoReplyRepo domainAutoReply.IAutoReplyRepository
+
+	// API keys
+	apiKeyRepo domainAPIKey.IAPIKeyRepository
+
+	// Audit log
+	auditRepo domainAudit.IAuditRepository
+
+	// Sending quotas
+	quotaRepo domainQuota.IQuotaRepository
+
+	// Send queue
+	queueRepo domainQueue.IQueueRepository
+
+	// Bulk send jobs
+	bulkRepo domainBulk.IBulkRepository
+
+	// Scheduled messages
+	scheduleRepo domainSchedule.IScheduleRepository
+
+	// Message templates
+	templateRepo domainTemplate.ITemplateRepository
+
+	// Idempotency keys of send requests
+	idempotencyRepo domainIdempotency.IIdempotencyRepository
+
 	// Usecase
-	appUsecase        domainApp.IAppUsecaseWithContext
-	chatUsecase       domainChat.IChatUsecase
-	sendUsecase       domainSend.ISendUsecase
-	userUsecase       domainUser.IUserUsecase
-	messageUsecase    domainMessage.IMessageUsecase
-	groupUsecase      domainGroup.IGroupUsecase
-	newsletterUsecase domainNewsletter.INewsletterUsecase
+	appUsecase         domainApp.IAppUsecaseWithContext
+	chatUsecase        domainChat.IChatUsecase
+	sendUsecase        domainSend.ISendUsecase
+	userUsecase        domainUser.IUserUsecase
+	messageUsecase     domainMessage.IMessageUsecase
+	groupUsecase       domainGroup.IGroupUsecase
+	newsletterUsecase  domainNewsletter.INewsletterUsecase
+	webhookUsecase     domainWebhook.IWebhookUsecase
+	autoReplyUsecase   domainAutoReply.IAutoReplyUsecase
+	apiKeyUsecase      domainAPIKey.IAPIKeyUsecase
+	auditUsecase       domainAudit.IAuditUsecase
+	quotaUsecase       domainQuota.IQuotaUsecase
+	queueUsecase       domainQueue.IQueueUsecase
+	bulkUsecase        domainBulk.IBulkUsecase
+	scheduleUsecase    domainSchedule.IScheduleUsecase
+	templateUsecase    domainTemplate.ITemplateUsecase
+	idempotencyUsecase domainIdempotency.IIdempotencyUsecase
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -91,6 +154,9 @@ func initEnvConfig() {
 	if envBasePath := viper.GetString("app_base_path"); envBasePath != "" {
 		config.AppBasePath = envBasePath
 	}
+	if envPublicURL := viper.GetString("app_public_url"); envPublicURL != "" {
+		config.AppPublicURL = envPublicURL
+	}
 
 	// Admin settings
 	if envAdminUsername := viper.GetString("admin_username"); envAdminUsername != "" {
@@ -125,9 +191,50 @@ func initEnvConfig() {
 	if envWebhookSecret := viper.GetString("whatsapp_webhook_secret"); envWebhookSecret != "" {
 		config.WhatsappWebhookSecret = envWebhookSecret
 	}
+	if envWebhookMaxAttempts := viper.GetInt("whatsapp_webhook_max_attempts"); envWebhookMaxAttempts > 0 {
+		config.WhatsappWebhookMaxAttempts = envWebhookMaxAttempts
+	}
+	if envWebhookFormat := viper.GetString("whatsapp_webhook_format"); envWebhookFormat != "" {
+		config.WhatsappWebhookFormat = envWebhookFormat
+	}
+	if envWebhookMediaMode := viper.GetString("whatsapp_webhook_media_mode"); envWebhookMediaMode != "" {
+		config.WhatsappWebhookMediaMode = envWebhookMediaMode
+	}
+	if envWebhookMediaURLExpiry := viper.GetInt("whatsapp_webhook_media_url_expiry"); envWebhookMediaURLExpiry > 0 {
+		config.WhatsappWebhookMediaURLExpiry = envWebhookMediaURLExpiry
+	}
+	if envMediaURLSecret := viper.GetString("whatsapp_media_url_secret"); envMediaURLSecret != "" {
+		config.WhatsappMediaURLSecret = envMediaURLSecret
+	}
+	if envStreamReplacedPolicy := viper.GetString("whatsapp_stream_replaced_policy"); envStreamReplacedPolicy != "" {
+		config.WhatsappStreamReplacedPolicy = envStreamReplacedPolicy
+	}
 	if viper.IsSet("whatsapp_account_validation") {
 		config.WhatsappAccountValidation = viper.GetBool("whatsapp_account_validation")
 	}
+	if viper.IsSet("whatsapp_send_queue_min_delay") {
+		config.WhatsappSendQueueMinDelay = viper.GetInt("whatsapp_send_queue_min_delay")
+	}
+	if viper.IsSet("whatsapp_send_queue_max_delay") {
+		config.WhatsappSendQueueMaxDelay = viper.GetInt("whatsapp_send_queue_max_delay")
+	}
+	if viper.IsSet("whatsapp_send_queue_typing") {
+		config.WhatsappSendQueueTyping = viper.GetBool("whatsapp_send_queue_typing")
+	}
+	if envSendQueueMaxAttempts := viper.GetInt("whatsapp_send_queue_max_attempts"); envSendQueueMaxAttempts > 0 {
+		config.WhatsappSendQueueMaxAttempts = envSendQueueMaxAttempts
+	}
+	if envIdempotencyWindow := viper.GetInt("whatsapp_idempotency_window"); envIdempotencyWindow > 0 {
+		config.WhatsappIdempotencyWindow = envIdempotencyWindow
+	}
+	if envMediaCacheHours := viper.GetInt("whatsapp_media_cache_hours"); envMediaCacheHours > 0 {
+		config.WhatsappMediaCacheHours = envMediaCacheHours
+	}
+
+	// Chat storage settings
+	if envLegacyOwner := viper.GetInt("chat_storage_legacy_owner_id"); envLegacyOwner > 0 {
+		config.ChatStorageLegacyOwnerID = envLegacyOwner
+	}
 }
 
 func initFlags() {
@@ -163,6 +270,12 @@ func initFlags() {
 		config.AppBasePath,
 		`base path for subpath deployment --base-path <string> | example: --base-path="/gowa"`,
 	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.AppPublicURL,
+		"public-url", "",
+		config.AppPublicURL,
+		`url the server is reached at, including the base path --public-url <string> | example: --public-url="https://wa.example.com/gowa"`,
+	)
 
 	// Database flags
 	rootCmd.PersistentFlags().StringVarP(
@@ -183,7 +296,7 @@ func initFlags() {
 		&config.WhatsappAutoReplyMessage,
 		"autoreply", "",
 		config.WhatsappAutoReplyMessage,
-		`auto reply when received message --autoreply <string> | example: --autoreply="Don't reply this message"`,
+		`auto reply to private chats of the default device without auto-reply rules, once an hour per contact --autoreply <string> | example: --autoreply="Don't reply this message"`,
 	)
 	rootCmd.PersistentFlags().BoolVarP(
 		&config.WhatsappAutoMarkRead,
@@ -203,12 +316,92 @@ func initFlags() {
 		config.WhatsappWebhookSecret,
 		`secure webhook request --webhook-secret <string> | example: --webhook-secret="super-secret-key"`,
 	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappWebhookMaxAttempts,
+		"webhook-max-attempts", "",
+		config.WhatsappWebhookMaxAttempts,
+		`delivery attempts before a webhook event is dead-lettered --webhook-max-attempts <number> | example: --webhook-max-attempts=10`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappWebhookFormat,
+		"webhook-format", "",
+		config.WhatsappWebhookFormat,
+		`payload format of the global webhook urls --webhook-format <legacy|v1> | example: --webhook-format="v1"`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappWebhookMediaMode,
+		"webhook-media-mode", "",
+		config.WhatsappWebhookMediaMode,
+		`how webhooks carry media --webhook-media-mode <download|link> | example: --webhook-media-mode="link"`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappWebhookMediaURLExpiry,
+		"webhook-media-url-expiry", "",
+		config.WhatsappWebhookMediaURLExpiry,
+		`hours a signed media url of a webhook stays valid --webhook-media-url-expiry <number> | example: --webhook-media-url-expiry=24`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappMediaURLSecret,
+		"media-url-secret", "",
+		config.WhatsappMediaURLSecret,
+		`key that signs media urls, keeps them valid across restarts --media-url-secret <string> | example: --media-url-secret="another-secret-key"`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappStreamReplacedPolicy,
+		"stream-replaced-policy", "",
+		config.WhatsappStreamReplacedPolicy,
+		`what to do when a session is opened on another device --stream-replaced-policy <disconnect|reclaim> | example: --stream-replaced-policy="reclaim"`,
+	)
 	rootCmd.PersistentFlags().BoolVarP(
 		&config.WhatsappAccountValidation,
 		"account-validation", "",
 		config.WhatsappAccountValidation,
 		`enable or disable account validation --account-validation <true/false> | example: --account-validation=true`,
 	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappSendQueueMinDelay,
+		"send-queue-min-delay", "",
+		config.WhatsappSendQueueMinDelay,
+		`minimum seconds between two queued messages of a user --send-queue-min-delay <number> | example: --send-queue-min-delay=3`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappSendQueueMaxDelay,
+		"send-queue-max-delay", "",
+		config.WhatsappSendQueueMaxDelay,
+		`maximum seconds between two queued messages of a user --send-queue-max-delay <number> | example: --send-queue-max-delay=10`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappSendQueueTyping,
+		"send-queue-typing", "",
+		config.WhatsappSendQueueTyping,
+		`show a typing indicator before each queued message --send-queue-typing <true/false> | example: --send-queue-typing=true`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappSendQueueMaxAttempts,
+		"send-queue-max-attempts", "",
+		config.WhatsappSendQueueMaxAttempts,
+		`send attempts before a queued message fails --send-queue-max-attempts <number> | example: --send-queue-max-attempts=5`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappIdempotencyWindow,
+		"idempotency-window", "",
+		config.WhatsappIdempotencyWindow,
+		`hours a send response is replayed for retries with the same Idempotency-Key --idempotency-window <number> | example: --idempotency-window=24`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappMediaCacheHours,
+		"media-cache-hours", "",
+		config.WhatsappMediaCacheHours,
+		`hours downloaded media stay cached on disk --media-cache-hours <number> | example: --media-cache-hours=24`,
+	)
+
+	// Chat storage flags
+	rootCmd.PersistentFlags().IntVarP(
+		&config.ChatStorageLegacyOwnerID,
+		"chat-storage-legacy-owner", "",
+		config.ChatStorageLegacyOwnerID,
+		`user id that inherits chats stored before multi-user support --chat-storage-legacy-owner <number> | example: --chat-storage-legacy-owner=1`,
+	)
 }
 
 func initChatStorage() (*sql.DB, error) {
@@ -242,11 +435,27 @@ func initApp() {
 	}
 
 	//preparing folder if not exist
-	err := utils.CreateFolder(config.PathQrCode, config.PathSendItems, config.PathStorages, config.PathMedia)
+	err := utils.CreateFolder(config.PathQrCode, config.PathSendItems, config.PathStorages, config.PathMedia, config.PathQueue, config.PathSchedule, config.PathTemplate, config.PathMediaCache, config.PathAutoReply)
 	if err != nil {
 		logrus.Errorln(err)
 	}
 
+	if config.WhatsappWebhookMediaMode != whatsapp.WebhookMediaModeDownload && config.WhatsappWebhookMediaMode != whatsapp.WebhookMediaModeLink {
+		logrus.Fatalf("invalid webhook media mode %q, expected %q or %q", config.WhatsappWebhookMediaMode, whatsapp.WebhookMediaModeDownload, whatsapp.WebhookMediaModeLink)
+	}
+	// Receivers cannot fetch media from a guessed address, so link mode needs to be told where the server is reached
+	if config.WhatsappWebhookMediaMode == whatsapp.WebhookMediaModeLink && config.AppPublicURL == "" {
+		logrus.Fatalf("webhook media mode %q requires APP_PUBLIC_URL (--public-url) to be set", whatsapp.WebhookMediaModeLink)
+	}
+	if config.WhatsappMediaURLSecret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			logrus.Fatalf("failed to generate media url secret: %v", err)
+		}
+		config.WhatsappMediaURLSecret = hex.EncodeToString(secret)
+		logrus.Warnln("WHATSAPP_MEDIA_URL_SECRET is not set, signed media URLs will stop working after a restart")
+	}
+
 	ctx := context.Background()
 
 	chatStorageDB, err = initChatStorage()
@@ -256,7 +465,76 @@ func initApp() {
 	}
 
 	chatStorageRepo = chatstorage.NewStorageRepository(chatStorageDB)
-	chatStorageRepo.InitializeSchema()
+	if err := chatStorageRepo.InitializeSchema(); err != nil {
+		logrus.Fatalf("failed to initialize chat storage schema: %v", err)
+	}
+
+	if config.ChatStorageLegacyOwnerID > 0 {
+		claimed, err := chatStorageRepo.ForUser(config.ChatStorageLegacyOwnerID).ClaimLegacyData()
+		if err != nil {
+			logrus.Errorf("failed to assign legacy chat storage to user %d: %v", config.ChatStorageLegacyOwnerID, err)
+		} else if claimed > 0 {
+			logrus.Infof("Assigned %d legacy chats to user %d", claimed, config.ChatStorageLegacyOwnerID)
+		}
+	}
+
+	webhookRepo, err = infraWebhook.NewWebhookRepository(config.UserManagementDBURI)
+	if err != nil {
+		logrus.Fatalf("failed to initialize webhook repository: %v", err)
+	}
+	whatsapp.SetWebhookRepository(webhookRepo)
+
+	webhookOutbox = infraWebhook.NewOutboxRepository(chatStorageDB)
+	if err := webhookOutbox.InitializeSchema(); err != nil {
+		logrus.Fatalf("failed to initialize webhook outbox schema: %v", err)
+	}
+	whatsapp.SetWebhookOutbox(webhookOutbox)
+
+	queueRepo = infraQueue.NewQueueRepository(chatStorageDB)
+	if err := queueRepo.InitializeSchema(); err != nil {
+		logrus.Fatalf("failed to initialize send queue schema: %v", err)
+	}
+
+	bulkRepo = infraBulk.NewBulkRepository(chatStorageDB)
+	if err := bulkRepo.InitializeSchema(); err != nil {
+		logrus.Fatalf("failed to initialize bulk job schema: %v", err)
+	}
+
+	scheduleRepo = infraSchedule.NewScheduleRepository(chatStorageDB)
+	if err := scheduleRepo.InitializeSchema(); err != nil {
+		logrus.Fatalf("failed to initialize schedule schema: %v", err)
+	}
+
+	templateRepo = infraTemplate.NewTemplateRepository(chatStorageDB)
+	if err := templateRepo.InitializeSchema(); err != nil {
+		logrus.Fatalf("failed to initialize template schema: %v", err)
+	}
+
+	idempotencyRepo = infraIdempotency.NewIdempotencyRepository(chatStorageDB)
+	if err := idempotencyRepo.InitializeSchema(); err != nil {
+		logrus.Fatalf("failed to initialize idempotency schema: %v", err)
+	}
+
+	autoReplyRepo, err = infraAutoReply.NewAutoReplyRepository(config.UserManagementDBURI)
+	if err != nil {
+		logrus.Fatalf("failed to initialize auto-reply repository: %v", err)
+	}
+	whatsapp.SetAutoReplyRepository(autoReplyRepo)
+
+	apiKeyRepo, err = infraAPIKey.NewAPIKeyRepository(config.UserManagementDBURI)
+	if err != nil {
+		logrus.Fatalf("failed to initialize api key repository: %v", err)
+	}
+
+	auditRepo, err = infraAudit.NewAuditRepository(config.UserManagementDBURI)
+	if err != nil {
+		logrus.Fatalf("failed to initialize audit repository: %v", err)
+	}
+
+	quotaRepo, err = infraQuota.NewQuotaRepository(config.UserManagementDBURI)
+	if err != nil {
+		logrus.Fatalf("failed to initialize quota repository: %v", err)
+	}
 
 	whatsappDB := whatsapp.InitWaDB(ctx, config.DBURI)
 	var keysDB *sqlstore.Container
@@ -267,13 +545,24 @@ func initApp() {
 	whatsapp.InitWaCLI(ctx, whatsappDB, keysDB, chatStorageRepo)
 
 	// Usecase
-	appUsecase = usecase.NewAppService(chatStorageRepo)
+	appUsecase = usecase.NewAppService(chatStorageRepo, auditRepo)
 	chatUsecase = usecase.NewChatService(chatStorageRepo)
-	sendUsecase = usecase.NewSendService(appUsecase, chatStorageRepo)
+	quotaUsecase = usecase.NewQuotaService(quotaRepo)
+	whatsapp.SetAutoReplyQuota(quotaUsecase)
+	sendUsecase = usecase.NewSendService(appUsecase, chatStorageRepo, auditRepo, quotaUsecase, queueRepo, scheduleRepo, templateRepo)
+	queueUsecase = usecase.NewQueueService(queueRepo, sendUsecase)
+	bulkUsecase = usecase.NewBulkService(bulkRepo, queueRepo)
+	scheduleUsecase = usecase.NewScheduleService(scheduleRepo, sendUsecase)
+	templateUsecase = usecase.NewTemplateService(templateRepo)
+	idempotencyUsecase = usecase.NewIdempotencyService(idempotencyRepo)
 	userUsecase = usecase.NewUserService()
-	messageUsecase = usecase.NewMessageService(chatStorageRepo)
-	groupUsecase = usecase.NewGroupService()
+	messageUsecase = usecase.NewMessageService(chatStorageRepo, auditRepo)
+	groupUsecase = usecase.NewGroupService(auditRepo)
 	newsletterUsecase = usecase.NewNewsletterService()
+	webhookUsecase = usecase.NewWebhookService(webhookRepo, webhookOutbox)
+	autoReplyUsecase = usecase.NewAutoReplyService(autoReplyRepo)
+	apiKeyUsecase = usecase.NewAPIKeyService(apiKeyRepo)
+	auditUsecase = usecase.NewAuditService(auditRepo)
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -12,8 +12,9 @@ var (
 	AppPlatform            = waCompanionReg.DeviceProps_PlatformType(1)
 	AppBasicAuthCredential []string
 	AppBasePath            = ""
+	AppPublicURL           = "" // URL the REST server is reached at, including the base path, used for links in webhooks
 
-	// Admin configuration
+	// Bootstrap admin, created in the user database while it has no active admin
 	AdminUsername = ""
 	AdminPassword = ""
 
@@ -23,10 +24,15 @@ var (
 	McpPort = "8080"
 	McpHost = "localhost"
 
-	PathQrCode    = "statics/qrcode"
-	PathSendItems = "statics/senditems"
-	PathMedia     = "statics/media"
-	PathStorages  = "storages"
+	PathQrCode     = "statics/qrcode"
+	PathSendItems  = "statics/senditems"
+	PathMedia      = "statics/media"
+	PathStorages   = "storages"
+	PathQueue      = "storages/queue"
+	PathSchedule   = "storages/schedules"
+	PathTemplate   = "storages/templates"
+	PathMediaCache = "storages/media-cache"
+	PathAutoReply  = "storages/auto-reply"
 
 	DBURI     = "file:storages/whatsapp.db?_foreign_keys=on"
 	DBKeysURI = ""
@@ -35,6 +41,12 @@ var (
 	WhatsappAutoMarkRead           = false // Auto-mark incoming messages as read
 	WhatsappWebhook                []string
 	WhatsappWebhookSecret                = "secret"
+	WhatsappWebhookMaxAttempts           = 10           // Delivery attempts before an event is moved to the dead letters
+	WhatsappWebhookFormat                = "legacy"     // Payload format of the global webhook URLs: legacy or v1
+	WhatsappWebhookMediaMode             = "download"   // How webhooks carry media: download into statics/media, or link to a signed URL
+	WhatsappWebhookMediaURLExpiry        = 24           // Hours a signed media URL of a webhook stays valid
+	WhatsappMediaURLSecret               = ""           // Key that signs media URLs, a random key per start when empty
+	WhatsappStreamReplacedPolicy         = "disconnect" // What to do when a session is opened on another device: disconnect or reclaim
 	WhatsappLogLevel                     = "ERROR"
 	WhatsappSettingMaxImageSize    int64 = 20000000  // 20MB
 	WhatsappSettingMaxFileSize     int64 = 50000000  // 50MB
@@ -43,8 +55,15 @@ var (
 	WhatsappTypeUser                     = "@s.whatsapp.net"
 	WhatsappTypeGroup                    = "@g.us"
 	WhatsappAccountValidation            = true
+	WhatsappSendQueueMinDelay            = 3    // Seconds to wait at least between two queued messages of a user
+	WhatsappSendQueueMaxDelay            = 10   // Seconds to wait at most between two queued messages of a user
+	WhatsappSendQueueTyping              = true // Show a typing indicator before each queued message
+	WhatsappSendQueueMaxAttempts         = 5    // Send attempts before a queued message fails on transient errors
+	WhatsappIdempotencyWindow            = 24   // Hours the response of a send is replayed for retries with the same Idempotency-Key
+	WhatsappMediaCacheHours              = 24   // Hours media downloaded through /message/:message_id/download stay on disk
 
 	ChatStorageURI               = "file:storages/chatstorage.db"
 	ChatStorageEnableForeignKeys = true
 	ChatStorageEnableWAL         = true
+	ChatStorageLegacyOwnerID     = 0 // User that inherits chats stored before multi-user support (0 keeps them unassigned)
 )
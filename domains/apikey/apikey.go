@@ -0,0 +1,59 @@
+package apikey
+
+import (
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
+)
+
+// KeyPrefix marks API keys issued by this server so they are easy to recognise in configs and logs
+const KeyPrefix = "wak_"
+
+// Key states reported in responses
+const (
+	StatusActive  = "active"
+	StatusExpired = "expired"
+	StatusRevoked = "revoked"
+)
+
+// APIKey lets a user authenticate with `Authorization: Bearer <key>` instead of their password.
+// Only the SHA-256 hash of the key is stored; the key itself is shown once when it is created.
+type APIKey struct {
+	ID         int        `json:"id"`
+	UserID     int        `json:"user_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"` // First characters of the key, to tell keys apart
+	KeyHash    string     `json:"-"`
+	Status     string     `json:"status"`
+	ExpiresAt  *time.Time `json:"expires_at"` // Nil never expires
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+
+	// Limits the key further than its user, never beyond
+	permission.Grant
+}
+
+// StatusAt reports whether the key can still be used at the given time
+func (k APIKey) StatusAt(now time.Time) string {
+	if k.RevokedAt != nil {
+		return StatusRevoked
+	}
+	if k.ExpiresAt != nil && !now.Before(*k.ExpiresAt) {
+		return StatusExpired
+	}
+	return StatusActive
+}
+
+type CreateAPIKeyRequest struct {
+	Name         string   `json:"name" form:"name"`
+	ExpiresAt    string   `json:"expires_at" form:"expires_at"` // RFC3339, empty never expires
+	Scopes       []string `json:"scopes" form:"scopes"`
+	AllowedChats []string `json:"allowed_chats" form:"allowed_chats"`
+}
+
+// CreateAPIKeyResponse is the only response that contains the key itself
+type CreateAPIKeyResponse struct {
+	APIKey
+	Key string `json:"key"`
+}
@@ -0,0 +1,24 @@
+package apikey
+
+import (
+	"context"
+	"time"
+)
+
+type IAPIKeyRepository interface {
+	Create(key *APIKey) error
+	GetByID(userID, id int) (*APIKey, error)
+	GetByHash(hash string) (*APIKey, error)
+	GetByUser(userID int) ([]APIKey, error)
+	Revoke(userID, id int, revokedAt time.Time) error
+	UpdateLastUsed(id int, usedAt time.Time) error
+}
+
+// IAPIKeyUsecase issues and verifies the API keys of a user
+type IAPIKeyUsecase interface {
+	CreateKey(ctx context.Context, userID int, request CreateAPIKeyRequest) (CreateAPIKeyResponse, error)
+	ListKeys(ctx context.Context, userID int) ([]APIKey, error)
+	RevokeKey(ctx context.Context, userID, id int) (APIKey, error)
+	// Authenticate returns the key matching a bearer token, or an error when it is unknown, expired or revoked
+	Authenticate(ctx context.Context, token string) (*APIKey, error)
+}
@@ -2,16 +2,34 @@ package app
 
 import (
 	"context"
+	"slices"
 
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
 	"github.com/gofiber/fiber/v2"
 )
 
+// Keys of the fiber locals the REST middlewares set for NewAppContext
+const (
+	// GrantsKey holds what the request may do: the user's grant, plus the API key's grant for Bearer requests
+	GrantsKey = "grants"
+	// AdminUsernameKey holds the admin acting on a user through the admin routes
+	AdminUsernameKey = "admin_username"
+	// APIKeyIDKey holds the id of the API key a request authenticated with; it is unset for Basic credentials
+	APIKeyIDKey = "api_key_id"
+)
+
 // AppContext wraps context with additional user information
 type AppContext struct {
 	context.Context
 	FiberCtx *fiber.Ctx
 	UserID   int
 	Username string
+	// Grants limit what the request may do; none means an internal caller, like the send queue, which is unrestricted
+	Grants []permission.Grant
+	// AdminUsername is the admin acting on behalf of the user, empty outside the admin routes
+	AdminUsername string
+	// APIKeyID is the API key the request authenticated with, zero for other credentials
+	APIKeyID int
 }
 
 // NewAppContext creates a new app context from fiber context
@@ -30,5 +48,51 @@ func NewAppContext(ctx context.Context, fiberCtx *fiber.Ctx) *AppContext {
 		appCtx.Username = username
 	}
 
+	if grants, ok := fiberCtx.Locals(GrantsKey).([]permission.Grant); ok {
+		appCtx.Grants = grants
+	}
+
+	if admin, ok := fiberCtx.Locals(AdminUsernameKey).(string); ok {
+		appCtx.AdminUsername = admin
+	}
+
+	if keyID, ok := fiberCtx.Locals(APIKeyIDKey).(int); ok {
+		appCtx.APIKeyID = keyID
+	}
+
 	return appCtx
 }
+
+// AllowsChat reports whether the request may act on a chat, given as a JID or a phone number. Handlers check the
+// chats a request names; this checks the chats the usecase only finds out about, like that of a stored message.
+func (c *AppContext) AllowsChat(chat string) bool {
+	for _, grant := range c.Grants {
+		if !grant.AllowsChat(chat) {
+			return false
+		}
+	}
+	return true
+}
+
+// AllowedChatKeys returns the chat keys a listing is limited to, and false when the request may see every chat.
+// A request limited by several grants only sees the chats all of them allow.
+func (c *AppContext) AllowedChatKeys() ([]string, bool) {
+	var keys []string
+	restricted := false
+	for _, grant := range c.Grants {
+		if len(grant.AllowedChats) == 0 {
+			continue
+		}
+
+		grantKeys := make([]string, 0, len(grant.AllowedChats))
+		for _, chat := range grant.AllowedChats {
+			grantKeys = append(grantKeys, permission.ChatKey(chat))
+		}
+		if !restricted {
+			keys, restricted = grantKeys, true
+			continue
+		}
+		keys = slices.DeleteFunc(keys, func(key string) bool { return !slices.Contains(grantKeys, key) })
+	}
+	return keys, restricted
+}
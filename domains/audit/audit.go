@@ -0,0 +1,150 @@
+package audit
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+
+	// ActorSystem is recorded for actions that were not requested through the API, like the admin bootstrap
+	ActorSystem = "system"
+)
+
+// Actions are named <area>.<verb>; filtering by the area alone matches every action of that area
+const (
+	ActionUserCreate        = "user.create"
+	ActionUserUpdate        = "user.update"
+	ActionUserDelete        = "user.delete"
+	ActionSessionDisconnect = "session.disconnect"
+	ActionSessionReconnect  = "session.reconnect"
+	ActionSessionClear      = "session.clear"
+
+	ActionAppLogin          = "app.login"
+	ActionAppLoginWithCode  = "app.login_with_code"
+	ActionAppLogout         = "app.logout"
+	ActionAppReconnect      = "app.reconnect"
+	ActionSendText          = "send.text"
+	ActionSendImage         = "send.image"
+	ActionSendFile          = "send.file"
+	ActionSendVideo         = "send.video"
+	ActionSendContact       = "send.contact"
+	ActionSendLink          = "send.link"
+	ActionSendLocation      = "send.location"
+	ActionSendAudio         = "send.audio"
+	ActionSendPoll          = "send.poll"
+	ActionSendSticker       = "send.sticker"
+	ActionMessageRead       = "message.read"
+	ActionMessageReact      = "message.react"
+	ActionMessageRevoke     = "message.revoke"
+	ActionMessageDelete     = "message.delete"
+	ActionMessageUpdate     = "message.update"
+	ActionMessageStar       = "message.star"
+	ActionMessageForward    = "message.forward"
+	ActionGroupJoin         = "group.join"
+	ActionGroupLeave        = "group.leave"
+	ActionGroupCreate       = "group.create"
+	ActionGroupParticipants = "group.participants"
+	ActionGroupRequests     = "group.requests"
+	ActionGroupPhoto        = "group.photo"
+	ActionGroupName         = "group.name"
+	ActionGroupLocked       = "group.locked"
+	ActionGroupAnnounce     = "group.announce"
+	ActionGroupTopic        = "group.topic"
+)
+
+// Entry is a single row of the append-only audit log
+type Entry struct {
+	ID     int64  `json:"id"`
+	Actor  string `json:"actor"`
+	UserID int    `json:"user_id"`
+	Action string `json:"action"`
+	// TargetJID is the chat, group or device the action was aimed at
+	TargetJID string `json:"target_jid"`
+	// Reference identifies what the action produced or changed, like a message ID or a group ID
+	Reference string    `json:"reference"`
+	Result    string    `json:"result"`
+	Error     string    `json:"error"`
+	Metadata  Metadata  `json:"metadata"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Metadata describes the HTTP request an action came from
+type Metadata struct {
+	IP        string `json:"ip,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+	Method    string `json:"method,omitempty"`
+	Path      string `json:"path,omitempty"`
+	APIKeyID  int    `json:"api_key_id,omitempty"`
+}
+
+func (m Metadata) Value() (driver.Value, error) {
+	encoded, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return string(encoded), nil
+}
+
+func (m *Metadata) Scan(src any) error {
+	var raw []byte
+	switch value := src.(type) {
+	case nil:
+		*m = Metadata{}
+		return nil
+	case string:
+		raw = []byte(value)
+	case []byte:
+		raw = value
+	default:
+		return fmt.Errorf("cannot scan %T into audit.Metadata", src)
+	}
+
+	if len(raw) == 0 {
+		*m = Metadata{}
+		return nil
+	}
+	return json.Unmarshal(raw, m)
+}
+
+type ListRequest struct {
+	UserID    int    `json:"user_id" query:"user_id"`
+	Actor     string `json:"actor" query:"actor"`
+	Action    string `json:"action" query:"action"`
+	TargetJID string `json:"target_jid" query:"target_jid"`
+	Result    string `json:"result" query:"result"`
+	From      string `json:"from" query:"from"`
+	To        string `json:"to" query:"to"`
+	Limit     int    `json:"limit" query:"limit"`
+	Offset    int    `json:"offset" query:"offset"`
+}
+
+type ListResponse struct {
+	Data       []Entry            `json:"data"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+type PaginationResponse struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Total  int `json:"total"`
+}
+
+// Filter selects audit entries, zero values match everything
+type Filter struct {
+	UserID    int
+	Actor     string
+	Action    string
+	TargetJID string
+	Result    string
+	From      *time.Time
+	To        *time.Time
+	// BeforeID only matches entries older than the given id, so exports page through a stable snapshot
+	BeforeID int64
+	Limit    int
+	Offset   int
+}
@@ -0,0 +1,19 @@
+package audit
+
+import (
+	"context"
+	"io"
+)
+
+// IAuditRepository only appends and reads, entries are never changed or removed
+type IAuditRepository interface {
+	Append(entry *Entry) error
+	List(filter Filter) ([]Entry, error)
+	Count(filter Filter) (int, error)
+}
+
+type IAuditUsecase interface {
+	List(ctx context.Context, request ListRequest) (ListResponse, error)
+	// ExportCSV writes every entry matching the request filters as CSV, ignoring limit and offset
+	ExportCSV(ctx context.Context, request ListRequest, w io.Writer) error
+}
@@ -0,0 +1,298 @@
+package autoreply
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// How the text of an incoming message is matched
+const (
+	MatchAny     = "any"     // Every message matches
+	MatchKeyword = "keyword" // Any keyword appears as a whole word, case-insensitive
+	MatchExact   = "exact"   // The whole message equals a keyword, case-insensitive
+	MatchRegex   = "regex"   // The pattern matches the message
+)
+
+// Which chats a rule applies to
+const (
+	ChatTypeAll     = "all"
+	ChatTypePrivate = "private"
+	ChatTypeGroup   = "group"
+)
+
+// What a rule replies with
+const (
+	ReplyText     = "text"
+	ReplyImage    = "image"
+	ReplyVideo    = "video"
+	ReplyAudio    = "audio"
+	ReplyDocument = "document"
+)
+
+// Rule replies to incoming messages of a user that satisfy every condition
+type Rule struct {
+	ID              int            `json:"id"`
+	UserID          int            `json:"user_id"`
+	Name            string         `json:"name"`
+	Priority        int            `json:"priority"` // Lower values are evaluated first
+	MatchType       string         `json:"match_type"`
+	Keywords        []string       `json:"keywords"`
+	Pattern         string         `json:"pattern"`
+	ChatType        string         `json:"chat_type"`
+	AllowSenders    []string       `json:"allow_senders"` // Only these senders, empty allows everyone
+	DenySenders     []string       `json:"deny_senders"`  // Never these senders
+	BusinessHours   *BusinessHours `json:"business_hours"`
+	CooldownSeconds int            `json:"cooldown_seconds"` // Minimum time between two replies to the same contact
+	ReplyType       string         `json:"reply_type"`
+	ReplyText       string         `json:"reply_text"` // Message text, or the caption of a media reply
+	MediaURL        string         `json:"media_url"`
+	MediaPath       string         `json:"-"` // Copy of the media at MediaURL, downloaded when the rule is saved
+	MediaName       string         `json:"-"` // File name of the media, used for documents
+	IsActive        bool           `json:"is_active"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+}
+
+// BusinessHours limits a rule to a weekly time window, or to the time outside of it
+type BusinessHours struct {
+	Timezone string `json:"timezone"` // IANA name, defaults to the server timezone
+	Days     []int  `json:"days"`     // 0 is Sunday, empty means every day
+	Start    string `json:"start"`    // HH:MM
+	End      string `json:"end"`      // HH:MM, earlier than Start for windows spanning midnight
+	Outside  bool   `json:"outside"`  // Apply the rule outside the window instead, e.g. "we are closed" replies
+}
+
+// IncomingMessage is what a rule is evaluated against
+type IncomingMessage struct {
+	Text    string
+	ChatJID string
+	Sender  string // JID or phone number of the sender
+	IsGroup bool
+	Time    time.Time
+}
+
+// Evaluate reports whether the rule applies to the message, and why not when it does not.
+// Cooldowns are runtime state and are checked by the caller.
+func (r Rule) Evaluate(message IncomingMessage) (bool, string) {
+	if !r.IsActive {
+		return false, "rule is inactive"
+	}
+	if !r.AcceptsChatType(message.IsGroup) {
+		return false, fmt.Sprintf("rule only applies to %s chats", r.ChatType)
+	}
+	if !r.AcceptsSender(message.Sender) {
+		return false, "sender is not allowed"
+	}
+	if !r.WithinBusinessHours(message.Time) {
+		return false, "outside of the rule's business hours"
+	}
+	if !r.MatchesText(message.Text) {
+		return false, "message text does not match"
+	}
+	return true, ""
+}
+
+// FirstMatch evaluates rules in order and returns the first one that applies, or nil,
+// together with the evaluation of every rule that was checked
+func FirstMatch(rules []Rule, message IncomingMessage) (*Rule, []RuleEvaluation) {
+	evaluations := make([]RuleEvaluation, 0, len(rules))
+	for i := range rules {
+		matched, reason := rules[i].Evaluate(message)
+		evaluations = append(evaluations, RuleEvaluation{RuleID: rules[i].ID, Name: rules[i].Name, Matched: matched, Reason: reason})
+		if matched {
+			return &rules[i], evaluations
+		}
+	}
+	return nil, evaluations
+}
+
+// AcceptsChatType reports whether the rule applies to private or group chats
+func (r Rule) AcceptsChatType(isGroup bool) bool {
+	switch r.ChatType {
+	case ChatTypePrivate:
+		return !isGroup
+	case ChatTypeGroup:
+		return isGroup
+	default:
+		return true
+	}
+}
+
+// AcceptsSender applies the deny list first, then the allow list.
+// Entries match a full JID or the user part of a JID such as a phone number.
+func (r Rule) AcceptsSender(sender string) bool {
+	if slices.ContainsFunc(r.DenySenders, func(entry string) bool { return senderMatches(entry, sender) }) {
+		return false
+	}
+	if len(r.AllowSenders) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(r.AllowSenders, func(entry string) bool { return senderMatches(entry, sender) })
+}
+
+// WithinBusinessHours reports whether the rule applies at the given time
+func (r Rule) WithinBusinessHours(at time.Time) bool {
+	if r.BusinessHours == nil {
+		return true
+	}
+	return r.BusinessHours.Contains(at) != r.BusinessHours.Outside
+}
+
+// MatchesText reports whether the message text satisfies the rule's match type
+func (r Rule) MatchesText(text string) bool {
+	switch r.MatchType {
+	case MatchKeyword:
+		words := strings.FieldsFunc(strings.ToLower(text), func(c rune) bool {
+			return !(c == '_' || c == '-' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c > 127)
+		})
+		return slices.ContainsFunc(r.Keywords, func(keyword string) bool {
+			keyword = strings.ToLower(strings.TrimSpace(keyword))
+			// Keywords with several words are matched as a phrase
+			if strings.Contains(keyword, " ") {
+				return strings.Contains(" "+strings.Join(words, " ")+" ", " "+keyword+" ")
+			}
+			return slices.Contains(words, keyword)
+		})
+	case MatchExact:
+		return slices.ContainsFunc(r.Keywords, func(keyword string) bool {
+			return strings.EqualFold(strings.TrimSpace(text), strings.TrimSpace(keyword))
+		})
+	case MatchRegex:
+		pattern, err := CompilePattern(r.Pattern)
+		return err == nil && pattern.MatchString(text)
+	default:
+		return true
+	}
+}
+
+// patterns holds the compiled regular expressions of rules, which are loaded again for every incoming message
+var patterns sync.Map
+
+// CompilePattern compiles a regular expression once and reuses it for every later match
+func CompilePattern(pattern string) (*regexp.Regexp, error) {
+	if compiled, ok := patterns.Load(pattern); ok {
+		return compiled.(*regexp.Regexp), nil
+	}
+
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(pattern, compiled)
+	return compiled, nil
+}
+
+// ForgetPattern drops a pattern a rule stopped using, other rules with the same pattern compile it again
+func ForgetPattern(pattern string) {
+	patterns.Delete(pattern)
+}
+
+// Contains reports whether the time falls inside the window
+func (h BusinessHours) Contains(at time.Time) bool {
+	location := time.Local
+	if h.Timezone != "" {
+		if loaded, err := time.LoadLocation(h.Timezone); err == nil {
+			location = loaded
+		}
+	}
+	at = at.In(location)
+
+	start, errStart := time.Parse("15:04", h.Start)
+	end, errEnd := time.Parse("15:04", h.End)
+	if errStart != nil || errEnd != nil {
+		return false
+	}
+
+	minute := at.Hour()*60 + at.Minute()
+	startMinute := start.Hour()*60 + start.Minute()
+	endMinute := end.Hour()*60 + end.Minute()
+
+	day := int(at.Weekday())
+	if startMinute <= endMinute {
+		return h.onDay(day) && minute >= startMinute && minute < endMinute
+	}
+
+	// The window spans midnight, the early morning part belongs to the previous day
+	if minute >= startMinute {
+		return h.onDay(day)
+	}
+	return minute < endMinute && h.onDay((day+6)%7)
+}
+
+func (h BusinessHours) onDay(day int) bool {
+	return len(h.Days) == 0 || slices.Contains(h.Days, day)
+}
+
+func senderMatches(entry, sender string) bool {
+	user, server, _ := strings.Cut(sender, "@")
+	// Strip the device part of a JID such as 628123456789:12@s.whatsapp.net
+	user, _, _ = strings.Cut(user, ":")
+
+	entry = strings.TrimPrefix(strings.TrimSpace(entry), "+")
+	return entry == sender || entry == user || (server != "" && entry == user+"@"+server)
+}
+
+type CreateRuleRequest struct {
+	Name            string         `json:"name"`
+	Priority        int            `json:"priority"`
+	MatchType       string         `json:"match_type"` // Defaults to MatchAny
+	Keywords        []string       `json:"keywords"`
+	Pattern         string         `json:"pattern"`
+	ChatType        string         `json:"chat_type"` // Defaults to ChatTypeAll
+	AllowSenders    []string       `json:"allow_senders"`
+	DenySenders     []string       `json:"deny_senders"`
+	BusinessHours   *BusinessHours `json:"business_hours"`
+	CooldownSeconds int            `json:"cooldown_seconds"`
+	ReplyType       string         `json:"reply_type"` // Defaults to ReplyText
+	ReplyText       string         `json:"reply_text"`
+	MediaURL        string         `json:"media_url"`
+	IsActive        *bool          `json:"is_active"`
+}
+
+type UpdateRuleRequest struct {
+	Name            string         `json:"name"`
+	Priority        *int           `json:"priority"`
+	MatchType       string         `json:"match_type"`
+	Keywords        []string       `json:"keywords"` // nil keeps the current keywords
+	Pattern         *string        `json:"pattern"`
+	ChatType        string         `json:"chat_type"`
+	AllowSenders    []string       `json:"allow_senders"` // nil keeps the current list
+	DenySenders     []string       `json:"deny_senders"`  // nil keeps the current list
+	BusinessHours   *BusinessHours `json:"business_hours"`
+	ClearHours      bool           `json:"clear_business_hours"` // Remove the business hours window
+	CooldownSeconds *int           `json:"cooldown_seconds"`
+	ReplyType       string         `json:"reply_type"`
+	ReplyText       *string        `json:"reply_text"`
+	MediaURL        *string        `json:"media_url"`
+	IsActive        *bool          `json:"is_active"`
+}
+
+type DryRunRequest struct {
+	Message   string `json:"message"`
+	ChatJID   string `json:"chat_jid"` // Defaults to the sender's private chat
+	Sender    string `json:"sender"`
+	IsGroup   bool   `json:"is_group"`
+	Timestamp string `json:"timestamp"` // RFC3339, defaults to now
+}
+
+type RuleEvaluation struct {
+	RuleID  int    `json:"rule_id"`
+	Name    string `json:"name"`
+	Matched bool   `json:"matched"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+type DryRunResponse struct {
+	Matched                  bool             `json:"matched"`
+	WouldReply               bool             `json:"would_reply"` // False while the matched rule is cooling down for the sender
+	CooldownRemainingSeconds int              `json:"cooldown_remaining_seconds,omitempty"`
+	Rule                     *Rule            `json:"rule,omitempty"`
+	ReplyType                string           `json:"reply_type,omitempty"`
+	ReplyText                string           `json:"reply_text,omitempty"`
+	MediaURL                 string           `json:"media_url,omitempty"`
+	Evaluations              []RuleEvaluation `json:"evaluations"`
+}
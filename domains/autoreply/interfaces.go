@@ -0,0 +1,24 @@
+package autoreply
+
+import (
+	"context"
+)
+
+type IAutoReplyRepository interface {
+	Create(rule *Rule) error
+	GetByID(userID, id int) (*Rule, error)
+	GetByUser(userID int) ([]Rule, error)
+	GetActiveByUser(userID int) ([]Rule, error)
+	Update(rule *Rule) error
+	Delete(userID, id int) error
+}
+
+// IAutoReplyUsecase manages the auto-reply rules of a user
+type IAutoReplyUsecase interface {
+	CreateRule(ctx context.Context, userID int, request CreateRuleRequest) (Rule, error)
+	ListRules(ctx context.Context, userID int) ([]Rule, error)
+	GetRule(ctx context.Context, userID, id int) (Rule, error)
+	UpdateRule(ctx context.Context, userID, id int, request UpdateRuleRequest) (Rule, error)
+	DeleteRule(ctx context.Context, userID, id int) error
+	DryRun(ctx context.Context, userID int, request DryRunRequest) (DryRunResponse, error)
+}
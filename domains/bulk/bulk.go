@@ -0,0 +1,84 @@
+package bulk
+
+import "time"
+
+// Statuses of a bulk job. A running job becomes completed once none of its messages are left to send.
+const (
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusCancelled = "cancelled"
+)
+
+// MaxRecipients caps the recipients of a single job
+const MaxRecipients = 1000
+
+// Recipient is a phone number with the values for the placeholders of the message template.
+// The {{phone}} placeholder is always available.
+type Recipient struct {
+	Phone     string            `json:"phone"`
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+type CreateJobRequest struct {
+	// Message is the text sent to every recipient, with {{placeholders}} filled from the recipient variables
+	Message    string      `json:"message" form:"message"`
+	Recipients []Recipient `json:"recipients" form:"-"`
+}
+
+type Job struct {
+	ID        int64     `json:"id"`
+	UserID    int       `json:"user_id"`
+	Message   string    `json:"message"`
+	Status    string    `json:"status"`
+	Progress  Progress  `json:"progress"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Progress counts the recipients of a job by status
+type Progress struct {
+	Total         int `json:"total"`
+	Pending       int `json:"pending"`
+	Sent          int `json:"sent"`
+	Failed        int `json:"failed"`
+	NotOnWhatsApp int `json:"not_on_whatsapp"`
+	Cancelled     int `json:"cancelled"`
+}
+
+// JobRecipient is the outcome of a job for one recipient
+type JobRecipient struct {
+	QueueID   int64      `json:"queue_id"`
+	Phone     string     `json:"phone"`
+	Status    string     `json:"status"`
+	Attempts  int        `json:"attempts"`
+	Error     string     `json:"error,omitempty"`
+	MessageID string     `json:"message_id,omitempty"`
+	SentAt    *time.Time `json:"sent_at,omitempty"`
+}
+
+type ListJobsRequest struct {
+	Limit  int `json:"limit" query:"limit"`
+	Offset int `json:"offset" query:"offset"`
+}
+
+type ListJobsResponse struct {
+	Data       []Job              `json:"data"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+type ListRecipientsRequest struct {
+	Status string `json:"status" query:"status"`
+	Limit  int    `json:"limit" query:"limit"`
+	Offset int    `json:"offset" query:"offset"`
+}
+
+type ListRecipientsResponse struct {
+	Data       []JobRecipient     `json:"data"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+type PaginationResponse struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Total  int `json:"total"`
+}
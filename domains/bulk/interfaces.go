@@ -0,0 +1,23 @@
+package bulk
+
+import "context"
+
+// IBulkRepository persists bulk jobs, their recipients are messages of the send queue
+type IBulkRepository interface {
+	InitializeSchema() error
+	Create(job *Job) error
+	GetByID(userID int, id int64) (*Job, error)
+	// List and Count only include jobs with a recipient in the given chats when chatKeys is not empty
+	List(userID int, chatKeys []string, limit, offset int) ([]Job, error)
+	Count(userID int, chatKeys []string) (int, error)
+	UpdateStatus(id int64, status string) error
+}
+
+// IBulkUsecase sends one message template to many recipients through the send queue
+type IBulkUsecase interface {
+	CreateJob(ctx context.Context, userID int, request CreateJobRequest) (Job, error)
+	ListJobs(ctx context.Context, userID int, request ListJobsRequest) (ListJobsResponse, error)
+	GetJob(ctx context.Context, userID int, id int64) (Job, error)
+	ListRecipients(ctx context.Context, userID int, id int64, request ListRecipientsRequest) (ListRecipientsResponse, error)
+	CancelJob(ctx context.Context, userID int, id int64) (Job, error)
+}
@@ -22,11 +22,13 @@ type Message struct {
 	IsFromMe      bool      `db:"is_from_me"`
 	MediaType     string    `db:"media_type"`
 	Filename      string    `db:"filename"`
+	Mimetype      string    `db:"mimetype"` // Empty for messages stored before it was kept
 	URL           string    `db:"url"`
 	MediaKey      []byte    `db:"media_key"`
 	FileSHA256    []byte    `db:"file_sha256"`
 	FileEncSHA256 []byte    `db:"file_enc_sha256"`
 	FileLength    uint64    `db:"file_length"`
+	Thumbnail     []byte    `db:"thumbnail"` // JPEG preview of images and videos
 	CreatedAt     time.Time `db:"created_at"`
 	UpdatedAt     time.Time `db:"updated_at"`
 }
@@ -61,4 +63,5 @@ type ChatFilter struct {
 	Offset     int
 	SearchName string
 	HasMedia   bool
+	ChatKeys   []string // Only chats whose JID has one of these user parts, see permission.ChatKey; empty allows every chat
 }
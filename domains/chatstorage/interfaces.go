@@ -4,11 +4,17 @@ import (
 	"context"
 	"time"
 
+	"go.mau.fi/whatsmeow/proto/waE2E"
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
 )
 
 type IChatStorageRepository interface {
+	// Ownership scoping
+	ForUser(userID int) IChatStorageRepository
+	UserID() int
+	ClaimLegacyData() (int64, error)
+
 	// Chat operations
 	CreateMessage(ctx context.Context, evt *events.Message) error
 	StoreChat(chat *Chat) error
@@ -23,12 +29,14 @@ type IChatStorageRepository interface {
 	GetMessages(filter *MessageFilter) ([]*Message, error)
 	SearchMessages(chatJID, searchText string, limit int) ([]*Message, error) // Database-level search
 	DeleteMessage(id, chatJID string) error
-	StoreSentMessageWithContext(ctx context.Context, messageID string, senderJID string, recipientJID string, content string, timestamp time.Time) error
+	// StoreSentMessageWithContext keeps the media of msg so it can be forwarded and quoted like received media
+	StoreSentMessageWithContext(ctx context.Context, messageID string, senderJID string, recipientJID string, msg *waE2E.Message, content string, timestamp time.Time) error
 
 	// Statistics
 	GetChatMessageCount(chatJID string) (int64, error)
 	GetTotalMessageCount() (int64, error)
 	GetTotalChatCount() (int64, error)
+	CountChats(filter *ChatFilter) (int64, error)
 	GetChatNameWithPushName(jid types.JID, chatJID string, senderUser string, pushName string) string
 	GetStorageStatistics() (chatCount int64, messageCount int64, err error)
 
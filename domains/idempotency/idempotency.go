@@ -0,0 +1,24 @@
+package idempotency
+
+import "time"
+
+// MaxKeyLength caps the length of an Idempotency-Key header
+const MaxKeyLength = 255
+
+// Key statuses
+const (
+	StatusProcessing = "processing"
+	StatusCompleted  = "completed"
+)
+
+// Record is the first request a user made with an idempotency key and, once it succeeded, its response
+type Record struct {
+	UserID      int
+	Key         string
+	Fingerprint string // Hash of the method, path and payload of the request
+	Status      string
+	StatusCode  int
+	Response    []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
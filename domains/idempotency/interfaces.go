@@ -0,0 +1,26 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+)
+
+// IIdempotencyRepository persists the idempotency keys of every user
+type IIdempotencyRepository interface {
+	InitializeSchema() error
+	// Reserve stores the record unless its key is already taken, in which case the record holding the key is
+	// returned. Expired records, and processing records created before staleBefore, no longer hold their key.
+	Reserve(record *Record, staleBefore time.Time) (*Record, error)
+	Complete(userID int, key string, statusCode int, response []byte) error
+	Release(userID int, key string) error
+}
+
+// IIdempotencyUsecase makes retried requests with the same key return the first response instead of running again
+type IIdempotencyUsecase interface {
+	// Begin reserves the key for a request. It returns the stored record when the key already answered the same
+	// request, and nil when the request should run.
+	Begin(ctx context.Context, userID int, key, fingerprint string) (*Record, error)
+	Complete(ctx context.Context, userID int, key string, statusCode int, response []byte) error
+	// Release frees the key of a request that did not succeed, so it can be retried
+	Release(ctx context.Context, userID int, key string) error
+}
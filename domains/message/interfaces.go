@@ -18,8 +18,14 @@ type IMessageManagement interface {
 	StarMessage(ctx context.Context, request StarRequest) (err error)
 }
 
+// IMessageMedia handles the media of stored messages
+type IMessageMedia interface {
+	DownloadMedia(ctx context.Context, request DownloadMediaRequest) (response DownloadMediaResponse, err error)
+}
+
 // IMessageUsecase combines all message interfaces
 type IMessageUsecase interface {
 	IMessageActions
 	IMessageManagement
+	IMessageMedia
 }
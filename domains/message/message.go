@@ -37,3 +37,17 @@ type StarRequest struct {
 	Phone     string `json:"phone" form:"phone"`
 	IsStarred bool   `json:"is_starred"`
 }
+
+type DownloadMediaRequest struct {
+	MessageID string `json:"message_id" uri:"message_id"`
+}
+
+// DownloadMediaResponse describes the decrypted media of a message, cached on disk at FilePath
+type DownloadMediaResponse struct {
+	MessageID  string `json:"message_id"`
+	MediaType  string `json:"media_type"`
+	Filename   string `json:"filename"`
+	MimeType   string `json:"mime_type"`
+	FileLength int64  `json:"file_length"`
+	FilePath   string `json:"-"`
+}
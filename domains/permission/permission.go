@@ -0,0 +1,237 @@
+package permission
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// All grants every scope
+const All = "*"
+
+// Scopes are named "<resource>:<action>"; "<resource>:*" grants every action of a resource
+const (
+	AppRead   = "app:read"   // Connection status and devices
+	AppManage = "app:manage" // Login, logout and reconnect
+
+	SendText     = "send:text"
+	SendImage    = "send:image"
+	SendFile     = "send:file"
+	SendVideo    = "send:video"
+	SendAudio    = "send:audio"
+	SendContact  = "send:contact"
+	SendLink     = "send:link"
+	SendLocation = "send:location"
+	SendPoll     = "send:poll"
+	SendSticker  = "send:sticker"
+	SendPresence = "send:presence"
+
+	ChatRead  = "chat:read"
+	ChatWrite = "chat:write" // Pin and unpin
+
+	MessageReact    = "message:react"
+	MessageRevoke   = "message:revoke"
+	MessageDelete   = "message:delete"
+	MessageUpdate   = "message:update"
+	MessageRead     = "message:read" // Mark as read
+	MessageStar     = "message:star"
+	MessageForward  = "message:forward"
+	MessageDownload = "message:download" // Media of stored messages
+
+	GroupRead   = "group:read"
+	GroupCreate = "group:create"
+	GroupJoin   = "group:join"
+	GroupLeave  = "group:leave"
+	GroupAdmin  = "group:admin" // Participants, join requests and settings
+
+	UserRead  = "user:read"
+	UserWrite = "user:write" // Own avatar and push name
+
+	NewsletterManage = "newsletter:manage"
+
+	WebhookRead  = "webhook:read"
+	WebhookWrite = "webhook:write"
+
+	AutoReplyRead  = "autoreply:read"
+	AutoReplyWrite = "autoreply:write"
+
+	APIKeyManage = "apikey:manage"
+
+	QueueRead  = "queue:read"
+	QueueWrite = "queue:write" // Cancel queued messages
+
+	ScheduleRead  = "schedule:read"
+	ScheduleWrite = "schedule:write" // Reschedule and cancel
+
+	TemplateRead  = "template:read"
+	TemplateWrite = "template:write"
+
+	EventsRead = "events:read" // Websocket event stream
+)
+
+// Scopes lists every scope that can be granted
+var Scopes = []string{
+	AppRead, AppManage,
+	SendText, SendImage, SendFile, SendVideo, SendAudio, SendContact, SendLink, SendLocation, SendPoll, SendSticker, SendPresence,
+	ChatRead, ChatWrite,
+	MessageReact, MessageRevoke, MessageDelete, MessageUpdate, MessageRead, MessageStar, MessageForward, MessageDownload,
+	GroupRead, GroupCreate, GroupJoin, GroupLeave, GroupAdmin,
+	UserRead, UserWrite,
+	NewsletterManage,
+	WebhookRead, WebhookWrite,
+	AutoReplyRead, AutoReplyWrite,
+	APIKeyManage,
+	QueueRead, QueueWrite,
+	ScheduleRead, ScheduleWrite,
+	TemplateRead, TemplateWrite,
+	EventsRead,
+}
+
+// IsValid reports whether a scope is known, including the wildcards
+func IsValid(scope string) bool {
+	if scope == All || slices.Contains(Scopes, scope) {
+		return true
+	}
+	resource, action, ok := strings.Cut(scope, ":")
+	if !ok || action != "*" {
+		return false
+	}
+	return slices.ContainsFunc(Scopes, func(known string) bool {
+		return strings.HasPrefix(known, resource+":")
+	})
+}
+
+// Grant is what a user or an API key is allowed to do. Empty lists grant everything, so users created
+// before scopes existed keep full access and an API key without scopes can do what its user can.
+type Grant struct {
+	Scopes       List `json:"scopes" db:"scopes"`
+	AllowedChats List `json:"allowed_chats" db:"allowed_chats"` // Chat JIDs or phone numbers, empty allows every chat
+}
+
+// Allows reports whether the grant includes the scope
+func (g Grant) Allows(scope string) bool {
+	if len(g.Scopes) == 0 {
+		return true
+	}
+	resource, _, _ := strings.Cut(scope, ":")
+	return slices.ContainsFunc(g.Scopes, func(granted string) bool {
+		return granted == All || granted == scope || granted == resource+":*"
+	})
+}
+
+// AllowsChat reports whether the grant may act on a chat, given as a JID or a phone number
+func (g Grant) AllowsChat(chat string) bool {
+	if len(g.AllowedChats) == 0 {
+		return true
+	}
+	target := ChatKey(chat)
+	return slices.ContainsFunc(g.AllowedChats, func(allowed string) bool {
+		return ChatKey(allowed) == target
+	})
+}
+
+// Covers reports whether everything the other grant allows is also allowed by this one
+func (g Grant) Covers(other Grant) bool {
+	scopes := other.Scopes
+	if len(scopes) == 0 {
+		scopes = List{All}
+	}
+	// Wildcards are only covered by the same or a broader wildcard
+	for _, scope := range scopes {
+		if !g.Allows(scope) {
+			return false
+		}
+	}
+
+	if len(g.AllowedChats) == 0 {
+		return true
+	}
+	if len(other.AllowedChats) == 0 {
+		return false
+	}
+	for _, chat := range other.AllowedChats {
+		if !g.AllowsChat(chat) {
+			return false
+		}
+	}
+	return true
+}
+
+// Inherit fills the empty lists of the grant from the grant it is limited by
+func (g Grant) Inherit(parent Grant) Grant {
+	if len(g.Scopes) == 0 {
+		g.Scopes = parent.Scopes
+	}
+	if len(g.AllowedChats) == 0 {
+		g.AllowedChats = parent.AllowedChats
+	}
+	return g
+}
+
+// ChatKey reduces a JID or phone number to the part that identifies the chat,
+// so "628123456789", "+628123456789" and "628123456789:12@s.whatsapp.net" are the same chat
+func ChatKey(chat string) string {
+	user, _, _ := strings.Cut(strings.TrimSpace(chat), "@")
+	user, _, _ = strings.Cut(user, ":")
+	return strings.TrimPrefix(user, "+")
+}
+
+// ChatKeyCondition returns an SQL condition matching the rows whose column, holding a JID or phone number,
+// reduces to one of the chat keys the way ChatKey does
+func ChatKeyCondition(column string, keys []string) (string, []any) {
+	user := fmt.Sprintf("substr(trim(%[1]s), 1, instr(trim(%[1]s) || '@', '@') - 1)", column)
+	key := fmt.Sprintf("ltrim(substr(%[1]s, 1, instr(%[1]s || ':', ':') - 1), '+')", user)
+
+	placeholders := make([]string, len(keys))
+	args := make([]any, len(keys))
+	for i, chat := range keys {
+		placeholders[i] = "?"
+		args[i] = chat
+	}
+	return fmt.Sprintf("%s IN (%s)", key, strings.Join(placeholders, ", ")), args
+}
+
+// List is a list of strings stored as a JSON array in a text column
+type List []string
+
+// MarshalJSON writes an empty list instead of null
+func (l List) MarshalJSON() ([]byte, error) {
+	if l == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]string(l))
+}
+
+func (l List) Value() (driver.Value, error) {
+	if len(l) == 0 {
+		return "", nil
+	}
+	encoded, err := json.Marshal([]string(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(encoded), nil
+}
+
+func (l *List) Scan(src any) error {
+	var raw []byte
+	switch value := src.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case string:
+		raw = []byte(value)
+	case []byte:
+		raw = value
+	default:
+		return fmt.Errorf("cannot scan %T into permission.List", src)
+	}
+
+	if len(raw) == 0 {
+		*l = nil
+		return nil
+	}
+	return json.Unmarshal(raw, (*[]string)(l))
+}
@@ -0,0 +1,173 @@
+package permission
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrantAllows(t *testing.T) {
+	tests := []struct {
+		name  string
+		grant Grant
+		scope string
+		want  bool
+	}{
+		{name: "should allow everything without scopes", grant: Grant{}, scope: GroupAdmin, want: true},
+		{name: "should allow everything with all", grant: Grant{Scopes: List{All}}, scope: APIKeyManage, want: true},
+		{name: "should allow exact scope", grant: Grant{Scopes: List{SendText}}, scope: SendText, want: true},
+		{name: "should allow action of resource wildcard", grant: Grant{Scopes: List{"group:*"}}, scope: GroupRead, want: true},
+		{name: "should allow resource wildcard itself", grant: Grant{Scopes: List{"group:*"}}, scope: "group:*", want: true},
+		{name: "should deny other resource", grant: Grant{Scopes: List{"group:*"}}, scope: SendText, want: false},
+		{name: "should deny other action", grant: Grant{Scopes: List{SendText}}, scope: SendImage, want: false},
+		{name: "should deny resource wildcard to exact scope", grant: Grant{Scopes: List{SendText}}, scope: "send:*", want: false},
+		{name: "should deny all to resource wildcard", grant: Grant{Scopes: List{"send:*"}}, scope: All, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.grant.Allows(tt.scope))
+		})
+	}
+}
+
+func TestGrantAllowsChat(t *testing.T) {
+	tests := []struct {
+		name  string
+		grant Grant
+		chat  string
+		want  bool
+	}{
+		{name: "should allow every chat without restriction", grant: Grant{}, chat: "628123456789@s.whatsapp.net", want: true},
+		{name: "should allow phone number given as jid", grant: Grant{AllowedChats: List{"628123456789"}}, chat: "628123456789@s.whatsapp.net", want: true},
+		{name: "should allow device jid given as phone number", grant: Grant{AllowedChats: List{"628123456789:12@s.whatsapp.net"}}, chat: "+628123456789", want: true},
+		{name: "should allow group", grant: Grant{AllowedChats: List{"120363402106123456@g.us"}}, chat: "120363402106123456@g.us", want: true},
+		{name: "should deny other chat", grant: Grant{AllowedChats: List{"628123456789"}}, chat: "628987654321@s.whatsapp.net", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.grant.AllowsChat(tt.chat))
+		})
+	}
+}
+
+func TestGrantCovers(t *testing.T) {
+	tests := []struct {
+		name  string
+		grant Grant
+		other Grant
+		want  bool
+	}{
+		{
+			name:  "should cover everything without restrictions",
+			grant: Grant{},
+			other: Grant{Scopes: List{All}, AllowedChats: List{"628123456789"}},
+			want:  true,
+		},
+		{
+			name:  "should cover action with resource wildcard",
+			grant: Grant{Scopes: List{"send:*"}},
+			other: Grant{Scopes: List{SendText, "send:*"}},
+			want:  true,
+		},
+		{
+			name:  "should not cover all with resource wildcard",
+			grant: Grant{Scopes: List{"send:*"}},
+			other: Grant{Scopes: List{All}},
+			want:  false,
+		},
+		{
+			name:  "should not cover empty scopes with restricted scopes",
+			grant: Grant{Scopes: List{"send:*"}},
+			other: Grant{},
+			want:  false,
+		},
+		{
+			name:  "should not cover resource wildcard with one of its actions",
+			grant: Grant{Scopes: List{SendText}},
+			other: Grant{Scopes: List{"send:*"}},
+			want:  false,
+		},
+		{
+			name:  "should not cover other resource",
+			grant: Grant{Scopes: List{"send:*"}},
+			other: Grant{Scopes: List{GroupRead}},
+			want:  false,
+		},
+		{
+			name:  "should cover subset of chats in another format",
+			grant: Grant{AllowedChats: List{"628123456789", "628987654321"}},
+			other: Grant{AllowedChats: List{"+628123456789", "628987654321:3@s.whatsapp.net"}},
+			want:  true,
+		},
+		{
+			name:  "should not cover every chat with restricted chats",
+			grant: Grant{AllowedChats: List{"628123456789"}},
+			other: Grant{},
+			want:  false,
+		},
+		{
+			name:  "should not cover chat outside the allowed chats",
+			grant: Grant{AllowedChats: List{"628123456789"}},
+			other: Grant{AllowedChats: List{"628123456789", "628987654321"}},
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.grant.Covers(tt.other))
+		})
+	}
+}
+
+func TestChatKey(t *testing.T) {
+	tests := []struct {
+		name string
+		chat string
+		want string
+	}{
+		{name: "should keep phone number", chat: "628123456789", want: "628123456789"},
+		{name: "should strip plus sign", chat: "+628123456789", want: "628123456789"},
+		{name: "should strip server", chat: "628123456789@s.whatsapp.net", want: "628123456789"},
+		{name: "should strip device and server", chat: "628123456789:12@s.whatsapp.net", want: "628123456789"},
+		{name: "should trim spaces", chat: " 628123456789 ", want: "628123456789"},
+		{name: "should keep group id", chat: "120363402106123456@g.us", want: "120363402106123456"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ChatKey(tt.chat))
+		})
+	}
+}
+
+func TestChatKeyCondition(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec("CREATE TABLE chats (phone TEXT NOT NULL)")
+	require.NoError(t, err)
+	chats := []string{"628123456789", "+628123456789", "628123456789@s.whatsapp.net", "628123456789:12@s.whatsapp.net",
+		" 628123456789 ", "120363402106123456@g.us", "628999999999"}
+	for _, chat := range chats {
+		_, err = db.Exec("INSERT INTO chats (phone) VALUES (?)", chat)
+		require.NoError(t, err)
+	}
+
+	condition, args := ChatKeyCondition("phone", []string{"628123456789", "120363402106123456"})
+	rows, err := db.Query("SELECT phone FROM chats WHERE "+condition, args...)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var matched []string
+	for rows.Next() {
+		var phone string
+		require.NoError(t, rows.Scan(&phone))
+		matched = append(matched, phone)
+	}
+	require.NoError(t, rows.Err())
+
+	assert.Equal(t, chats[:6], matched)
+}
@@ -0,0 +1,47 @@
+package queue
+
+import (
+	"context"
+	"time"
+)
+
+// IQueueRepository persists queued messages so they survive restarts
+type IQueueRepository interface {
+	InitializeSchema() error
+	Enqueue(message *Message) error
+	// EnqueueBatch records all messages or none of them
+	EnqueueBatch(messages []*Message) error
+	GetByID(userID int, id int64) (*Message, error)
+	// List and Count only include messages to the given chats when chatKeys is not empty
+	List(userID int, status string, chatKeys []string, limit, offset int) ([]Message, error)
+	Count(userID int, status string, chatKeys []string) (int, error)
+	// ListByJob and CountByJob only include messages to the given chats when chatKeys is not empty
+	ListByJob(jobID int64, status string, chatKeys []string, limit, offset int) ([]Message, error)
+	CountByJob(jobID int64, status string, chatKeys []string) (int, error)
+	// CountJobStatuses returns how many messages of a bulk job are in each status
+	CountJobStatuses(jobID int64) (map[string]int, error)
+	// GetDueUsers returns the users with pending messages due at or before now
+	GetDueUsers(now time.Time) ([]int, error)
+	// ClaimNext marks the oldest due pending message of a user as sending and returns it, nil when none is due
+	ClaimNext(userID int, now time.Time) (*Message, error)
+	MarkSent(id int64, attempts int, messageID string) error
+	// MarkFailed records why a message will not be sent, status is StatusFailed or StatusNotOnWhatsApp
+	MarkFailed(id int64, attempts int, status, lastError string) error
+	// Reschedule puts a message back to pending until nextAttemptAt
+	Reschedule(id int64, attempts int, lastError string, nextAttemptAt time.Time) error
+	// Cancel cancels a pending message, it reports false when the message is not pending
+	Cancel(userID int, id int64) (bool, error)
+	// CancelJob cancels the pending messages of a bulk job and returns how many were cancelled
+	CancelJob(jobID int64) (int64, error)
+	// ResetSending puts messages that were being sent when the process stopped back to pending
+	ResetSending() (int64, error)
+}
+
+// IQueueUsecase inspects and drains the send queue
+type IQueueUsecase interface {
+	ListMessages(ctx context.Context, userID int, request ListRequest) (ListResponse, error)
+	GetMessage(ctx context.Context, userID int, id int64) (Message, error)
+	CancelMessage(ctx context.Context, userID int, id int64) (Message, error)
+	// RunWorker sends due messages, one at a time per user, until ctx is cancelled
+	RunWorker(ctx context.Context)
+}
@@ -0,0 +1,75 @@
+package queue
+
+import "time"
+
+// Statuses of a queued message. Pending messages wait for the worker, the others but sending are final.
+const (
+	StatusPending       = "pending"
+	StatusSending       = "sending"
+	StatusSent          = "sent"
+	StatusFailed        = "failed"
+	StatusNotOnWhatsApp = "not_on_whatsapp"
+	StatusCancelled     = "cancelled"
+)
+
+// Types of queued messages, named after the send endpoint they came from
+const (
+	TypeText     = "text"
+	TypeImage    = "image"
+	TypeFile     = "file"
+	TypeVideo    = "video"
+	TypeAudio    = "audio"
+	TypeContact  = "contact"
+	TypeLink     = "link"
+	TypeLocation = "location"
+	TypePoll     = "poll"
+	TypeSticker  = "sticker"
+)
+
+// Message is a send request persisted until the worker of its user delivers it
+type Message struct {
+	ID     int64 `json:"id"`
+	UserID int   `json:"user_id"`
+	// JobID is the bulk job the message belongs to, 0 for messages queued on their own
+	JobID    int64  `json:"job_id,omitempty"`
+	Username string `json:"-"`
+	Type     string `json:"type"`
+	Phone    string `json:"phone"`
+	// Payload is the send request as JSON, without its uploaded file
+	Payload string `json:"-"`
+	// The uploaded file of a media request is kept on disk until the message is final
+	AttachmentPath string `json:"-"`
+	AttachmentName string `json:"attachment_name,omitempty"`
+	AttachmentType string `json:"-"`
+	Status         string `json:"status"`
+	Attempts       int    `json:"attempts"`
+	LastError      string `json:"last_error,omitempty"`
+	// MessageID is the WhatsApp message ID once sent
+	MessageID     string     `json:"message_id,omitempty"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+}
+
+// IsFinal reports whether the message will not be sent anymore, or already was
+func (m Message) IsFinal() bool {
+	return m.Status == StatusSent || m.Status == StatusFailed || m.Status == StatusNotOnWhatsApp || m.Status == StatusCancelled
+}
+
+type ListRequest struct {
+	Status string `json:"status" query:"status"`
+	Limit  int    `json:"limit" query:"limit"`
+	Offset int    `json:"offset" query:"offset"`
+}
+
+type ListResponse struct {
+	Data       []Message          `json:"data"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+type PaginationResponse struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Total  int `json:"total"`
+}
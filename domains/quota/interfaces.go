@@ -0,0 +1,33 @@
+package quota
+
+import (
+	"context"
+	"time"
+)
+
+type IQuotaRepository interface {
+	// GetLimits returns nil when no limits were set for the user
+	GetLimits(userID int) (*Limits, error)
+	SaveLimits(userID int, limits Limits) error
+	RecordSend(event *SendEvent) error
+	DeleteSend(event SendEvent) error
+	GetTotals(userID int, since time.Time) (Totals, error)
+	// GetSends returns the user's sends since a point in time, oldest first
+	GetSends(userID int, since time.Time) ([]SendEvent, error)
+	IsKnownContact(userID int, recipient string) (bool, error)
+	PruneSends(before time.Time) error
+}
+
+// IQuotaUsecase enforces the sending limits of users
+type IQuotaUsecase interface {
+	GetLimits(ctx context.Context, userID int) (Limits, error)
+	SetLimits(ctx context.Context, userID int, limits Limits) (Limits, error)
+	GetUsage(ctx context.Context, userID int) (UsageResponse, error)
+	// Reserve counts a send against the user's limits, or returns a rate limit error when it would exceed one
+	Reserve(ctx context.Context, send Send) (SendEvent, error)
+	// Check returns the rate limit error Reserve would return for the message and new contact limits, without
+	// reserving anything, so a message over them is refused before its media is processed and uploaded
+	Check(ctx context.Context, send Send) error
+	// Release gives back a reservation whose message could not be sent
+	Release(ctx context.Context, event SendEvent) error
+}
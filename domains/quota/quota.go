@@ -0,0 +1,67 @@
+package quota
+
+import "time"
+
+// Names of the limits, reported in rate limit errors
+const (
+	LimitMessagesPerMinute = "messages_per_minute"
+	LimitMessagesPerHour   = "messages_per_hour"
+	LimitMessagesPerDay    = "messages_per_day"
+	LimitNewContactsPerDay = "new_contacts_per_day"
+	LimitMediaBytesPerDay  = "media_bytes_per_day"
+)
+
+// Limits caps what a user may send within sliding windows, zero means unlimited
+type Limits struct {
+	MessagesPerMinute int   `json:"messages_per_minute"`
+	MessagesPerHour   int   `json:"messages_per_hour"`
+	MessagesPerDay    int   `json:"messages_per_day"`
+	NewContactsPerDay int   `json:"new_contacts_per_day"`
+	MediaBytesPerDay  int64 `json:"media_bytes_per_day"`
+}
+
+// IsUnlimited reports whether no limit is set
+func (l Limits) IsUnlimited() bool {
+	return l == Limits{}
+}
+
+// Send is a message about to be sent, counted against the limits of its user
+type Send struct {
+	UserID     int
+	Recipient  string
+	MediaBytes int64
+	// KnownChat is set when the user already has a chat with the recipient, so it is not a new contact
+	KnownChat bool
+}
+
+// SendEvent is a recorded send, kept for a day to count the sliding windows
+type SendEvent struct {
+	ID         int64
+	UserID     int
+	Recipient  string
+	MediaBytes int64
+	NewContact bool
+	SentAt     time.Time
+}
+
+// Totals sums the sends of a user since a point in time
+type Totals struct {
+	Messages    int
+	NewContacts int
+	MediaBytes  int64
+}
+
+// Usage counts the sends of a user within each sliding window
+type Usage struct {
+	MessagesLastMinute int   `json:"messages_last_minute"`
+	MessagesLastHour   int   `json:"messages_last_hour"`
+	MessagesLastDay    int   `json:"messages_last_day"`
+	NewContactsLastDay int   `json:"new_contacts_last_day"`
+	MediaBytesLastDay  int64 `json:"media_bytes_last_day"`
+}
+
+type UsageResponse struct {
+	UserID int    `json:"user_id"`
+	Limits Limits `json:"limits"`
+	Usage  Usage  `json:"usage"`
+}
@@ -0,0 +1,44 @@
+package schedule
+
+import (
+	"context"
+	"time"
+)
+
+// IScheduleRepository persists schedules so they survive restarts
+type IScheduleRepository interface {
+	InitializeSchema() error
+	Create(schedule *Schedule) error
+	GetByID(userID int, id int64) (*Schedule, error)
+	// List and Count only include schedules to the given chats when chatKeys is not empty
+	List(userID int, status string, chatKeys []string, limit, offset int) ([]Schedule, error)
+	Count(userID int, status string, chatKeys []string) (int, error)
+	// GetDue returns the active schedules whose next run is at or before now
+	GetDue(now time.Time) ([]Schedule, error)
+	// Claim marks an active schedule as running if its next run is still nextRunAt, it reports false when another
+	// run claimed it first or it was changed in the meantime
+	Claim(id int64, nextRunAt time.Time) (bool, error)
+	// ResetRunning puts schedules that were running when the process stopped back to active
+	ResetRunning() (int64, error)
+	// UpdateTiming stores new SendAt, Cron, Timezone and NextRunAt of an active schedule, it reports false when the schedule is not active
+	UpdateTiming(schedule *Schedule) (bool, error)
+	// SaveRun stores the outcome of a run: status, next run and the last run fields. The schedule gets the stored
+	// status, which stays cancelled when it was cancelled during the run.
+	SaveRun(schedule *Schedule) error
+	// Postpone makes a running schedule active again with a later next run, without counting a run. It reports false
+	// when the schedule was cancelled during the run.
+	Postpone(id int64, nextRunAt time.Time, lastError string) (bool, error)
+	// Cancel cancels an active or running schedule, it reports false when the schedule is neither, and whether a run
+	// was in progress
+	Cancel(userID int, id int64) (cancelled bool, running bool, err error)
+}
+
+// IScheduleUsecase manages and runs scheduled sends
+type IScheduleUsecase interface {
+	ListSchedules(ctx context.Context, userID int, request ListRequest) (ListResponse, error)
+	GetSchedule(ctx context.Context, userID int, id int64) (Schedule, error)
+	UpdateSchedule(ctx context.Context, userID int, id int64, request UpdateRequest) (Schedule, error)
+	CancelSchedule(ctx context.Context, userID int, id int64) (Schedule, error)
+	// RunScheduler sends due schedules through the session of their user until ctx is cancelled
+	RunScheduler(ctx context.Context)
+}
@@ -0,0 +1,72 @@
+package schedule
+
+import "time"
+
+// Statuses of a schedule. Active schedules wait for their next run, running ones are being sent, the others are final.
+const (
+	StatusActive    = "active"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+	StatusCancelled = "cancelled"
+)
+
+// Results of a run
+const (
+	ResultSent   = "sent"
+	ResultQueued = "queued"
+	ResultFailed = "failed"
+)
+
+// Schedule is a send request stored to be sent at a given time, or repeatedly on a cron expression
+type Schedule struct {
+	ID       int64  `json:"id"`
+	UserID   int    `json:"user_id"`
+	Username string `json:"-"`
+	// Type is the send endpoint the request came from, one of the send queue types
+	Type  string `json:"type"`
+	Phone string `json:"phone"`
+	// Payload is the send request as JSON, without its uploaded file
+	Payload string `json:"-"`
+	// The uploaded file of a media request is kept on disk until the schedule is final
+	AttachmentPath string     `json:"-"`
+	AttachmentName string     `json:"attachment_name,omitempty"`
+	AttachmentType string     `json:"-"`
+	SendAt         *time.Time `json:"send_at,omitempty"`
+	Cron           string     `json:"cron,omitempty"`
+	Timezone       string     `json:"timezone"`
+	Status         string     `json:"status"`
+	// NextRunAt is when the schedule runs next, it is cleared once the schedule is final
+	NextRunAt     *time.Time `json:"next_run_at,omitempty"`
+	Runs          int        `json:"runs"`
+	LastRunAt     *time.Time `json:"last_run_at,omitempty"`
+	LastResult    string     `json:"last_result,omitempty"`
+	LastMessageID string     `json:"last_message_id,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// UpdateRequest replaces when an active schedule runs, exactly one of SendAt and Cron is required
+type UpdateRequest struct {
+	SendAt   string `json:"send_at"`
+	Cron     string `json:"cron"`
+	Timezone string `json:"timezone"`
+}
+
+type ListRequest struct {
+	Status string `json:"status" query:"status"`
+	Limit  int    `json:"limit" query:"limit"`
+	Offset int    `json:"offset" query:"offset"`
+}
+
+type ListResponse struct {
+	Data       []Schedule         `json:"data"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+type PaginationResponse struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Total  int `json:"total"`
+}
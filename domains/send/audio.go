@@ -4,6 +4,7 @@ import "mime/multipart"
 
 type AudioRequest struct {
 	BaseRequest
+	ReplyRequest
 	Audio    *multipart.FileHeader `json:"audio" form:"audio"`
 	AudioURL *string               `json:"audio_url" form:"audio_url"`
 }
@@ -4,4 +4,32 @@ type BaseRequest struct {
 	Phone       string `json:"phone" form:"phone"`
 	Duration    *int   `json:"duration,omitempty" form:"duration"`
 	IsForwarded bool   `json:"is_forwarded,omitempty" form:"is_forwarded"`
+	// Queue persists the request and returns right away, the send queue worker of the user sends it later
+	Queue bool `json:"queue,omitempty" form:"queue"`
+	Schedule
+}
+
+// Schedule stores the request to be sent once at SendAt or repeatedly on a cron expression, in Timezone
+type Schedule struct {
+	SendAt   string `json:"send_at,omitempty" form:"send_at"`
+	Cron     string `json:"cron,omitempty" form:"cron"`
+	Timezone string `json:"timezone,omitempty" form:"timezone"`
+}
+
+// IsScheduled reports whether the request should be stored as a schedule instead of sent now
+func (s Schedule) IsScheduled() bool {
+	return s.SendAt != "" || s.Cron != ""
+}
+
+// TemplateRequest fills the text of a request from a stored template of the user, rendered with Variables
+type TemplateRequest struct {
+	TemplateName string            `json:"template_name,omitempty" form:"template_name"`
+	Variables    map[string]string `json:"variables,omitempty" form:"-"`
+}
+
+// ReplyRequest quotes an earlier message of the chat and mentions users. Mentions are phone numbers or JIDs, @phone
+// numbers in the text or caption are mentioned as well.
+type ReplyRequest struct {
+	ReplyMessageID *string  `json:"reply_message_id,omitempty" form:"reply_message_id"`
+	Mentions       []string `json:"mentions,omitempty" form:"mentions"`
 }
@@ -2,6 +2,7 @@ package send
 
 type ContactRequest struct {
 	BaseRequest
+	ReplyRequest
 	ContactName  string `json:"contact_name" form:"contact_name"`
 	ContactPhone string `json:"contact_phone" form:"contact_phone"`
 }
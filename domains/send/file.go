@@ -4,6 +4,8 @@ import "mime/multipart"
 
 type FileRequest struct {
 	BaseRequest
+	TemplateRequest
+	ReplyRequest
 	File    *multipart.FileHeader `json:"file" form:"file"`
 	Caption string                `json:"caption" form:"caption"`
 }
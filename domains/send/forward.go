@@ -0,0 +1,24 @@
+package send
+
+// MaxForwardTargets caps the chats a message is forwarded to in one request
+const MaxForwardTargets = 50
+
+// ForwardRequest forwards a message from the chat storage to one or more phones or groups
+type ForwardRequest struct {
+	MessageID string   `json:"message_id" uri:"message_id"`
+	Phones    []string `json:"phones" form:"phones"`
+	Duration  *int     `json:"duration,omitempty" form:"duration"`
+}
+
+type ForwardResponse struct {
+	MessageID string          `json:"message_id"`
+	Status    string          `json:"status"`
+	Results   []ForwardResult `json:"results"`
+}
+
+// ForwardResult is the outcome of a forward for one target, MessageID is the ID of the forwarded copy
+type ForwardResult struct {
+	Phone     string `json:"phone"`
+	MessageID string `json:"message_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
@@ -4,6 +4,8 @@ import "mime/multipart"
 
 type ImageRequest struct {
 	BaseRequest
+	TemplateRequest
+	ReplyRequest
 	Caption  string                `json:"caption" form:"caption"`
 	Image    *multipart.FileHeader `json:"image" form:"image"`
 	ImageURL *string               `json:"image_url" form:"image_url"`
@@ -15,6 +15,7 @@ type IMediaSender interface {
 	SendFile(ctx context.Context, request FileRequest) (response GenericResponse, err error)
 	SendVideo(ctx context.Context, request VideoRequest) (response GenericResponse, err error)
 	SendAudio(ctx context.Context, request AudioRequest) (response GenericResponse, err error)
+	SendSticker(ctx context.Context, request StickerRequest) (response GenericResponse, err error)
 }
 
 // IInteractionSender handles interaction message sending operations
@@ -25,6 +26,11 @@ type IInteractionSender interface {
 	SendPoll(ctx context.Context, request PollRequest) (response GenericResponse, err error)
 }
 
+// IForwardSender handles forwarding of stored messages
+type IForwardSender interface {
+	ForwardMessage(ctx context.Context, request ForwardRequest) (response ForwardResponse, err error)
+}
+
 // IPresenceSender handles presence-related operations
 type IPresenceSender interface {
 	SendPresence(ctx context.Context, request PresenceRequest) (response GenericResponse, err error)
@@ -36,5 +42,6 @@ type ISendUsecase interface {
 	ITextSender
 	IMediaSender
 	IInteractionSender
+	IForwardSender
 	IPresenceSender
 }
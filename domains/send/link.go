@@ -2,6 +2,8 @@ package send
 
 type LinkRequest struct {
 	BaseRequest
+	TemplateRequest
+	ReplyRequest
 	Caption string `json:"caption"`
 	Link    string `json:"link"`
 }
@@ -2,6 +2,7 @@ package send
 
 type LocationRequest struct {
 	BaseRequest
+	ReplyRequest
 	Latitude  string `json:"latitude" form:"latitude"`
 	Longitude string `json:"longitude" form:"longitude"`
 }
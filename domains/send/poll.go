@@ -2,6 +2,7 @@ package send
 
 type PollRequest struct {
 	BaseRequest
+	ReplyRequest
 	Question  string   `json:"question" form:"question"`
 	Options   []string `json:"options" form:"options"`
 	MaxAnswer int      `json:"max_answer" form:"max_answer"`
@@ -3,4 +3,8 @@ package send
 type GenericResponse struct {
 	MessageID string `json:"message_id"`
 	Status    string `json:"status"`
+	// QueueID identifies a queued request, the message ID is only known once the queue sent it
+	QueueID int64 `json:"queue_id,omitempty"`
+	// ScheduleID identifies a scheduled request
+	ScheduleID int64 `json:"schedule_id,omitempty"`
 }
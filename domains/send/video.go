@@ -4,6 +4,8 @@ import "mime/multipart"
 
 type VideoRequest struct {
 	BaseRequest
+	TemplateRequest
+	ReplyRequest
 	Caption  string                `json:"caption" form:"caption"`
 	Video    *multipart.FileHeader `json:"video" form:"video"`
 	ViewOnce bool                  `json:"view_once" form:"view_once"`
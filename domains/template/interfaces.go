@@ -0,0 +1,24 @@
+package template
+
+import "context"
+
+// ITemplateRepository persists the message templates of every user
+type ITemplateRepository interface {
+	InitializeSchema() error
+	Create(template *Template) error
+	GetByID(userID int, id int64) (*Template, error)
+	GetByName(userID int, name string) (*Template, error)
+	List(userID int, limit, offset int) ([]Template, error)
+	Count(userID int) (int, error)
+	Update(template *Template) error
+	Delete(userID int, id int64) error
+}
+
+// ITemplateUsecase manages the message templates of a user, the send endpoints render them by name
+type ITemplateUsecase interface {
+	CreateTemplate(ctx context.Context, userID int, request CreateTemplateRequest) (Template, error)
+	ListTemplates(ctx context.Context, userID int, request ListRequest) (ListResponse, error)
+	GetTemplate(ctx context.Context, userID int, id int64) (Template, error)
+	UpdateTemplate(ctx context.Context, userID int, id int64, request UpdateTemplateRequest) (Template, error)
+	DeleteTemplate(ctx context.Context, userID int, id int64) error
+}
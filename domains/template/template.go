@@ -0,0 +1,59 @@
+package template
+
+import (
+	"mime/multipart"
+	"time"
+)
+
+// MaxNameLength caps template names, they are typed into send requests
+const MaxNameLength = 64
+
+// Template is a reusable message of a user. The {{placeholders}} of its body are filled in from the variables
+// of a send request, {{phone}} always holds the recipient's phone number.
+type Template struct {
+	ID     int64  `json:"id"`
+	UserID int    `json:"user_id"`
+	Name   string `json:"name"`
+	Body   string `json:"body"`
+	// Variables are the placeholders of the body, a send request must provide every one except phone
+	Variables []string `json:"variables"`
+	// AttachmentPath is the stored media file, used by the media send endpoints when the request has none
+	AttachmentPath string    `json:"-"`
+	AttachmentName string    `json:"attachment_name,omitempty"`
+	AttachmentType string    `json:"attachment_type,omitempty"`
+	Duration       *int      `json:"duration,omitempty"` // Disappearing message duration used when the request has none
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type CreateTemplateRequest struct {
+	Name       string                `json:"name" form:"name"`
+	Body       string                `json:"body" form:"body"`
+	Duration   *int                  `json:"duration" form:"duration"`
+	Attachment *multipart.FileHeader `json:"-" form:"-"`
+}
+
+type UpdateTemplateRequest struct {
+	Name             string                `json:"name" form:"name"` // Empty keeps the current name
+	Body             *string               `json:"body" form:"body"`
+	Duration         *int                  `json:"duration" form:"duration"`
+	ClearDuration    bool                  `json:"clear_duration" form:"clear_duration"` // Remove the default duration
+	Attachment       *multipart.FileHeader `json:"-" form:"-"`                           // Replaces the current attachment
+	RemoveAttachment bool                  `json:"remove_attachment" form:"remove_attachment"`
+}
+
+type ListRequest struct {
+	Limit  int `json:"limit" query:"limit"`
+	Offset int `json:"offset" query:"offset"`
+}
+
+type ListResponse struct {
+	Data       []Template         `json:"data"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+type PaginationResponse struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Total  int `json:"total"`
+}
@@ -1,5 +1,7 @@
 package usermanagement
 
+import "context"
+
 type IUserManagementRepository interface {
 	Create(user *User) error
 	GetByID(id int) (*User, error)
@@ -8,20 +10,23 @@ type IUserManagementRepository interface {
 	Update(id int, user *UpdateUserRequest) error
 	Delete(id int) error
 	GetActiveUsers() ([]User, error)
+	CountActiveAdmins() (int, error)
 	ValidateCredentials(username, password string) bool
 }
 
 type IUserManagementUsecase interface {
-	CreateUser(request CreateUserRequest) (*UserResponse, error)
+	CreateUser(ctx context.Context, request CreateUserRequest) (*UserResponse, error)
 	GetUser(id int) (*UserResponse, error)
 	GetUserByUsername(username string) (*UserResponse, error)
 	GetAllUsers() ([]UserResponse, error)
-	UpdateUser(id int, request UpdateUserRequest) (*UserResponse, error)
-	DeleteUser(id int) error
+	UpdateUser(ctx context.Context, id int, request UpdateUserRequest) (*UserResponse, error)
+	DeleteUser(ctx context.Context, id int) error
 	ValidateUserCredentials(username, password string) bool
 	GetActiveUserCredentials() map[string]string
+	// BootstrapAdmin creates an admin account from the given credentials while the database has no active admin
+	BootstrapAdmin(username, password string) error
 	// WhatsApp Session Management for Admin
-	DisconnectWhatsAppSession(userID int) error
-	ReconnectWhatsAppSession(userID int) error
-	ClearWhatsAppSession(userID int) error
+	DisconnectWhatsAppSession(ctx context.Context, userID int) error
+	ReconnectWhatsAppSession(ctx context.Context, userID int) error
+	ClearWhatsAppSession(ctx context.Context, userID int) error
 }
@@ -1,35 +1,69 @@
 package usermanagement
 
 import (
+	"errors"
 	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
+)
+
+// Roles decide access to the /admin routes; every role can use the WhatsApp API as a regular user
+const (
+	RoleUser    = "user"
+	RoleAdmin   = "admin"   // Full access to the /admin routes
+	RoleAuditor = "auditor" // Read-only access to the /admin routes
 )
 
+// Roles lists every role that can be assigned
+var Roles = []string{RoleUser, RoleAdmin, RoleAuditor}
+
+// ErrLastActiveAdmin is returned for changes that would leave no active admin, locking everyone out of /admin
+var ErrLastActiveAdmin = errors.New("cannot remove the last active admin")
+
+// IsAdminRole reports whether the role may sign in to the /admin routes
+func IsAdminRole(role string) bool {
+	return role == RoleAdmin || role == RoleAuditor
+}
+
 type User struct {
 	ID        int       `json:"id" db:"id"`
 	Username  string    `json:"username" db:"username"`
 	Password  string    `json:"password" db:"password"`
 	IsActive  bool      `json:"is_active" db:"is_active"`
+	Role      string    `json:"role" db:"role"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+
+	// Empty scopes grant every scope and empty allowed chats allow every chat
+	permission.Grant
 }
 
 type CreateUserRequest struct {
-	Username string `json:"username" validate:"required,min=3,max=50"`
-	Password string `json:"password" validate:"required,min=6"`
+	Username     string   `json:"username" validate:"required,min=3,max=50"`
+	Password     string   `json:"password" validate:"required,min=6"`
+	Role         string   `json:"role"` // Defaults to user
+	Scopes       []string `json:"scopes"`
+	AllowedChats []string `json:"allowed_chats"`
 }
 
 type UpdateUserRequest struct {
-	Username string `json:"username" validate:"omitempty,min=3,max=50"`
-	Password string `json:"password" validate:"omitempty,min=6"`
-	IsActive *bool  `json:"is_active"`
+	Username     string   `json:"username" validate:"omitempty,min=3,max=50"`
+	Password     string   `json:"password" validate:"omitempty,min=6"`
+	IsActive     *bool    `json:"is_active"`
+	Role         string   `json:"role"`
+	Scopes       []string `json:"scopes"`        // Omitted keeps the scopes, an empty list grants every scope
+	AllowedChats []string `json:"allowed_chats"` // Omitted keeps the chats, an empty list allows every chat
 }
 
 type UserResponse struct {
 	ID          int       `json:"id"`
 	Username    string    `json:"username"`
 	IsActive    bool      `json:"is_active"`
+	Role        string    `json:"role"`
 	IsConnected bool      `json:"is_connected"`
 	IsLoggedIn  bool      `json:"is_logged_in"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+
+	permission.Grant
 }
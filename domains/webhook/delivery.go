@@ -0,0 +1,111 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Delivery is a webhook event in the outbox to be posted to a single endpoint. Delivered ones are kept for a while
+// so their attempts can be reviewed.
+type Delivery struct {
+	ID             int64
+	UserID         int
+	WebhookID      int // 0 for the globally configured URLs
+	Event          string
+	URL            string
+	Payload        string
+	Attempts       int // Tries since the delivery was enqueued or last replayed
+	LastError      string
+	LastStatusCode int
+	NextAttemptAt  time.Time
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+	History        []DeliveryAttempt // Every try, oldest first, including those before a replay
+}
+
+// DeadLetter is a delivery that exhausted every retry
+type DeadLetter struct {
+	ID             int64
+	UserID         int
+	WebhookID      int
+	Event          string
+	URL            string
+	Payload        string
+	Attempts       int
+	LastError      string
+	LastStatusCode int
+	CreatedAt      time.Time
+	FailedAt       time.Time
+	History        []DeliveryAttempt // Every try, oldest first, including those before a replay
+}
+
+// DeliveryAttempt is a single try to post a delivery. Attempts follow the delivery into the dead letters and back,
+// and are numbered across replays.
+type DeliveryAttempt struct {
+	OutboxID    int64     `json:"-"`
+	Attempt     int       `json:"attempt"` // Set when the attempt is recorded
+	StatusCode  int       `json:"status_code,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	DurationMs  int64     `json:"duration_ms"`
+	AttemptedAt time.Time `json:"attempted_at"`
+}
+
+type ListDeadLettersRequest struct {
+	Limit  int `json:"limit" query:"limit"`
+	Offset int `json:"offset" query:"offset"`
+}
+
+type ListDeadLettersResponse struct {
+	Data       []DeadLetterResponse `json:"data"`
+	Pagination PaginationResponse   `json:"pagination"`
+}
+
+type DeadLetterResponse struct {
+	ID             int64             `json:"id"`
+	WebhookID      int               `json:"webhook_id"`
+	Event          string            `json:"event"`
+	URL            string            `json:"url"`
+	Payload        json.RawMessage   `json:"payload"`
+	Attempts       int               `json:"attempts"`
+	LastError      string            `json:"last_error"`
+	LastStatusCode int               `json:"last_status_code,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	FailedAt       time.Time         `json:"failed_at"`
+	History        []DeliveryAttempt `json:"history"`
+}
+
+type ListDeliveriesRequest struct {
+	Limit  int `json:"limit" query:"limit"`
+	Offset int `json:"offset" query:"offset"`
+}
+
+type ListDeliveriesResponse struct {
+	Data       []DeliveryResponse `json:"data"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+type DeliveryResponse struct {
+	ID             int64             `json:"id"`
+	WebhookID      int               `json:"webhook_id"`
+	Event          string            `json:"event"`
+	URL            string            `json:"url"`
+	Payload        json.RawMessage   `json:"payload"`
+	LastStatusCode int               `json:"last_status_code,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	DeliveredAt    time.Time         `json:"delivered_at"`
+	History        []DeliveryAttempt `json:"history"`
+}
+
+type ReplayDeadLettersRequest struct {
+	IDs []int64 `json:"ids"` // Empty replays every dead letter of the user
+}
+
+type ReplayDeadLettersResponse struct {
+	Replayed int64 `json:"replayed"`
+}
+
+type PaginationResponse struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Total  int `json:"total"`
+}
@@ -0,0 +1,21 @@
+package webhook
+
+// Payload formats an endpoint can receive
+const (
+	FormatLegacy = "legacy" // Event specific shapes kept for existing consumers
+	FormatV1     = "v1"     // Envelope with SchemaVersion 1
+)
+
+// SchemaVersion is the envelope version delivered to endpoints using FormatV1
+const SchemaVersion = 1
+
+// Envelope is the versioned body shared by every webhook event
+type Envelope struct {
+	Event         string `json:"event"`
+	EventID       string `json:"event_id"`
+	SchemaVersion int    `json:"schema_version"`
+	UserID        int    `json:"user_id"`
+	DeviceID      string `json:"device_id,omitempty"`
+	Timestamp     string `json:"timestamp"` // RFC3339
+	Payload       any    `json:"payload"`
+}
@@ -0,0 +1,47 @@
+package webhook
+
+import (
+	"context"
+	"time"
+)
+
+type IWebhookRepository interface {
+	Create(webhook *Webhook) error
+	GetByID(userID, id int) (*Webhook, error)
+	GetByUser(userID int) ([]Webhook, error)
+	GetActiveByUser(userID int) ([]Webhook, error)
+	Update(webhook *Webhook) error
+	Delete(userID, id int) error
+}
+
+// IWebhookUsecase manages the webhook endpoints of a user
+type IWebhookUsecase interface {
+	CreateWebhook(ctx context.Context, userID int, request CreateWebhookRequest) (WebhookResponse, error)
+	ListWebhooks(ctx context.Context, userID int) ([]WebhookResponse, error)
+	GetWebhook(ctx context.Context, userID, id int) (WebhookResponse, error)
+	UpdateWebhook(ctx context.Context, userID, id int, request UpdateWebhookRequest) (WebhookResponse, error)
+	DeleteWebhook(ctx context.Context, userID, id int) error
+	TestWebhook(ctx context.Context, userID, id int) (TestWebhookResponse, error)
+	ListDeliveries(ctx context.Context, userID int, request ListDeliveriesRequest) (ListDeliveriesResponse, error)
+	ListDeadLetters(ctx context.Context, userID int, request ListDeadLettersRequest) (ListDeadLettersResponse, error)
+	ReplayDeadLetter(ctx context.Context, userID int, id int64) error
+	ReplayDeadLetters(ctx context.Context, userID int, request ReplayDeadLettersRequest) (ReplayDeadLettersResponse, error)
+}
+
+// IWebhookOutboxRepository persists pending deliveries so they survive restarts and receiver outages
+type IWebhookOutboxRepository interface {
+	InitializeSchema() error
+	Enqueue(delivery *Delivery) error
+	GetDue(now time.Time, limit int) ([]Delivery, error)
+	RecordAttempt(attempt *DeliveryAttempt) error
+	MarkDelivered(id int64, attempts int, statusCode int) error
+	MarkFailed(id int64, attempts int, lastError string, statusCode int, nextAttemptAt time.Time) error
+	MoveToDeadLetter(id int64, attempts int, lastError string, statusCode int) error
+	ListDelivered(userID, limit, offset int) ([]Delivery, error)
+	CountDelivered(userID int) (int, error)
+	PruneDelivered(before time.Time) error
+	ListDeadLetters(userID, limit, offset int) ([]DeadLetter, error)
+	CountDeadLetters(userID int) (int, error)
+	ReplayDeadLetter(userID int, id int64) (bool, error)
+	ReplayDeadLetters(userID int, ids []int64) (int64, error)
+}
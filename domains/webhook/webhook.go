@@ -0,0 +1,147 @@
+package webhook
+
+import (
+	"strings"
+	"time"
+)
+
+// Event names a webhook endpoint can subscribe to
+const (
+	EventMessage                = "message"
+	EventMessageAck             = "message.ack"
+	EventMessageDeleted         = "message.deleted"
+	EventMessageRevoked         = "message.revoked"
+	EventMessageEdited          = "message.edited"
+	EventGroupParticipants      = "group.participants"
+	EventPresence               = "presence"
+	EventConnectionConnected    = "connection.connected"
+	EventConnectionDisconnected = "connection.disconnected"
+	EventConnectionLoggedOut    = "connection.logged_out"
+	EventPairSuccess            = "pair.success"
+	EventStreamReplaced         = "stream.replaced"
+	EventStreamReclaimStopped   = "stream.reclaim_stopped"
+	EventQRCode                 = "qr.code"
+	EventScheduleExecuted       = "schedule.executed"
+	EventWebhookTest            = "webhook.test"
+)
+
+// SupportedEvents lists every event that can be used in a subscription
+var SupportedEvents = []string{
+	EventMessage,
+	EventMessageAck,
+	EventMessageDeleted,
+	EventMessageRevoked,
+	EventMessageEdited,
+	EventGroupParticipants,
+	EventPresence,
+	EventConnectionConnected,
+	EventConnectionDisconnected,
+	EventConnectionLoggedOut,
+	EventPairSuccess,
+	EventStreamReplaced,
+	EventStreamReclaimStopped,
+	EventQRCode,
+	EventScheduleExecuted,
+}
+
+// Webhook is a delivery endpoint owned by a user
+type Webhook struct {
+	ID        int       `json:"id" db:"id"`
+	UserID    int       `json:"user_id" db:"user_id"`
+	URL       string    `json:"url" db:"url"`
+	Secret    string    `json:"-" db:"secret"`
+	Events    []string  `json:"events" db:"-"`
+	Chats     []string  `json:"chats" db:"-"`
+	Format    string    `json:"format" db:"format"`
+	IsActive  bool      `json:"is_active" db:"is_active"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Subscribes reports whether the endpoint should receive the given event.
+// An endpoint without explicit subscriptions receives every event.
+func (w Webhook) Subscribes(event string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+
+	for _, subscribed := range w.Events {
+		if subscribed == "*" || subscribed == event {
+			return true
+		}
+		// Allow prefix subscriptions such as "group.*"
+		if prefix, ok := strings.CutSuffix(subscribed, ".*"); ok && strings.HasPrefix(event, prefix+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AcceptsChat reports whether the endpoint should receive events from the given chat.
+// Filters match a full JID, the user part of a JID or a whole server such as "*@g.us".
+// Endpoints without chat filters, and events that do not belong to a chat, are always accepted.
+func (w Webhook) AcceptsChat(chatJID string) bool {
+	if len(w.Chats) == 0 || chatJID == "" {
+		return true
+	}
+
+	user, server, _ := strings.Cut(chatJID, "@")
+	// Strip the device part of a JID such as 628123456789:12@s.whatsapp.net
+	user, _, _ = strings.Cut(user, ":")
+
+	for _, chat := range w.Chats {
+		switch {
+		case chat == chatJID, chat == user, chat == user+"@"+server:
+			return true
+		case strings.HasPrefix(chat, "*@") && chat[2:] == server:
+			return true
+		}
+	}
+
+	return false
+}
+
+// Matches reports whether the endpoint should receive the event from the given chat
+func (w Webhook) Matches(event, chatJID string) bool {
+	return w.Subscribes(event) && w.AcceptsChat(chatJID)
+}
+
+type CreateWebhookRequest struct {
+	URL      string   `json:"url"`
+	Secret   string   `json:"secret"`
+	Events   []string `json:"events"`
+	Chats    []string `json:"chats"`
+	Format   string   `json:"format"` // Defaults to FormatLegacy
+	IsActive *bool    `json:"is_active"`
+}
+
+type UpdateWebhookRequest struct {
+	URL      string   `json:"url"`
+	Secret   *string  `json:"secret"`
+	Events   []string `json:"events"` // nil keeps the current subscriptions
+	Chats    []string `json:"chats"`  // nil keeps the current chat filters
+	Format   string   `json:"format"` // Empty keeps the current format
+	IsActive *bool    `json:"is_active"`
+}
+
+type WebhookResponse struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"` // Only returned when the secret is created
+	Events    []string  `json:"events"`
+	Chats     []string  `json:"chats"`
+	Format    string    `json:"format"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type TestWebhookResponse struct {
+	WebhookID  int    `json:"webhook_id"`
+	Delivered  bool   `json:"delivered"`
+	StatusCode int    `json:"status_code"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
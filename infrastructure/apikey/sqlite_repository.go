@@ -0,0 +1,173 @@
+package apikey
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type repository struct {
+	db *sqlx.DB
+}
+
+// keyRow mirrors the api_keys table
+type keyRow struct {
+	ID           int             `db:"id"`
+	UserID       int             `db:"user_id"`
+	Name         string          `db:"name"`
+	Prefix       string          `db:"prefix"`
+	KeyHash      string          `db:"key_hash"`
+	Scopes       permission.List `db:"scopes"`
+	AllowedChats permission.List `db:"allowed_chats"`
+	ExpiresAt    sql.NullTime    `db:"expires_at"`
+	LastUsedAt   sql.NullTime    `db:"last_used_at"`
+	RevokedAt    sql.NullTime    `db:"revoked_at"`
+	CreatedAt    time.Time       `db:"created_at"`
+}
+
+const keyColumns = `id, user_id, name, prefix, key_hash, scopes, allowed_chats, expires_at, last_used_at, revoked_at, created_at`
+
+func (row keyRow) toDomain() domainAPIKey.APIKey {
+	return domainAPIKey.APIKey{
+		ID:         row.ID,
+		UserID:     row.UserID,
+		Name:       row.Name,
+		Prefix:     row.Prefix,
+		KeyHash:    row.KeyHash,
+		Grant:      permission.Grant{Scopes: row.Scopes, AllowedChats: row.AllowedChats},
+		ExpiresAt:  nullTime(row.ExpiresAt),
+		LastUsedAt: nullTime(row.LastUsedAt),
+		RevokedAt:  nullTime(row.RevokedAt),
+		CreatedAt:  row.CreatedAt,
+	}
+}
+
+// NewAPIKeyRepository stores API keys in the user management database
+func NewAPIKeyRepository(dbPath string) (domainAPIKey.IAPIKeyRepository, error) {
+	db, err := sqlx.Connect("sqlite3", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to api key database: %w", err)
+	}
+
+	repo := &repository{db: db}
+	if err := repo.migrate(); err != nil {
+		return nil, fmt.Errorf("failed to migrate api key database: %w", err)
+	}
+
+	return repo, nil
+}
+
+func (r *repository) migrate() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS api_keys (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		name TEXT NOT NULL DEFAULT '',
+		prefix TEXT NOT NULL,
+		key_hash TEXT NOT NULL UNIQUE,
+		scopes TEXT NOT NULL DEFAULT '',
+		allowed_chats TEXT NOT NULL DEFAULT '',
+		expires_at DATETIME NULL,
+		last_used_at DATETIME NULL,
+		revoked_at DATETIME NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys(user_id);
+	`
+
+	_, err := r.db.Exec(query)
+	return err
+}
+
+func (r *repository) Create(key *domainAPIKey.APIKey) error {
+	query := `
+		INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, allowed_chats, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	now := time.Now()
+	result, err := r.db.Exec(query, key.UserID, key.Name, key.Prefix, key.KeyHash, key.Scopes, key.AllowedChats, key.ExpiresAt, now)
+	if err != nil {
+		return fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	key.ID = int(id)
+	key.CreatedAt = now
+	return nil
+}
+
+func (r *repository) GetByID(userID, id int) (*domainAPIKey.APIKey, error) {
+	query := "SELECT " + keyColumns + " FROM api_keys WHERE user_id = ? AND id = ?"
+	return r.getKey(query, userID, id)
+}
+
+func (r *repository) GetByHash(hash string) (*domainAPIKey.APIKey, error) {
+	query := "SELECT " + keyColumns + " FROM api_keys WHERE key_hash = ?"
+	return r.getKey(query, hash)
+}
+
+func (r *repository) GetByUser(userID int) ([]domainAPIKey.APIKey, error) {
+	var rows []keyRow
+	query := "SELECT " + keyColumns + " FROM api_keys WHERE user_id = ? ORDER BY id"
+	if err := r.db.Select(&rows, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to get api keys: %w", err)
+	}
+
+	keys := make([]domainAPIKey.APIKey, 0, len(rows))
+	for _, row := range rows {
+		keys = append(keys, row.toDomain())
+	}
+	return keys, nil
+}
+
+func (r *repository) Revoke(userID, id int, revokedAt time.Time) error {
+	query := "UPDATE api_keys SET revoked_at = ? WHERE user_id = ? AND id = ? AND revoked_at IS NULL"
+	_, err := r.db.Exec(query, revokedAt, userID, id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repository) UpdateLastUsed(id int, usedAt time.Time) error {
+	query := "UPDATE api_keys SET last_used_at = ? WHERE id = ?"
+	_, err := r.db.Exec(query, usedAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to update api key last used: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repository) getKey(query string, args ...any) (*domainAPIKey.APIKey, error) {
+	var row keyRow
+	err := r.db.Get(&row, query, args...)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	key := row.toDomain()
+	return &key, nil
+}
+
+func nullTime(value sql.NullTime) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Time
+}
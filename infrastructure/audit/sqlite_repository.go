@@ -0,0 +1,199 @@
+package audit
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	domainAudit "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/audit"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type repository struct {
+	db *sqlx.DB
+}
+
+// entryRow mirrors the audit_log table
+type entryRow struct {
+	ID        int64                `db:"id"`
+	Actor     string               `db:"actor"`
+	UserID    int                  `db:"user_id"`
+	Action    string               `db:"action"`
+	TargetJID string               `db:"target_jid"`
+	Reference string               `db:"reference"`
+	Result    string               `db:"result"`
+	Error     string               `db:"error"`
+	Metadata  domainAudit.Metadata `db:"metadata"`
+	CreatedAt time.Time            `db:"created_at"`
+}
+
+const entryColumns = `id, actor, user_id, action, target_jid, reference, result, error, metadata, created_at`
+
+func (row entryRow) toDomain() domainAudit.Entry {
+	return domainAudit.Entry{
+		ID:        row.ID,
+		Actor:     row.Actor,
+		UserID:    row.UserID,
+		Action:    row.Action,
+		TargetJID: row.TargetJID,
+		Reference: row.Reference,
+		Result:    row.Result,
+		Error:     row.Error,
+		Metadata:  row.Metadata,
+		CreatedAt: row.CreatedAt,
+	}
+}
+
+// NewAuditRepository stores the audit log in the user management database
+func NewAuditRepository(dbPath string) (domainAudit.IAuditRepository, error) {
+	db, err := sqlx.Connect("sqlite3", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to audit database: %w", err)
+	}
+
+	repo := &repository{db: db}
+	if err := repo.migrate(); err != nil {
+		return nil, fmt.Errorf("failed to migrate audit database: %w", err)
+	}
+
+	return repo, nil
+}
+
+func (r *repository) migrate() error {
+	// The triggers keep the log append-only for anything else sharing the database
+	query := `
+	CREATE TABLE IF NOT EXISTS audit_log (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		actor TEXT NOT NULL,
+		user_id INTEGER NOT NULL DEFAULT 0,
+		action TEXT NOT NULL,
+		target_jid TEXT NOT NULL DEFAULT '',
+		reference TEXT NOT NULL DEFAULT '',
+		result TEXT NOT NULL,
+		error TEXT NOT NULL DEFAULT '',
+		metadata TEXT NOT NULL DEFAULT '{}',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_audit_log_user_id ON audit_log(user_id, id);
+	CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at);
+
+	CREATE TRIGGER IF NOT EXISTS audit_log_no_update BEFORE UPDATE ON audit_log
+	BEGIN
+		SELECT RAISE(ABORT, 'audit log is append-only');
+	END;
+
+	CREATE TRIGGER IF NOT EXISTS audit_log_no_delete BEFORE DELETE ON audit_log
+	BEGIN
+		SELECT RAISE(ABORT, 'audit log is append-only');
+	END;
+	`
+
+	_, err := r.db.Exec(query)
+	return err
+}
+
+func (r *repository) Append(entry *domainAudit.Entry) error {
+	query := `
+		INSERT INTO audit_log (actor, user_id, action, target_jid, reference, result, error, metadata, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	// Stored in UTC so the text timestamps sort and compare chronologically
+	now := time.Now().UTC()
+	result, err := r.db.Exec(query, entry.Actor, entry.UserID, entry.Action, entry.TargetJID, entry.Reference,
+		entry.Result, entry.Error, entry.Metadata, now)
+	if err != nil {
+		return fmt.Errorf("failed to append audit entry: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	entry.ID = id
+	entry.CreatedAt = now
+	return nil
+}
+
+// List returns the matching entries, most recent first
+func (r *repository) List(filter domainAudit.Filter) ([]domainAudit.Entry, error) {
+	where, args := buildWhere(filter)
+	query := "SELECT " + entryColumns + " FROM audit_log" + where + " ORDER BY id DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, filter.Limit, filter.Offset)
+	}
+
+	var rows []entryRow
+	if err := r.db.Select(&rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+
+	entries := make([]domainAudit.Entry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, row.toDomain())
+	}
+	return entries, nil
+}
+
+func (r *repository) Count(filter domainAudit.Filter) (int, error) {
+	where, args := buildWhere(filter)
+
+	var count int
+	if err := r.db.Get(&count, "SELECT COUNT(*) FROM audit_log"+where, args...); err != nil {
+		return 0, fmt.Errorf("failed to count audit entries: %w", err)
+	}
+	return count, nil
+}
+
+func buildWhere(filter domainAudit.Filter) (string, []any) {
+	var conditions []string
+	var args []any
+
+	if filter.UserID > 0 {
+		conditions = append(conditions, "user_id = ?")
+		args = append(args, filter.UserID)
+	}
+	if filter.Actor != "" {
+		conditions = append(conditions, "actor = ?")
+		args = append(args, filter.Actor)
+	}
+	if filter.Action != "" {
+		// An area like "send" matches all of its actions
+		if strings.Contains(filter.Action, ".") {
+			conditions = append(conditions, "action = ?")
+			args = append(args, filter.Action)
+		} else {
+			conditions = append(conditions, "action LIKE ?")
+			args = append(args, filter.Action+".%")
+		}
+	}
+	if filter.TargetJID != "" {
+		conditions = append(conditions, "target_jid = ?")
+		args = append(args, filter.TargetJID)
+	}
+	if filter.Result != "" {
+		conditions = append(conditions, "result = ?")
+		args = append(args, filter.Result)
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.From.UTC())
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, filter.To.UTC())
+	}
+	if filter.BeforeID > 0 {
+		conditions = append(conditions, "id < ?")
+		args = append(args, filter.BeforeID)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
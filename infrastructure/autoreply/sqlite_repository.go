@@ -0,0 +1,241 @@
+package autoreply
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type repository struct {
+	db *sqlx.DB
+}
+
+// ruleRow mirrors the auto_reply_rules table; lists and business hours are stored as JSON
+// because keywords and patterns may contain commas
+type ruleRow struct {
+	ID              int       `db:"id"`
+	UserID          int       `db:"user_id"`
+	Name            string    `db:"name"`
+	Priority        int       `db:"priority"`
+	MatchType       string    `db:"match_type"`
+	Keywords        string    `db:"keywords"`
+	Pattern         string    `db:"pattern"`
+	ChatType        string    `db:"chat_type"`
+	AllowSenders    string    `db:"allow_senders"`
+	DenySenders     string    `db:"deny_senders"`
+	BusinessHours   string    `db:"business_hours"`
+	CooldownSeconds int       `db:"cooldown_seconds"`
+	ReplyType       string    `db:"reply_type"`
+	ReplyText       string    `db:"reply_text"`
+	MediaURL        string    `db:"media_url"`
+	MediaPath       string    `db:"media_path"`
+	MediaName       string    `db:"media_name"`
+	IsActive        bool      `db:"is_active"`
+	CreatedAt       time.Time `db:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at"`
+}
+
+const ruleColumns = `id, user_id, name, priority, match_type, keywords, pattern, chat_type, allow_senders, deny_senders,
+	business_hours, cooldown_seconds, reply_type, reply_text, media_url, media_path, media_name, is_active, created_at, updated_at`
+
+func (row ruleRow) toDomain() domainAutoReply.Rule {
+	rule := domainAutoReply.Rule{
+		ID:              row.ID,
+		UserID:          row.UserID,
+		Name:            row.Name,
+		Priority:        row.Priority,
+		MatchType:       row.MatchType,
+		Keywords:        decodeList(row.Keywords),
+		Pattern:         row.Pattern,
+		ChatType:        row.ChatType,
+		AllowSenders:    decodeList(row.AllowSenders),
+		DenySenders:     decodeList(row.DenySenders),
+		CooldownSeconds: row.CooldownSeconds,
+		ReplyType:       row.ReplyType,
+		ReplyText:       row.ReplyText,
+		MediaURL:        row.MediaURL,
+		MediaPath:       row.MediaPath,
+		MediaName:       row.MediaName,
+		IsActive:        row.IsActive,
+		CreatedAt:       row.CreatedAt,
+		UpdatedAt:       row.UpdatedAt,
+	}
+	if row.BusinessHours != "" {
+		var hours domainAutoReply.BusinessHours
+		if err := json.Unmarshal([]byte(row.BusinessHours), &hours); err == nil {
+			rule.BusinessHours = &hours
+		}
+	}
+	return rule
+}
+
+// NewAutoReplyRepository stores auto-reply rules in the user management database
+func NewAutoReplyRepository(dbPath string) (domainAutoReply.IAutoReplyRepository, error) {
+	db, err := sqlx.Connect("sqlite3", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to auto-reply database: %w", err)
+	}
+
+	repo := &repository{db: db}
+	if err := repo.migrate(); err != nil {
+		return nil, fmt.Errorf("failed to migrate auto-reply database: %w", err)
+	}
+
+	return repo, nil
+}
+
+func (r *repository) migrate() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS auto_reply_rules (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		name TEXT NOT NULL DEFAULT '',
+		priority INTEGER NOT NULL DEFAULT 0,
+		match_type TEXT NOT NULL DEFAULT 'any',
+		keywords TEXT NOT NULL DEFAULT '',
+		pattern TEXT NOT NULL DEFAULT '',
+		chat_type TEXT NOT NULL DEFAULT 'all',
+		allow_senders TEXT NOT NULL DEFAULT '',
+		deny_senders TEXT NOT NULL DEFAULT '',
+		business_hours TEXT NOT NULL DEFAULT '',
+		cooldown_seconds INTEGER NOT NULL DEFAULT 0,
+		reply_type TEXT NOT NULL DEFAULT 'text',
+		reply_text TEXT NOT NULL DEFAULT '',
+		media_url TEXT NOT NULL DEFAULT '',
+		media_path TEXT NOT NULL DEFAULT '',
+		media_name TEXT NOT NULL DEFAULT '',
+		is_active BOOLEAN DEFAULT TRUE,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_auto_reply_rules_user_id ON auto_reply_rules(user_id, priority);
+	`
+
+	_, err := r.db.Exec(query)
+	return err
+}
+
+func (r *repository) Create(rule *domainAutoReply.Rule) error {
+	query := `
+		INSERT INTO auto_reply_rules (user_id, name, priority, match_type, keywords, pattern, chat_type, allow_senders, deny_senders,
+			business_hours, cooldown_seconds, reply_type, reply_text, media_url, media_path, media_name, is_active, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	now := time.Now()
+	result, err := r.db.Exec(query, rule.UserID, rule.Name, rule.Priority, rule.MatchType, encodeList(rule.Keywords), rule.Pattern,
+		rule.ChatType, encodeList(rule.AllowSenders), encodeList(rule.DenySenders), encodeHours(rule.BusinessHours),
+		rule.CooldownSeconds, rule.ReplyType, rule.ReplyText, rule.MediaURL, rule.MediaPath, rule.MediaName, rule.IsActive, now, now)
+	if err != nil {
+		return fmt.Errorf("failed to create auto-reply rule: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	rule.ID = int(id)
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+	return nil
+}
+
+func (r *repository) GetByID(userID, id int) (*domainAutoReply.Rule, error) {
+	var row ruleRow
+	query := "SELECT " + ruleColumns + " FROM auto_reply_rules WHERE user_id = ? AND id = ?"
+
+	err := r.db.Get(&row, query, userID, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get auto-reply rule by id: %w", err)
+	}
+
+	rule := row.toDomain()
+	return &rule, nil
+}
+
+func (r *repository) GetByUser(userID int) ([]domainAutoReply.Rule, error) {
+	query := "SELECT " + ruleColumns + " FROM auto_reply_rules WHERE user_id = ? ORDER BY priority, id"
+	return r.selectRules(query, userID)
+}
+
+func (r *repository) GetActiveByUser(userID int) ([]domainAutoReply.Rule, error) {
+	query := "SELECT " + ruleColumns + " FROM auto_reply_rules WHERE user_id = ? AND is_active = TRUE ORDER BY priority, id"
+	return r.selectRules(query, userID)
+}
+
+func (r *repository) Update(rule *domainAutoReply.Rule) error {
+	query := `
+		UPDATE auto_reply_rules SET name = ?, priority = ?, match_type = ?, keywords = ?, pattern = ?, chat_type = ?,
+			allow_senders = ?, deny_senders = ?, business_hours = ?, cooldown_seconds = ?, reply_type = ?, reply_text = ?,
+			media_url = ?, media_path = ?, media_name = ?, is_active = ?, updated_at = ?
+		WHERE user_id = ? AND id = ?
+	`
+
+	now := time.Now()
+	_, err := r.db.Exec(query, rule.Name, rule.Priority, rule.MatchType, encodeList(rule.Keywords), rule.Pattern, rule.ChatType,
+		encodeList(rule.AllowSenders), encodeList(rule.DenySenders), encodeHours(rule.BusinessHours), rule.CooldownSeconds,
+		rule.ReplyType, rule.ReplyText, rule.MediaURL, rule.MediaPath, rule.MediaName, rule.IsActive, now, rule.UserID, rule.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update auto-reply rule: %w", err)
+	}
+
+	rule.UpdatedAt = now
+	return nil
+}
+
+func (r *repository) Delete(userID, id int) error {
+	query := "DELETE FROM auto_reply_rules WHERE user_id = ? AND id = ?"
+	_, err := r.db.Exec(query, userID, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete auto-reply rule: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repository) selectRules(query string, args ...any) ([]domainAutoReply.Rule, error) {
+	var rows []ruleRow
+	if err := r.db.Select(&rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get auto-reply rules: %w", err)
+	}
+
+	rules := make([]domainAutoReply.Rule, 0, len(rows))
+	for _, row := range rows {
+		rules = append(rules, row.toDomain())
+	}
+	return rules, nil
+}
+
+func encodeList(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	encoded, _ := json.Marshal(values)
+	return string(encoded)
+}
+
+func decodeList(value string) []string {
+	values := []string{}
+	if value != "" {
+		_ = json.Unmarshal([]byte(value), &values)
+	}
+	return values
+}
+
+func encodeHours(hours *domainAutoReply.BusinessHours) string {
+	if hours == nil {
+		return ""
+	}
+	encoded, _ := json.Marshal(hours)
+	return string(encoded)
+}
@@ -0,0 +1,117 @@
+package bulk
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	domainBulk "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/bulk"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
+)
+
+const jobColumns = "id, user_id, message, status, created_at, updated_at"
+
+// SQLiteRepository keeps bulk jobs in the chat storage database, next to the send queue holding their messages
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+// NewBulkRepository creates a bulk job store backed by an already opened SQLite database
+func NewBulkRepository(db *sql.DB) domainBulk.IBulkRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// InitializeSchema creates the bulk jobs table
+func (r *SQLiteRepository) InitializeSchema() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS bulk_jobs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		message TEXT NOT NULL,
+		status TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_bulk_jobs_user ON bulk_jobs(user_id, created_at DESC);
+	`
+
+	_, err := r.db.Exec(query)
+	return err
+}
+
+func (r *SQLiteRepository) Create(job *domainBulk.Job) error {
+	now := time.Now().UTC()
+	job.CreatedAt = now
+	job.UpdatedAt = now
+
+	result, err := r.db.Exec("INSERT INTO bulk_jobs (user_id, message, status, created_at, updated_at) VALUES (?, ?, ?, ?, ?)",
+		job.UserID, job.Message, job.Status, now, now)
+	if err != nil {
+		return fmt.Errorf("failed to create bulk job: %w", err)
+	}
+
+	job.ID, err = result.LastInsertId()
+	return err
+}
+
+func (r *SQLiteRepository) GetByID(userID int, id int64) (*domainBulk.Job, error) {
+	var job domainBulk.Job
+	err := r.db.QueryRow("SELECT "+jobColumns+" FROM bulk_jobs WHERE user_id = ? AND id = ?", userID, id).
+		Scan(&job.ID, &job.UserID, &job.Message, &job.Status, &job.CreatedAt, &job.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bulk job: %w", err)
+	}
+	return &job, nil
+}
+
+// List returns the jobs of a user, most recent first
+func (r *SQLiteRepository) List(userID int, chatKeys []string, limit, offset int) ([]domainBulk.Job, error) {
+	where, args := listWhere(userID, chatKeys)
+	rows, err := r.db.Query("SELECT "+jobColumns+" FROM bulk_jobs"+where+" ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?",
+		append(args, limit, offset)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bulk jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := []domainBulk.Job{}
+	for rows.Next() {
+		var job domainBulk.Job
+		if err := rows.Scan(&job.ID, &job.UserID, &job.Message, &job.Status, &job.CreatedAt, &job.UpdatedAt); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
+
+func (r *SQLiteRepository) Count(userID int, chatKeys []string) (int, error) {
+	where, args := listWhere(userID, chatKeys)
+
+	var count int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM bulk_jobs"+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count bulk jobs: %w", err)
+	}
+	return count, nil
+}
+
+func (r *SQLiteRepository) UpdateStatus(id int64, status string) error {
+	_, err := r.db.Exec("UPDATE bulk_jobs SET status = ?, updated_at = ? WHERE id = ?", status, time.Now().UTC(), id)
+	return err
+}
+
+// listWhere selects the jobs of a user, only those with a recipient in the given chats when chatKeys is not empty
+func listWhere(userID int, chatKeys []string) (string, []any) {
+	if len(chatKeys) == 0 {
+		return " WHERE user_id = ?", []any{userID}
+	}
+
+	condition, args := permission.ChatKeyCondition("phone", chatKeys)
+	return " WHERE user_id = ? AND EXISTS (SELECT 1 FROM send_queue WHERE job_id = bulk_jobs.id AND " + condition + ")",
+		append([]any{userID}, args...)
+}
@@ -10,13 +10,16 @@ import (
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/proto/waE2E"
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
 )
 
-// SQLiteRepository implements Repository using SQLite
+// SQLiteRepository implements Repository using SQLite.
+// Every query is scoped to userID so tenants sharing the same database never see each other's data.
 type SQLiteRepository struct {
-	db *sql.DB
+	db     *sql.DB
+	userID int
 }
 
 // NewSQLiteRepository creates a new SQLite repository
@@ -24,22 +27,32 @@ func NewStorageRepository(db *sql.DB) domainChatStorage.IChatStorageRepository {
 	return &SQLiteRepository{db: db}
 }
 
+// ForUser returns a repository sharing the same database but scoped to the given user
+func (r *SQLiteRepository) ForUser(userID int) domainChatStorage.IChatStorageRepository {
+	return &SQLiteRepository{db: r.db, userID: userID}
+}
+
+// UserID returns the owner this repository is scoped to
+func (r *SQLiteRepository) UserID() int {
+	return r.userID
+}
+
 // StoreChat creates or updates a chat
 func (r *SQLiteRepository) StoreChat(chat *domainChatStorage.Chat) error {
 	now := time.Now()
 	chat.UpdatedAt = now
 
 	query := `
-		INSERT INTO chats (jid, name, last_message_time, ephemeral_expiration, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?)
-		ON CONFLICT(jid) DO UPDATE SET
+		INSERT INTO chats (user_id, jid, name, last_message_time, ephemeral_expiration, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id, jid) DO UPDATE SET
 			name = excluded.name,
 			last_message_time = excluded.last_message_time,
 			ephemeral_expiration = excluded.ephemeral_expiration,
 			updated_at = excluded.updated_at
 	`
 
-	_, err := r.db.Exec(query, chat.JID, chat.Name, chat.LastMessageTime, chat.EphemeralExpiration, now, chat.UpdatedAt)
+	_, err := r.db.Exec(query, r.userID, chat.JID, chat.Name, chat.LastMessageTime, chat.EphemeralExpiration, now, chat.UpdatedAt)
 	return err
 }
 
@@ -48,10 +61,10 @@ func (r *SQLiteRepository) GetChat(jid string) (*domainChatStorage.Chat, error)
 	query := `
 		SELECT jid, name, last_message_time, ephemeral_expiration, created_at, updated_at
 		FROM chats
-		WHERE jid = ?
+		WHERE user_id = ? AND jid = ?
 	`
 
-	chat, err := r.scanChat(r.db.QueryRow(query, jid))
+	chat, err := r.scanChat(r.db.QueryRow(query, r.userID, jid))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -64,14 +77,14 @@ func (r *SQLiteRepository) GetChat(jid string) (*domainChatStorage.Chat, error)
 func (r *SQLiteRepository) GetMessageByID(id string) (*domainChatStorage.Message, error) {
 	query := `
 		SELECT id, chat_jid, sender, content, timestamp, is_from_me,
-			media_type, filename, url, media_key, file_sha256,
-			file_enc_sha256, file_length, created_at, updated_at
+			media_type, filename, mimetype, url, media_key, file_sha256,
+			file_enc_sha256, file_length, thumbnail, created_at, updated_at
 		FROM messages
-		WHERE id = ?
+		WHERE user_id = ? AND id = ?
 		LIMIT 1
 	`
 
-	message, err := r.scanMessage(r.db.QueryRow(query, id))
+	message, err := r.scanMessage(r.db.QueryRow(query, r.userID, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -81,28 +94,13 @@ func (r *SQLiteRepository) GetMessageByID(id string) (*domainChatStorage.Message
 
 // GetChats retrieves chats with filtering
 func (r *SQLiteRepository) GetChats(filter *domainChatStorage.ChatFilter) ([]*domainChatStorage.Chat, error) {
-	var conditions []string
-	var args []any
-
 	query := `
 		SELECT c.jid, c.name, c.last_message_time, c.ephemeral_expiration, c.created_at, c.updated_at
 		FROM chats c
 	`
 
-	if filter.SearchName != "" {
-		conditions = append(conditions, "c.name LIKE ?")
-		args = append(args, "%"+filter.SearchName+"%")
-	}
-
-	if filter.HasMedia {
-		query += " INNER JOIN messages m ON c.jid = m.chat_jid"
-		conditions = append(conditions, "m.media_type != ''")
-	}
-
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
-	}
-
+	from, args := r.chatFilterClauses(filter)
+	query += from
 	query += " ORDER BY c.last_message_time DESC"
 
 	// Safely add LIMIT and OFFSET using parameterized values
@@ -147,13 +145,13 @@ func (r *SQLiteRepository) DeleteChat(jid string) error {
 	defer tx.Rollback()
 
 	// Delete messages first (foreign key constraint)
-	_, err = tx.Exec("DELETE FROM messages WHERE chat_jid = ?", jid)
+	_, err = tx.Exec("DELETE FROM messages WHERE user_id = ? AND chat_jid = ?", r.userID, jid)
 	if err != nil {
 		return err
 	}
 
 	// Delete chat
-	_, err = tx.Exec("DELETE FROM chats WHERE jid = ?", jid)
+	_, err = tx.Exec("DELETE FROM chats WHERE user_id = ? AND jid = ?", r.userID, jid)
 	if err != nil {
 		return err
 	}
@@ -175,30 +173,32 @@ func (r *SQLiteRepository) StoreMessage(message *domainChatStorage.Message) erro
 
 	query := `
 		INSERT INTO messages (
-			id, chat_jid, sender, content, timestamp, is_from_me, 
-			media_type, filename, url, media_key, file_sha256, 
-			file_enc_sha256, file_length, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-		ON CONFLICT(id, chat_jid) DO UPDATE SET
+			user_id, id, chat_jid, sender, content, timestamp, is_from_me, 
+			media_type, filename, mimetype, url, media_key, file_sha256, 
+			file_enc_sha256, file_length, thumbnail, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id, id, chat_jid) DO UPDATE SET
 			sender = excluded.sender,
 			content = excluded.content,
 			timestamp = excluded.timestamp,
 			is_from_me = excluded.is_from_me,
 			media_type = excluded.media_type,
 			filename = excluded.filename,
+			mimetype = excluded.mimetype,
 			url = excluded.url,
 			media_key = excluded.media_key,
 			file_sha256 = excluded.file_sha256,
 			file_enc_sha256 = excluded.file_enc_sha256,
 			file_length = excluded.file_length,
+			thumbnail = excluded.thumbnail,
 			updated_at = excluded.updated_at
 	`
 
 	_, err := r.db.Exec(query,
-		message.ID, message.ChatJID, message.Sender, message.Content,
+		r.userID, message.ID, message.ChatJID, message.Sender, message.Content,
 		message.Timestamp, message.IsFromMe, message.MediaType, message.Filename,
-		message.URL, message.MediaKey, message.FileSHA256, message.FileEncSHA256,
-		message.FileLength, message.CreatedAt, message.UpdatedAt,
+		message.Mimetype, message.URL, message.MediaKey, message.FileSHA256, message.FileEncSHA256,
+		message.FileLength, message.Thumbnail, message.CreatedAt, message.UpdatedAt,
 	)
 
 	return err
@@ -219,22 +219,24 @@ func (r *SQLiteRepository) StoreMessagesBatch(messages []*domainChatStorage.Mess
 	// Prepare the statement once for better performance
 	stmt, err := tx.Prepare(`
 		INSERT INTO messages (
-			id, chat_jid, sender, content, timestamp, is_from_me, 
-			media_type, filename, url, media_key, file_sha256, 
-			file_enc_sha256, file_length, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-		ON CONFLICT(id, chat_jid) DO UPDATE SET
+			user_id, id, chat_jid, sender, content, timestamp, is_from_me, 
+			media_type, filename, mimetype, url, media_key, file_sha256, 
+			file_enc_sha256, file_length, thumbnail, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id, id, chat_jid) DO UPDATE SET
 			sender = excluded.sender,
 			content = excluded.content,
 			timestamp = excluded.timestamp,
 			is_from_me = excluded.is_from_me,
 			media_type = excluded.media_type,
 			filename = excluded.filename,
+			mimetype = excluded.mimetype,
 			url = excluded.url,
 			media_key = excluded.media_key,
 			file_sha256 = excluded.file_sha256,
 			file_enc_sha256 = excluded.file_enc_sha256,
 			file_length = excluded.file_length,
+			thumbnail = excluded.thumbnail,
 			updated_at = excluded.updated_at
 	`)
 	if err != nil {
@@ -253,10 +255,10 @@ func (r *SQLiteRepository) StoreMessagesBatch(messages []*domainChatStorage.Mess
 		message.UpdatedAt = now
 
 		_, err = stmt.Exec(
-			message.ID, message.ChatJID, message.Sender, message.Content,
+			r.userID, message.ID, message.ChatJID, message.Sender, message.Content,
 			message.Timestamp, message.IsFromMe, message.MediaType, message.Filename,
-			message.URL, message.MediaKey, message.FileSHA256, message.FileEncSHA256,
-			message.FileLength, message.CreatedAt, message.UpdatedAt,
+			message.Mimetype, message.URL, message.MediaKey, message.FileSHA256, message.FileEncSHA256,
+			message.FileLength, message.Thumbnail, message.CreatedAt, message.UpdatedAt,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to store message %s: %w", message.ID, err)
@@ -268,11 +270,8 @@ func (r *SQLiteRepository) StoreMessagesBatch(messages []*domainChatStorage.Mess
 
 // GetMessages retrieves messages with filtering
 func (r *SQLiteRepository) GetMessages(filter *domainChatStorage.MessageFilter) ([]*domainChatStorage.Message, error) {
-	var conditions []string
-	var args []any
-
-	conditions = append(conditions, "chat_jid = ?")
-	args = append(args, filter.ChatJID)
+	conditions := []string{"user_id = ?", "chat_jid = ?"}
+	args := []any{r.userID, filter.ChatJID}
 
 	if filter.StartTime != nil {
 		conditions = append(conditions, "timestamp >= ?")
@@ -295,8 +294,8 @@ func (r *SQLiteRepository) GetMessages(filter *domainChatStorage.MessageFilter)
 
 	query := `
 		SELECT id, chat_jid, sender, content, timestamp, is_from_me,
-			media_type, filename, url, media_key, file_sha256,
-			file_enc_sha256, file_length, created_at, updated_at
+			media_type, filename, mimetype, url, media_key, file_sha256,
+			file_enc_sha256, file_length, thumbnail, created_at, updated_at
 		FROM messages
 		WHERE ` + strings.Join(conditions, " AND ") + `
 		ORDER BY timestamp DESC
@@ -342,12 +341,9 @@ func (r *SQLiteRepository) SearchMessages(chatJID, searchText string, limit int)
 		return []*domainChatStorage.Message{}, nil
 	}
 
-	var conditions []string
-	var args []any
-
-	// Always filter by chat JID
-	conditions = append(conditions, "chat_jid = ?")
-	args = append(args, chatJID)
+	// Always filter by owner and chat JID
+	conditions := []string{"user_id = ?", "chat_jid = ?"}
+	args := []any{r.userID, chatJID}
 
 	// Add search condition using LIKE operator for case-insensitive search
 	conditions = append(conditions, "LOWER(content) LIKE ?")
@@ -355,8 +351,8 @@ func (r *SQLiteRepository) SearchMessages(chatJID, searchText string, limit int)
 
 	query := `
 		SELECT id, chat_jid, sender, content, timestamp, is_from_me,
-			media_type, filename, url, media_key, file_sha256,
-			file_enc_sha256, file_length, created_at, updated_at
+			media_type, filename, mimetype, url, media_key, file_sha256,
+			file_enc_sha256, file_length, thumbnail, created_at, updated_at
 		FROM messages
 		WHERE ` + strings.Join(conditions, " AND ") + `
 		ORDER BY timestamp DESC
@@ -396,7 +392,7 @@ func (r *SQLiteRepository) SearchMessages(chatJID, searchText string, limit int)
 
 // DeleteMessage deletes a specific message
 func (r *SQLiteRepository) DeleteMessage(id, chatJID string) error {
-	_, err := r.db.Exec("DELETE FROM messages WHERE id = ? AND chat_jid = ?", id, chatJID)
+	_, err := r.db.Exec("DELETE FROM messages WHERE user_id = ? AND id = ? AND chat_jid = ?", r.userID, id, chatJID)
 	return err
 }
 
@@ -413,8 +409,8 @@ func (r *SQLiteRepository) scanMessage(scanner interface{ Scan(...any) error })
 	err := scanner.Scan(
 		&message.ID, &message.ChatJID, &message.Sender, &message.Content,
 		&message.Timestamp, &message.IsFromMe, &message.MediaType, &message.Filename,
-		&message.URL, &message.MediaKey, &message.FileSHA256, &message.FileEncSHA256,
-		&message.FileLength, &message.CreatedAt, &message.UpdatedAt,
+		&message.Mimetype, &message.URL, &message.MediaKey, &message.FileSHA256, &message.FileEncSHA256,
+		&message.FileLength, &message.Thumbnail, &message.CreatedAt, &message.UpdatedAt,
 	)
 	return message, err
 }
@@ -431,20 +427,55 @@ func (r *SQLiteRepository) scanChat(scanner interface{ Scan(...any) error }) (*d
 
 // GetChatMessageCount returns the number of messages in a chat
 func (r *SQLiteRepository) GetChatMessageCount(chatJID string) (int64, error) {
-	return r.getCount("SELECT COUNT(*) FROM messages WHERE chat_jid = ?", chatJID)
+	return r.getCount("SELECT COUNT(*) FROM messages WHERE user_id = ? AND chat_jid = ?", r.userID, chatJID)
 }
 
 // GetTotalMessageCount returns the total number of messages
 func (r *SQLiteRepository) GetTotalMessageCount() (int64, error) {
-	return r.getCount("SELECT COUNT(*) FROM messages")
+	return r.getCount("SELECT COUNT(*) FROM messages WHERE user_id = ?", r.userID)
 }
 
 // GetTotalChatCount returns the total number of chats
 func (r *SQLiteRepository) GetTotalChatCount() (int64, error) {
-	return r.getCount("SELECT COUNT(*) FROM chats")
+	return r.getCount("SELECT COUNT(*) FROM chats WHERE user_id = ?", r.userID)
+}
+
+// CountChats returns the number of chats matching the filter, ignoring its limit and offset
+func (r *SQLiteRepository) CountChats(filter *domainChatStorage.ChatFilter) (int64, error) {
+	from, args := r.chatFilterClauses(filter)
+	return r.getCount("SELECT COUNT(DISTINCT c.jid) FROM chats c"+from, args...)
 }
 
-// TruncateAllChats deletes all chats from the database
+// chatFilterClauses returns the joins and conditions that select the chats of a filter
+func (r *SQLiteRepository) chatFilterClauses(filter *domainChatStorage.ChatFilter) (string, []any) {
+	conditions := []string{"c.user_id = ?"}
+	args := []any{r.userID}
+
+	if filter.SearchName != "" {
+		conditions = append(conditions, "c.name LIKE ?")
+		args = append(args, "%"+filter.SearchName+"%")
+	}
+
+	if len(filter.ChatKeys) > 0 {
+		placeholders := make([]string, len(filter.ChatKeys))
+		for i, key := range filter.ChatKeys {
+			placeholders[i] = "?"
+			args = append(args, key)
+		}
+		// Chat JIDs are stored without a device, so the part before the server is the key
+		conditions = append(conditions, fmt.Sprintf("substr(c.jid, 1, instr(c.jid, '@') - 1) IN (%s)", strings.Join(placeholders, ", ")))
+	}
+
+	var joins string
+	if filter.HasMedia {
+		joins = " INNER JOIN messages m ON c.user_id = m.user_id AND c.jid = m.chat_jid"
+		conditions = append(conditions, "m.media_type != ''")
+	}
+
+	return joins + " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// TruncateAllChats deletes all chats owned by the repository's user
 // Note: Due to foreign key constraints, messages must be deleted first
 func (r *SQLiteRepository) TruncateAllChats() error {
 	tx, err := r.db.Begin()
@@ -454,13 +485,13 @@ func (r *SQLiteRepository) TruncateAllChats() error {
 	defer tx.Rollback()
 
 	// Delete messages first (foreign key constraint)
-	_, err = tx.Exec("DELETE FROM messages")
+	_, err = tx.Exec("DELETE FROM messages WHERE user_id = ?", r.userID)
 	if err != nil {
 		return fmt.Errorf("failed to delete messages: %w", err)
 	}
 
 	// Delete chats
-	_, err = tx.Exec("DELETE FROM chats")
+	_, err = tx.Exec("DELETE FROM chats WHERE user_id = ?", r.userID)
 	if err != nil {
 		return fmt.Errorf("failed to delete chats: %w", err)
 	}
@@ -568,11 +599,13 @@ func (r *SQLiteRepository) CreateMessage(ctx context.Context, evt *events.Messag
 		IsFromMe:      evt.Info.IsFromMe,
 		MediaType:     mediaType,
 		Filename:      filename,
+		Mimetype:      utils.ExtractMediaMimetype(evt.Message),
 		URL:           url,
 		MediaKey:      mediaKey,
 		FileSHA256:    fileSHA256,
 		FileEncSHA256: fileEncSHA256,
 		FileLength:    fileLength,
+		Thumbnail:     utils.ExtractMediaThumbnail(evt.Message),
 	}
 
 	// Store the message
@@ -627,8 +660,10 @@ func (r *SQLiteRepository) TruncateAllDataWithLogging(logPrefix string) error {
 	return nil
 }
 
-// StoreSentMessageWithContext stores a message that was sent by the user with context cancellation support
-func (r *SQLiteRepository) StoreSentMessageWithContext(ctx context.Context, messageID string, senderJID string, recipientJID string, content string, timestamp time.Time) error {
+// StoreSentMessageWithContext stores a message that was sent by the user with context cancellation support.
+// Contacts, locations and polls are stored as their content summary, with their kind as media type so they are not
+// taken for text.
+func (r *SQLiteRepository) StoreSentMessageWithContext(ctx context.Context, messageID string, senderJID string, recipientJID string, msg *waE2E.Message, content string, timestamp time.Time) error {
 	// Check if context is already cancelled before starting
 	select {
 	case <-ctx.Done():
@@ -684,18 +719,79 @@ func (r *SQLiteRepository) StoreSentMessageWithContext(ctx context.Context, mess
 	}
 
 	// Store the sent message
+	mediaType, filename, url, mediaKey, fileSHA256, fileEncSHA256, fileLength := utils.ExtractMediaInfo(msg)
+	if mediaType == "" {
+		mediaType = summaryType(msg)
+	}
 	message := &domainChatStorage.Message{
-		ID:        messageID,
-		ChatJID:   chatJID,
-		Sender:    senderJID,
-		Content:   content,
-		Timestamp: timestamp,
-		IsFromMe:  true,
+		ID:            messageID,
+		ChatJID:       chatJID,
+		Sender:        senderJID,
+		Content:       content,
+		Timestamp:     timestamp,
+		IsFromMe:      true,
+		MediaType:     mediaType,
+		Filename:      filename,
+		Mimetype:      utils.ExtractMediaMimetype(msg),
+		URL:           url,
+		MediaKey:      mediaKey,
+		FileSHA256:    fileSHA256,
+		FileEncSHA256: fileEncSHA256,
+		FileLength:    fileLength,
+		Thumbnail:     utils.ExtractMediaThumbnail(msg),
 	}
 
 	return r.StoreMessage(message)
 }
 
+// summaryType names the kind of a sent message whose content is only a summary of it, empty for text and media
+func summaryType(msg *waE2E.Message) string {
+	switch {
+	case msg.GetContactMessage() != nil:
+		return "contact"
+	case msg.GetLocationMessage() != nil:
+		return "location"
+	case msg.GetPollCreationMessage() != nil, msg.GetPollCreationMessageV3() != nil:
+		return "poll"
+	}
+	return ""
+}
+
+// ClaimLegacyData moves chats and messages stored before multi-user support (user_id 0) to the repository's user.
+// Rows that would collide with chats the user already has are left untouched.
+func (r *SQLiteRepository) ClaimLegacyData() (int64, error) {
+	if r.userID == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("UPDATE OR IGNORE chats SET user_id = ? WHERE user_id = 0", r.userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim legacy chats: %w", err)
+	}
+
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	// Messages follow their chat; this is a no-op when foreign keys already cascaded the update
+	_, err = tx.Exec(`
+		UPDATE OR IGNORE messages SET user_id = ?
+		WHERE user_id = 0 AND chat_jid NOT IN (SELECT jid FROM chats WHERE user_id = 0)
+	`, r.userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim legacy messages: %w", err)
+	}
+
+	return claimed, tx.Commit()
+}
+
 // _____________________________________________________________________________________________________________________
 
 // initializeSchema creates or migrates the database schema
@@ -810,5 +906,78 @@ func (r *SQLiteRepository) getMigrations() []string {
 		`
 		CREATE INDEX IF NOT EXISTS idx_messages_id ON messages(id);
 		`,
+
+		// Migration 3: Scope chats and messages by owning user.
+		// Existing rows predate multi-user support and are kept under user_id 0 until claimed.
+		`
+		ALTER TABLE messages RENAME TO messages_legacy;
+		ALTER TABLE chats RENAME TO chats_legacy;
+
+		CREATE TABLE chats (
+			user_id INTEGER NOT NULL DEFAULT 0,
+			jid TEXT NOT NULL,
+			name TEXT NOT NULL,
+			last_message_time TIMESTAMP NOT NULL,
+			ephemeral_expiration INTEGER DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, jid)
+		);
+
+		CREATE TABLE messages (
+			user_id INTEGER NOT NULL DEFAULT 0,
+			id TEXT NOT NULL,
+			chat_jid TEXT NOT NULL,
+			sender TEXT NOT NULL,
+			content TEXT,
+			timestamp TIMESTAMP NOT NULL,
+			is_from_me BOOLEAN DEFAULT FALSE,
+			media_type TEXT,
+			filename TEXT,
+			url TEXT,
+			media_key BLOB,
+			file_sha256 BLOB,
+			file_enc_sha256 BLOB,
+			file_length INTEGER DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, id, chat_jid),
+			FOREIGN KEY (user_id, chat_jid) REFERENCES chats(user_id, jid) ON DELETE CASCADE ON UPDATE CASCADE
+		);
+
+		INSERT INTO chats (user_id, jid, name, last_message_time, ephemeral_expiration, created_at, updated_at)
+		SELECT 0, jid, name, last_message_time, ephemeral_expiration, created_at, updated_at FROM chats_legacy;
+
+		INSERT INTO messages (
+			user_id, id, chat_jid, sender, content, timestamp, is_from_me,
+			media_type, filename, url, media_key, file_sha256,
+			file_enc_sha256, file_length, created_at, updated_at
+		)
+		SELECT 0, id, chat_jid, sender, content, timestamp, is_from_me,
+			media_type, filename, url, media_key, file_sha256,
+			file_enc_sha256, file_length, created_at, updated_at
+		FROM messages_legacy;
+
+		DROP TABLE messages_legacy;
+		DROP TABLE chats_legacy;
+
+		CREATE INDEX IF NOT EXISTS idx_messages_chat_jid ON messages(user_id, chat_jid);
+		CREATE INDEX IF NOT EXISTS idx_messages_timestamp ON messages(timestamp);
+		CREATE INDEX IF NOT EXISTS idx_messages_media_type ON messages(media_type);
+		CREATE INDEX IF NOT EXISTS idx_messages_sender ON messages(sender);
+		CREATE INDEX IF NOT EXISTS idx_messages_id ON messages(user_id, id);
+		CREATE INDEX IF NOT EXISTS idx_chats_last_message ON chats(user_id, last_message_time);
+		CREATE INDEX IF NOT EXISTS idx_chats_name ON chats(name);
+		`,
+
+		// Migration 4: Keep the mimetype of media, rows stored before fall back to the usual one of their type
+		`
+		ALTER TABLE messages ADD COLUMN mimetype TEXT NOT NULL DEFAULT '';
+		`,
+
+		// Migration 5: Keep the preview of images and videos to show it when they are quoted
+		`
+		ALTER TABLE messages ADD COLUMN thumbnail BLOB;
+		`,
 	}
 }
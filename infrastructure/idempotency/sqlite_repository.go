@@ -0,0 +1,107 @@
+package idempotency
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	domainIdempotency "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/idempotency"
+)
+
+// SQLiteRepository keeps idempotency keys in the chat storage database
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+// NewIdempotencyRepository creates an idempotency key store backed by an already opened SQLite database
+func NewIdempotencyRepository(db *sql.DB) domainIdempotency.IIdempotencyRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// InitializeSchema creates the idempotency keys table, keys are unique per user
+func (r *SQLiteRepository) InitializeSchema() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS idempotency_keys (
+		user_id INTEGER NOT NULL,
+		idempotency_key TEXT NOT NULL,
+		fingerprint TEXT NOT NULL,
+		status TEXT NOT NULL,
+		status_code INTEGER NOT NULL DEFAULT 0,
+		response BLOB,
+		created_at TIMESTAMP NOT NULL,
+		expires_at TIMESTAMP NOT NULL,
+		PRIMARY KEY (user_id, idempotency_key)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires ON idempotency_keys(expires_at);
+	`
+
+	_, err := r.db.Exec(query)
+	return err
+}
+
+// Reserve drops expired keys and abandoned reservations, then inserts the record unless its key is still taken
+func (r *SQLiteRepository) Reserve(record *domainIdempotency.Record, staleBefore time.Time) (*domainIdempotency.Record, error) {
+	now := time.Now().UTC()
+	record.CreatedAt = now
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.Exec(`DELETE FROM idempotency_keys WHERE expires_at <= ?
+		OR (user_id = ? AND idempotency_key = ? AND status = ? AND created_at <= ?)`,
+		now, record.UserID, record.Key, domainIdempotency.StatusProcessing, staleBefore.UTC()); err != nil {
+		return nil, fmt.Errorf("failed to purge idempotency keys: %w", err)
+	}
+
+	result, err := tx.Exec(`INSERT INTO idempotency_keys (user_id, idempotency_key, fingerprint, status, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?) ON CONFLICT(user_id, idempotency_key) DO NOTHING`,
+		record.UserID, record.Key, record.Fingerprint, record.Status, now, record.ExpiresAt.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	var existing *domainIdempotency.Record
+	if inserted, _ := result.RowsAffected(); inserted == 0 {
+		existing = &domainIdempotency.Record{}
+		var response []byte
+		err = tx.QueryRow(`SELECT user_id, idempotency_key, fingerprint, status, status_code, response, created_at, expires_at
+			FROM idempotency_keys WHERE user_id = ? AND idempotency_key = ?`, record.UserID, record.Key).Scan(
+			&existing.UserID, &existing.Key, &existing.Fingerprint, &existing.Status, &existing.StatusCode,
+			&response, &existing.CreatedAt, &existing.ExpiresAt)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("idempotency key %s disappeared while reserving it", record.Key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+		}
+		existing.Response = response
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	return existing, nil
+}
+
+func (r *SQLiteRepository) Complete(userID int, key string, statusCode int, response []byte) error {
+	_, err := r.db.Exec(`UPDATE idempotency_keys SET status = ?, status_code = ?, response = ? WHERE user_id = ? AND idempotency_key = ?`,
+		domainIdempotency.StatusCompleted, statusCode, response, userID, key)
+	if err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) Release(userID int, key string) error {
+	_, err := r.db.Exec(`DELETE FROM idempotency_keys WHERE user_id = ? AND idempotency_key = ? AND status = ?`,
+		userID, key, domainIdempotency.StatusProcessing)
+	if err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,354 @@
+package queue
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
+	domainQueue "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/queue"
+)
+
+const messageColumns = `id, user_id, job_id, username, type, phone, payload, attachment_path, attachment_name, attachment_type,
+	status, attempts, last_error, message_id, next_attempt_at, created_at, updated_at, sent_at`
+
+// SQLiteRepository keeps queued messages in the chat storage database
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+// NewQueueRepository creates a send queue backed by an already opened SQLite database
+func NewQueueRepository(db *sql.DB) domainQueue.IQueueRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// InitializeSchema creates the send queue table
+func (r *SQLiteRepository) InitializeSchema() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS send_queue (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		job_id INTEGER NOT NULL DEFAULT 0,
+		username TEXT NOT NULL DEFAULT '',
+		type TEXT NOT NULL,
+		phone TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		attachment_path TEXT NOT NULL DEFAULT '',
+		attachment_name TEXT NOT NULL DEFAULT '',
+		attachment_type TEXT NOT NULL DEFAULT '',
+		status TEXT NOT NULL,
+		attempts INTEGER NOT NULL DEFAULT 0,
+		last_error TEXT NOT NULL DEFAULT '',
+		message_id TEXT NOT NULL DEFAULT '',
+		next_attempt_at TIMESTAMP NOT NULL,
+		created_at TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP NOT NULL,
+		sent_at TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_send_queue_due ON send_queue(status, next_attempt_at);
+	CREATE INDEX IF NOT EXISTS idx_send_queue_user ON send_queue(user_id, created_at DESC);
+	`
+
+	if _, err := r.db.Exec(query); err != nil {
+		return err
+	}
+
+	// Tables created by earlier versions lack the newer columns
+	if err := r.addColumnIfMissing("send_queue", "job_id", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+
+	_, err := r.db.Exec("CREATE INDEX IF NOT EXISTS idx_send_queue_job ON send_queue(job_id, status)")
+	return err
+}
+
+func (r *SQLiteRepository) addColumnIfMissing(table, column, definition string) error {
+	var count int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&count)
+	if err != nil || count > 0 {
+		return err
+	}
+
+	_, err = r.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	return err
+}
+
+// Enqueue records a pending message that is due immediately unless NextAttemptAt is set
+func (r *SQLiteRepository) Enqueue(message *domainQueue.Message) error {
+	return insertMessage(r.db, message)
+}
+
+// EnqueueBatch records several pending messages at once, none of them are queued when one fails
+func (r *SQLiteRepository) EnqueueBatch(messages []*domainQueue.Message) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to enqueue messages: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, message := range messages {
+		if err := insertMessage(tx, message); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to enqueue messages: %w", err)
+	}
+	return nil
+}
+
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+func insertMessage(db execer, message *domainQueue.Message) error {
+	now := time.Now().UTC()
+	if message.NextAttemptAt.IsZero() {
+		message.NextAttemptAt = now
+	}
+	message.Status = domainQueue.StatusPending
+	message.CreatedAt = now
+	message.UpdatedAt = now
+
+	query := `
+		INSERT INTO send_queue (user_id, job_id, username, type, phone, payload, attachment_path, attachment_name, attachment_type,
+			status, next_attempt_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := db.Exec(query, message.UserID, message.JobID, message.Username, message.Type, message.Phone, message.Payload,
+		message.AttachmentPath, message.AttachmentName, message.AttachmentType, message.Status,
+		message.NextAttemptAt.UTC(), now, now)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue message: %w", err)
+	}
+
+	message.ID, err = result.LastInsertId()
+	return err
+}
+
+func (r *SQLiteRepository) GetByID(userID int, id int64) (*domainQueue.Message, error) {
+	query := "SELECT " + messageColumns + " FROM send_queue WHERE user_id = ? AND id = ?"
+
+	message, err := scanMessage(r.db.QueryRow(query, userID, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get queued message: %w", err)
+	}
+	return message, nil
+}
+
+// List returns the messages of a user, most recent first, only those to the given chats when chatKeys is not empty
+func (r *SQLiteRepository) List(userID int, status string, chatKeys []string, limit, offset int) ([]domainQueue.Message, error) {
+	where, args := listWhere("user_id", userID, status, chatKeys)
+	return r.list(where+" ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?", append(args, limit, offset)...)
+}
+
+func (r *SQLiteRepository) Count(userID int, status string, chatKeys []string) (int, error) {
+	where, args := listWhere("user_id", userID, status, chatKeys)
+	return r.count(where, args...)
+}
+
+// ListByJob returns the messages of a bulk job in the order of its recipients
+func (r *SQLiteRepository) ListByJob(jobID int64, status string, chatKeys []string, limit, offset int) ([]domainQueue.Message, error) {
+	where, args := listWhere("job_id", jobID, status, chatKeys)
+	return r.list(where+" ORDER BY id LIMIT ? OFFSET ?", append(args, limit, offset)...)
+}
+
+func (r *SQLiteRepository) CountByJob(jobID int64, status string, chatKeys []string) (int, error) {
+	where, args := listWhere("job_id", jobID, status, chatKeys)
+	return r.count(where, args...)
+}
+
+func (r *SQLiteRepository) CountJobStatuses(jobID int64) (map[string]int, error) {
+	rows, err := r.db.Query("SELECT status, COUNT(*) FROM send_queue WHERE job_id = ? GROUP BY status", jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count job messages: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[status] = count
+	}
+
+	return counts, rows.Err()
+}
+
+func (r *SQLiteRepository) list(conditions string, args ...any) ([]domainQueue.Message, error) {
+	rows, err := r.db.Query("SELECT "+messageColumns+" FROM send_queue"+conditions, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list queued messages: %w", err)
+	}
+	defer rows.Close()
+
+	messages := []domainQueue.Message{}
+	for rows.Next() {
+		message, err := scanMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, *message)
+	}
+
+	return messages, rows.Err()
+}
+
+func (r *SQLiteRepository) count(where string, args ...any) (int, error) {
+	var count int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM send_queue"+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count queued messages: %w", err)
+	}
+	return count, nil
+}
+
+func (r *SQLiteRepository) GetDueUsers(now time.Time) ([]int, error) {
+	rows, err := r.db.Query("SELECT DISTINCT user_id FROM send_queue WHERE status = ? AND next_attempt_at <= ?",
+		domainQueue.StatusPending, now.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users with due messages: %w", err)
+	}
+	defer rows.Close()
+
+	var userIDs []int
+	for rows.Next() {
+		var userID int
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	return userIDs, rows.Err()
+}
+
+func (r *SQLiteRepository) ClaimNext(userID int, now time.Time) (*domainQueue.Message, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim queued message: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := "SELECT " + messageColumns + ` FROM send_queue
+		WHERE user_id = ? AND status = ? AND next_attempt_at <= ?
+		ORDER BY next_attempt_at, id
+		LIMIT 1`
+
+	message, err := scanMessage(tx.QueryRow(query, userID, domainQueue.StatusPending, now.UTC()))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim queued message: %w", err)
+	}
+
+	// The status condition keeps a message cancelled in the meantime from being claimed
+	result, err := tx.Exec("UPDATE send_queue SET status = ?, updated_at = ? WHERE id = ? AND status = ?",
+		domainQueue.StatusSending, now.UTC(), message.ID, domainQueue.StatusPending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim queued message: %w", err)
+	}
+	if claimed, _ := result.RowsAffected(); claimed == 0 {
+		return nil, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to claim queued message: %w", err)
+	}
+
+	message.Status = domainQueue.StatusSending
+	return message, nil
+}
+
+func (r *SQLiteRepository) MarkSent(id int64, attempts int, messageID string) error {
+	now := time.Now().UTC()
+	_, err := r.db.Exec("UPDATE send_queue SET status = ?, attempts = ?, last_error = '', message_id = ?, sent_at = ?, updated_at = ? WHERE id = ?",
+		domainQueue.StatusSent, attempts, messageID, now, now, id)
+	return err
+}
+
+func (r *SQLiteRepository) MarkFailed(id int64, attempts int, status, lastError string) error {
+	_, err := r.db.Exec("UPDATE send_queue SET status = ?, attempts = ?, last_error = ?, updated_at = ? WHERE id = ?",
+		status, attempts, lastError, time.Now().UTC(), id)
+	return err
+}
+
+func (r *SQLiteRepository) Reschedule(id int64, attempts int, lastError string, nextAttemptAt time.Time) error {
+	_, err := r.db.Exec("UPDATE send_queue SET status = ?, attempts = ?, last_error = ?, next_attempt_at = ?, updated_at = ? WHERE id = ?",
+		domainQueue.StatusPending, attempts, lastError, nextAttemptAt.UTC(), time.Now().UTC(), id)
+	return err
+}
+
+func (r *SQLiteRepository) Cancel(userID int, id int64) (bool, error) {
+	result, err := r.db.Exec("UPDATE send_queue SET status = ?, updated_at = ? WHERE user_id = ? AND id = ? AND status = ?",
+		domainQueue.StatusCancelled, time.Now().UTC(), userID, id, domainQueue.StatusPending)
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel queued message: %w", err)
+	}
+
+	cancelled, err := result.RowsAffected()
+	return cancelled > 0, err
+}
+
+func (r *SQLiteRepository) CancelJob(jobID int64) (int64, error) {
+	result, err := r.db.Exec("UPDATE send_queue SET status = ?, updated_at = ? WHERE job_id = ? AND status = ?",
+		domainQueue.StatusCancelled, time.Now().UTC(), jobID, domainQueue.StatusPending)
+	if err != nil {
+		return 0, fmt.Errorf("failed to cancel job messages: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func (r *SQLiteRepository) ResetSending() (int64, error) {
+	result, err := r.db.Exec("UPDATE send_queue SET status = ?, updated_at = ? WHERE status = ?",
+		domainQueue.StatusPending, time.Now().UTC(), domainQueue.StatusSending)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reset interrupted messages: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// listWhere filters on the owning user or job, on the status when given and on the chats when chatKeys is not empty
+func listWhere(column string, id any, status string, chatKeys []string) (string, []any) {
+	conditions := []string{column + " = ?"}
+	args := []any{id}
+	if status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, status)
+	}
+	if len(chatKeys) > 0 {
+		condition, chatArgs := permission.ChatKeyCondition("phone", chatKeys)
+		conditions = append(conditions, condition)
+		args = append(args, chatArgs...)
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanMessage(row rowScanner) (*domainQueue.Message, error) {
+	var message domainQueue.Message
+	var sentAt sql.NullTime
+	err := row.Scan(&message.ID, &message.UserID, &message.JobID, &message.Username, &message.Type, &message.Phone, &message.Payload,
+		&message.AttachmentPath, &message.AttachmentName, &message.AttachmentType, &message.Status, &message.Attempts,
+		&message.LastError, &message.MessageID, &message.NextAttemptAt, &message.CreatedAt, &message.UpdatedAt, &sentAt)
+	if err != nil {
+		return nil, err
+	}
+	if sentAt.Valid {
+		message.SentAt = &sentAt.Time
+	}
+	return &message, nil
+}
@@ -0,0 +1,240 @@
+package quota
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	domainQuota "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/quota"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type repository struct {
+	db *sqlx.DB
+}
+
+// limitsRow mirrors the send_limits table
+type limitsRow struct {
+	MessagesPerMinute int   `db:"messages_per_minute"`
+	MessagesPerHour   int   `db:"messages_per_hour"`
+	MessagesPerDay    int   `db:"messages_per_day"`
+	NewContactsPerDay int   `db:"new_contacts_per_day"`
+	MediaBytesPerDay  int64 `db:"media_bytes_per_day"`
+}
+
+// sendRow mirrors the send_usage table
+type sendRow struct {
+	ID         int64     `db:"id"`
+	UserID     int       `db:"user_id"`
+	Recipient  string    `db:"recipient"`
+	MediaBytes int64     `db:"media_bytes"`
+	NewContact bool      `db:"new_contact"`
+	SentAt     time.Time `db:"sent_at"`
+}
+
+// NewQuotaRepository stores sending limits and usage in the user management database
+func NewQuotaRepository(dbPath string) (domainQuota.IQuotaRepository, error) {
+	db, err := sqlx.Connect("sqlite3", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to quota database: %w", err)
+	}
+
+	repo := &repository{db: db}
+	if err := repo.migrate(); err != nil {
+		return nil, fmt.Errorf("failed to migrate quota database: %w", err)
+	}
+
+	return repo, nil
+}
+
+func (r *repository) migrate() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS send_limits (
+		user_id INTEGER PRIMARY KEY,
+		messages_per_minute INTEGER NOT NULL DEFAULT 0,
+		messages_per_hour INTEGER NOT NULL DEFAULT 0,
+		messages_per_day INTEGER NOT NULL DEFAULT 0,
+		new_contacts_per_day INTEGER NOT NULL DEFAULT 0,
+		media_bytes_per_day INTEGER NOT NULL DEFAULT 0,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS send_usage (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		recipient TEXT NOT NULL,
+		media_bytes INTEGER NOT NULL DEFAULT 0,
+		new_contact BOOLEAN NOT NULL DEFAULT 0,
+		sent_at DATETIME NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_send_usage_user_sent_at ON send_usage(user_id, sent_at);
+
+	CREATE TABLE IF NOT EXISTS send_contacts (
+		user_id INTEGER NOT NULL,
+		recipient TEXT NOT NULL,
+		first_sent_at DATETIME NOT NULL,
+		PRIMARY KEY (user_id, recipient)
+	);
+	`
+
+	_, err := r.db.Exec(query)
+	return err
+}
+
+func (r *repository) GetLimits(userID int) (*domainQuota.Limits, error) {
+	var row limitsRow
+	query := `
+		SELECT messages_per_minute, messages_per_hour, messages_per_day, new_contacts_per_day, media_bytes_per_day
+		FROM send_limits WHERE user_id = ?
+	`
+	err := r.db.Get(&row, query, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get send limits: %w", err)
+	}
+
+	return &domainQuota.Limits{
+		MessagesPerMinute: row.MessagesPerMinute,
+		MessagesPerHour:   row.MessagesPerHour,
+		MessagesPerDay:    row.MessagesPerDay,
+		NewContactsPerDay: row.NewContactsPerDay,
+		MediaBytesPerDay:  row.MediaBytesPerDay,
+	}, nil
+}
+
+func (r *repository) SaveLimits(userID int, limits domainQuota.Limits) error {
+	query := `
+		INSERT INTO send_limits (user_id, messages_per_minute, messages_per_hour, messages_per_day, new_contacts_per_day, media_bytes_per_day, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET
+			messages_per_minute = excluded.messages_per_minute,
+			messages_per_hour = excluded.messages_per_hour,
+			messages_per_day = excluded.messages_per_day,
+			new_contacts_per_day = excluded.new_contacts_per_day,
+			media_bytes_per_day = excluded.media_bytes_per_day,
+			updated_at = excluded.updated_at
+	`
+
+	_, err := r.db.Exec(query, userID, limits.MessagesPerMinute, limits.MessagesPerHour, limits.MessagesPerDay,
+		limits.NewContactsPerDay, limits.MediaBytesPerDay, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to save send limits: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repository) RecordSend(event *domainQuota.SendEvent) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to record send: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Stored in UTC so the text timestamps compare chronologically
+	sentAt := event.SentAt.UTC()
+	result, err := tx.Exec("INSERT INTO send_usage (user_id, recipient, media_bytes, new_contact, sent_at) VALUES (?, ?, ?, ?, ?)",
+		event.UserID, event.Recipient, event.MediaBytes, event.NewContact, sentAt)
+	if err != nil {
+		return fmt.Errorf("failed to record send: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	if event.NewContact {
+		_, err = tx.Exec("INSERT OR IGNORE INTO send_contacts (user_id, recipient, first_sent_at) VALUES (?, ?, ?)",
+			event.UserID, event.Recipient, sentAt)
+		if err != nil {
+			return fmt.Errorf("failed to record contact: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to record send: %w", err)
+	}
+
+	event.ID = id
+	return nil
+}
+
+func (r *repository) DeleteSend(event domainQuota.SendEvent) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to delete send: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM send_usage WHERE id = ?", event.ID); err != nil {
+		return fmt.Errorf("failed to delete send: %w", err)
+	}
+	if event.NewContact {
+		if _, err := tx.Exec("DELETE FROM send_contacts WHERE user_id = ? AND recipient = ?", event.UserID, event.Recipient); err != nil {
+			return fmt.Errorf("failed to delete contact: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *repository) GetTotals(userID int, since time.Time) (domainQuota.Totals, error) {
+	var totals domainQuota.Totals
+	query := `
+		SELECT COUNT(*), COALESCE(SUM(new_contact), 0), COALESCE(SUM(media_bytes), 0)
+		FROM send_usage WHERE user_id = ? AND sent_at > ?
+	`
+	err := r.db.QueryRow(query, userID, since.UTC()).Scan(&totals.Messages, &totals.NewContacts, &totals.MediaBytes)
+	if err != nil {
+		return totals, fmt.Errorf("failed to count sends: %w", err)
+	}
+
+	return totals, nil
+}
+
+func (r *repository) GetSends(userID int, since time.Time) ([]domainQuota.SendEvent, error) {
+	var rows []sendRow
+	query := `
+		SELECT id, user_id, recipient, media_bytes, new_contact, sent_at
+		FROM send_usage WHERE user_id = ? AND sent_at > ?
+		ORDER BY sent_at, id
+	`
+	if err := r.db.Select(&rows, query, userID, since.UTC()); err != nil {
+		return nil, fmt.Errorf("failed to get sends: %w", err)
+	}
+
+	events := make([]domainQuota.SendEvent, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, domainQuota.SendEvent{
+			ID:         row.ID,
+			UserID:     row.UserID,
+			Recipient:  row.Recipient,
+			MediaBytes: row.MediaBytes,
+			NewContact: row.NewContact,
+			SentAt:     row.SentAt,
+		})
+	}
+	return events, nil
+}
+
+func (r *repository) IsKnownContact(userID int, recipient string) (bool, error) {
+	var count int
+	err := r.db.Get(&count, "SELECT COUNT(*) FROM send_contacts WHERE user_id = ? AND recipient = ?", userID, recipient)
+	if err != nil {
+		return false, fmt.Errorf("failed to check contact: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (r *repository) PruneSends(before time.Time) error {
+	_, err := r.db.Exec("DELETE FROM send_usage WHERE sent_at <= ?", before.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to prune sends: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,299 @@
+package schedule
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
+	domainSchedule "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/schedule"
+)
+
+const scheduleColumns = `id, user_id, username, type, phone, payload, attachment_path, attachment_name, attachment_type,
+	send_at, cron, timezone, status, next_run_at, runs, last_run_at, last_result, last_message_id, last_error, created_at, updated_at`
+
+// SQLiteRepository keeps schedules in the chat storage database
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+// NewScheduleRepository creates a schedule store backed by an already opened SQLite database
+func NewScheduleRepository(db *sql.DB) domainSchedule.IScheduleRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// InitializeSchema creates the schedules table
+func (r *SQLiteRepository) InitializeSchema() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS scheduled_messages (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		username TEXT NOT NULL DEFAULT '',
+		type TEXT NOT NULL,
+		phone TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		attachment_path TEXT NOT NULL DEFAULT '',
+		attachment_name TEXT NOT NULL DEFAULT '',
+		attachment_type TEXT NOT NULL DEFAULT '',
+		send_at TIMESTAMP,
+		cron TEXT NOT NULL DEFAULT '',
+		timezone TEXT NOT NULL,
+		status TEXT NOT NULL,
+		next_run_at TIMESTAMP,
+		runs INTEGER NOT NULL DEFAULT 0,
+		last_run_at TIMESTAMP,
+		last_result TEXT NOT NULL DEFAULT '',
+		last_message_id TEXT NOT NULL DEFAULT '',
+		last_error TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_scheduled_messages_due ON scheduled_messages(status, next_run_at);
+	CREATE INDEX IF NOT EXISTS idx_scheduled_messages_user ON scheduled_messages(user_id, created_at DESC);
+	`
+
+	_, err := r.db.Exec(query)
+	return err
+}
+
+func (r *SQLiteRepository) Create(schedule *domainSchedule.Schedule) error {
+	now := time.Now().UTC()
+	schedule.Status = domainSchedule.StatusActive
+	schedule.CreatedAt = now
+	schedule.UpdatedAt = now
+
+	query := `
+		INSERT INTO scheduled_messages (user_id, username, type, phone, payload, attachment_path, attachment_name, attachment_type,
+			send_at, cron, timezone, status, next_run_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.Exec(query, schedule.UserID, schedule.Username, schedule.Type, schedule.Phone, schedule.Payload,
+		schedule.AttachmentPath, schedule.AttachmentName, schedule.AttachmentType, utcOrNil(schedule.SendAt),
+		schedule.Cron, schedule.Timezone, schedule.Status, utcOrNil(schedule.NextRunAt), now, now)
+	if err != nil {
+		return fmt.Errorf("failed to create schedule: %w", err)
+	}
+
+	schedule.ID, err = result.LastInsertId()
+	return err
+}
+
+func (r *SQLiteRepository) GetByID(userID int, id int64) (*domainSchedule.Schedule, error) {
+	query := "SELECT " + scheduleColumns + " FROM scheduled_messages WHERE user_id = ? AND id = ?"
+
+	schedule, err := scanSchedule(r.db.QueryRow(query, userID, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule: %w", err)
+	}
+	return schedule, nil
+}
+
+// List returns the schedules of a user, most recent first
+func (r *SQLiteRepository) List(userID int, status string, chatKeys []string, limit, offset int) ([]domainSchedule.Schedule, error) {
+	where, args := listWhere(userID, status, chatKeys)
+	query := "SELECT " + scheduleColumns + " FROM scheduled_messages" + where + " ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?"
+
+	rows, err := r.db.Query(query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedules: %w", err)
+	}
+	return scanSchedules(rows)
+}
+
+func (r *SQLiteRepository) Count(userID int, status string, chatKeys []string) (int, error) {
+	where, args := listWhere(userID, status, chatKeys)
+
+	var count int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM scheduled_messages"+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count schedules: %w", err)
+	}
+	return count, nil
+}
+
+func (r *SQLiteRepository) GetDue(now time.Time) ([]domainSchedule.Schedule, error) {
+	query := "SELECT " + scheduleColumns + ` FROM scheduled_messages
+		WHERE status = ? AND next_run_at <= ?
+		ORDER BY next_run_at, id`
+
+	rows, err := r.db.Query(query, domainSchedule.StatusActive, now.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due schedules: %w", err)
+	}
+	return scanSchedules(rows)
+}
+
+func (r *SQLiteRepository) Claim(id int64, nextRunAt time.Time) (bool, error) {
+	// The status and next run conditions keep a schedule from being claimed twice for the same run
+	result, err := r.db.Exec("UPDATE scheduled_messages SET status = ?, updated_at = ? WHERE id = ? AND status = ? AND next_run_at = ?",
+		domainSchedule.StatusRunning, time.Now().UTC(), id, domainSchedule.StatusActive, nextRunAt.UTC())
+	if err != nil {
+		return false, fmt.Errorf("failed to claim schedule: %w", err)
+	}
+
+	claimed, err := result.RowsAffected()
+	return claimed > 0, err
+}
+
+func (r *SQLiteRepository) ResetRunning() (int64, error) {
+	result, err := r.db.Exec("UPDATE scheduled_messages SET status = ?, updated_at = ? WHERE status = ?",
+		domainSchedule.StatusActive, time.Now().UTC(), domainSchedule.StatusRunning)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reset interrupted schedules: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func (r *SQLiteRepository) UpdateTiming(schedule *domainSchedule.Schedule) (bool, error) {
+	schedule.UpdatedAt = time.Now().UTC()
+	result, err := r.db.Exec(`UPDATE scheduled_messages SET send_at = ?, cron = ?, timezone = ?, next_run_at = ?, updated_at = ?
+		WHERE user_id = ? AND id = ? AND status = ?`,
+		utcOrNil(schedule.SendAt), schedule.Cron, schedule.Timezone, utcOrNil(schedule.NextRunAt), schedule.UpdatedAt,
+		schedule.UserID, schedule.ID, domainSchedule.StatusActive)
+	if err != nil {
+		return false, fmt.Errorf("failed to update schedule: %w", err)
+	}
+
+	updated, err := result.RowsAffected()
+	return updated > 0, err
+}
+
+func (r *SQLiteRepository) SaveRun(schedule *domainSchedule.Schedule) error {
+	schedule.UpdatedAt = time.Now().UTC()
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to save schedule run: %w", err)
+	}
+	defer tx.Rollback()
+
+	// A schedule cancelled while it was running keeps its status
+	_, err = tx.Exec(`UPDATE scheduled_messages SET status = CASE WHEN status = ? THEN ? ELSE status END,
+		next_run_at = CASE WHEN status = ? THEN ? ELSE NULL END,
+		runs = ?, last_run_at = ?, last_result = ?, last_message_id = ?, last_error = ?, updated_at = ? WHERE id = ?`,
+		domainSchedule.StatusRunning, schedule.Status, domainSchedule.StatusRunning, utcOrNil(schedule.NextRunAt),
+		schedule.Runs, utcOrNil(schedule.LastRunAt), schedule.LastResult, schedule.LastMessageID, schedule.LastError,
+		schedule.UpdatedAt, schedule.ID)
+	if err != nil {
+		return fmt.Errorf("failed to save schedule run: %w", err)
+	}
+
+	if err := tx.QueryRow("SELECT status FROM scheduled_messages WHERE id = ?", schedule.ID).Scan(&schedule.Status); err != nil {
+		return fmt.Errorf("failed to save schedule run: %w", err)
+	}
+	if schedule.Status != domainSchedule.StatusActive {
+		schedule.NextRunAt = nil
+	}
+	return tx.Commit()
+}
+
+func (r *SQLiteRepository) Postpone(id int64, nextRunAt time.Time, lastError string) (bool, error) {
+	result, err := r.db.Exec("UPDATE scheduled_messages SET status = ?, next_run_at = ?, last_error = ?, updated_at = ? WHERE id = ? AND status = ?",
+		domainSchedule.StatusActive, nextRunAt.UTC(), lastError, time.Now().UTC(), id, domainSchedule.StatusRunning)
+	if err != nil {
+		return false, fmt.Errorf("failed to postpone schedule: %w", err)
+	}
+
+	postponed, err := result.RowsAffected()
+	return postponed > 0, err
+}
+
+func (r *SQLiteRepository) Cancel(userID int, id int64) (cancelled bool, running bool, err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, false, fmt.Errorf("failed to cancel schedule: %w", err)
+	}
+	defer tx.Rollback()
+
+	var status string
+	err = tx.QueryRow("SELECT status FROM scheduled_messages WHERE user_id = ? AND id = ?", userID, id).Scan(&status)
+	if err == sql.ErrNoRows || (err == nil && status != domainSchedule.StatusActive && status != domainSchedule.StatusRunning) {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, fmt.Errorf("failed to cancel schedule: %w", err)
+	}
+
+	// The status condition keeps a run that finished in the meantime from being overwritten
+	result, err := tx.Exec("UPDATE scheduled_messages SET status = ?, next_run_at = NULL, updated_at = ? WHERE id = ? AND status = ?",
+		domainSchedule.StatusCancelled, time.Now().UTC(), id, status)
+	if err != nil {
+		return false, false, fmt.Errorf("failed to cancel schedule: %w", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return false, false, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, false, fmt.Errorf("failed to cancel schedule: %w", err)
+	}
+	return true, status == domainSchedule.StatusRunning, nil
+}
+
+func listWhere(userID int, status string, chatKeys []string) (string, []any) {
+	conditions := []string{"user_id = ?"}
+	args := []any{userID}
+	if status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, status)
+	}
+	if len(chatKeys) > 0 {
+		condition, chatArgs := permission.ChatKeyCondition("phone", chatKeys)
+		conditions = append(conditions, condition)
+		args = append(args, chatArgs...)
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func utcOrNil(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSchedules(rows *sql.Rows) ([]domainSchedule.Schedule, error) {
+	defer rows.Close()
+
+	schedules := []domainSchedule.Schedule{}
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, *schedule)
+	}
+
+	return schedules, rows.Err()
+}
+
+func scanSchedule(row rowScanner) (*domainSchedule.Schedule, error) {
+	var schedule domainSchedule.Schedule
+	var sendAt, nextRunAt, lastRunAt sql.NullTime
+	err := row.Scan(&schedule.ID, &schedule.UserID, &schedule.Username, &schedule.Type, &schedule.Phone, &schedule.Payload,
+		&schedule.AttachmentPath, &schedule.AttachmentName, &schedule.AttachmentType, &sendAt, &schedule.Cron,
+		&schedule.Timezone, &schedule.Status, &nextRunAt, &schedule.Runs, &lastRunAt, &schedule.LastResult,
+		&schedule.LastMessageID, &schedule.LastError, &schedule.CreatedAt, &schedule.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if sendAt.Valid {
+		schedule.SendAt = &sendAt.Time
+	}
+	if nextRunAt.Valid {
+		schedule.NextRunAt = &nextRunAt.Time
+	}
+	if lastRunAt.Valid {
+		schedule.LastRunAt = &lastRunAt.Time
+	}
+	return &schedule, nil
+}
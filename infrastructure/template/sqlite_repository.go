@@ -0,0 +1,166 @@
+package template
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+)
+
+const templateColumns = "id, user_id, name, body, attachment_path, attachment_name, attachment_type, duration, created_at, updated_at"
+
+// SQLiteRepository keeps message templates in the chat storage database
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+// NewTemplateRepository creates a template store backed by an already opened SQLite database
+func NewTemplateRepository(db *sql.DB) domainTemplate.ITemplateRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// InitializeSchema creates the message templates table, names are unique per user
+func (r *SQLiteRepository) InitializeSchema() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS message_templates (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		name TEXT NOT NULL,
+		body TEXT NOT NULL DEFAULT '',
+		attachment_path TEXT NOT NULL DEFAULT '',
+		attachment_name TEXT NOT NULL DEFAULT '',
+		attachment_type TEXT NOT NULL DEFAULT '',
+		duration INTEGER,
+		created_at TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP NOT NULL
+	);
+
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_message_templates_name ON message_templates(user_id, name);
+	`
+
+	_, err := r.db.Exec(query)
+	return err
+}
+
+func (r *SQLiteRepository) Create(template *domainTemplate.Template) error {
+	now := time.Now().UTC()
+	template.CreatedAt = now
+	template.UpdatedAt = now
+
+	result, err := r.db.Exec(`INSERT INTO message_templates (user_id, name, body, attachment_path, attachment_name, attachment_type, duration, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		template.UserID, template.Name, template.Body, template.AttachmentPath, template.AttachmentName, template.AttachmentType,
+		durationOrNil(template.Duration), now, now)
+	if err != nil {
+		return fmt.Errorf("failed to create template: %w", err)
+	}
+
+	template.ID, err = result.LastInsertId()
+	template.Variables = bodyVariables(template.Body)
+	return err
+}
+
+func (r *SQLiteRepository) GetByID(userID int, id int64) (*domainTemplate.Template, error) {
+	return r.get("SELECT "+templateColumns+" FROM message_templates WHERE user_id = ? AND id = ?", userID, id)
+}
+
+func (r *SQLiteRepository) GetByName(userID int, name string) (*domainTemplate.Template, error) {
+	return r.get("SELECT "+templateColumns+" FROM message_templates WHERE user_id = ? AND name = ?", userID, name)
+}
+
+// List returns the templates of a user ordered by name
+func (r *SQLiteRepository) List(userID int, limit, offset int) ([]domainTemplate.Template, error) {
+	rows, err := r.db.Query("SELECT "+templateColumns+" FROM message_templates WHERE user_id = ? ORDER BY name LIMIT ? OFFSET ?",
+		userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+	defer rows.Close()
+
+	templates := []domainTemplate.Template{}
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *template)
+	}
+
+	return templates, rows.Err()
+}
+
+func (r *SQLiteRepository) Count(userID int) (int, error) {
+	var count int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM message_templates WHERE user_id = ?", userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count templates: %w", err)
+	}
+	return count, nil
+}
+
+func (r *SQLiteRepository) Update(template *domainTemplate.Template) error {
+	template.UpdatedAt = time.Now().UTC()
+	_, err := r.db.Exec(`UPDATE message_templates SET name = ?, body = ?, attachment_path = ?, attachment_name = ?, attachment_type = ?,
+		duration = ?, updated_at = ? WHERE user_id = ? AND id = ?`,
+		template.Name, template.Body, template.AttachmentPath, template.AttachmentName, template.AttachmentType,
+		durationOrNil(template.Duration), template.UpdatedAt, template.UserID, template.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update template: %w", err)
+	}
+
+	template.Variables = bodyVariables(template.Body)
+	return nil
+}
+
+func (r *SQLiteRepository) Delete(userID int, id int64) error {
+	_, err := r.db.Exec("DELETE FROM message_templates WHERE user_id = ? AND id = ?", userID, id)
+	return err
+}
+
+func (r *SQLiteRepository) get(query string, args ...any) (*domainTemplate.Template, error) {
+	template, err := scanTemplate(r.db.QueryRow(query, args...))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+	return template, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTemplate(row rowScanner) (*domainTemplate.Template, error) {
+	var template domainTemplate.Template
+	var duration sql.NullInt64
+	err := row.Scan(&template.ID, &template.UserID, &template.Name, &template.Body, &template.AttachmentPath,
+		&template.AttachmentName, &template.AttachmentType, &duration, &template.CreatedAt, &template.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if duration.Valid {
+		value := int(duration.Int64)
+		template.Duration = &value
+	}
+	template.Variables = bodyVariables(template.Body)
+	return &template, nil
+}
+
+func durationOrNil(duration *int) any {
+	if duration == nil {
+		return nil
+	}
+	return *duration
+}
+
+// bodyVariables lists the placeholders of a body, as an empty list rather than null when there are none
+func bodyVariables(body string) []string {
+	if variables := utils.TemplateVariables(body); variables != nil {
+		return variables
+	}
+	return []string{}
+}
@@ -3,8 +3,10 @@ package usermanagement
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
 	domainUserManagement "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/usermanagement"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/mattn/go-sqlite3"
@@ -15,6 +17,8 @@ type repository struct {
 	db *sqlx.DB
 }
 
+const userColumns = "id, username, password, is_active, role, scopes, allowed_chats, created_at, updated_at"
+
 func NewUserManagementRepository(dbPath string) (domainUserManagement.IUserManagementRepository, error) {
 	db, err := sqlx.Connect("sqlite3", dbPath)
 	if err != nil {
@@ -44,7 +48,36 @@ func (r *repository) migrate() error {
 	CREATE INDEX IF NOT EXISTS idx_users_is_active ON users(is_active);
 	`
 
-	_, err := r.db.Exec(query)
+	if _, err := r.db.Exec(query); err != nil {
+		return err
+	}
+
+	// Columns added after the table was first released
+	columns := []struct{ name, definition string }{
+		{"scopes", "TEXT NOT NULL DEFAULT ''"},
+		{"allowed_chats", "TEXT NOT NULL DEFAULT ''"},
+		{"role", "TEXT NOT NULL DEFAULT 'user'"},
+	}
+	for _, column := range columns {
+		if err := r.addColumnIfMissing("users", column.name, column.definition); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *repository) addColumnIfMissing(table, column, definition string) error {
+	var count int
+	query := "SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?"
+	if err := r.db.Get(&count, query, table, column); err != nil {
+		return fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	_, err := r.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
 	return err
 }
 
@@ -55,12 +88,12 @@ func (r *repository) Create(user *domainUserManagement.User) error {
 	}
 
 	query := `
-		INSERT INTO users (username, password, is_active, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?)
+		INSERT INTO users (username, password, is_active, role, scopes, allowed_chats, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	now := time.Now()
-	result, err := r.db.Exec(query, user.Username, string(hashedPassword), user.IsActive, now, now)
+	result, err := r.db.Exec(query, user.Username, string(hashedPassword), user.IsActive, user.Role, user.Scopes, user.AllowedChats, now, now)
 	if err != nil {
 		return fmt.Errorf("failed to create user: %w", err)
 	}
@@ -78,7 +111,7 @@ func (r *repository) Create(user *domainUserManagement.User) error {
 
 func (r *repository) GetByID(id int) (*domainUserManagement.User, error) {
 	var user domainUserManagement.User
-	query := "SELECT id, username, password, is_active, created_at, updated_at FROM users WHERE id = ?"
+	query := "SELECT " + userColumns + " FROM users WHERE id = ?"
 
 	err := r.db.Get(&user, query, id)
 	if err != nil {
@@ -93,7 +126,7 @@ func (r *repository) GetByID(id int) (*domainUserManagement.User, error) {
 
 func (r *repository) GetByUsername(username string) (*domainUserManagement.User, error) {
 	var user domainUserManagement.User
-	query := "SELECT id, username, password, is_active, created_at, updated_at FROM users WHERE username = ?"
+	query := "SELECT " + userColumns + " FROM users WHERE username = ?"
 
 	err := r.db.Get(&user, query, username)
 	if err != nil {
@@ -108,7 +141,7 @@ func (r *repository) GetByUsername(username string) (*domainUserManagement.User,
 
 func (r *repository) GetAll() ([]domainUserManagement.User, error) {
 	var users []domainUserManagement.User
-	query := "SELECT id, username, password, is_active, created_at, updated_at FROM users ORDER BY created_at DESC"
+	query := "SELECT " + userColumns + " FROM users ORDER BY created_at DESC"
 
 	err := r.db.Select(&users, query)
 	if err != nil {
@@ -141,6 +174,21 @@ func (r *repository) Update(id int, updateReq *domainUserManagement.UpdateUserRe
 		args = append(args, *updateReq.IsActive)
 	}
 
+	if updateReq.Role != "" {
+		setParts = append(setParts, "role = ?")
+		args = append(args, updateReq.Role)
+	}
+
+	if updateReq.Scopes != nil {
+		setParts = append(setParts, "scopes = ?")
+		args = append(args, permission.List(updateReq.Scopes))
+	}
+
+	if updateReq.AllowedChats != nil {
+		setParts = append(setParts, "allowed_chats = ?")
+		args = append(args, permission.List(updateReq.AllowedChats))
+	}
+
 	if len(setParts) == 0 {
 		return fmt.Errorf("no fields to update")
 	}
@@ -149,33 +197,61 @@ func (r *repository) Update(id int, updateReq *domainUserManagement.UpdateUserRe
 	args = append(args, time.Now())
 	args = append(args, id)
 
-	query := fmt.Sprintf("UPDATE users SET %s WHERE id = ?",
-		fmt.Sprintf("%s", setParts[0]))
-	for i := 1; i < len(setParts); i++ {
-		query = fmt.Sprintf("%s, %s", query, setParts[i])
-	}
-
-	_, err := r.db.Exec(query, args...)
-	if err != nil {
-		return fmt.Errorf("failed to update user: %w", err)
-	}
+	query := fmt.Sprintf("UPDATE users SET %s WHERE id = ?", strings.Join(setParts, ", "))
 
-	return nil
+	return r.keepingAnActiveAdmin(id, func(tx *sqlx.Tx) error {
+		if _, err := tx.Exec(query, args...); err != nil {
+			return fmt.Errorf("failed to update user: %w", err)
+		}
+		return nil
+	})
 }
 
 func (r *repository) Delete(id int) error {
-	query := "DELETE FROM users WHERE id = ?"
-	_, err := r.db.Exec(query, id)
+	return r.keepingAnActiveAdmin(id, func(tx *sqlx.Tx) error {
+		if _, err := tx.Exec("DELETE FROM users WHERE id = ?", id); err != nil {
+			return fmt.Errorf("failed to delete user: %w", err)
+		}
+		return nil
+	})
+}
+
+// keepingAnActiveAdmin applies a change to a user in a transaction that is rolled back when it removes the last
+// active admin, so two admins demoting each other at the same time cannot both succeed
+func (r *repository) keepingAnActiveAdmin(id int, change func(tx *sqlx.Tx) error) error {
+	tx, err := r.db.Beginx()
 	if err != nil {
-		return fmt.Errorf("failed to delete user: %w", err)
+		return err
 	}
+	defer tx.Rollback()
 
-	return nil
+	var wasActiveAdmin bool
+	query := "SELECT COUNT(*) > 0 FROM users WHERE id = ? AND is_active = TRUE AND role = ?"
+	if err := tx.Get(&wasActiveAdmin, query, id, domainUserManagement.RoleAdmin); err != nil {
+		return fmt.Errorf("failed to check user role: %w", err)
+	}
+
+	if err := change(tx); err != nil {
+		return err
+	}
+
+	if wasActiveAdmin {
+		var count int
+		query := "SELECT COUNT(*) FROM users WHERE is_active = TRUE AND role = ?"
+		if err := tx.Get(&count, query, domainUserManagement.RoleAdmin); err != nil {
+			return fmt.Errorf("failed to count active admins: %w", err)
+		}
+		if count == 0 {
+			return domainUserManagement.ErrLastActiveAdmin
+		}
+	}
+
+	return tx.Commit()
 }
 
 func (r *repository) GetActiveUsers() ([]domainUserManagement.User, error) {
 	var users []domainUserManagement.User
-	query := "SELECT id, username, password, is_active, created_at, updated_at FROM users WHERE is_active = TRUE"
+	query := "SELECT " + userColumns + " FROM users WHERE is_active = TRUE"
 
 	err := r.db.Select(&users, query)
 	if err != nil {
@@ -185,6 +261,17 @@ func (r *repository) GetActiveUsers() ([]domainUserManagement.User, error) {
 	return users, nil
 }
 
+func (r *repository) CountActiveAdmins() (int, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM users WHERE is_active = TRUE AND role = ?"
+
+	if err := r.db.Get(&count, query, domainUserManagement.RoleAdmin); err != nil {
+		return 0, fmt.Errorf("failed to count active admins: %w", err)
+	}
+
+	return count, nil
+}
+
 func (r *repository) ValidateCredentials(username, password string) bool {
 	user, err := r.GetByUsername(username)
 	if err != nil || user == nil || !user.IsActive {
@@ -0,0 +1,446 @@
+package webhook
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+)
+
+// OutboxRepository keeps pending, delivered and dead-lettered webhook deliveries in the chat storage database
+type OutboxRepository struct {
+	db *sql.DB
+}
+
+// NewOutboxRepository creates an outbox backed by an already opened SQLite database
+func NewOutboxRepository(db *sql.DB) domainWebhook.IWebhookOutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// InitializeSchema creates the outbox, dead-letter and attempt tables
+func (r *OutboxRepository) InitializeSchema() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS webhook_outbox (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL DEFAULT 0,
+		webhook_id INTEGER NOT NULL DEFAULT 0,
+		event TEXT NOT NULL,
+		url TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		attempts INTEGER NOT NULL DEFAULT 0,
+		last_error TEXT NOT NULL DEFAULT '',
+		last_status_code INTEGER NOT NULL DEFAULT 0,
+		next_attempt_at TIMESTAMP NOT NULL,
+		delivered_at TIMESTAMP,
+		created_at TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL DEFAULT 0,
+		webhook_id INTEGER NOT NULL DEFAULT 0,
+		event TEXT NOT NULL,
+		url TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		attempts INTEGER NOT NULL DEFAULT 0,
+		last_error TEXT NOT NULL DEFAULT '',
+		last_status_code INTEGER NOT NULL DEFAULT 0,
+		created_at TIMESTAMP NOT NULL,
+		failed_at TIMESTAMP NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_webhook_dead_letters_user ON webhook_dead_letters(user_id, failed_at DESC);
+
+	-- Attempts belong to an outbox delivery while it is pending or delivered and to a dead letter once it failed
+	CREATE TABLE IF NOT EXISTS webhook_attempts (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		outbox_id INTEGER NOT NULL DEFAULT 0,
+		dead_letter_id INTEGER NOT NULL DEFAULT 0,
+		attempt INTEGER NOT NULL,
+		status_code INTEGER NOT NULL DEFAULT 0,
+		error TEXT NOT NULL DEFAULT '',
+		duration_ms INTEGER NOT NULL DEFAULT 0,
+		attempted_at TIMESTAMP NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_webhook_attempts_outbox ON webhook_attempts(outbox_id);
+	CREATE INDEX IF NOT EXISTS idx_webhook_attempts_dead_letter ON webhook_attempts(dead_letter_id);
+	`
+
+	if _, err := r.db.Exec(query); err != nil {
+		return err
+	}
+
+	// Outboxes created by earlier versions removed delivered deliveries instead of keeping them
+	if err := r.addColumnIfMissing("webhook_outbox", "delivered_at", "TIMESTAMP"); err != nil {
+		return err
+	}
+
+	_, err := r.db.Exec(`
+	CREATE INDEX IF NOT EXISTS idx_webhook_outbox_pending ON webhook_outbox(delivered_at, next_attempt_at);
+	CREATE INDEX IF NOT EXISTS idx_webhook_outbox_delivered ON webhook_outbox(user_id, delivered_at DESC);
+	`)
+	return err
+}
+
+func (r *OutboxRepository) addColumnIfMissing(table, column, definition string) error {
+	var count int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&count)
+	if err != nil || count > 0 {
+		return err
+	}
+
+	_, err = r.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	return err
+}
+
+// Enqueue records a delivery that is due immediately unless NextAttemptAt is set
+func (r *OutboxRepository) Enqueue(delivery *domainWebhook.Delivery) error {
+	now := time.Now().UTC()
+	if delivery.NextAttemptAt.IsZero() {
+		delivery.NextAttemptAt = now
+	}
+	delivery.CreatedAt = now
+
+	query := `
+		INSERT INTO webhook_outbox (user_id, webhook_id, event, url, payload, attempts, next_attempt_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.Exec(query, delivery.UserID, delivery.WebhookID, delivery.Event, delivery.URL, delivery.Payload,
+		delivery.Attempts, delivery.NextAttemptAt.UTC(), now, now)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue webhook delivery: %w", err)
+	}
+
+	delivery.ID, err = result.LastInsertId()
+	return err
+}
+
+// GetDue returns pending deliveries whose next attempt is at or before now, oldest first
+func (r *OutboxRepository) GetDue(now time.Time, limit int) ([]domainWebhook.Delivery, error) {
+	query := `
+		SELECT id, user_id, webhook_id, event, url, payload, attempts, last_error, last_status_code, next_attempt_at, created_at
+		FROM webhook_outbox
+		WHERE delivered_at IS NULL AND next_attempt_at <= ?
+		ORDER BY next_attempt_at, id
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, now.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []domainWebhook.Delivery
+	for rows.Next() {
+		var delivery domainWebhook.Delivery
+		err := rows.Scan(&delivery.ID, &delivery.UserID, &delivery.WebhookID, &delivery.Event, &delivery.URL, &delivery.Payload,
+			&delivery.Attempts, &delivery.LastError, &delivery.LastStatusCode, &delivery.NextAttemptAt, &delivery.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// RecordAttempt keeps the outcome of a single try to post an outbox delivery. The attempt is numbered after the
+// attempts the delivery already has, so numbering continues after a replay.
+func (r *OutboxRepository) RecordAttempt(attempt *domainWebhook.DeliveryAttempt) error {
+	if attempt.AttemptedAt.IsZero() {
+		attempt.AttemptedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO webhook_attempts (outbox_id, attempt, status_code, error, duration_ms, attempted_at)
+		SELECT ?, COALESCE(MAX(attempt), 0) + 1, ?, ?, ?, ?
+		FROM webhook_attempts WHERE outbox_id = ?
+		RETURNING attempt
+	`
+
+	err := r.db.QueryRow(query, attempt.OutboxID, attempt.StatusCode, attempt.Error, attempt.DurationMs, attempt.AttemptedAt.UTC(),
+		attempt.OutboxID).Scan(&attempt.Attempt)
+	if err != nil {
+		return fmt.Errorf("failed to record webhook attempt: %w", err)
+	}
+	return nil
+}
+
+// MarkDelivered keeps a successfully posted delivery, with its attempts, until it is pruned
+func (r *OutboxRepository) MarkDelivered(id int64, attempts int, statusCode int) error {
+	query := `
+		UPDATE webhook_outbox
+		SET attempts = ?, last_error = '', last_status_code = ?, delivered_at = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	now := time.Now().UTC()
+	if _, err := r.db.Exec(query, attempts, statusCode, now, now, id); err != nil {
+		return fmt.Errorf("failed to mark webhook delivery as delivered: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records a failed attempt and schedules the next one
+func (r *OutboxRepository) MarkFailed(id int64, attempts int, lastError string, statusCode int, nextAttemptAt time.Time) error {
+	query := `
+		UPDATE webhook_outbox
+		SET attempts = ?, last_error = ?, last_status_code = ?, next_attempt_at = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	_, err := r.db.Exec(query, attempts, lastError, statusCode, nextAttemptAt.UTC(), time.Now().UTC(), id)
+	return err
+}
+
+// MoveToDeadLetter moves an exhausted delivery out of the outbox
+func (r *OutboxRepository) MoveToDeadLetter(id int64, attempts int, lastError string, statusCode int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		INSERT INTO webhook_dead_letters (user_id, webhook_id, event, url, payload, attempts, last_error, last_status_code, created_at, failed_at)
+		SELECT user_id, webhook_id, event, url, payload, ?, ?, ?, created_at, ?
+		FROM webhook_outbox WHERE id = ?
+	`, attempts, lastError, statusCode, time.Now().UTC(), id)
+	if err != nil {
+		return fmt.Errorf("failed to insert dead letter: %w", err)
+	}
+
+	deadLetterID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	if _, err = tx.Exec("UPDATE webhook_attempts SET outbox_id = 0, dead_letter_id = ? WHERE outbox_id = ?", deadLetterID, id); err != nil {
+		return fmt.Errorf("failed to move delivery attempts: %w", err)
+	}
+
+	if _, err = tx.Exec("DELETE FROM webhook_outbox WHERE id = ?", id); err != nil {
+		return fmt.Errorf("failed to remove delivery from outbox: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// ListDelivered returns the user's delivered deliveries, most recent first
+func (r *OutboxRepository) ListDelivered(userID, limit, offset int) ([]domainWebhook.Delivery, error) {
+	query := `
+		SELECT id, user_id, webhook_id, event, url, payload, attempts, last_error, last_status_code, next_attempt_at, created_at, delivered_at
+		FROM webhook_outbox
+		WHERE user_id = ? AND delivered_at IS NOT NULL
+		ORDER BY delivered_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.db.Query(query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list delivered webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []domainWebhook.Delivery
+	for rows.Next() {
+		var delivery domainWebhook.Delivery
+		err := rows.Scan(&delivery.ID, &delivery.UserID, &delivery.WebhookID, &delivery.Event, &delivery.URL, &delivery.Payload,
+			&delivery.Attempts, &delivery.LastError, &delivery.LastStatusCode, &delivery.NextAttemptAt, &delivery.CreatedAt, &delivery.DeliveredAt)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range deliveries {
+		if deliveries[i].History, err = r.attempts("outbox_id", deliveries[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return deliveries, nil
+}
+
+// CountDelivered returns how many delivered deliveries the user has
+func (r *OutboxRepository) CountDelivered(userID int) (int, error) {
+	var count int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM webhook_outbox WHERE user_id = ? AND delivered_at IS NOT NULL", userID).Scan(&count)
+	return count, err
+}
+
+// PruneDelivered removes deliveries delivered at or before the given time, together with their attempts
+func (r *OutboxRepository) PruneDelivered(before time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		DELETE FROM webhook_attempts
+		WHERE outbox_id IN (SELECT id FROM webhook_outbox WHERE delivered_at IS NOT NULL AND delivered_at <= ?)
+	`, before.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to prune delivery attempts: %w", err)
+	}
+	if _, err = tx.Exec("DELETE FROM webhook_outbox WHERE delivered_at IS NOT NULL AND delivered_at <= ?", before.UTC()); err != nil {
+		return fmt.Errorf("failed to prune delivered webhooks: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// ListDeadLetters returns the user's dead letters, most recent failure first
+func (r *OutboxRepository) ListDeadLetters(userID, limit, offset int) ([]domainWebhook.DeadLetter, error) {
+	query := `
+		SELECT id, user_id, webhook_id, event, url, payload, attempts, last_error, last_status_code, created_at, failed_at
+		FROM webhook_dead_letters
+		WHERE user_id = ?
+		ORDER BY failed_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.db.Query(query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	var deadLetters []domainWebhook.DeadLetter
+	for rows.Next() {
+		var deadLetter domainWebhook.DeadLetter
+		err := rows.Scan(&deadLetter.ID, &deadLetter.UserID, &deadLetter.WebhookID, &deadLetter.Event, &deadLetter.URL, &deadLetter.Payload,
+			&deadLetter.Attempts, &deadLetter.LastError, &deadLetter.LastStatusCode, &deadLetter.CreatedAt, &deadLetter.FailedAt)
+		if err != nil {
+			return nil, err
+		}
+		deadLetters = append(deadLetters, deadLetter)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range deadLetters {
+		if deadLetters[i].History, err = r.attempts("dead_letter_id", deadLetters[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return deadLetters, nil
+}
+
+// attempts returns every try of the delivery or dead letter the column refers to, oldest first
+func (r *OutboxRepository) attempts(column string, id int64) ([]domainWebhook.DeliveryAttempt, error) {
+	query := fmt.Sprintf(`
+		SELECT attempt, status_code, error, duration_ms, attempted_at
+		FROM webhook_attempts
+		WHERE %s = ?
+		ORDER BY id
+	`, column)
+
+	rows, err := r.db.Query(query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook attempts: %w", err)
+	}
+	defer rows.Close()
+
+	attempts := []domainWebhook.DeliveryAttempt{}
+	for rows.Next() {
+		var attempt domainWebhook.DeliveryAttempt
+		if err := rows.Scan(&attempt.Attempt, &attempt.StatusCode, &attempt.Error, &attempt.DurationMs, &attempt.AttemptedAt); err != nil {
+			return nil, err
+		}
+		attempts = append(attempts, attempt)
+	}
+
+	return attempts, rows.Err()
+}
+
+// CountDeadLetters returns how many dead letters the user has
+func (r *OutboxRepository) CountDeadLetters(userID int) (int, error) {
+	var count int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM webhook_dead_letters WHERE user_id = ?", userID).Scan(&count)
+	return count, err
+}
+
+// ReplayDeadLetter puts a single dead letter back into the outbox with a fresh retry budget
+func (r *OutboxRepository) ReplayDeadLetter(userID int, id int64) (bool, error) {
+	replayed, err := r.replay("user_id = ? AND id = ?", userID, id)
+	return replayed > 0, err
+}
+
+// ReplayDeadLetters puts the given dead letters back into the outbox, or all of them when ids is empty
+func (r *OutboxRepository) ReplayDeadLetters(userID int, ids []int64) (int64, error) {
+	if len(ids) == 0 {
+		return r.replay("user_id = ?", userID)
+	}
+
+	placeholders := make([]string, len(ids))
+	args := []any{userID}
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args = append(args, id)
+	}
+
+	return r.replay(fmt.Sprintf("user_id = ? AND id IN (%s)", strings.Join(placeholders, ",")), args...)
+}
+
+// replay moves the dead letters matching the condition back into the outbox, together with their attempts.
+// The retry budget starts over, while RecordAttempt numbers the new tries after the moved ones.
+func (r *OutboxRepository) replay(condition string, args ...any) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(fmt.Sprintf("SELECT id FROM webhook_dead_letters WHERE %s ORDER BY id", condition), args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to select dead letters: %w", err)
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	now := time.Now().UTC()
+	for _, id := range ids {
+		result, err := tx.Exec(`
+			INSERT INTO webhook_outbox (user_id, webhook_id, event, url, payload, attempts, next_attempt_at, created_at, updated_at)
+			SELECT user_id, webhook_id, event, url, payload, 0, ?, created_at, ?
+			FROM webhook_dead_letters WHERE id = ?
+		`, now, now, id)
+		if err != nil {
+			return 0, fmt.Errorf("failed to requeue dead letter: %w", err)
+		}
+
+		outboxID, err := result.LastInsertId()
+		if err != nil {
+			return 0, err
+		}
+		if _, err = tx.Exec("UPDATE webhook_attempts SET outbox_id = ?, dead_letter_id = 0 WHERE dead_letter_id = ?", outboxID, id); err != nil {
+			return 0, fmt.Errorf("failed to move dead letter attempts: %w", err)
+		}
+		if _, err = tx.Exec("DELETE FROM webhook_dead_letters WHERE id = ?", id); err != nil {
+			return 0, fmt.Errorf("failed to remove replayed dead letter: %w", err)
+		}
+	}
+
+	return int64(len(ids)), tx.Commit()
+}
@@ -0,0 +1,195 @@
+package webhook
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type repository struct {
+	db *sqlx.DB
+}
+
+// webhookRow mirrors the webhooks table; events and chats are stored as comma separated lists
+type webhookRow struct {
+	ID        int       `db:"id"`
+	UserID    int       `db:"user_id"`
+	URL       string    `db:"url"`
+	Secret    string    `db:"secret"`
+	Events    string    `db:"events"`
+	Chats     string    `db:"chats"`
+	Format    string    `db:"format"`
+	IsActive  bool      `db:"is_active"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (row webhookRow) toDomain() domainWebhook.Webhook {
+	webhook := domainWebhook.Webhook{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		URL:       row.URL,
+		Secret:    row.Secret,
+		Events:    []string{},
+		Chats:     []string{},
+		Format:    row.Format,
+		IsActive:  row.IsActive,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+	if row.Events != "" {
+		webhook.Events = strings.Split(row.Events, ",")
+	}
+	if row.Chats != "" {
+		webhook.Chats = strings.Split(row.Chats, ",")
+	}
+	return webhook
+}
+
+// NewWebhookRepository stores webhook endpoints in the user management database
+func NewWebhookRepository(dbPath string) (domainWebhook.IWebhookRepository, error) {
+	db, err := sqlx.Connect("sqlite3", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to webhook database: %w", err)
+	}
+
+	repo := &repository{db: db}
+	if err := repo.migrate(); err != nil {
+		return nil, fmt.Errorf("failed to migrate webhook database: %w", err)
+	}
+
+	return repo, nil
+}
+
+func (r *repository) migrate() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS webhooks (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		url TEXT NOT NULL,
+		secret TEXT NOT NULL,
+		events TEXT NOT NULL DEFAULT '',
+		chats TEXT NOT NULL DEFAULT '',
+		format TEXT NOT NULL DEFAULT 'legacy',
+		is_active BOOLEAN DEFAULT TRUE,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_webhooks_user_id ON webhooks(user_id);
+	`
+
+	if _, err := r.db.Exec(query); err != nil {
+		return err
+	}
+
+	// Tables created by earlier versions lack the newer columns
+	if err := r.addColumnIfMissing("webhooks", "chats", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	return r.addColumnIfMissing("webhooks", "format", "TEXT NOT NULL DEFAULT 'legacy'")
+}
+
+func (r *repository) addColumnIfMissing(table, column, definition string) error {
+	var count int
+	err := r.db.Get(&count, "SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column)
+	if err != nil || count > 0 {
+		return err
+	}
+
+	_, err = r.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	return err
+}
+
+func (r *repository) Create(webhook *domainWebhook.Webhook) error {
+	query := `
+		INSERT INTO webhooks (user_id, url, secret, events, chats, format, is_active, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	now := time.Now()
+	result, err := r.db.Exec(query, webhook.UserID, webhook.URL, webhook.Secret, strings.Join(webhook.Events, ","), strings.Join(webhook.Chats, ","), webhook.Format, webhook.IsActive, now, now)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	webhook.ID = int(id)
+	webhook.CreatedAt = now
+	webhook.UpdatedAt = now
+	return nil
+}
+
+func (r *repository) GetByID(userID, id int) (*domainWebhook.Webhook, error) {
+	var row webhookRow
+	query := "SELECT id, user_id, url, secret, events, chats, format, is_active, created_at, updated_at FROM webhooks WHERE user_id = ? AND id = ?"
+
+	err := r.db.Get(&row, query, userID, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get webhook by id: %w", err)
+	}
+
+	webhook := row.toDomain()
+	return &webhook, nil
+}
+
+func (r *repository) GetByUser(userID int) ([]domainWebhook.Webhook, error) {
+	query := "SELECT id, user_id, url, secret, events, chats, format, is_active, created_at, updated_at FROM webhooks WHERE user_id = ? ORDER BY id"
+	return r.selectWebhooks(query, userID)
+}
+
+func (r *repository) GetActiveByUser(userID int) ([]domainWebhook.Webhook, error) {
+	query := "SELECT id, user_id, url, secret, events, chats, format, is_active, created_at, updated_at FROM webhooks WHERE user_id = ? AND is_active = TRUE ORDER BY id"
+	return r.selectWebhooks(query, userID)
+}
+
+func (r *repository) Update(webhook *domainWebhook.Webhook) error {
+	query := `
+		UPDATE webhooks SET url = ?, secret = ?, events = ?, chats = ?, format = ?, is_active = ?, updated_at = ?
+		WHERE user_id = ? AND id = ?
+	`
+
+	now := time.Now()
+	_, err := r.db.Exec(query, webhook.URL, webhook.Secret, strings.Join(webhook.Events, ","), strings.Join(webhook.Chats, ","), webhook.Format, webhook.IsActive, now, webhook.UserID, webhook.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	webhook.UpdatedAt = now
+	return nil
+}
+
+func (r *repository) Delete(userID, id int) error {
+	query := "DELETE FROM webhooks WHERE user_id = ? AND id = ?"
+	_, err := r.db.Exec(query, userID, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repository) selectWebhooks(query string, args ...any) ([]domainWebhook.Webhook, error) {
+	var rows []webhookRow
+	if err := r.db.Select(&rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	webhooks := make([]domainWebhook.Webhook, 0, len(rows))
+	for _, row := range rows {
+		webhooks = append(webhooks, row.toDomain())
+	}
+	return webhooks, nil
+}
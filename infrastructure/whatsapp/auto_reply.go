@@ -0,0 +1,341 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	domainQuota "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/quota"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// autoReplyMaxMessageAge keeps messages received while the server was offline from triggering a burst of replies
+	autoReplyMaxMessageAge = 10 * time.Minute
+	// globalAutoReplyCooldown keeps the global auto-reply message from answering every message of a contact
+	globalAutoReplyCooldown = time.Hour
+	// autoReplyPruneInterval is how often cooldowns that ran out are evicted
+	autoReplyPruneInterval = 10 * time.Minute
+)
+
+// autoReplyRepo holds the per-user auto-reply rules; nil leaves only the global auto-reply message
+var autoReplyRepo domainAutoReply.IAutoReplyRepository
+
+// autoReplyQuota counts replies against the sending limits of their user; nil sends without limits
+var autoReplyQuota domainQuota.IQuotaUsecase
+
+var (
+	// autoReplyCooldowns holds when a rule may reply to a contact again, keyed by user, rule and contact
+	autoReplyCooldowns sync.Map
+
+	autoReplyPruneMu sync.Mutex
+	autoReplyPruned  time.Time
+)
+
+// SetAutoReplyRepository configures where per-user auto-reply rules are looked up
+func SetAutoReplyRepository(repo domainAutoReply.IAutoReplyRepository) {
+	autoReplyRepo = repo
+}
+
+// SetAutoReplyQuota configures the sending limits auto-replies are counted against
+func SetAutoReplyQuota(quota domainQuota.IQuotaUsecase) {
+	autoReplyQuota = quota
+}
+
+// AutoReplyCooldownRemaining returns how long a rule still waits before replying to the sender again
+func AutoReplyCooldownRemaining(userID int, rule domainAutoReply.Rule, sender string) time.Duration {
+	until, ok := autoReplyCooldowns.Load(autoReplyCooldownKey(userID, rule.ID, sender))
+	if !ok {
+		return 0
+	}
+	return max(time.Until(until.(time.Time)), 0)
+}
+
+// startAutoReplyCooldown keeps a rule from replying to the sender again until its cooldown ran out
+func startAutoReplyCooldown(userID int, rule domainAutoReply.Rule, sender string) {
+	if rule.CooldownSeconds <= 0 {
+		return
+	}
+
+	now := time.Now()
+	autoReplyCooldowns.Store(autoReplyCooldownKey(userID, rule.ID, sender), now.Add(time.Duration(rule.CooldownSeconds)*time.Second))
+	pruneAutoReplyCooldowns(now)
+}
+
+// pruneAutoReplyCooldowns evicts the cooldowns that ran out, at most once per prune interval
+func pruneAutoReplyCooldowns(now time.Time) {
+	autoReplyPruneMu.Lock()
+	if now.Sub(autoReplyPruned) < autoReplyPruneInterval {
+		autoReplyPruneMu.Unlock()
+		return
+	}
+	autoReplyPruned = now
+	autoReplyPruneMu.Unlock()
+
+	autoReplyCooldowns.Range(func(key, until any) bool {
+		if !until.(time.Time).After(now) {
+			autoReplyCooldowns.Delete(key)
+		}
+		return true
+	})
+}
+
+func autoReplyCooldownKey(userID, ruleID int, sender string) string {
+	contact, _, _ := strings.Cut(sender, "@")
+	contact, _, _ = strings.Cut(contact, ":")
+	return fmt.Sprintf("%d:%d:%s", userID, ruleID, strings.TrimPrefix(contact, "+"))
+}
+
+// autoReplyRules returns the active rules of the session's user. The legacy global client falls back to
+// the global auto-reply message without rules of its own, which only answers private chats as it always has.
+func autoReplyRules(session *UserSession) ([]domainAutoReply.Rule, error) {
+	var rules []domainAutoReply.Rule
+	if autoReplyRepo != nil {
+		var err error
+		if rules, err = autoReplyRepo.GetActiveByUser(session.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(rules) == 0 && session.UserID == 0 && config.WhatsappAutoReplyMessage != "" {
+		rules = append(rules, domainAutoReply.Rule{
+			Name:            "global auto-reply",
+			MatchType:       domainAutoReply.MatchAny,
+			ChatType:        domainAutoReply.ChatTypePrivate,
+			CooldownSeconds: int(globalAutoReplyCooldown / time.Second),
+			ReplyType:       domainAutoReply.ReplyText,
+			ReplyText:       config.WhatsappAutoReplyMessage,
+			IsActive:        true,
+		})
+	}
+
+	return rules, nil
+}
+
+func handleAutoReply(ctx context.Context, evt *events.Message, session *UserSession) {
+	if evt.Info.IsFromMe || evt.Info.IsIncomingBroadcast() || evt.Info.Chat.Server == types.NewsletterServer {
+		return
+	}
+	// Protocol messages and reactions are not conversations to answer
+	if evt.Message.GetProtocolMessage() != nil || evt.Message.GetReactionMessage() != nil {
+		return
+	}
+	if time.Since(evt.Info.Timestamp) > autoReplyMaxMessageAge {
+		return
+	}
+
+	rules, err := autoReplyRules(session)
+	if err != nil {
+		log.Errorf("Failed to load auto-reply rules of user %d: %v", session.UserID, err)
+		return
+	}
+	if len(rules) == 0 {
+		return
+	}
+
+	sender := autoReplySender(ctx, session.Client, evt.Info)
+	rule, _ := domainAutoReply.FirstMatch(rules, domainAutoReply.IncomingMessage{
+		Text:    utils.ExtractMessageTextFromEvent(evt),
+		ChatJID: evt.Info.Chat.String(),
+		Sender:  sender,
+		IsGroup: evt.Info.IsGroup,
+		Time:    evt.Info.Timestamp,
+	})
+	if rule == nil {
+		return
+	}
+
+	if remaining := AutoReplyCooldownRemaining(session.UserID, *rule, sender); remaining > 0 {
+		log.Debugf("Auto-reply rule %d of user %d cooling down for %s (%s left)", rule.ID, session.UserID, sender, remaining)
+		return
+	}
+	startAutoReplyCooldown(session.UserID, *rule, sender)
+
+	// Media replies are uploaded first, so send outside of the event loop
+	go func(rule domainAutoReply.Rule) {
+		if err := sendAutoReply(ctx, session, evt, rule); err != nil {
+			logrus.Errorf("Failed to send auto-reply rule %d of user %d to %s: %v", rule.ID, session.UserID, evt.Info.Chat, err)
+			return
+		}
+		logrus.Infof("Auto-reply rule %d of user %d answered %s in %s", rule.ID, session.UserID, sender, evt.Info.Chat)
+	}(*rule)
+}
+
+// autoReplySender returns the phone number JID of the sender so allow and deny lists can use phone numbers,
+// falling back to the address the message came from
+func autoReplySender(ctx context.Context, client *whatsmeow.Client, info types.MessageInfo) string {
+	if info.Sender.Server != types.HiddenUserServer {
+		return info.Sender.ToNonAD().String()
+	}
+	if !info.SenderAlt.IsEmpty() {
+		return info.SenderAlt.ToNonAD().String()
+	}
+	if pn, err := client.Store.LIDs.GetPNForLID(ctx, info.Sender.ToNonAD()); err == nil && !pn.IsEmpty() {
+		return pn.String()
+	}
+	return info.Sender.ToNonAD().String()
+}
+
+// sendAutoReply answers in the chat the message came from, quoting it, through the session's own client.
+// Replies count against the sending limits of the user like any other message.
+func sendAutoReply(ctx context.Context, session *UserSession, evt *events.Message, rule domainAutoReply.Rule) error {
+	client := session.Client
+	contextInfo := &waE2E.ContextInfo{
+		StanzaID:      proto.String(evt.Info.ID),
+		Participant:   proto.String(evt.Info.Sender.ToNonAD().String()),
+		QuotedMessage: evt.Message,
+	}
+
+	var media []byte
+	if rule.ReplyType != domainAutoReply.ReplyText && rule.ReplyType != "" {
+		var err error
+		if media, err = os.ReadFile(rule.MediaPath); err != nil {
+			return fmt.Errorf("failed to read reply media: %w", err)
+		}
+	}
+
+	var reservation domainQuota.SendEvent
+	if autoReplyQuota != nil {
+		var err error
+		// The contact just wrote to the user, so a reply never counts as a new contact
+		reservation, err = autoReplyQuota.Reserve(ctx, domainQuota.Send{
+			UserID:     session.UserID,
+			Recipient:  evt.Info.Chat.ToNonAD().String(),
+			MediaBytes: int64(len(media)),
+			KnownChat:  true,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	var resp whatsmeow.SendResponse
+	msg, err := buildAutoReplyMessage(ctx, client, rule, media, contextInfo)
+	if err == nil {
+		resp, err = client.SendMessage(ctx, evt.Info.Chat, msg)
+	}
+	if err != nil {
+		// The reply never left, it does not count against the limits
+		if reservation.ID != 0 {
+			if releaseErr := autoReplyQuota.Release(ctx, reservation); releaseErr != nil {
+				logrus.Warnf("Failed to release quota reservation: %v", releaseErr)
+			}
+		}
+		return err
+	}
+
+	storeAutoReply(ctx, session, evt, rule, msg, resp)
+	return nil
+}
+
+// storeAutoReply keeps a sent reply in the chat storage of the session
+func storeAutoReply(ctx context.Context, session *UserSession, evt *events.Message, rule domainAutoReply.Rule, msg *waE2E.Message, resp whatsmeow.SendResponse) {
+	if session.ChatStorageRepo == nil {
+		return
+	}
+
+	senderJID := ""
+	if session.Client.Store.ID != nil {
+		senderJID = session.Client.Store.ID.String()
+	}
+	if err := session.ChatStorageRepo.StoreSentMessageWithContext(ctx, resp.ID, senderJID, evt.Info.Chat.String(), msg, rule.ReplyText, resp.Timestamp); err != nil {
+		logrus.Warnf("Failed to store auto-reply message: %v", err)
+	}
+}
+
+// buildAutoReplyMessage builds the reply of a rule; data is the stored media of media replies
+func buildAutoReplyMessage(ctx context.Context, client *whatsmeow.Client, rule domainAutoReply.Rule, data []byte, contextInfo *waE2E.ContextInfo) (*waE2E.Message, error) {
+	if rule.ReplyType == domainAutoReply.ReplyText || rule.ReplyType == "" {
+		return &waE2E.Message{ExtendedTextMessage: &waE2E.ExtendedTextMessage{
+			Text:        proto.String(rule.ReplyText),
+			ContextInfo: contextInfo,
+		}}, nil
+	}
+
+	var mediaType whatsmeow.MediaType
+	switch rule.ReplyType {
+	case domainAutoReply.ReplyImage:
+		mediaType = whatsmeow.MediaImage
+	case domainAutoReply.ReplyVideo:
+		mediaType = whatsmeow.MediaVideo
+	case domainAutoReply.ReplyAudio:
+		mediaType = whatsmeow.MediaAudio
+	case domainAutoReply.ReplyDocument:
+		mediaType = whatsmeow.MediaDocument
+	default:
+		return nil, fmt.Errorf("unsupported reply type %q", rule.ReplyType)
+	}
+
+	uploaded, err := client.Upload(ctx, data, mediaType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload reply media: %w", err)
+	}
+
+	mimeType := http.DetectContentType(data)
+	fileLength := proto.Uint64(uint64(len(data)))
+	fileName := rule.MediaName
+
+	switch rule.ReplyType {
+	case domainAutoReply.ReplyImage:
+		return &waE2E.Message{ImageMessage: &waE2E.ImageMessage{
+			Caption:       proto.String(rule.ReplyText),
+			Mimetype:      proto.String(mimeType),
+			URL:           proto.String(uploaded.URL),
+			DirectPath:    proto.String(uploaded.DirectPath),
+			MediaKey:      uploaded.MediaKey,
+			FileEncSHA256: uploaded.FileEncSHA256,
+			FileSHA256:    uploaded.FileSHA256,
+			FileLength:    fileLength,
+			ContextInfo:   contextInfo,
+		}}, nil
+	case domainAutoReply.ReplyVideo:
+		return &waE2E.Message{VideoMessage: &waE2E.VideoMessage{
+			Caption:       proto.String(rule.ReplyText),
+			Mimetype:      proto.String(mimeType),
+			URL:           proto.String(uploaded.URL),
+			DirectPath:    proto.String(uploaded.DirectPath),
+			MediaKey:      uploaded.MediaKey,
+			FileEncSHA256: uploaded.FileEncSHA256,
+			FileSHA256:    uploaded.FileSHA256,
+			FileLength:    fileLength,
+			ContextInfo:   contextInfo,
+		}}, nil
+	case domainAutoReply.ReplyAudio:
+		return &waE2E.Message{AudioMessage: &waE2E.AudioMessage{
+			Mimetype:      proto.String(mimeType),
+			URL:           proto.String(uploaded.URL),
+			DirectPath:    proto.String(uploaded.DirectPath),
+			MediaKey:      uploaded.MediaKey,
+			FileEncSHA256: uploaded.FileEncSHA256,
+			FileSHA256:    uploaded.FileSHA256,
+			FileLength:    fileLength,
+			ContextInfo:   contextInfo,
+		}}, nil
+	default:
+		return &waE2E.Message{DocumentMessage: &waE2E.DocumentMessage{
+			Caption:       proto.String(rule.ReplyText),
+			FileName:      proto.String(fileName),
+			Title:         proto.String(fileName),
+			Mimetype:      proto.String(mimeType),
+			URL:           proto.String(uploaded.URL),
+			DirectPath:    proto.String(uploaded.DirectPath),
+			MediaKey:      uploaded.MediaKey,
+			FileEncSHA256: uploaded.FileEncSHA256,
+			FileSHA256:    uploaded.FileSHA256,
+			FileLength:    fileLength,
+			ContextInfo:   contextInfo,
+		}}, nil
+	}
+}
@@ -0,0 +1,76 @@
+package whatsapp
+
+import (
+	"context"
+	"maps"
+	"strings"
+	"time"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+// lifecycleWebsocketCodes keeps the websocket codes the dashboard already understands,
+// other lifecycle events use their event name in upper case, e.g. CONNECTION_LOGGED_OUT
+var lifecycleWebsocketCodes = map[string]string{
+	domainWebhook.EventPairSuccess: "LOGIN_SUCCESS",
+}
+
+// createConnectionPayload creates a webhook payload for connection state changes of the session in ctx
+func createConnectionPayload(ctx context.Context) map[string]any {
+	payload := make(map[string]any)
+
+	if session := UserSessionFromContext(ctx); session != nil {
+		payload["user_id"] = session.UserID
+		if session.Client != nil && session.Client.Store.ID != nil {
+			payload["device_id"] = session.Client.Store.ID.String()
+		}
+	}
+
+	return payload
+}
+
+// emitLifecycleEvent notifies the owner of the session in ctx about a connection lifecycle change
+// through their websocket connections and webhooks. It blocks until the webhook delivery is queued or sent.
+func emitLifecycleEvent(ctx context.Context, event string, message string, extra map[string]any) {
+	payload := createConnectionPayload(ctx)
+	maps.Copy(payload, extra)
+
+	var userID int
+	if session := UserSessionFromContext(ctx); session != nil {
+		userID = session.UserID
+	}
+
+	code, ok := lifecycleWebsocketCodes[event]
+	if !ok {
+		code = strings.ToUpper(strings.ReplaceAll(event, ".", "_"))
+	}
+	websocket.BroadcastToUser(userID, code, message, payload)
+
+	if !hasWebhookTargets(ctx, event, "") {
+		return
+	}
+
+	if err := dispatchWebhook(ctx, webhookEvent{Name: event, Payload: payload}); err != nil {
+		logrus.Errorf("Failed to forward %s event to webhook: %v", event, err)
+		return
+	}
+
+	logrus.Infof("Lifecycle event %s forwarded to webhook for user %d", event, userID)
+}
+
+// EmitQRCode notifies the user that a new login QR code is available
+func EmitQRCode(userID int, code string, imagePath string, duration time.Duration) {
+	session := GetSessionManager().GetUserSession(userID)
+	if session == nil {
+		return
+	}
+
+	ctx := ContextWithUserSession(context.Background(), session)
+	go emitLifecycleEvent(ctx, domainWebhook.EventQRCode, "Scan the QR code to log in", map[string]any{
+		"code":       code,
+		"image_path": imagePath,
+		"duration":   int(duration.Seconds()),
+	})
+}
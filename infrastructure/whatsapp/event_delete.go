@@ -4,24 +4,32 @@ import (
 	"context"
 	"time"
 
-	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow/types/events"
 )
 
-// forwardDeleteToWebhook sends a delete event to webhook
+// forwardDeleteToWebhook sends a delete event to the owner's webhooks
 func forwardDeleteToWebhook(ctx context.Context, evt *events.DeleteForMe, message *domainChatStorage.Message) error {
-	logrus.Infof("Forwarding delete event to %d configured webhook(s)", len(config.WhatsappWebhook))
 	payload, err := createDeletePayload(ctx, evt, message)
 	if err != nil {
 		return err
 	}
 
-	for _, url := range config.WhatsappWebhook {
-		if err = submitWebhook(ctx, payload, url); err != nil {
-			return err
-		}
+	var chatJID string
+	if message != nil {
+		chatJID = message.ChatJID
+	}
+
+	err = dispatchWebhook(ctx, webhookEvent{
+		Name:    domainWebhook.EventMessageDeleted,
+		ChatJID: chatJID,
+		Payload: payload,
+		Flat:    true,
+	})
+	if err != nil {
+		return err
 	}
 
 	logrus.Info("Delete event forwarded to webhook")
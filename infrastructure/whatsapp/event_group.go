@@ -2,11 +2,8 @@ package whatsapp
 
 import (
 	"context"
-	"fmt"
-	"strings"
-	"time"
 
-	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
@@ -14,9 +11,6 @@ import (
 
 // createGroupInfoPayload creates a webhook payload for group information events
 func createGroupInfoPayload(evt *events.GroupInfo, actionType string, jids []types.JID) map[string]any {
-	body := make(map[string]any)
-
-	// Create payload structure matching the expected format
 	payload := make(map[string]any)
 
 	// Add group chat ID
@@ -26,14 +20,7 @@ func createGroupInfoPayload(evt *events.GroupInfo, actionType string, jids []typ
 	payload["type"] = actionType
 	payload["jids"] = jidsToStrings(jids)
 
-	// Wrap in payload structure
-	body["payload"] = payload
-
-	// Add metadata for webhook processing
-	body["event"] = "group.participants"
-	body["timestamp"] = evt.Timestamp.Format(time.RFC3339)
-
-	return body
+	return payload
 }
 
 // jidsToStrings converts a slice of JIDs to a slice of strings
@@ -49,10 +36,8 @@ func jidsToStrings(jids []types.JID) []string {
 	return result
 }
 
-// forwardGroupInfoToWebhook forwards group information events to the configured webhook URLs
+// forwardGroupInfoToWebhook forwards group information events to the owner's webhook URLs
 func forwardGroupInfoToWebhook(ctx context.Context, evt *events.GroupInfo) error {
-	logrus.Infof("Forwarding group info event to %d configured webhook(s)", len(config.WhatsappWebhook))
-
 	// Send separate webhook events for each action type
 	actions := []struct {
 		actionType string
@@ -66,28 +51,15 @@ func forwardGroupInfoToWebhook(ctx context.Context, evt *events.GroupInfo) error
 
 	for _, action := range actions {
 		if len(action.jids) > 0 {
-			payload := createGroupInfoPayload(evt, action.actionType, action.jids)
-
-			// Collect errors from all webhook URLs instead of failing fast
-			var errors []error
-			for _, url := range config.WhatsappWebhook {
-				if err := submitWebhook(ctx, payload, url); err != nil {
-					errors = append(errors, fmt.Errorf("webhook %s failed: %w", url, err))
-				}
-			}
-
-			// If all webhooks failed, return combined error
-			if len(errors) == len(config.WhatsappWebhook) && len(errors) > 0 {
-				var errMessages []string
-				for _, err := range errors {
-					errMessages = append(errMessages, err.Error())
-				}
-				return fmt.Errorf("all webhook URLs failed: %s", strings.Join(errMessages, "; "))
-			}
-
-			// Log partial failures
-			if len(errors) > 0 {
-				logrus.Warnf("Some webhook URLs failed for group %s event: %v", action.actionType, errors)
+			// Errors from individual URLs are collected; only a total failure aborts
+			err := dispatchWebhook(ctx, webhookEvent{
+				Name:      domainWebhook.EventGroupParticipants,
+				ChatJID:   evt.JID.String(),
+				Timestamp: evt.Timestamp,
+				Payload:   createGroupInfoPayload(evt, action.actionType, action.jids),
+			})
+			if err != nil {
+				return err
 			}
 
 			logrus.Infof("Group %s event forwarded to webhook: %d users %s", action.actionType, len(action.jids), action.actionType)
@@ -7,38 +7,65 @@ import (
 	"strings"
 	"time"
 
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
 	"go.mau.fi/whatsmeow/types"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow/types/events"
 )
 
-// forwardMessageToWebhook is a helper function to forward message event to webhook url
+// forwardMessageToWebhook is a helper function to forward message event to the owner's webhook urls
 func forwardMessageToWebhook(ctx context.Context, evt *events.Message) error {
-	logrus.Infof("Forwarding message event to %d configured webhook(s)", len(config.WhatsappWebhook))
 	payload, err := createMessagePayload(ctx, evt)
 	if err != nil {
 		return err
 	}
 
-	for _, url := range config.WhatsappWebhook {
-		if err = submitWebhook(ctx, payload, url); err != nil {
-			return err
-		}
+	event := messageWebhookEvent(evt)
+	err = dispatchWebhook(ctx, webhookEvent{
+		Name:      event,
+		ChatJID:   evt.Info.Chat.String(),
+		Timestamp: evt.Info.Timestamp,
+		Payload:   payload,
+		Flat:      true,
+	})
+	if err != nil {
+		return err
 	}
 
-	logrus.Info("Message event forwarded to webhook")
+	logrus.Infof("Message event forwarded to webhook as %s", event)
 	return nil
 }
 
+// messageWebhookEvent returns the webhook event a message is delivered as,
+// revoked and edited messages have their own events so endpoints can subscribe to them separately
+func messageWebhookEvent(evt *events.Message) string {
+	if protocolMessage := evt.Message.GetProtocolMessage(); protocolMessage != nil {
+		switch protocolMessage.GetType() {
+		case waE2E.ProtocolMessage_REVOKE:
+			return domainWebhook.EventMessageRevoked
+		case waE2E.ProtocolMessage_MESSAGE_EDIT:
+			return domainWebhook.EventMessageEdited
+		}
+	}
+	return domainWebhook.EventMessage
+}
+
 func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]any, error) {
 	message := utils.BuildEventMessage(evt)
 	waReaction := utils.BuildEventReaction(evt)
 	forwarded := utils.BuildForwarded(evt)
 
+	client := eventClient(ctx)
+	if client == nil {
+		return nil, pkgError.WebhookError("no WhatsApp client bound to message event")
+	}
+
 	body := make(map[string]any)
 
 	body["sender_id"] = evt.Info.Sender.User
@@ -59,7 +86,7 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 			if err != nil {
 				logrus.Errorf("Error when parse jid: %v", err)
 			} else {
-				pn, err := cli.Store.LIDs.GetPNForLID(ctx, lid)
+				pn, err := client.Store.LIDs.GetPNForLID(ctx, lid)
 				if err != nil {
 					logrus.Errorf("Error when get pn for lid %s: %v", lid.String(), err)
 				}
@@ -84,7 +111,7 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 			if err != nil {
 				logrus.Errorf("Error when parse jid: %v", err)
 			} else {
-				pn, err := cli.Store.LIDs.GetPNForLID(ctx, lid)
+				pn, err := client.Store.LIDs.GetPNForLID(ctx, lid)
 				if err != nil {
 					logrus.Errorf("Error when get pn for lid %s: %v", lid.String(), err)
 				}
@@ -138,12 +165,7 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 	}
 
 	if audioMedia := evt.Message.GetAudioMessage(); audioMedia != nil {
-		path, err := utils.ExtractMedia(ctx, cli, config.PathMedia, audioMedia)
-		if err != nil {
-			logrus.Errorf("Failed to download audio from %s: %v", evt.Info.SourceString(), err)
-			return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download audio: %v", err))
-		}
-		body["audio"] = path
+		body["audio"] = webhookMedia(ctx, client, evt, "audio", audioMedia)
 	}
 
 	if contactMessage := evt.Message.GetContactMessage(); contactMessage != nil {
@@ -151,21 +173,11 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 	}
 
 	if documentMedia := evt.Message.GetDocumentMessage(); documentMedia != nil {
-		path, err := utils.ExtractMedia(ctx, cli, config.PathMedia, documentMedia)
-		if err != nil {
-			logrus.Errorf("Failed to download document from %s: %v", evt.Info.SourceString(), err)
-			return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download document: %v", err))
-		}
-		body["document"] = path
+		body["document"] = webhookMedia(ctx, client, evt, "document", documentMedia)
 	}
 
 	if imageMedia := evt.Message.GetImageMessage(); imageMedia != nil {
-		path, err := utils.ExtractMedia(ctx, cli, config.PathMedia, imageMedia)
-		if err != nil {
-			logrus.Errorf("Failed to download image from %s: %v", evt.Info.SourceString(), err)
-			return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download image: %v", err))
-		}
-		body["image"] = path
+		body["image"] = webhookMedia(ctx, client, evt, "image", imageMedia)
 	}
 
 	if listMessage := evt.Message.GetListMessage(); listMessage != nil {
@@ -185,22 +197,76 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 	}
 
 	if stickerMedia := evt.Message.GetStickerMessage(); stickerMedia != nil {
-		path, err := utils.ExtractMedia(ctx, cli, config.PathMedia, stickerMedia)
-		if err != nil {
-			logrus.Errorf("Failed to download sticker from %s: %v", evt.Info.SourceString(), err)
-			return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download sticker: %v", err))
-		}
-		body["sticker"] = path
+		body["sticker"] = webhookMedia(ctx, client, evt, "sticker", stickerMedia)
 	}
 
 	if videoMedia := evt.Message.GetVideoMessage(); videoMedia != nil {
-		path, err := utils.ExtractMedia(ctx, cli, config.PathMedia, videoMedia)
-		if err != nil {
-			logrus.Errorf("Failed to download video from %s: %v", evt.Info.SourceString(), err)
-			return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download video: %v", err))
-		}
-		body["video"] = path
+		body["video"] = webhookMedia(ctx, client, evt, "video", videoMedia)
 	}
 
 	return body, nil
 }
+
+// Media modes of webhooks, see config.WhatsappWebhookMediaMode
+const (
+	WebhookMediaModeDownload = "download"
+	WebhookMediaModeLink     = "link"
+)
+
+// WebhookMediaLink is the media of a message in a webhook of the link media mode. The URL downloads it from the
+// REST server without credentials until it expires.
+type WebhookMediaLink struct {
+	MimeType   string    `json:"mime_type"`
+	Caption    string    `json:"caption,omitempty"`
+	Filename   string    `json:"filename,omitempty"`
+	FileLength uint64    `json:"file_length"`
+	URL        string    `json:"url"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// webhookMedia describes the media of a message for its webhook: a link to download it later, or the media
+// downloaded into statics/media. A failed download is reported in the media instead of dropping the event.
+func webhookMedia(ctx context.Context, client *whatsmeow.Client, evt *events.Message, mediaType string, media whatsmeow.DownloadableMessage) any {
+	mimetype, caption := "", ""
+	if withMimetype, ok := media.(interface{ GetMimetype() string }); ok {
+		mimetype = withMimetype.GetMimetype()
+	}
+	if withCaption, ok := media.(interface{ GetCaption() string }); ok {
+		caption = withCaption.GetCaption()
+	}
+
+	if config.WhatsappWebhookMediaMode == WebhookMediaModeLink {
+		if session := UserSessionFromContext(ctx); session != nil {
+			expiresAt := time.Now().Add(time.Duration(config.WhatsappWebhookMediaURLExpiry) * time.Hour).Truncate(time.Second).UTC()
+			path, err := utils.SignMediaURL(config.WhatsappMediaURLSecret, session.UserID, evt.Info.ID, expiresAt)
+			if err == nil {
+				_, filename, _, _, _, _, fileLength := utils.ExtractMediaInfo(evt.Message)
+				return WebhookMediaLink{
+					MimeType:   mimetype,
+					Caption:    caption,
+					Filename:   filename,
+					FileLength: fileLength,
+					URL:        publicURL() + path,
+					ExpiresAt:  expiresAt,
+				}
+			}
+			logrus.Errorf("Failed to sign %s URL of message %s: %v", mediaType, evt.Info.ID, err)
+		}
+	}
+
+	extracted, err := utils.ExtractMedia(ctx, client, config.PathMedia, media)
+	if err != nil {
+		logrus.Errorf("Failed to download %s from %s: %v", mediaType, evt.Info.SourceString(), err)
+		return utils.ExtractedMedia{
+			MimeType: mimetype,
+			Caption:  caption,
+			Error:    fmt.Sprintf("failed to download %s: %v", mediaType, err),
+		}
+	}
+	return extracted
+}
+
+// publicURL is the URL the REST server is reached at, without a trailing slash. Link mode requires it at startup.
+func publicURL() string {
+	return strings.TrimSuffix(config.AppPublicURL, "/")
+}
@@ -0,0 +1,74 @@
+package whatsapp
+
+import (
+	"context"
+	"time"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// createPresencePayload creates a webhook payload for online/offline presence events
+func createPresencePayload(evt *events.Presence) map[string]any {
+	payload := make(map[string]any)
+
+	payload["from"] = evt.From.String()
+	if evt.Unavailable {
+		payload["type"] = "unavailable"
+		if !evt.LastSeen.IsZero() {
+			payload["last_seen"] = evt.LastSeen.Format(time.RFC3339)
+		}
+	} else {
+		payload["type"] = "available"
+	}
+
+	return payload
+}
+
+// createChatPresencePayload creates a webhook payload for typing and recording indicators
+func createChatPresencePayload(evt *events.ChatPresence) map[string]any {
+	payload := make(map[string]any)
+
+	payload["chat_id"] = evt.Chat.String()
+	payload["from"] = evt.Sender.String()
+
+	presenceType := string(evt.State)
+	if evt.State == types.ChatPresenceComposing && evt.Media == types.ChatPresenceMediaAudio {
+		presenceType = "recording"
+	}
+	payload["type"] = presenceType
+
+	return payload
+}
+
+// forwardPresenceToWebhook forwards online/offline presence events to the owner's webhook URLs
+func forwardPresenceToWebhook(ctx context.Context, evt *events.Presence) error {
+	err := dispatchWebhook(ctx, webhookEvent{
+		Name:    domainWebhook.EventPresence,
+		ChatJID: evt.From.String(),
+		Payload: createPresencePayload(evt),
+	})
+	if err != nil {
+		return err
+	}
+
+	logrus.Debug("Presence event forwarded to webhook")
+	return nil
+}
+
+// forwardChatPresenceToWebhook forwards typing and recording indicators to the owner's webhook URLs
+func forwardChatPresenceToWebhook(ctx context.Context, evt *events.ChatPresence) error {
+	err := dispatchWebhook(ctx, webhookEvent{
+		Name:    domainWebhook.EventPresence,
+		ChatJID: evt.Chat.String(),
+		Payload: createChatPresencePayload(evt),
+	})
+	if err != nil {
+		return err
+	}
+
+	logrus.Debug("Chat presence event forwarded to webhook")
+	return nil
+}
@@ -2,9 +2,8 @@ package whatsapp
 
 import (
 	"context"
-	"time"
 
-	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
@@ -36,9 +35,6 @@ func getReceiptTypeDescription(evt types.ReceiptType) string {
 
 // createReceiptPayload creates a webhook payload for message acknowledgement (receipt) events
 func createReceiptPayload(evt *events.Receipt) map[string]any {
-	body := make(map[string]any)
-
-	// Create payload structure matching the expected format
 	payload := make(map[string]any)
 
 	// Add message ID (use first message ID if multiple)
@@ -58,25 +54,19 @@ func createReceiptPayload(evt *events.Receipt) map[string]any {
 	}
 	payload["receipt_type_description"] = getReceiptTypeDescription(evt.Type)
 
-	// Wrap in payload structure
-	body["payload"] = payload
-
-	// Add metadata for webhook processing
-	body["event"] = "message.ack"
-	body["timestamp"] = evt.Timestamp.Format(time.RFC3339)
-
-	return body
+	return payload
 }
 
-// forwardReceiptToWebhook forwards message acknowledgement events to the configured webhook URLs
+// forwardReceiptToWebhook forwards message acknowledgement events to the owner's webhook URLs
 func forwardReceiptToWebhook(ctx context.Context, evt *events.Receipt) error {
-	logrus.Infof("Forwarding message ack event to %d configured webhook(s)", len(config.WhatsappWebhook))
-	payload := createReceiptPayload(evt)
-
-	for _, url := range config.WhatsappWebhook {
-		if err := submitWebhook(ctx, payload, url); err != nil {
-			return err
-		}
+	err := dispatchWebhook(ctx, webhookEvent{
+		Name:      domainWebhook.EventMessageAck,
+		ChatJID:   evt.Chat.String(),
+		Timestamp: evt.Timestamp,
+		Payload:   createReceiptPayload(evt),
+	})
+	if err != nil {
+		return err
 	}
 
 	logrus.Info("Message ack event forwarded to webhook")
@@ -0,0 +1,29 @@
+package whatsapp
+
+import (
+	"context"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+// EmitScheduleExecuted notifies the user that one of their schedules ran, through their websocket connections
+// and the webhooks subscribed to the chat it was sent to
+func EmitScheduleExecuted(userID int, chatJID string, payload map[string]any) {
+	session := GetSessionManager().GetUserSession(userID)
+	if session == nil {
+		return
+	}
+
+	ctx := ContextWithUserSession(context.Background(), session)
+	websocket.BroadcastChatToUser(userID, chatJID, "SCHEDULE_EXECUTED", "Scheduled message executed", payload)
+
+	if !hasWebhookTargets(ctx, domainWebhook.EventScheduleExecuted, chatJID) {
+		return
+	}
+
+	if err := dispatchWebhook(ctx, webhookEvent{Name: domainWebhook.EventScheduleExecuted, ChatJID: chatJID, Payload: payload}); err != nil {
+		logrus.Errorf("Failed to forward %s event to webhook: %v", domainWebhook.EventScheduleExecuted, err)
+	}
+}
@@ -14,8 +14,10 @@ import (
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/websocket"
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow"
@@ -43,6 +45,15 @@ var (
 	startupTime   = time.Now().Unix()
 )
 
+const (
+	// streamReplacedReclaimDelay is how long a replaced session waits before it is first reclaimed, doubling after each attempt
+	streamReplacedReclaimDelay = 30 * time.Second
+	// maxStreamReplacedReclaims is how many times in a row a replaced session is reclaimed before it stays disconnected
+	maxStreamReplacedReclaims = 5
+	// streamReplacedReclaimReset is how long a reclaimed session has to keep the connection for the attempts to start over
+	streamReplacedReclaimReset = time.Hour
+)
+
 // InitWaDB initializes the WhatsApp database connection
 func InitWaDB(ctx context.Context, DBURI string) *sqlstore.Container {
 	log = waLog.Stdout("Main", config.WhatsappLogLevel, true)
@@ -97,8 +108,33 @@ func syncKeysDevice(ctx context.Context, db, keysDB *sqlstore.Container) {
 	}
 }
 
-// InitWaCLI initializes the WhatsApp client
+// InitWaCLI initializes the global WhatsApp client
 func InitWaCLI(ctx context.Context, storeContainer, keysStoreContainer *sqlstore.Container, chatStorageRepo domainChatStorage.IChatStorageRepository) *whatsmeow.Client {
+	// Set global database reference for remote logout cleanup
+	db = storeContainer
+	keysDB = keysStoreContainer
+
+	cli = newWaClient(ctx, storeContainer, keysStoreContainer)
+	registerEventHandler(ctx, &UserSession{
+		Client:          cli,
+		DB:              storeContainer,
+		KeysDB:          keysStoreContainer,
+		ChatStorageRepo: chatStorageRepo,
+	})
+
+	return cli
+}
+
+// InitUserWaCLI initializes the WhatsApp client of a user session.
+// Events received by the client are handled with the session's own client, storage and identity.
+func InitUserWaCLI(ctx context.Context, session *UserSession) *whatsmeow.Client {
+	session.Client = newWaClient(ctx, session.DB, session.KeysDB)
+	registerEventHandler(ctx, session)
+	return session.Client
+}
+
+// newWaClient creates a WhatsApp client for the first device in the store
+func newWaClient(ctx context.Context, storeContainer, keysStoreContainer *sqlstore.Container) *whatsmeow.Client {
 	device, err := storeContainer.GetFirstDevice(ctx)
 	if err != nil {
 		log.Errorf("Failed to get device: %v", err)
@@ -115,15 +151,11 @@ func InitWaCLI(ctx context.Context, storeContainer, keysStoreContainer *sqlstore
 	store.DeviceProps.PlatformType = &config.AppPlatform
 	store.DeviceProps.Os = &osName
 
-	// Set global database reference for remote logout cleanup
-	db = storeContainer
-	keysDB = keysStoreContainer
-
 	// Configure a separated database for accelerating encryption caching
-	if keysDB != nil && device.ID != nil {
+	if keysStoreContainer != nil && device.ID != nil {
 		innerStore := sqlstore.NewSQLStore(keysStoreContainer, *device.ID)
 
-		syncKeysDevice(ctx, db, keysDB)
+		syncKeysDevice(ctx, storeContainer, keysStoreContainer)
 		device.Identities = innerStore
 		device.Sessions = innerStore
 		device.PreKeys = innerStore
@@ -133,15 +165,28 @@ func InitWaCLI(ctx context.Context, storeContainer, keysStoreContainer *sqlstore
 	}
 
 	// Create and configure the client
-	cli = whatsmeow.NewClient(device, waLog.Stdout("Client", config.WhatsappLogLevel, true))
-	cli.EnableAutoReconnect = false // Disable built-in auto-reconnect, we handle it smartly in session manager
-	cli.AutoTrustIdentity = true
+	client := whatsmeow.NewClient(device, waLog.Stdout("Client", config.WhatsappLogLevel, true))
+	client.EnableAutoReconnect = false // Disable built-in auto-reconnect, we handle it smartly in session manager
+	client.AutoTrustIdentity = true
+
+	return client
+}
 
-	cli.AddEventHandler(func(rawEvt interface{}) {
-		handler(ctx, rawEvt, chatStorageRepo)
+// registerEventHandler binds the session's client events to the handler.
+// The session travels in the context so downstream consumers know which user an event belongs to.
+func registerEventHandler(ctx context.Context, session *UserSession) {
+	eventCtx := ContextWithUserSession(ctx, session)
+	session.Client.AddEventHandler(func(rawEvt interface{}) {
+		handler(eventCtx, rawEvt, session)
 	})
+}
 
-	return cli
+// eventClient returns the WhatsApp client that received the event being handled
+func eventClient(ctx context.Context) *whatsmeow.Client {
+	if session := UserSessionFromContext(ctx); session != nil {
+		return session.Client
+	}
+	return nil
 }
 
 // UpdateGlobalClient updates the global cli variable with a new client instance
@@ -195,295 +240,133 @@ func GetConnectionStatus() (isConnected bool, isLoggedIn bool, deviceID string)
 	return false, false, "multi-user-mode"
 }
 
-// CleanupDatabase properly closes database connections and removes the database file
-func CleanupDatabase() error {
-	// Close main database connection first
-	if db != nil {
-		logrus.Info("[CLEANUP] Closing main database connection...")
-		if err := db.Close(); err != nil {
-			logrus.Errorf("[CLEANUP] Error closing main database: %v", err)
+// databaseFilePath returns the file behind a SQLite URI, or an empty string for in-memory databases
+func databaseFilePath(uri string) string {
+	if uri == "" || strings.Contains(uri, ":memory:") {
+		return ""
+	}
+	path, _, _ := strings.Cut(strings.TrimPrefix(uri, "file:"), "?")
+	return path
+}
+
+// CleanupUserDatabase closes the session's store connections and removes its database files
+func CleanupUserDatabase(session *UserSession) error {
+	if session.DB != nil {
+		logrus.Infof("[CLEANUP] Closing database connection of user %d...", session.UserID)
+		if err := session.DB.Close(); err != nil {
+			logrus.Errorf("[CLEANUP] Error closing database of user %d: %v", session.UserID, err)
 			// Continue with cleanup even if close fails
-		} else {
-			logrus.Info("[CLEANUP] Main database connection closed successfully")
 		}
-		db = nil
+		session.DB = nil
 	}
 
-	// Close keys database connection if it exists
-	if keysDB != nil {
-		logrus.Info("[CLEANUP] Closing keys database connection...")
-		if err := keysDB.Close(); err != nil {
-			logrus.Errorf("[CLEANUP] Error closing keys database: %v", err)
+	if session.KeysDB != nil {
+		logrus.Infof("[CLEANUP] Closing keys database connection of user %d...", session.UserID)
+		if err := session.KeysDB.Close(); err != nil {
+			logrus.Errorf("[CLEANUP] Error closing keys database of user %d: %v", session.UserID, err)
 			// Continue with cleanup even if close fails
-		} else {
-			logrus.Info("[CLEANUP] Keys database connection closed successfully")
 		}
-		keysDB = nil
+		session.KeysDB = nil
 	}
 
 	// Wait a moment for connections to be properly closed
 	time.Sleep(100 * time.Millisecond)
 
-	// Remove main database file
-	dbPath := strings.TrimPrefix(config.DBURI, "file:")
-	if strings.Contains(dbPath, "?") {
-		dbPath = strings.Split(dbPath, "?")[0]
-	}
+	dbURI, keysDBURI := userDatabaseURIs(session.UserID)
 
+	dbPath := databaseFilePath(dbURI)
 	logrus.Infof("[CLEANUP] Removing database file: %s", dbPath)
-	if err := os.Remove(dbPath); err != nil {
-		if !os.IsNotExist(err) {
-			logrus.Errorf("[CLEANUP] Error removing database file: %v", err)
-			return err
-		} else {
-			logrus.Info("[CLEANUP] Database file already removed")
-		}
-	} else {
-		logrus.Info("[CLEANUP] Database file removed successfully")
+	if err := os.Remove(dbPath); err != nil && !os.IsNotExist(err) {
+		logrus.Errorf("[CLEANUP] Error removing database file: %v", err)
+		return err
 	}
 
-	// Remove keys database file if configured (skip memory databases)
-	if config.DBKeysURI != "" && config.DBKeysURI != config.DBURI && !strings.Contains(config.DBKeysURI, ":memory:") {
-		keysDbPath := strings.TrimPrefix(config.DBKeysURI, "file:")
-		if strings.Contains(keysDbPath, "?") {
-			keysDbPath = strings.Split(keysDbPath, "?")[0]
-		}
-
+	if keysDbPath := databaseFilePath(keysDBURI); keysDbPath != "" {
 		logrus.Infof("[CLEANUP] Removing keys database file: %s", keysDbPath)
-		if err := os.Remove(keysDbPath); err != nil {
-			if !os.IsNotExist(err) {
-				logrus.Errorf("[CLEANUP] Error removing keys database file: %v", err)
-				// Don't return error for keys database as it's secondary
-			} else {
-				logrus.Info("[CLEANUP] Keys database file already removed")
-			}
-		} else {
-			logrus.Info("[CLEANUP] Keys database file removed successfully")
+		if err := os.Remove(keysDbPath); err != nil && !os.IsNotExist(err) {
+			logrus.Errorf("[CLEANUP] Error removing keys database file: %v", err)
+			// Don't return error for keys database as it's secondary
 		}
-	} else if strings.Contains(config.DBKeysURI, ":memory:") {
-		logrus.Info("[CLEANUP] Keys database is in memory - no file to remove")
 	}
 
 	return nil
 }
 
-// CleanupTemporaryFiles removes history files, QR images, and send items
-func CleanupTemporaryFiles() error {
-	// Clean up history files
-	if files, err := filepath.Glob(fmt.Sprintf("./%s/history-*", config.PathStorages)); err == nil {
-		for _, f := range files {
-			if err := os.Remove(f); err != nil {
-				logrus.Errorf("[CLEANUP] Error removing history file %s: %v", f, err)
-				return err
-			}
-		}
-		logrus.Info("[CLEANUP] History files cleaned up")
+// CleanupUserTemporaryFiles removes the history files and QR images of a single user.
+// Send items are removed right after sending and belong to no particular user, so they are left alone.
+func CleanupUserTemporaryFiles(userID int) error {
+	patterns := []string{
+		fmt.Sprintf("./%s/history-user-%d-*", config.PathStorages, userID),
+		fmt.Sprintf("./%s/scan-qr-user-%d-*", config.PathQrCode, userID),
 	}
 
-	// Clean up QR images
-	if qrImages, err := filepath.Glob(fmt.Sprintf("./%s/scan-*", config.PathQrCode)); err == nil {
-		for _, f := range qrImages {
+	for _, pattern := range patterns {
+		files, err := filepath.Glob(pattern)
+		if err != nil {
+			continue
+		}
+		for _, f := range files {
 			if err := os.Remove(f); err != nil {
-				logrus.Errorf("[CLEANUP] Error removing QR image %s: %v", f, err)
+				logrus.Errorf("[CLEANUP] Error removing temporary file %s: %v", f, err)
 				return err
 			}
 		}
-		logrus.Info("[CLEANUP] QR images cleaned up")
-	}
-
-	// Clean up send items
-	if qrItems, err := filepath.Glob(fmt.Sprintf("./%s/*", config.PathSendItems)); err == nil {
-		for _, f := range qrItems {
-			if !strings.Contains(f, ".gitignore") {
-				if err := os.Remove(f); err != nil {
-					logrus.Errorf("[CLEANUP] Error removing send item %s: %v", f, err)
-					return err
-				}
-			}
-		}
-		logrus.Info("[CLEANUP] Send items cleaned up")
 	}
 
+	logrus.Infof("[CLEANUP] Temporary files of user %d cleaned up", userID)
 	return nil
 }
 
-// ReinitializeWhatsAppComponents reinitializes database and client components
-func ReinitializeWhatsAppComponents(ctx context.Context, chatStorageRepo domainChatStorage.IChatStorageRepository) (*sqlstore.Container, *whatsmeow.Client, error) {
-	logrus.Info("[CLEANUP] Reinitializing database and client...")
-
-	newDB := InitWaDB(ctx, config.DBURI)
-	if config.DBKeysURI != "" {
-		keysDB = InitWaDB(ctx, config.DBKeysURI)
-	}
-	newCli := InitWaCLI(ctx, newDB, keysDB, chatStorageRepo)
-
-	// Update global references
-	db = newDB
-	cli = newCli
-
-	logrus.Info("[CLEANUP] Database and client reinitialized successfully")
-
-	return newDB, newCli, nil
-}
-
-// PerformPartialCleanup performs cleanup without logging out from WhatsApp server (for disconnect)
-// This clears local data but keeps device registered with WhatsApp server
-func PerformPartialCleanup(ctx context.Context, logPrefix string, chatStorageRepo domainChatStorage.IChatStorageRepository) (*sqlstore.Container, *whatsmeow.Client, error) {
-	logrus.Infof("[%s] Starting partial cleanup process (keeping server session active)...", logPrefix)
-
-	// Disconnect current client if it exists
-	if cli != nil {
-		cli.Disconnect()
-		logrus.Infof("[%s] Client disconnected", logPrefix)
-	}
-
-	// Truncate all chatstorage data before other cleanup
-	if chatStorageRepo != nil {
-		logrus.Infof("[%s] Truncating chatstorage data...", logPrefix)
-		if err := chatStorageRepo.TruncateAllDataWithLogging(logPrefix); err != nil {
-			logrus.Errorf("[%s] Failed to truncate chatstorage data: %v", logPrefix, err)
-			// Continue with cleanup even if chatstorage truncation fails
-		}
-	}
-
-	// Clean up database
-	if err := CleanupDatabase(); err != nil {
-		return nil, nil, fmt.Errorf("database cleanup failed: %v", err)
-	}
-
-	// Reinitialize components
-	newDB, newCli, err := ReinitializeWhatsAppComponents(ctx, chatStorageRepo)
-	if err != nil {
-		return nil, nil, fmt.Errorf("reinitialization failed: %v", err)
-	}
-
-	// Clean up temporary files
-	if err := CleanupTemporaryFiles(); err != nil {
-		logrus.Errorf("[%s] Temporary file cleanup failed (non-critical): %v", logPrefix, err)
-		// Don't return error for file cleanup as it's non-critical
-	}
-
-	logrus.Infof("[%s] Partial cleanup process finished successfully", logPrefix)
-	logrus.Infof("[%s] Device remains registered with WhatsApp server - ready for login", logPrefix)
-
-	return newDB, newCli, nil
-}
-
-// PerformPartialCleanupAndUpdateGlobals is a convenience function for partial cleanup
-func PerformPartialCleanupAndUpdateGlobals(ctx context.Context, logPrefix string, chatStorageRepo domainChatStorage.IChatStorageRepository) (*sqlstore.Container, *whatsmeow.Client, error) {
-	newDB, newCli, err := PerformPartialCleanup(ctx, logPrefix, chatStorageRepo)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	// Ensure global client is properly synchronized
-	UpdateGlobalClient(newCli, newDB)
-
-	return newDB, newCli, nil
-}
-
-// PerformCompleteCleanup performs all cleanup operations in the correct order
-func PerformCompleteCleanup(ctx context.Context, logPrefix string, chatStorageRepo domainChatStorage.IChatStorageRepository) (*sqlstore.Container, *whatsmeow.Client, error) {
-	logrus.Infof("[%s] Starting complete cleanup process...", logPrefix)
-
-	// Disconnect current client if it exists
-	if cli != nil {
-		cli.Disconnect()
-		logrus.Infof("[%s] Client disconnected", logPrefix)
-	}
-
-	// Truncate all chatstorage data before other cleanup
-	if chatStorageRepo != nil {
-		logrus.Infof("[%s] Truncating chatstorage data...", logPrefix)
-		if err := chatStorageRepo.TruncateAllDataWithLogging(logPrefix); err != nil {
-			logrus.Errorf("[%s] Failed to truncate chatstorage data: %v", logPrefix, err)
-			// Continue with cleanup even if chatstorage truncation fails
-		}
-	}
-
-	// Clean up database
-	if err := CleanupDatabase(); err != nil {
-		return nil, nil, fmt.Errorf("database cleanup failed: %v", err)
-	}
-
-	// Reinitialize components
-	newDB, newCli, err := ReinitializeWhatsAppComponents(ctx, chatStorageRepo)
-	if err != nil {
-		return nil, nil, fmt.Errorf("reinitialization failed: %v", err)
-	}
-
-	// Clean up temporary files
-	if err := CleanupTemporaryFiles(); err != nil {
-		logrus.Errorf("[%s] Temporary file cleanup failed (non-critical): %v", logPrefix, err)
-		// Don't return error for file cleanup as it's non-critical
-	}
-
-	logrus.Infof("[%s] Complete cleanup process finished successfully", logPrefix)
-	logrus.Infof("[%s] Application is ready for next login without restart", logPrefix)
-
-	return newDB, newCli, nil
-}
-
-// PerformCleanupAndUpdateGlobals is a convenience function that performs cleanup
-// and ensures global client synchronization
-func PerformCleanupAndUpdateGlobals(ctx context.Context, logPrefix string, chatStorageRepo domainChatStorage.IChatStorageRepository) (*sqlstore.Container, *whatsmeow.Client, error) {
-	newDB, newCli, err := PerformCompleteCleanup(ctx, logPrefix, chatStorageRepo)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	// Ensure global client is properly synchronized
-	UpdateGlobalClient(newCli, newDB)
-
-	return newDB, newCli, nil
-}
-
-// handleRemoteLogout performs cleanup when user logs out from their phone
-func handleRemoteLogout(ctx context.Context, chatStorageRepo domainChatStorage.IChatStorageRepository) {
-	logrus.Info("[REMOTE_LOGOUT] User logged out from phone - starting cleanup...")
-	logrus.Info("[REMOTE_LOGOUT] This will clear all WhatsApp session data and chat storage")
+// handleRemoteLogout performs cleanup when user logs out from their phone.
+// Only the affected user's store, chats and temporary files are removed, other sessions keep running.
+func handleRemoteLogout(ctx context.Context, session *UserSession) {
+	logPrefix := fmt.Sprintf("REMOTE_LOGOUT user %d", session.UserID)
+	logrus.Infof("[%s] User %s logged out from phone - starting cleanup...", logPrefix, session.Username)
 
 	// Log database state before cleanup
-	if db != nil {
-		devices, dbErr := db.GetAllDevices(ctx)
+	if session.DB != nil {
+		devices, dbErr := session.DB.GetAllDevices(ctx)
 		if dbErr != nil {
-			logrus.Errorf("[REMOTE_LOGOUT] Error getting devices before cleanup: %v", dbErr)
+			logrus.Errorf("[%s] Error getting devices before cleanup: %v", logPrefix, dbErr)
 		} else {
-			logrus.Infof("[REMOTE_LOGOUT] Devices before cleanup: %d found", len(devices))
+			logrus.Infof("[%s] Devices before cleanup: %d found", logPrefix, len(devices))
 		}
 	}
 
-	// Perform complete cleanup with global client synchronization
-	_, _, err := PerformCleanupAndUpdateGlobals(ctx, "REMOTE_LOGOUT", chatStorageRepo)
-	if err != nil {
-		logrus.Errorf("[REMOTE_LOGOUT] Cleanup failed: %v", err)
+	if _, err := GetSessionManager().ResetUserSession(ctx, session.UserID, logPrefix); err != nil {
+		logrus.Errorf("[%s] Cleanup failed: %v", logPrefix, err)
 		return
 	}
 
-	logrus.Info("[REMOTE_LOGOUT] Remote logout cleanup completed successfully")
+	websocket.BroadcastToUser(session.UserID, "LOGOUT_COMPLETE", "Logged out from phone, ready for a new login", nil)
+	logrus.Infof("[%s] Remote logout cleanup completed successfully", logPrefix)
 }
 
-// handler is the main event handler for WhatsApp events
-func handler(ctx context.Context, rawEvt any, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+// handler is the main event handler for WhatsApp events of a single session
+func handler(ctx context.Context, rawEvt any, session *UserSession) {
+	chatStorageRepo := session.ChatStorageRepo
+
 	switch evt := rawEvt.(type) {
 	case *events.DeleteForMe:
 		handleDeleteForMe(ctx, evt, chatStorageRepo)
 	case *events.AppStateSyncComplete:
 		handleAppStateSyncComplete(ctx, evt)
 	case *events.PairSuccess:
-		handlePairSuccess(ctx, evt)
+		handlePairSuccess(ctx, evt, session)
 	case *events.LoggedOut:
-		handleLoggedOut(ctx, chatStorageRepo)
-	case *events.Connected, *events.PushNameSetting:
-		handleConnectionEvents(ctx)
+		handleLoggedOut(ctx, evt, session)
+	case *events.Connected, *events.Disconnected, *events.PushNameSetting:
+		handleConnectionEvents(ctx, evt, session)
 	case *events.StreamReplaced:
-		handleStreamReplaced(ctx)
+		handleStreamReplaced(ctx, session)
 	case *events.Message:
-		handleMessage(ctx, evt, chatStorageRepo)
+		handleMessage(ctx, evt, session)
 	case *events.Receipt:
 		handleReceipt(ctx, evt)
 	case *events.Presence:
 		handlePresence(ctx, evt)
+	case *events.ChatPresence:
+		handleChatPresence(ctx, evt)
 	case *events.HistorySync:
 		handleHistorySync(ctx, evt, chatStorageRepo)
 	case *events.AppState:
@@ -518,7 +401,7 @@ func handleDeleteForMe(ctx context.Context, evt *events.DeleteForMe, chatStorage
 	}
 
 	// Send webhook notification for delete event
-	if len(config.WhatsappWebhook) > 0 {
+	if hasWebhookTargets(ctx, domainWebhook.EventMessageDeleted, message.ChatJID) {
 		go func() {
 			if err := forwardDeleteToWebhook(ctx, evt, message); err != nil {
 				log.Errorf("Failed to forward delete event to webhook: %v", err)
@@ -532,35 +415,107 @@ func handleAppStateSyncComplete(_ context.Context, evt *events.AppStateSyncCompl
 	log.Debugf("AppState sync complete event ignored in multi-user mode")
 }
 
-func handlePairSuccess(ctx context.Context, evt *events.PairSuccess) {
-	// Pairing success handling disabled in multi-user mode
-	log.Infof("Pair success event ignored in multi-user mode for ID: %s", evt.ID.String())
-	syncKeysDevice(ctx, db, keysDB)
+func handlePairSuccess(ctx context.Context, evt *events.PairSuccess, session *UserSession) {
+	log.Infof("Pair success for user %s (ID: %d) with device %s", session.Username, session.UserID, evt.ID.String())
+	syncKeysDevice(ctx, session.DB, session.KeysDB)
+
+	go emitLifecycleEvent(ctx, domainWebhook.EventPairSuccess, fmt.Sprintf("Successfully pair with %s", evt.ID.String()), map[string]any{
+		"device_id":     evt.ID.String(),
+		"business_name": evt.BusinessName,
+		"platform":      evt.Platform,
+	})
 }
 
-func handleLoggedOut(ctx context.Context, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+func handleLoggedOut(ctx context.Context, evt *events.LoggedOut, session *UserSession) {
 	logrus.Warn("[REMOTE_LOGOUT] Received LoggedOut event - user logged out from phone")
 
+	// Notify before cleanup so the payload still carries the device that was logged out
+	emitLifecycleEvent(ctx, domainWebhook.EventConnectionLoggedOut, "Logged out from WhatsApp", map[string]any{
+		"reason":     evt.Reason.String(),
+		"on_connect": evt.OnConnect,
+	})
+
 	// Perform comprehensive cleanup
-	handleRemoteLogout(ctx, chatStorageRepo)
+	handleRemoteLogout(ctx, session)
 
 	// Broadcast final notification that cleanup is complete and ready for new login
 	log.Infof("Remote logout cleanup completed - ready for new login")
 }
 
-func handleConnectionEvents(_ context.Context) {
-	// Connection events handling disabled in multi-user mode - would need per-user client context
-	log.Debugf("Connection event ignored in multi-user mode")
+func handleConnectionEvents(ctx context.Context, rawEvt any, session *UserSession) {
+	var event string
+	switch rawEvt.(type) {
+	case *events.Connected:
+		event = domainWebhook.EventConnectionConnected
+		session.ClearDisconnectReason()
+	case *events.Disconnected:
+		event = domainWebhook.EventConnectionDisconnected
+	default:
+		log.Debugf("Connection event %T ignored", rawEvt)
+		return
+	}
+
+	message := "Connected to WhatsApp"
+	if event == domainWebhook.EventConnectionDisconnected {
+		message = "Disconnected from WhatsApp"
+	}
+	go emitLifecycleEvent(ctx, event, message, nil)
 }
 
-func handleStreamReplaced(_ context.Context) {
-	os.Exit(0)
+// handleStreamReplaced disconnects only the session that was opened on another device,
+// every other user's session keeps running
+func handleStreamReplaced(ctx context.Context, session *UserSession) {
+	session.MarkDisconnected(DisconnectReasonStreamReplaced)
+	reclaim := config.WhatsappStreamReplacedPolicy == StreamReplacedPolicyReclaim
+	log.Warnf("Session of user %s (ID: %d) was opened on another device (policy: %s)", session.Username, session.UserID, config.WhatsappStreamReplacedPolicy)
+
+	var attempt int
+	var delay time.Duration
+	stopped := false
+	if reclaim {
+		attempt, delay, reclaim = session.nextReclaim()
+		stopped = !reclaim
+	}
+
+	go func() {
+		session.Client.Disconnect()
+		emitLifecycleEvent(ctx, domainWebhook.EventStreamReplaced, "WhatsApp session was opened on another device", map[string]any{
+			"reason":       DisconnectReasonStreamReplaced,
+			"auto_reclaim": reclaim,
+		})
+
+		if stopped {
+			log.Warnf("Stopped reclaiming session of user %s (ID: %d) after %d attempts", session.Username, session.UserID, attempt)
+			emitLifecycleEvent(ctx, domainWebhook.EventStreamReclaimStopped, "Stopped reclaiming the WhatsApp session, it keeps being opened on another device", map[string]any{
+				"reason":   DisconnectReasonStreamReplaced,
+				"attempts": attempt,
+			})
+			return
+		}
+		if !reclaim {
+			return
+		}
+
+		time.Sleep(delay)
+		// The user may have reconnected or logged out in the meantime
+		if !session.IsReplaced() || session.Client.IsConnected() {
+			return
+		}
+
+		log.Infof("Reclaiming session of user %s (ID: %d), attempt %d of %d", session.Username, session.UserID, attempt, maxStreamReplacedReclaims)
+		if err := session.Client.Connect(); err != nil {
+			log.Errorf("Failed to reclaim session of user %s (ID: %d): %v", session.Username, session.UserID, err)
+		}
+	}()
 }
 
-func handleMessage(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+func handleMessage(ctx context.Context, evt *events.Message, session *UserSession) {
+	chatStorageRepo := session.ChatStorageRepo
+
 	// Log message metadata
 	metaParts := buildMessageMetaParts(evt)
-	log.Infof("Received message %s from %s (%s): %+v",
+	log.Infof("[user %d] Received message %s from %s (%s): %+v",
+		session.UserID,
 		evt.Info.ID,
 		evt.Info.SourceString(),
 		strings.Join(metaParts, ", "),
@@ -573,13 +528,13 @@ func handleMessage(ctx context.Context, evt *events.Message, chatStorageRepo dom
 	}
 
 	// Handle image message if present
-	handleImageMessage(ctx, evt)
+	handleImageMessage(ctx, evt, session.Client)
 
 	// Auto-mark message as read if configured
-	handleAutoMarkRead(ctx, evt)
+	handleAutoMarkRead(ctx, evt, session.Client)
 
 	// Handle auto-reply if configured
-	handleAutoReply(ctx, evt, chatStorageRepo)
+	handleAutoReply(ctx, evt, session)
 
 	// Forward to webhook if configured
 	handleWebhookForward(ctx, evt)
@@ -602,9 +557,9 @@ func buildMessageMetaParts(evt *events.Message) []string {
 	return metaParts
 }
 
-func handleImageMessage(ctx context.Context, evt *events.Message) {
+func handleImageMessage(ctx context.Context, evt *events.Message, client *whatsmeow.Client) {
 	if img := evt.Message.GetImageMessage(); img != nil {
-		if path, err := utils.ExtractMedia(ctx, cli, config.PathStorages, img); err != nil {
+		if path, err := utils.ExtractMedia(ctx, client, config.PathStorages, img); err != nil {
 			log.Errorf("Failed to download image: %v", err)
 		} else {
 			log.Infof("Image downloaded to %s", path)
@@ -612,13 +567,13 @@ func handleImageMessage(ctx context.Context, evt *events.Message) {
 	}
 }
 
-func handleAutoMarkRead(_ context.Context, evt *events.Message) {
+func handleAutoMarkRead(_ context.Context, evt *events.Message, client *whatsmeow.Client) {
 	// Only mark read if auto-mark read is enabled and message is incoming
 	if !config.WhatsappAutoMarkRead || evt.Info.IsFromMe {
 		return
 	}
 
-	if cli == nil {
+	if client == nil {
 		log.Warnf("Client is nil, cannot mark message as read")
 		return
 	}
@@ -629,18 +584,13 @@ func handleAutoMarkRead(_ context.Context, evt *events.Message) {
 	chat := evt.Info.Chat
 	sender := evt.Info.Sender
 
-	if err := cli.MarkRead(messageIDs, timestamp, chat, sender); err != nil {
+	if err := client.MarkRead(messageIDs, timestamp, chat, sender); err != nil {
 		log.Warnf("Failed to mark message %s as read: %v", evt.Info.ID, err)
 	} else {
 		log.Debugf("Marked message %s as read", evt.Info.ID)
 	}
 }
 
-func handleAutoReply(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository) {
-	// Auto-reply disabled in multi-user mode - would need per-user client context
-	log.Debugf("Auto-reply not available in multi-user mode for message from %s", evt.Info.Sender.String())
-}
-
 func handleWebhookForward(ctx context.Context, evt *events.Message) {
 	// Skip webhook for specific protocol messages that shouldn't trigger webhooks
 	if protocolMessage := evt.Message.GetProtocolMessage(); protocolMessage != nil {
@@ -652,7 +602,7 @@ func handleWebhookForward(ctx context.Context, evt *events.Message) {
 		}
 	}
 
-	if len(config.WhatsappWebhook) > 0 &&
+	if hasWebhookTargets(ctx, messageWebhookEvent(evt), evt.Info.Chat.String()) &&
 		!strings.Contains(evt.Info.SourceString(), "broadcast") {
 		go func(evt *events.Message) {
 			if err := forwardMessageToWebhook(ctx, evt); err != nil {
@@ -675,7 +625,7 @@ func handleReceipt(ctx context.Context, evt *events.Receipt) {
 
 	// Forward receipt (ack) event to webhook if configured
 	// Note: Receipt events are not rate limited as they are critical for message delivery status
-	if len(config.WhatsappWebhook) > 0 && sendReceipt {
+	if sendReceipt && hasWebhookTargets(ctx, domainWebhook.EventMessageAck, evt.Chat.String()) {
 		go func(e *events.Receipt) {
 			if err := forwardReceiptToWebhook(ctx, e); err != nil {
 				logrus.Errorf("Failed to forward ack event to webhook: %v", err)
@@ -684,7 +634,7 @@ func handleReceipt(ctx context.Context, evt *events.Receipt) {
 	}
 }
 
-func handlePresence(_ context.Context, evt *events.Presence) {
+func handlePresence(ctx context.Context, evt *events.Presence) {
 	if evt.Unavailable {
 		if evt.LastSeen.IsZero() {
 			log.Infof("%s is now offline", evt.From)
@@ -694,6 +644,26 @@ func handlePresence(_ context.Context, evt *events.Presence) {
 	} else {
 		log.Infof("%s is now online", evt.From)
 	}
+
+	if hasWebhookTargets(ctx, domainWebhook.EventPresence, evt.From.String()) {
+		go func(e *events.Presence) {
+			if err := forwardPresenceToWebhook(ctx, e); err != nil {
+				logrus.Errorf("Failed to forward presence event to webhook: %v", err)
+			}
+		}(evt)
+	}
+}
+
+func handleChatPresence(ctx context.Context, evt *events.ChatPresence) {
+	log.Debugf("%s is %s in %s", evt.Sender, evt.State, evt.Chat)
+
+	if hasWebhookTargets(ctx, domainWebhook.EventPresence, evt.Chat.String()) {
+		go func(e *events.ChatPresence) {
+			if err := forwardChatPresenceToWebhook(ctx, e); err != nil {
+				logrus.Errorf("Failed to forward chat presence event to webhook: %v", err)
+			}
+		}(evt)
+	}
 }
 
 func handleHistorySync(ctx context.Context, evt *events.HistorySync, chatStorageRepo domainChatStorage.IChatStorageRepository) {
@@ -704,10 +674,14 @@ func handleHistorySync(ctx context.Context, evt *events.HistorySync, chatStorage
 
 	id := atomic.AddInt32(&historySyncID, 1)
 
-	// In multi-user system, we can't rely on global client for device ID
-	// Use generic filename with timestamp and sync ID
-	fileName := fmt.Sprintf("%s/history-%d-multiuser-%d-%s.json",
+	// Prefix the file with the owner so it can be removed when only that user logs out
+	var userID int
+	if session := UserSessionFromContext(ctx); session != nil {
+		userID = session.UserID
+	}
+	fileName := fmt.Sprintf("%s/history-user-%d-%d-%d-%s.json",
 		config.PathStorages,
+		userID,
 		startupTime,
 		id,
 		evt.Data.SyncType.String(),
@@ -871,11 +845,13 @@ func processConversationMessages(_ context.Context, data *waHistorySync.HistoryS
 				IsFromMe:      isFromMe,
 				MediaType:     mediaType,
 				Filename:      filename,
+				Mimetype:      utils.ExtractMediaMimetype(msg.GetMessage()),
 				URL:           url,
 				MediaKey:      mediaKey,
 				FileSHA256:    fileSHA256,
 				FileEncSHA256: fileEncSHA256,
 				FileLength:    fileLength,
+				Thumbnail:     utils.ExtractMediaThumbnail(msg.GetMessage()),
 			}
 
 			messageBatch = append(messageBatch, message)
@@ -966,7 +942,7 @@ func handleGroupInfo(ctx context.Context, evt *events.GroupInfo) {
 	}
 
 	// Forward group info event to webhook if configured
-	if len(config.WhatsappWebhook) > 0 {
+	if hasWebhookTargets(ctx, domainWebhook.EventGroupParticipants, evt.JID.String()) {
 		go func(e *events.GroupInfo) {
 			if err := forwardGroupInfoToWebhook(ctx, e); err != nil {
 				logrus.Errorf("Failed to forward group info event to webhook: %v", err)
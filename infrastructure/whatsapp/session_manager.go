@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
@@ -21,6 +22,113 @@ type UserSession struct {
 	DB              *sqlstore.Container
 	KeysDB          *sqlstore.Container
 	ChatStorageRepo domainChatStorage.IChatStorageRepository
+
+	// Why the session was last disconnected by the server, cleared once it connects again
+	stateMutex       sync.RWMutex
+	disconnectReason string
+	disconnectedAt   time.Time
+
+	// Automatic reclaims since the session was last replaced after a quiet period, see nextReclaim
+	reclaimAttempts int
+	lastReplacedAt  time.Time
+	reclaimAt       time.Time
+}
+
+// DisconnectReasonStreamReplaced is recorded when the session was opened on another device
+const DisconnectReasonStreamReplaced = "stream_replaced"
+
+// Policies for sessions opened on another device, see config.WhatsappStreamReplacedPolicy
+const (
+	StreamReplacedPolicyDisconnect = "disconnect" // Keep the session disconnected until the user reconnects it
+	StreamReplacedPolicyReclaim    = "reclaim"    // Reconnect the session after a short delay
+)
+
+// MarkDisconnected records why the server disconnected the session
+func (s *UserSession) MarkDisconnected(reason string) {
+	s.stateMutex.Lock()
+	defer s.stateMutex.Unlock()
+	s.disconnectReason = reason
+	s.disconnectedAt = time.Now()
+}
+
+// ClearDisconnectReason forgets the recorded disconnect reason, called once the session connects again
+func (s *UserSession) ClearDisconnectReason() {
+	s.stateMutex.Lock()
+	defer s.stateMutex.Unlock()
+	s.disconnectReason = ""
+	s.disconnectedAt = time.Time{}
+}
+
+// DisconnectReason returns the recorded disconnect reason and when it happened, empty when none is recorded
+func (s *UserSession) DisconnectReason() (string, time.Time) {
+	s.stateMutex.RLock()
+	defer s.stateMutex.RUnlock()
+	return s.disconnectReason, s.disconnectedAt
+}
+
+// IsReplaced reports whether the session was taken over by another device and has not connected since
+func (s *UserSession) IsReplaced() bool {
+	reason, _ := s.DisconnectReason()
+	return reason == DisconnectReasonStreamReplaced
+}
+
+// ShouldAutoReconnect reports whether background reconnection may bring the session back,
+// a replaced session is only reclaimed automatically when the policy allows it, once its reclaim is due
+// and as long as reclaiming has not stopped
+func (s *UserSession) ShouldAutoReconnect() bool {
+	if !s.IsReplaced() {
+		return true
+	}
+	if config.WhatsappStreamReplacedPolicy != StreamReplacedPolicyReclaim {
+		return false
+	}
+	s.stateMutex.RLock()
+	defer s.stateMutex.RUnlock()
+	return s.reclaimAttempts <= maxStreamReplacedReclaims && !time.Now().Before(s.reclaimAt)
+}
+
+// nextReclaim counts another replacement of the session and returns how long to wait before reclaiming it.
+// The wait doubles with every attempt so two devices holding the session do not keep taking it from each other,
+// ok is false once the attempts are used up. The count starts over when the session was last replaced long ago.
+func (s *UserSession) nextReclaim() (attempt int, delay time.Duration, ok bool) {
+	s.stateMutex.Lock()
+	defer s.stateMutex.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastReplacedAt) > streamReplacedReclaimReset {
+		s.reclaimAttempts = 0
+	}
+	s.lastReplacedAt = now
+	if s.reclaimAttempts >= maxStreamReplacedReclaims {
+		s.reclaimAttempts = maxStreamReplacedReclaims + 1
+		return maxStreamReplacedReclaims, 0, false
+	}
+	s.reclaimAttempts++
+	delay = streamReplacedReclaimDelay << (s.reclaimAttempts - 1)
+	s.reclaimAt = now.Add(delay)
+	return s.reclaimAttempts, delay, true
+}
+
+// ResetReclaimAttempts lets the session be reclaimed automatically again, called when the user reconnects it
+func (s *UserSession) ResetReclaimAttempts() {
+	s.stateMutex.Lock()
+	defer s.stateMutex.Unlock()
+	s.reclaimAttempts = 0
+	s.lastReplacedAt = time.Time{}
+	s.reclaimAt = time.Time{}
+}
+
+type userSessionContextKey struct{}
+
+// ContextWithUserSession returns a copy of ctx carrying the session an event belongs to
+func ContextWithUserSession(ctx context.Context, session *UserSession) context.Context {
+	return context.WithValue(ctx, userSessionContextKey{}, session)
+}
+
+// UserSessionFromContext returns the session an event belongs to, or nil when the context carries none
+func UserSessionFromContext(ctx context.Context) *UserSession {
+	session, _ := ctx.Value(userSessionContextKey{}).(*UserSession)
+	return session
 }
 
 // SessionManager manages WhatsApp sessions for multiple users
@@ -51,22 +159,12 @@ func (sm *SessionManager) GetUserSession(userID int) *UserSession {
 	return sm.sessions[userID]
 }
 
-// CreateUserSession creates a new WhatsApp session for a user
-func (sm *SessionManager) CreateUserSession(ctx context.Context, userID int, username string, chatStorageRepo domainChatStorage.IChatStorageRepository) (*UserSession, error) {
-	sm.mutex.Lock()
-	defer sm.mutex.Unlock()
-
-	// Check if session already exists
-	if session, exists := sm.sessions[userID]; exists {
-		logrus.Infof("Session already exists for user %s (ID: %d)", username, userID)
-		return session, nil
-	}
-
-	// Create user-specific database URIs
-	userDBURI := fmt.Sprintf("file:%s/user_%d_whatsapp.db?_foreign_keys=on",
+// userDatabaseURIs returns the URIs of the WhatsApp store and keys databases of a user,
+// the keys URI is empty when no keys database is configured
+func userDatabaseURIs(userID int) (userDBURI string, userKeysDBURI string) {
+	userDBURI = fmt.Sprintf("file:%s/user_%d_whatsapp.db?_foreign_keys=on",
 		filepath.Dir(config.DBURI[5:]), userID) // Remove "file:" prefix
 
-	var userKeysDBURI string
 	if config.DBKeysURI != "" {
 		if config.DBKeysURI == ":memory:" || config.DBKeysURI == "file::memory:?cache=shared&_foreign_keys=on" {
 			// Use per-user persistent keys database instead of memory to avoid FK constraints issues
@@ -77,6 +175,22 @@ func (sm *SessionManager) CreateUserSession(ctx context.Context, userID int, use
 		}
 	}
 
+	return userDBURI, userKeysDBURI
+}
+
+// CreateUserSession creates a new WhatsApp session for a user
+func (sm *SessionManager) CreateUserSession(ctx context.Context, userID int, username string, chatStorageRepo domainChatStorage.IChatStorageRepository) (*UserSession, error) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	// Check if session already exists
+	if session, exists := sm.sessions[userID]; exists {
+		logrus.Infof("Session already exists for user %s (ID: %d)", username, userID)
+		return session, nil
+	}
+
+	userDBURI, userKeysDBURI := userDatabaseURIs(userID)
+
 	logrus.Infof("Creating WhatsApp session for user %s (ID: %d)", username, userID)
 	logrus.Infof("User DB URI: %s", userDBURI)
 	logrus.Infof("User Keys DB URI: %s", userKeysDBURI)
@@ -88,19 +202,23 @@ func (sm *SessionManager) CreateUserSession(ctx context.Context, userID int, use
 		userKeysDB = InitWaDB(ctx, userKeysDBURI)
 	}
 
-	// Initialize user-specific client
-	userClient := InitWaCLI(ctx, userDB, userKeysDB, chatStorageRepo)
+	// Scope chat storage to this user so tenants never share chats or messages
+	if chatStorageRepo != nil {
+		chatStorageRepo = chatStorageRepo.ForUser(userID)
+	}
 
 	// Create user session
 	session := &UserSession{
 		UserID:          userID,
 		Username:        username,
-		Client:          userClient,
 		DB:              userDB,
 		KeysDB:          userKeysDB,
 		ChatStorageRepo: chatStorageRepo,
 	}
 
+	// Initialize user-specific client with events bound to this session
+	InitUserWaCLI(ctx, session)
+
 	// Store session
 	sm.sessions[userID] = session
 
@@ -138,6 +256,50 @@ func (sm *SessionManager) RemoveUserSession(userID int) error {
 	return nil
 }
 
+// ResetUserSession wipes a user's WhatsApp store, scoped chat data and temporary files,
+// then replaces the session with a fresh, unpaired one. Sessions of other users are left untouched.
+func (sm *SessionManager) ResetUserSession(ctx context.Context, userID int, logPrefix string) (*UserSession, error) {
+	sm.mutex.Lock()
+	session, exists := sm.sessions[userID]
+	delete(sm.sessions, userID)
+	sm.mutex.Unlock()
+
+	if !exists {
+		return nil, fmt.Errorf("session not found for user ID: %d", userID)
+	}
+
+	if session.Client != nil {
+		session.Client.Disconnect()
+		logrus.Infof("[%s] Client disconnected", logPrefix)
+	}
+
+	// Truncate the user's chatstorage data before other cleanup
+	if session.ChatStorageRepo != nil {
+		logrus.Infof("[%s] Truncating chatstorage data...", logPrefix)
+		if err := session.ChatStorageRepo.TruncateAllDataWithLogging(logPrefix); err != nil {
+			logrus.Errorf("[%s] Failed to truncate chatstorage data: %v", logPrefix, err)
+			// Continue with cleanup even if chatstorage truncation fails
+		}
+	}
+
+	if err := CleanupUserDatabase(session); err != nil {
+		return nil, fmt.Errorf("database cleanup failed: %v", err)
+	}
+
+	if err := CleanupUserTemporaryFiles(userID); err != nil {
+		logrus.Errorf("[%s] Temporary file cleanup failed (non-critical): %v", logPrefix, err)
+		// Don't return error for file cleanup as it's non-critical
+	}
+
+	newSession, err := sm.CreateUserSession(ctx, userID, session.Username, session.ChatStorageRepo)
+	if err != nil {
+		return nil, fmt.Errorf("reinitialization failed: %v", err)
+	}
+
+	logrus.Infof("[%s] Session reset, user %s is ready for a new login", logPrefix, session.Username)
+	return newSession, nil
+}
+
 // GetUserClient returns the WhatsApp client for a specific user
 func (sm *SessionManager) GetUserClient(userID int) *whatsmeow.Client {
 	session := sm.GetUserSession(userID)
@@ -209,6 +371,8 @@ func (sm *SessionManager) ManualReconnectUser(userID int) error {
 	}
 
 	logrus.Infof("[MANUAL-RECONNECT] Connecting user %d (%s) to WhatsApp server...", userID, session.Username)
+	session.ClearDisconnectReason()
+	session.ResetReclaimAttempts()
 
 	if err := session.Client.Connect(); err != nil {
 		logrus.Errorf("[MANUAL-RECONNECT] Failed to connect user %d (%s): %v", userID, session.Username, err)
@@ -246,6 +410,10 @@ func (sm *SessionManager) ReconnectAllLoggedInUsers() {
 
 	for userID, session := range sm.sessions {
 		if session.Client != nil {
+			if !session.ShouldAutoReconnect() {
+				logrus.Infof("[RECONNECT-LOGGED-IN] User %d (%s) was replaced by another device, keeping disconnected", userID, session.Username)
+				continue
+			}
 			if session.Client.IsLoggedIn() {
 				loggedInCount++
 				if !session.Client.IsConnected() {
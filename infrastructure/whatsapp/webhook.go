@@ -7,53 +7,188 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
-func submitWebhook(ctx context.Context, payload map[string]any, url string) error {
-	client := &http.Client{Timeout: 10 * time.Second}
+// webhookRepo holds the per-user webhook endpoints; nil disables per-user delivery
+var webhookRepo domainWebhook.IWebhookRepository
 
-	postBody, err := json.Marshal(payload)
-	if err != nil {
-		return pkgError.WebhookError(fmt.Sprintf("Failed to marshal body: %v", err))
+// SetWebhookRepository configures where per-user webhook endpoints are looked up
+func SetWebhookRepository(repo domainWebhook.IWebhookRepository) {
+	webhookRepo = repo
+}
+
+// webhookTarget is a single delivery destination together with its signing secret and payload format
+type webhookTarget struct {
+	WebhookID int // 0 for the globally configured URLs
+	URL       string
+	Secret    string
+	Format    string
+}
+
+// webhookEvent is a single event to deliver, rendered for every endpoint in the format it opted into
+type webhookEvent struct {
+	Name      string
+	ChatJID   string // Empty for events that do not belong to a chat
+	Timestamp time.Time
+	Payload   map[string]any
+	// Flat marks events whose legacy shape is the payload itself instead of {event, payload, timestamp}
+	Flat bool
+}
+
+// getWebhookTargets resolves the endpoints that should receive an event from chatJID for the session in ctx.
+// User sessions only deliver to their own endpoints; the legacy global client keeps using the configured URLs.
+// chatJID may be empty for events that do not belong to a chat.
+func getWebhookTargets(ctx context.Context, event string, chatJID string) []webhookTarget {
+	session := UserSessionFromContext(ctx)
+	if session == nil || session.UserID == 0 {
+		targets := make([]webhookTarget, 0, len(config.WhatsappWebhook))
+		for _, url := range config.WhatsappWebhook {
+			targets = append(targets, webhookTarget{URL: url, Secret: config.WhatsappWebhookSecret, Format: config.WhatsappWebhookFormat})
+		}
+		return targets
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if webhookRepo == nil {
+		return nil
+	}
+
+	webhooks, err := webhookRepo.GetActiveByUser(session.UserID)
 	if err != nil {
-		return pkgError.WebhookError(fmt.Sprintf("error when create http object %v", err))
+		logrus.Errorf("Failed to load webhooks for user %d: %v", session.UserID, err)
+		return nil
 	}
 
-	secretKey := []byte(config.WhatsappWebhookSecret)
-	signature, err := utils.GetMessageDigestOrSignature(postBody, secretKey)
+	var targets []webhookTarget
+	for _, webhook := range webhooks {
+		if webhook.Matches(event, chatJID) {
+			targets = append(targets, webhookTarget{WebhookID: webhook.ID, URL: webhook.URL, Secret: webhook.Secret, Format: webhook.Format})
+		}
+	}
+	return targets
+}
+
+// hasWebhookTargets reports whether any endpoint would receive the event from chatJID
+func hasWebhookTargets(ctx context.Context, event string, chatJID string) bool {
+	return len(getWebhookTargets(ctx, event, chatJID)) > 0
+}
+
+// dispatchWebhook delivers the event to every endpoint subscribed to it.
+// With an outbox configured the deliveries are queued and retried by the outbox worker,
+// otherwise they are posted in-process. It only fails when every endpoint failed, partial failures are logged.
+func dispatchWebhook(ctx context.Context, evt webhookEvent) error {
+	targets := getWebhookTargets(ctx, evt.Name, evt.ChatJID)
+	logrus.Infof("Forwarding %s event to %d webhook(s)", evt.Name, len(targets))
+
+	// Every endpoint receives the same event ID so consumers can deduplicate retries and replays
+	eventID := uuid.NewString()
+	bodies := make(map[string][]byte)
+	for _, target := range targets {
+		if _, ok := bodies[target.Format]; ok {
+			continue
+		}
+		body, err := renderWebhookBody(ctx, evt, eventID, target.Format)
+		if err != nil {
+			return err
+		}
+		bodies[target.Format] = body
+	}
+
+	if webhookOutbox != nil {
+		return enqueueWebhook(ctx, evt.Name, targets, bodies)
+	}
+
+	var errors []error
+	for _, target := range targets {
+		if err := submitWebhook(ctx, bodies[target.Format], target.URL, target.Secret); err != nil {
+			errors = append(errors, fmt.Errorf("webhook %s failed: %w", target.URL, err))
+		}
+	}
+
+	if len(errors) > 0 && len(errors) == len(targets) {
+		var errMessages []string
+		for _, err := range errors {
+			errMessages = append(errMessages, err.Error())
+		}
+		return fmt.Errorf("all webhook URLs failed: %s", strings.Join(errMessages, "; "))
+	}
+
+	if len(errors) > 0 {
+		logrus.Warnf("Some webhook URLs failed for %s event: %v", evt.Name, errors)
+	}
+
+	return nil
+}
+
+// renderWebhookBody marshals the event either as a versioned envelope or in its legacy shape
+func renderWebhookBody(ctx context.Context, evt webhookEvent, eventID string, format string) ([]byte, error) {
+	timestamp := evt.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	var body any
+	switch format {
+	case domainWebhook.FormatV1:
+		envelope := domainWebhook.Envelope{
+			Event:         evt.Name,
+			EventID:       eventID,
+			SchemaVersion: domainWebhook.SchemaVersion,
+			Timestamp:     timestamp.Format(time.RFC3339),
+			Payload:       evt.Payload,
+		}
+		if session := UserSessionFromContext(ctx); session != nil {
+			envelope.UserID = session.UserID
+			if session.Client != nil && session.Client.Store.ID != nil {
+				envelope.DeviceID = session.Client.Store.ID.String()
+			}
+		}
+		body = envelope
+	default:
+		if evt.Flat {
+			body = evt.Payload
+		} else {
+			body = map[string]any{
+				"event":     evt.Name,
+				"payload":   evt.Payload,
+				"timestamp": timestamp.Format(time.RFC3339),
+			}
+		}
+	}
+
+	postBody, err := json.Marshal(body)
 	if err != nil {
-		return pkgError.WebhookError(fmt.Sprintf("error when create signature %v", err))
+		return nil, pkgError.WebhookError(fmt.Sprintf("Failed to marshal body: %v", err))
 	}
+	return postBody, nil
+}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Hub-Signature-256", fmt.Sprintf("sha256=%s", signature))
+func submitWebhook(ctx context.Context, postBody []byte, url string, secret string) error {
+	var userID int
+	if session := UserSessionFromContext(ctx); session != nil {
+		userID = session.UserID
+	}
 
+	var err error
 	var attempt int
 	var maxAttempts = 5
 	var sleepDuration = 1 * time.Second
 
 	for attempt = 0; attempt < maxAttempts; attempt++ {
-		// Create new request body for each attempt
-		req.Body = io.NopCloser(bytes.NewBuffer(postBody))
-		resp, err := client.Do(req)
+		_, err = postWebhook(ctx, url, secret, postBody)
 		if err == nil {
-			defer resp.Body.Close()
-			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-				logrus.Infof("Successfully submitted webhook on attempt %d", attempt+1)
-				return nil
-			}
-			err = fmt.Errorf("webhook returned status %d", resp.StatusCode)
+			logrus.Infof("Successfully submitted webhook for user %d on attempt %d", userID, attempt+1)
+			return nil
 		}
-		logrus.Warnf("Attempt %d to submit webhook failed: %v", attempt+1, err)
+		logrus.Warnf("Attempt %d to submit webhook for user %d failed: %v", attempt+1, userID, err)
 		if attempt < maxAttempts-1 {
 			time.Sleep(sleepDuration)
 			sleepDuration *= 2
@@ -62,3 +197,53 @@ func submitWebhook(ctx context.Context, payload map[string]any, url string) erro
 
 	return pkgError.WebhookError(fmt.Sprintf("error when submit webhook after %d attempts: %v", attempt, err))
 }
+
+// postWebhook performs a single signed delivery attempt and returns the response status code
+func postWebhook(ctx context.Context, url string, secret string, postBody []byte) (int, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(postBody))
+	if err != nil {
+		return 0, pkgError.WebhookError(fmt.Sprintf("error when create http object %v", err))
+	}
+
+	signature, err := utils.GetMessageDigestOrSignature(postBody, []byte(secret))
+	if err != nil {
+		return 0, pkgError.WebhookError(fmt.Sprintf("error when create signature %v", err))
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Hub-Signature-256", fmt.Sprintf("sha256=%s", signature))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// SendTestWebhook delivers a single test event to an endpoint without retrying
+func SendTestWebhook(ctx context.Context, webhook domainWebhook.Webhook) (int, error) {
+	evt := webhookEvent{
+		Name: domainWebhook.EventWebhookTest,
+		Payload: map[string]any{
+			"webhook_id": webhook.ID,
+			"user_id":    webhook.UserID,
+			"message":    "This is a test event",
+		},
+	}
+
+	postBody, err := renderWebhookBody(ctx, evt, uuid.NewString(), webhook.Format)
+	if err != nil {
+		return 0, err
+	}
+
+	return postWebhook(ctx, webhook.URL, webhook.Secret, postBody)
+}
@@ -0,0 +1,201 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	webhookOutboxPollInterval = 1 * time.Second
+	webhookOutboxBatchSize    = 20
+	webhookRetryBaseDelay     = 5 * time.Second
+	webhookRetryMaxDelay      = 30 * time.Minute
+	// Delivered deliveries are kept this long so their attempts can be reviewed
+	webhookDeliveredRetention     = 7 * 24 * time.Hour
+	webhookDeliveredPruneInterval = time.Hour
+)
+
+// webhookOutbox persists deliveries across restarts; nil falls back to in-process retries
+var webhookOutbox domainWebhook.IWebhookOutboxRepository
+
+// SetWebhookOutbox enables durable webhook delivery through the given outbox
+func SetWebhookOutbox(outbox domainWebhook.IWebhookOutboxRepository) {
+	webhookOutbox = outbox
+}
+
+// enqueueWebhook stores one delivery per target with the body rendered in its format; the outbox worker posts them
+func enqueueWebhook(ctx context.Context, event string, targets []webhookTarget, bodies map[string][]byte) error {
+	var userID int
+	if session := UserSessionFromContext(ctx); session != nil {
+		userID = session.UserID
+	}
+
+	var err error
+	var failed int
+	for _, target := range targets {
+		delivery := &domainWebhook.Delivery{
+			UserID:    userID,
+			WebhookID: target.WebhookID,
+			Event:     event,
+			URL:       target.URL,
+			Payload:   string(bodies[target.Format]),
+		}
+		if err = webhookOutbox.Enqueue(delivery); err != nil {
+			logrus.Errorf("Failed to enqueue %s webhook for user %d to %s: %v", event, userID, target.URL, err)
+			failed++
+		}
+	}
+
+	if failed > 0 && failed == len(targets) {
+		return pkgError.WebhookError(fmt.Sprintf("failed to enqueue webhook: %v", err))
+	}
+
+	return nil
+}
+
+// RunWebhookOutboxWorker posts due deliveries until ctx is cancelled.
+// Pending deliveries left behind by a previous process are picked up on start.
+func RunWebhookOutboxWorker(ctx context.Context) {
+	if webhookOutbox == nil {
+		return
+	}
+
+	ticker := time.NewTicker(webhookOutboxPollInterval)
+	defer ticker.Stop()
+	pruneTicker := time.NewTicker(webhookDeliveredPruneInterval)
+	defer pruneTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			processWebhookOutbox(ctx)
+		case <-pruneTicker.C:
+			if err := webhookOutbox.PruneDelivered(time.Now().Add(-webhookDeliveredRetention)); err != nil {
+				logrus.Errorf("Failed to prune delivered webhooks: %v", err)
+			}
+		}
+	}
+}
+
+func processWebhookOutbox(ctx context.Context) {
+	deliveries, err := webhookOutbox.GetDue(time.Now(), webhookOutboxBatchSize)
+	if err != nil {
+		logrus.Errorf("Failed to load webhook outbox: %v", err)
+		return
+	}
+
+	// Deliver concurrently so one slow receiver does not hold up the others
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func(delivery domainWebhook.Delivery) {
+			defer wg.Done()
+			deliverFromOutbox(ctx, delivery)
+		}(delivery)
+	}
+	wg.Wait()
+}
+
+func deliverFromOutbox(ctx context.Context, delivery domainWebhook.Delivery) {
+	target, dropReason, err := resolveDeliveryTarget(delivery)
+	if dropReason != "" {
+		logrus.Warnf("Dropping webhook delivery %d to dead letters: %s", delivery.ID, dropReason)
+		if err := webhookOutbox.MoveToDeadLetter(delivery.ID, delivery.Attempts, dropReason, 0); err != nil {
+			logrus.Errorf("Failed to dead-letter webhook delivery %d: %v", delivery.ID, err)
+		}
+		return
+	}
+
+	// A failed lookup of the webhook is retried like a failed post
+	startedAt := time.Now()
+	var statusCode int
+	if err == nil {
+		statusCode, err = postWebhook(ctx, target.URL, target.Secret, []byte(delivery.Payload))
+	}
+	attempts := delivery.Attempts + 1
+	recordAttempt(delivery.ID, statusCode, err, startedAt)
+
+	if err == nil {
+		logrus.Infof("Successfully submitted %s webhook for user %d on attempt %d", delivery.Event, delivery.UserID, attempts)
+		if err := webhookOutbox.MarkDelivered(delivery.ID, attempts, statusCode); err != nil {
+			logrus.Errorf("Failed to mark webhook %d as delivered: %v", delivery.ID, err)
+		}
+		return
+	}
+
+	if attempts >= config.WhatsappWebhookMaxAttempts {
+		logrus.Errorf("Webhook delivery %d for user %d failed after %d attempts, moved to dead letters: %v", delivery.ID, delivery.UserID, attempts, err)
+		if err := webhookOutbox.MoveToDeadLetter(delivery.ID, attempts, err.Error(), statusCode); err != nil {
+			logrus.Errorf("Failed to dead-letter webhook delivery %d: %v", delivery.ID, err)
+		}
+		return
+	}
+
+	delay := webhookRetryDelay(attempts)
+	logrus.Warnf("Attempt %d to submit webhook %d for user %d failed, retrying in %s: %v", attempts, delivery.ID, delivery.UserID, delay, err)
+	if err := webhookOutbox.MarkFailed(delivery.ID, attempts, err.Error(), statusCode, time.Now().Add(delay)); err != nil {
+		logrus.Errorf("Failed to reschedule webhook delivery %d: %v", delivery.ID, err)
+	}
+}
+
+// recordAttempt keeps the outcome of a try, so deliveries and dead letters show how each attempt went
+func recordAttempt(id int64, statusCode int, err error, startedAt time.Time) {
+	record := domainWebhook.DeliveryAttempt{
+		OutboxID:    id,
+		StatusCode:  statusCode,
+		DurationMs:  time.Since(startedAt).Milliseconds(),
+		AttemptedAt: startedAt,
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	if err := webhookOutbox.RecordAttempt(&record); err != nil {
+		logrus.Warnf("Failed to record an attempt of webhook delivery %d: %v", id, err)
+	}
+}
+
+// resolveDeliveryTarget looks up the current URL and secret so rotated secrets apply to queued deliveries.
+// It returns a reason to give up on the delivery when it can never be sent, and an error when the lookup failed.
+func resolveDeliveryTarget(delivery domainWebhook.Delivery) (webhookTarget, string, error) {
+	if delivery.WebhookID == 0 {
+		return webhookTarget{URL: delivery.URL, Secret: config.WhatsappWebhookSecret}, "", nil
+	}
+
+	if webhookRepo == nil {
+		return webhookTarget{}, "webhook repository is not configured", nil
+	}
+
+	webhook, err := webhookRepo.GetByID(delivery.UserID, delivery.WebhookID)
+	if err != nil {
+		return webhookTarget{}, "", fmt.Errorf("failed to look up webhook %d: %w", delivery.WebhookID, err)
+	}
+	if webhook == nil {
+		return webhookTarget{}, fmt.Sprintf("webhook %d no longer exists", delivery.WebhookID), nil
+	}
+	if !webhook.IsActive {
+		return webhookTarget{}, fmt.Sprintf("webhook %d is disabled", delivery.WebhookID), nil
+	}
+
+	return webhookTarget{WebhookID: webhook.ID, URL: webhook.URL, Secret: webhook.Secret}, "", nil
+}
+
+// webhookRetryDelay doubles the wait after every failed attempt up to webhookRetryMaxDelay
+func webhookRetryDelay(attempts int) time.Duration {
+	delay := webhookRetryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= webhookRetryMaxDelay {
+			return webhookRetryMaxDelay
+		}
+	}
+	return delay
+}
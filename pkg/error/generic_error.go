@@ -1,6 +1,9 @@
 package error
 
-import "net/http"
+import (
+	"fmt"
+	"net/http"
+)
 
 // GenericError represent as the contract of generic error
 type GenericError interface {
@@ -42,3 +45,94 @@ func (e ContextError) ErrCode() string {
 func (e ContextError) StatusCode() int {
 	return http.StatusRequestTimeout
 }
+
+type NotFoundError string
+
+// Error for complying the error interface
+func (e NotFoundError) Error() string {
+	return string(e)
+}
+
+// ErrCode will return the error code based on the error data type
+func (e NotFoundError) ErrCode() string {
+	return "NOT_FOUND"
+}
+
+// StatusCode will return the HTTP status code based on the error data type
+func (e NotFoundError) StatusCode() int {
+	return http.StatusNotFound
+}
+
+type ForbiddenError string
+
+// Error for complying the error interface
+func (e ForbiddenError) Error() string {
+	return string(e)
+}
+
+// ErrCode will return the error code based on the error data type
+func (e ForbiddenError) ErrCode() string {
+	return "FORBIDDEN"
+}
+
+// StatusCode will return the HTTP status code based on the error data type
+func (e ForbiddenError) StatusCode() int {
+	return http.StatusForbidden
+}
+
+// RateLimitError is returned when a request would exceed one of the user's sending limits
+type RateLimitError struct {
+	Limit string `json:"limit"`
+	Max   int64  `json:"max"`
+	// RetryAfter is the number of seconds until the request fits the limit again
+	RetryAfter int `json:"retry_after"`
+}
+
+// Error for complying the error interface
+func (e RateLimitError) Error() string {
+	return fmt.Sprintf("%s limit of %d reached, retry after %d seconds", e.Limit, e.Max, e.RetryAfter)
+}
+
+// ErrCode will return the error code based on the error data type
+func (e RateLimitError) ErrCode() string {
+	return "RATE_LIMIT_EXCEEDED"
+}
+
+// StatusCode will return the HTTP status code based on the error data type
+func (e RateLimitError) StatusCode() int {
+	return http.StatusTooManyRequests
+}
+
+type ConflictError string
+
+// Error for complying the error interface
+func (e ConflictError) Error() string {
+	return string(e)
+}
+
+// ErrCode will return the error code based on the error data type
+func (e ConflictError) ErrCode() string {
+	return "CONFLICT"
+}
+
+// StatusCode will return the HTTP status code based on the error data type
+func (e ConflictError) StatusCode() int {
+	return http.StatusConflict
+}
+
+type UnprocessableError string
+
+// Error for complying the error interface
+func (e UnprocessableError) Error() string {
+	return string(e)
+}
+
+// ErrCode will return the error code based on the error data type
+func (e UnprocessableError) ErrCode() string {
+	return "UNPROCESSABLE_ENTITY"
+}
+
+// StatusCode will return the HTTP status code based on the error data type
+func (e UnprocessableError) StatusCode() int {
+	return http.StatusUnprocessableEntity
+}
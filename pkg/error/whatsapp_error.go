@@ -75,3 +75,20 @@ const (
 	ErrUserNotRegistered = InvalidJID("user is not registered")
 	ErrWaCLI             = WaCliError("your WhatsApp CLI is invalid or empty")
 )
+
+type MediaExpiredError string
+
+// Error for complying the error interface
+func (e MediaExpiredError) Error() string {
+	return string(e)
+}
+
+// ErrCode will return the error code based on the error data type
+func (e MediaExpiredError) ErrCode() string {
+	return "MEDIA_EXPIRED"
+}
+
+// StatusCode will return the HTTP status code based on the error data type
+func (e MediaExpiredError) StatusCode() int {
+	return http.StatusGone
+}
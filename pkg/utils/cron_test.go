@@ -0,0 +1,134 @@
+package utils_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type CronTestSuite struct {
+	suite.Suite
+}
+
+func (suite *CronTestSuite) TestParseCron() {
+	tests := []struct {
+		name       string
+		expression string
+		wantErr    string
+	}{
+		{name: "should parse every minute", expression: "* * * * *"},
+		{name: "should parse lists ranges and steps", expression: "*/15 9-17 1,15 jan-jun mon-fri"},
+		{name: "should parse macro", expression: "@daily"},
+		{name: "should error with missing field", expression: "0 9 * *", wantErr: "expected 5 fields, got 4"},
+		{name: "should error with value out of range", expression: "60 * * * *", wantErr: "minute: value 60 out of range 0-59"},
+		{name: "should error with zero step", expression: "*/0 * * * *", wantErr: `minute: invalid step "0"`},
+		{name: "should error with reversed range", expression: "* 17-9 * * *", wantErr: `hour: invalid range "17-9"`},
+		{name: "should error with unknown name", expression: "* * * * funday", wantErr: `day of week: invalid value "funday"`},
+	}
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			_, err := utils.ParseCron(tt.expression)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func (suite *CronTestSuite) TestCronNext() {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	assert.NoError(suite.T(), err)
+
+	tests := []struct {
+		name       string
+		expression string
+		after      time.Time
+		want       time.Time
+	}{
+		{
+			name:       "should run next minute",
+			expression: "* * * * *",
+			after:      time.Date(2025, 8, 1, 9, 30, 15, 0, time.UTC),
+			want:       time.Date(2025, 8, 1, 9, 31, 0, 0, time.UTC),
+		},
+		{
+			name:       "should run next weekday morning",
+			expression: "0 9 * * mon-fri",
+			after:      time.Date(2025, 8, 1, 9, 0, 0, 0, time.UTC), // Friday
+			want:       time.Date(2025, 8, 4, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "should run in the given location",
+			expression: "30 8 * * *",
+			after:      time.Date(2025, 8, 1, 10, 0, 0, 0, jakarta),
+			want:       time.Date(2025, 8, 2, 8, 30, 0, 0, jakarta),
+		},
+		{
+			name:       "should match either day field when both are set",
+			expression: "0 0 13 * fri",
+			after:      time.Date(2025, 8, 2, 0, 0, 0, 0, time.UTC),
+			want:       time.Date(2025, 8, 8, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "should treat 7 as sunday",
+			expression: "0 12 * * 7",
+			after:      time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC),
+			want:       time.Date(2025, 8, 3, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "should skip months without the day",
+			expression: "0 0 31 * *",
+			after:      time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC),
+			want:       time.Date(2025, 10, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "should return zero when nothing matches",
+			expression: "0 0 30 2 *",
+			after:      time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC),
+			want:       time.Time{},
+		},
+	}
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			schedule, err := utils.ParseCron(tt.expression)
+			assert.NoError(t, err)
+			assert.True(t, tt.want.Equal(schedule.Next(tt.after)), "got %s", schedule.Next(tt.after))
+		})
+	}
+}
+
+func (suite *CronTestSuite) TestParseLocalTime() {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	assert.NoError(suite.T(), err)
+
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "should parse RFC3339 with its own offset", value: "2025-08-01T09:30:00Z", want: time.Date(2025, 8, 1, 9, 30, 0, 0, time.UTC)},
+		{name: "should parse local time in location", value: "2025-08-01 09:30", want: time.Date(2025, 8, 1, 9, 30, 0, 0, jakarta)},
+		{name: "should error with date only", value: "2025-08-01", wantErr: true},
+	}
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			got, err := utils.ParseLocalTime(tt.value, jakarta)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "got %s", got)
+		})
+	}
+}
+
+func TestCronTestSuite(t *testing.T) {
+	suite.Run(t, new(CronTestSuite))
+}
@@ -232,7 +232,54 @@ func ContainsMention(message string) []string {
 	return phoneNumbers
 }
 
+// templatePlaceholder matches {{name}} placeholders, spaces inside the braces are allowed
+var templatePlaceholder = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+// TemplateVariables returns the distinct placeholder names of a template in order of appearance
+func TemplateVariables(template string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, match := range templatePlaceholder.FindAllStringSubmatch(template, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
+
+// RenderTemplate replaces the placeholders of a template with their variables, unknown placeholders are kept as is
+func RenderTemplate(template string, variables map[string]string) string {
+	return templatePlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		name := templatePlaceholder.FindStringSubmatch(placeholder)[1]
+		if value, ok := variables[name]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
+
 func DownloadImageFromURL(url string) ([]byte, string, error) {
+	return downloadImage(url, map[string]bool{
+		".jpg":  true,
+		".jpeg": true,
+		".png":  true,
+		".webp": true,
+	})
+}
+
+// DownloadStickerFromURL downloads an image that can be converted to a sticker, GIFs included
+func DownloadStickerFromURL(url string) ([]byte, string, error) {
+	return downloadImage(url, map[string]bool{
+		".jpg":  true,
+		".jpeg": true,
+		".png":  true,
+		".webp": true,
+		".gif":  true,
+	})
+}
+
+func downloadImage(url string, allowedExtensions map[string]bool) ([]byte, string, error) {
 	client := &http.Client{
 		Timeout: 30 * time.Second,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
@@ -267,12 +314,6 @@ func DownloadImageFromURL(url string) ([]byte, string, error) {
 	fileName := segments[len(segments)-1]
 	fileName = strings.Split(fileName, "?")[0]
 	// Check if the file extension is supported
-	allowedExtensions := map[string]bool{
-		".jpg":  true,
-		".jpeg": true,
-		".png":  true,
-		".webp": true,
-	}
 	extension := strings.ToLower(filepath.Ext(fileName))
 	if !allowedExtensions[extension] {
 		return nil, "", fmt.Errorf("unsupported file type: %s", extension)
@@ -439,6 +480,55 @@ func DownloadVideoFromURL(videoURL string) ([]byte, string, error) {
 	return videoData, fileName, nil
 }
 
+// DownloadFileFromURL downloads a document of any type from the provided URL and returns the bytes and sanitized filename.
+// The size is limited to WhatsappSettingMaxFileSize to avoid memory exhaustion.
+func DownloadFileFromURL(fileURL string) ([]byte, string, error) {
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return fmt.Errorf("too many redirects")
+			}
+			return nil
+		},
+	}
+
+	resp, err := client.Get(fileURL)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("HTTP request failed with status: %s", resp.Status)
+	}
+
+	maxSize := config.WhatsappSettingMaxFileSize
+	if resp.ContentLength > 0 && resp.ContentLength > maxSize {
+		return nil, "", fmt.Errorf("file size %d exceeds maximum allowed size %d", resp.ContentLength, maxSize)
+	}
+
+	// Guard against unknown Content-Length by limiting reader
+	limitedReader := &io.LimitedReader{R: resp.Body, N: maxSize + 1}
+	fileData, err := io.ReadAll(limitedReader)
+	if err != nil {
+		return nil, "", err
+	}
+	if int64(len(fileData)) > maxSize {
+		return nil, "", fmt.Errorf("downloaded file size of %d bytes exceeds the maximum allowed size of %d bytes", len(fileData), maxSize)
+	}
+
+	// Derive filename from URL path
+	segments := strings.Split(fileURL, "/")
+	fileName := segments[len(segments)-1]
+	fileName = strings.Split(fileName, "?")[0]
+	if fileName == "" {
+		fileName = fmt.Sprintf("file_%d", time.Now().Unix())
+	}
+
+	return fileData, fileName, nil
+}
+
 // FormatBusinessHourTime converts numeric time format (e.g., 600, 1200) to HH:MM format (e.g., "06:00", "12:00")
 func FormatBusinessHourTime(timeValue any) string {
 	var timeInt int
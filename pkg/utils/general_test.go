@@ -53,6 +53,68 @@ func (suite *UtilsTestSuite) TestContainsMention() {
 	}
 }
 
+func (suite *UtilsTestSuite) TestTemplateVariables() {
+	tests := []struct {
+		name     string
+		template string
+		want     []string
+	}{
+		{
+			name:     "should return nothing without placeholders",
+			template: "Hello there",
+			want:     nil,
+		},
+		{
+			name:     "should return placeholders once in order",
+			template: "Hi {{name}}, your order {{ order_id }} is ready. Bye {{name}}",
+			want:     []string{"name", "order_id"},
+		},
+		{
+			name:     "should ignore malformed placeholders",
+			template: "Hi {{first name}} {name} {{}}",
+			want:     nil,
+		},
+	}
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, utils.TemplateVariables(tt.template))
+		})
+	}
+}
+
+func (suite *UtilsTestSuite) TestRenderTemplate() {
+	tests := []struct {
+		name      string
+		template  string
+		variables map[string]string
+		want      string
+	}{
+		{
+			name:      "should replace placeholders",
+			template:  "Hi {{name}}, your order {{ order_id }} is ready",
+			variables: map[string]string{"name": "Budi", "order_id": "A-12"},
+			want:      "Hi Budi, your order A-12 is ready",
+		},
+		{
+			name:      "should keep unknown placeholders",
+			template:  "Hi {{name}}",
+			variables: map[string]string{},
+			want:      "Hi {{name}}",
+		},
+		{
+			name:      "should not render placeholders inside variables",
+			template:  "{{a}} {{b}}",
+			variables: map[string]string{"a": "{{b}}", "b": "x"},
+			want:      "{{b}} x",
+		},
+	}
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, utils.RenderTemplate(tt.template, tt.variables))
+		})
+	}
+}
+
 func (suite *UtilsTestSuite) TestRemoveFile() {
 	tempFile, err := os.CreateTemp("", "testfile")
 	assert.NoError(suite.T(), err)
@@ -604,6 +666,63 @@ func (suite *UtilsTestSuite) TestDownloadVideoFromURL() {
 	assert.Contains(suite.T(), err.Error(), "too many redirects")
 }
 
+func (suite *UtilsTestSuite) TestMediaDirectPath() {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{
+			name: "should drop mms3 flag",
+			url:  "https://mmg.whatsapp.net/v/t62.7118-24/1_2.enc?ccb=11-4&oh=01_Q5A&oe=68B1&_nc_sid=5e03e0&mms3=true",
+			want: "/v/t62.7118-24/1_2.enc?ccb=11-4&oh=01_Q5A&oe=68B1&_nc_sid=5e03e0",
+		},
+		{
+			name: "should keep path without query",
+			url:  "https://mmg.whatsapp.net/d/f/Agx.enc",
+			want: "/d/f/Agx.enc",
+		},
+		{
+			name: "should return empty for empty url",
+			url:  "",
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, utils.MediaDirectPath(tt.url))
+		})
+	}
+}
+
+func (suite *UtilsTestSuite) TestBuildMediaMessage() {
+	mediaURL := "https://mmg.whatsapp.net/v/t62.7118-24/1_2.enc?ccb=11-4&mms3=true"
+	key, sha, encSHA := []byte("key"), []byte("sha"), []byte("enc")
+
+	image := utils.BuildMediaMessage("image", "image.jpg", "", mediaURL, key, sha, encSHA, 1024, "look")
+	assert.Equal(suite.T(), "look", image.GetImageMessage().GetCaption())
+	assert.Equal(suite.T(), "/v/t62.7118-24/1_2.enc?ccb=11-4", image.GetImageMessage().GetDirectPath())
+	assert.Equal(suite.T(), key, image.GetImageMessage().GetMediaKey())
+	assert.Equal(suite.T(), uint64(1024), image.GetImageMessage().GetFileLength())
+	assert.Equal(suite.T(), "image/jpeg", image.GetImageMessage().GetMimetype())
+
+	png := utils.BuildMediaMessage("image", "image.jpg", "image/png", mediaURL, key, sha, encSHA, 1024, "")
+	assert.Equal(suite.T(), "image/png", png.GetImageMessage().GetMimetype())
+
+	audio := utils.BuildMediaMessage("audio", "audio.ogg", "audio/mpeg", mediaURL, key, sha, encSHA, 512, "")
+	assert.Equal(suite.T(), "audio/mpeg", audio.GetAudioMessage().GetMimetype())
+
+	document := utils.BuildMediaMessage("document", "report.pdf", "", mediaURL, key, sha, encSHA, 2048, "")
+	assert.Equal(suite.T(), "application/pdf", document.GetDocumentMessage().GetMimetype())
+	assert.Equal(suite.T(), "report.pdf", document.GetDocumentMessage().GetFileName())
+	assert.Nil(suite.T(), document.GetDocumentMessage().Caption)
+
+	sticker := utils.BuildMediaMessage("sticker", "sticker.webp", "", mediaURL, key, sha, encSHA, 10, "")
+	assert.Equal(suite.T(), "image/webp", sticker.GetStickerMessage().GetMimetype())
+
+	assert.Nil(suite.T(), utils.BuildMediaMessage("", "", "", "", nil, nil, nil, 0, "text"))
+}
+
 func TestUtilsTestSuite(t *testing.T) {
 	suite.Run(t, new(UtilsTestSuite))
 }
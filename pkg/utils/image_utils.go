@@ -5,10 +5,15 @@ import (
 	"fmt"
 	"image"
 	"image/jpeg"
+	"image/png"
 	"io"
 	"mime/multipart"
+	"os/exec"
+	"strconv"
+	"strings"
 
 	"github.com/disintegration/imaging"
+	_ "golang.org/x/image/webp"
 )
 
 const (
@@ -16,8 +21,15 @@ const (
 	MaxGroupPhotoSize      = 100 * 1024 // 100KB
 	GroupPhotoQuality      = 80         // JPEG quality
 	MaxGroupPhotoDimension = 640        // Max width/height in pixels
+
+	// WhatsApp sticker constraints
+	StickerDimension = 512        // Width and height in pixels
+	MaxStickerSize   = 100 * 1024 // WhatsApp does not show larger static stickers
 )
 
+// stickerQualities are tried in order until a sticker fits MaxStickerSize
+var stickerQualities = []int{90, 75, 60, 45, 30}
+
 // ProcessGroupPhoto processes an image for WhatsApp group photo requirements:
 // - Converts to JPEG format
 // - Crops to 1:1 aspect ratio (square)
@@ -57,6 +69,70 @@ func ProcessGroupPhoto(file *multipart.FileHeader) (*bytes.Buffer, error) {
 	return compressToJPEG(img, GroupPhotoQuality)
 }
 
+// ProcessSticker processes an image for WhatsApp sticker requirements:
+// - Accepts JPEG, PNG, GIF (first frame only) and WebP
+// - Resizes its longest side to StickerDimension, keeping the aspect ratio
+// - Centers it on a transparent StickerDimension square
+// - Converts to lossy WebP with ffmpeg, lowering the quality until it fits MaxStickerSize
+func ProcessSticker(src io.Reader) (*bytes.Buffer, error) {
+	img, format, err := image.Decode(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	switch format {
+	case "jpeg", "png", "gif", "webp":
+		// Supported formats
+	default:
+		return nil, fmt.Errorf("unsupported image format: %s (only JPEG, PNG, GIF and WebP are supported)", format)
+	}
+
+	// Scale the longest side to StickerDimension, small images are scaled up as stickers are shown at a fixed size
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	longest := max(width, height)
+	img = imaging.Resize(img, max(1, width*StickerDimension/longest), max(1, height*StickerDimension/longest), imaging.Lanczos)
+	canvas := imaging.New(StickerDimension, StickerDimension, image.Transparent)
+	canvas = imaging.PasteCenter(canvas, img)
+
+	var source bytes.Buffer
+	if err := png.Encode(&source, canvas); err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	size := 0
+	for _, quality := range stickerQualities {
+		sticker, err := encodeWebP(source.Bytes(), quality)
+		if err != nil {
+			return nil, err
+		}
+		if sticker.Len() <= MaxStickerSize {
+			return sticker, nil
+		}
+		size = sticker.Len()
+	}
+	return nil, fmt.Errorf("sticker is %d KB even at the lowest quality, WhatsApp accepts up to %d KB", size/1024, MaxStickerSize/1024)
+}
+
+// encodeWebP converts a PNG to a lossy WebP with ffmpeg, its alpha channel is kept lossless
+func encodeWebP(pngData []byte, quality int) (*bytes.Buffer, error) {
+	var output, stderr bytes.Buffer
+	cmd := exec.Command("ffmpeg", "-loglevel", "error",
+		"-f", "png_pipe", "-i", "pipe:0",
+		"-frames:v", "1",
+		"-c:v", "libwebp",
+		"-lossless", "0",
+		"-compression_level", "6",
+		"-quality", strconv.Itoa(quality),
+		"-f", "webp", "pipe:1")
+	cmd.Stdin = bytes.NewReader(pngData)
+	cmd.Stdout = &output
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to encode WebP: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return &output, nil
+}
+
 // cropToSquare crops an image to a 1:1 aspect ratio, keeping the center
 func cropToSquare(img image.Image) image.Image {
 	bounds := img.Bounds()
@@ -0,0 +1,95 @@
+package utils_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"math/rand"
+	"os/exec"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/image/webp"
+)
+
+type ImageUtilsTestSuite struct {
+	suite.Suite
+}
+
+func filledImage(width, height int, fill func(x, y int) color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetNRGBA(x, y, fill(x, y))
+		}
+	}
+	return img
+}
+
+func (suite *ImageUtilsTestSuite) TestProcessSticker() {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		suite.T().Skip("ffmpeg not installed")
+	}
+
+	wide := filledImage(200, 100, func(x, y int) color.NRGBA { return color.NRGBA{R: 255, A: 255} })
+	random := rand.New(rand.NewSource(1))
+	photo := filledImage(800, 600, func(x, y int) color.NRGBA {
+		return color.NRGBA{R: uint8(x/3 + random.Intn(40)), G: uint8(y/3 + random.Intn(40)), B: uint8(random.Intn(256)), A: 255}
+	})
+	var pngData, jpegData, gifData, photoData bytes.Buffer
+	assert.NoError(suite.T(), png.Encode(&pngData, wide))
+	assert.NoError(suite.T(), jpeg.Encode(&jpegData, wide, nil))
+	assert.NoError(suite.T(), gif.Encode(&gifData, wide, nil))
+	assert.NoError(suite.T(), png.Encode(&photoData, photo))
+
+	tests := []struct {
+		name    string
+		data    []byte
+		opaque  bool
+		wantErr bool
+	}{
+		{name: "should convert png", data: pngData.Bytes()},
+		{name: "should convert jpeg", data: jpegData.Bytes()},
+		{name: "should convert gif", data: gifData.Bytes()},
+		{name: "should fit a photo within the size limit", data: photoData.Bytes(), opaque: true},
+		{name: "should error with invalid image", data: []byte("not an image"), wantErr: true},
+	}
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			sticker, err := utils.ProcessSticker(bytes.NewReader(tt.data))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			if err != nil {
+				return
+			}
+			assert.LessOrEqual(t, sticker.Len(), utils.MaxStickerSize)
+
+			decoded, err := webp.Decode(sticker)
+			assert.NoError(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, image.Rect(0, 0, utils.StickerDimension, utils.StickerDimension), decoded.Bounds())
+			if tt.opaque {
+				return
+			}
+			// The wide image fills the middle half, the rest is transparent
+			_, _, _, top := decoded.At(256, 10).RGBA()
+			_, _, _, middle := decoded.At(256, 256).RGBA()
+			assert.Zero(t, top)
+			assert.Equal(t, uint32(0xffff), middle)
+		})
+	}
+}
+
+func TestImageUtilsTestSuite(t *testing.T) {
+	suite.Run(t, new(ImageUtilsTestSuite))
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"fmt"
+	"net/url"
+	"time"
+
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+// MediaURLPath is the route of signed media downloads, below the base path
+const MediaURLPath = "/media"
+
+// SignMediaURL returns the path of a download URL for the media of a message of a user, valid until expires, e.g.
+// /media/1/3EB0B430B6F8F1D0E053AC120E0A9E5C?expires=1754006400&signature=9f2c...
+func SignMediaURL(secret string, userID int, messageID string, expires time.Time) (string, error) {
+	signature, err := mediaURLSignature(secret, userID, messageID, expires.Unix())
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/%d/%s?expires=%d&signature=%s", MediaURLPath, userID, url.PathEscape(messageID), expires.Unix(), signature), nil
+}
+
+// VerifyMediaURL checks that a signed media URL was issued for the message of the user and has not expired
+func VerifyMediaURL(secret string, userID int, messageID string, expires int64, signature string, now time.Time) error {
+	expected, err := mediaURLSignature(secret, userID, messageID, expires)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return pkgError.ForbiddenError("invalid media url signature")
+	}
+	if now.Unix() > expires {
+		return pkgError.ForbiddenError("media url has expired")
+	}
+	return nil
+}
+
+func mediaURLSignature(secret string, userID int, messageID string, expires int64) (string, error) {
+	return GetMessageDigestOrSignature([]byte(fmt.Sprintf("%d\n%s\n%d", userID, messageID, expires)), []byte(secret))
+}
@@ -0,0 +1,70 @@
+package utils_test
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type MediaURLTestSuite struct {
+	suite.Suite
+}
+
+// parseMediaURL splits a signed media URL into the values VerifyMediaURL takes
+func (suite *MediaURLTestSuite) parseMediaURL(signed string) (userID int, messageID string, expires int64, signature string) {
+	parsed, err := url.Parse(signed)
+	assert.NoError(suite.T(), err)
+
+	parts := strings.Split(strings.TrimPrefix(parsed.Path, utils.MediaURLPath+"/"), "/")
+	assert.Len(suite.T(), parts, 2)
+	userID, err = strconv.Atoi(parts[0])
+	assert.NoError(suite.T(), err)
+	expires, err = strconv.ParseInt(parsed.Query().Get("expires"), 10, 64)
+	assert.NoError(suite.T(), err)
+	return userID, parts[1], expires, parsed.Query().Get("signature")
+}
+
+func (suite *MediaURLTestSuite) TestSignAndVerify() {
+	now := time.Unix(1754006400, 0)
+	signed, err := utils.SignMediaURL("secret", 7, "3EB0B430B6F8F1D0E053AC120E0A9E5C", now.Add(time.Hour))
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), strings.HasPrefix(signed, "/media/7/3EB0B430B6F8F1D0E053AC120E0A9E5C?expires=1754010000&signature="))
+
+	userID, messageID, expires, signature := suite.parseMediaURL(signed)
+
+	suite.T().Run("valid until it expires", func(t *testing.T) {
+		assert.NoError(t, utils.VerifyMediaURL("secret", userID, messageID, expires, signature, now))
+		assert.NoError(t, utils.VerifyMediaURL("secret", userID, messageID, expires, signature, now.Add(time.Hour)))
+	})
+
+	suite.T().Run("expired", func(t *testing.T) {
+		err := utils.VerifyMediaURL("secret", userID, messageID, expires, signature, now.Add(time.Hour+time.Second))
+		assert.Equal(t, pkgError.ForbiddenError("media url has expired"), err)
+	})
+
+	suite.T().Run("tampered", func(t *testing.T) {
+		invalid := pkgError.ForbiddenError("invalid media url signature")
+		assert.Equal(t, invalid, utils.VerifyMediaURL("secret", 8, messageID, expires, signature, now))
+		assert.Equal(t, invalid, utils.VerifyMediaURL("secret", userID, "3EB0B430B6F8F1D0E053AC120E0A9E5D", expires, signature, now))
+		assert.Equal(t, invalid, utils.VerifyMediaURL("secret", userID, messageID, expires+3600, signature, now))
+		assert.Equal(t, invalid, utils.VerifyMediaURL("other", userID, messageID, expires, signature, now))
+		assert.Equal(t, invalid, utils.VerifyMediaURL("secret", userID, messageID, expires, "", now))
+	})
+}
+
+func (suite *MediaURLTestSuite) TestSignEscapesMessageID() {
+	signed, err := utils.SignMediaURL("secret", 1, "ABC/DEF", time.Unix(1754006400, 0))
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), strings.HasPrefix(signed, "/media/1/ABC%2FDEF?"))
+}
+
+func TestMediaURLTestSuite(t *testing.T) {
+	suite.Run(t, new(MediaURLTestSuite))
+}
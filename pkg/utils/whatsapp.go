@@ -7,7 +7,9 @@ import (
 	"encoding/hex"
 	"fmt"
 	"mime"
+	"net/url"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
@@ -17,6 +19,7 @@ import (
 	"go.mau.fi/whatsmeow/proto/waE2E"
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
@@ -240,6 +243,106 @@ func ExtractMediaInfo(msg *waE2E.Message) (mediaType string, filename string, ur
 	return "", "", "", nil, nil, nil, 0
 }
 
+// ExtractMediaMimetype returns the mimetype of the media of a WhatsApp message, empty when it has none
+func ExtractMediaMimetype(msg *waE2E.Message) string {
+	switch {
+	case msg.GetImageMessage() != nil:
+		return msg.GetImageMessage().GetMimetype()
+	case msg.GetVideoMessage() != nil:
+		return msg.GetVideoMessage().GetMimetype()
+	case msg.GetAudioMessage() != nil:
+		return msg.GetAudioMessage().GetMimetype()
+	case msg.GetDocumentMessage() != nil:
+		return msg.GetDocumentMessage().GetMimetype()
+	case msg.GetStickerMessage() != nil:
+		return msg.GetStickerMessage().GetMimetype()
+	}
+	return ""
+}
+
+// ExtractMediaThumbnail returns the JPEG preview of the image or video of a WhatsApp message, nil when it has none
+func ExtractMediaThumbnail(msg *waE2E.Message) []byte {
+	switch {
+	case msg.GetImageMessage() != nil:
+		return msg.GetImageMessage().GetJPEGThumbnail()
+	case msg.GetVideoMessage() != nil:
+		return msg.GetVideoMessage().GetJPEGThumbnail()
+	}
+	return nil
+}
+
+// BuildMediaMessage rebuilds a downloadable media message from the information ExtractMediaInfo and
+// ExtractMediaMimetype return. Without a mimetype, messages stored before it was kept get the usual one of their type.
+// The direct path is derived from the URL, as it is not stored. It returns nil for unknown media types.
+func BuildMediaMessage(mediaType, filename, mimetype, url string, mediaKey, fileSHA256, fileEncSHA256 []byte, fileLength uint64, caption string) *waE2E.Message {
+	var optionalCaption *string
+	if caption != "" {
+		optionalCaption = proto.String(caption)
+	}
+	directPath := proto.String(MediaDirectPath(url))
+	withDefault := func(fallback string) *string {
+		if mimetype == "" {
+			return proto.String(fallback)
+		}
+		return proto.String(mimetype)
+	}
+
+	switch mediaType {
+	case "image":
+		return &waE2E.Message{ImageMessage: &waE2E.ImageMessage{
+			URL: proto.String(url), DirectPath: directPath, Mimetype: withDefault("image/jpeg"), Caption: optionalCaption,
+			MediaKey: mediaKey, FileSHA256: fileSHA256, FileEncSHA256: fileEncSHA256, FileLength: proto.Uint64(fileLength),
+		}}
+	case "video":
+		return &waE2E.Message{VideoMessage: &waE2E.VideoMessage{
+			URL: proto.String(url), DirectPath: directPath, Mimetype: withDefault("video/mp4"), Caption: optionalCaption,
+			MediaKey: mediaKey, FileSHA256: fileSHA256, FileEncSHA256: fileEncSHA256, FileLength: proto.Uint64(fileLength),
+		}}
+	case "audio":
+		return &waE2E.Message{AudioMessage: &waE2E.AudioMessage{
+			URL: proto.String(url), DirectPath: directPath, Mimetype: withDefault("audio/ogg; codecs=opus"),
+			MediaKey: mediaKey, FileSHA256: fileSHA256, FileEncSHA256: fileEncSHA256, FileLength: proto.Uint64(fileLength),
+		}}
+	case "document":
+		fallback := mime.TypeByExtension(filepath.Ext(filename))
+		if fallback == "" {
+			fallback = "application/octet-stream"
+		}
+		return &waE2E.Message{DocumentMessage: &waE2E.DocumentMessage{
+			URL: proto.String(url), DirectPath: directPath, Mimetype: withDefault(fallback), Caption: optionalCaption,
+			FileName: proto.String(filename), Title: proto.String(filename),
+			MediaKey: mediaKey, FileSHA256: fileSHA256, FileEncSHA256: fileEncSHA256, FileLength: proto.Uint64(fileLength),
+		}}
+	case "sticker":
+		return &waE2E.Message{StickerMessage: &waE2E.StickerMessage{
+			URL: proto.String(url), DirectPath: directPath, Mimetype: withDefault("image/webp"),
+			MediaKey: mediaKey, FileSHA256: fileSHA256, FileEncSHA256: fileEncSHA256, FileLength: proto.Uint64(fileLength),
+		}}
+	}
+	return nil
+}
+
+// MediaDirectPath derives the direct path of WhatsApp media from its URL: the path and query of the URL without the
+// mms3 flag, e.g. https://mmg.whatsapp.net/v/t62.7118-24/1_2.enc?ccb=11-4&oh=x&mms3=true becomes
+// /v/t62.7118-24/1_2.enc?ccb=11-4&oh=x
+func MediaDirectPath(mediaURL string) string {
+	parsed, err := url.Parse(mediaURL)
+	if err != nil || parsed.Path == "" {
+		return ""
+	}
+
+	var query []string
+	for _, param := range strings.Split(parsed.RawQuery, "&") {
+		if param != "" && !strings.HasPrefix(param, "mms3=") {
+			query = append(query, param)
+		}
+	}
+	if len(query) == 0 {
+		return parsed.EscapedPath()
+	}
+	return parsed.EscapedPath() + "?" + strings.Join(query, "&")
+}
+
 // ExtractEphemeralExpiration extracts ephemeral expiration from a WhatsApp message
 func ExtractEphemeralExpiration(msg *waE2E.Message) uint32 {
 	logrus.Debug("ExtractEphemeralExpiration: Starting extraction process")
@@ -470,6 +573,7 @@ type ExtractedMedia struct {
 	MediaPath string `json:"media_path"`
 	MimeType  string `json:"mime_type"`
 	Caption   string `json:"caption"`
+	Error     string `json:"error,omitempty"` // Why the media could not be downloaded, MediaPath is empty then
 }
 
 // ExtractMedia is a helper function to extract media from whatsapp
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 
@@ -20,7 +21,7 @@ func main() {
 		log.Fatalf("Failed to initialize user management repository: %v", err)
 	}
 
-	userManagementUsecase := usecase.NewUserManagementUsecase(userManagementRepo, nil)
+	userManagementUsecase := usecase.NewUserManagementUsecase(userManagementRepo, nil, nil)
 
 	// Create initial users
 	users := []domainUserManagement.CreateUserRequest{
@@ -32,7 +33,7 @@ func main() {
 
 	fmt.Println("Creating initial users...")
 	for _, user := range users {
-		response, err := userManagementUsecase.CreateUser(user)
+		response, err := userManagementUsecase.CreateUser(context.Background(), user)
 		if err != nil {
 			log.Printf("Failed to create user %s: %v", user.Username, err)
 			continue
@@ -48,7 +49,5 @@ func main() {
 	fmt.Println("- GET /admin/users/:id - Get user by ID")
 	fmt.Println("- PUT /admin/users/:id - Update user")
 	fmt.Println("- DELETE /admin/users/:id - Delete user")
-	fmt.Println("\nAdmin credentials (set in .env):")
-	fmt.Println("- ADMIN_USERNAME=admin")
-	fmt.Println("- ADMIN_PASSWORD=admin123")
+	fmt.Println("\nSet ADMIN_USERNAME and ADMIN_PASSWORD in .env to create the first admin account")
 }
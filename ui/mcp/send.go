@@ -26,6 +26,7 @@ func (s *SendHandler) AddSendTools(mcpServer *server.MCPServer) {
 	mcpServer.AddTool(s.toolSendLink(), s.handleSendLink)
 	mcpServer.AddTool(s.toolSendLocation(), s.handleSendLocation)
 	mcpServer.AddTool(s.toolSendImage(), s.handleSendImage)
+	mcpServer.AddTool(s.toolSendSticker(), s.handleSendSticker)
 }
 
 func (s *SendHandler) toolSendText() mcp.Tool {
@@ -36,8 +37,7 @@ func (s *SendHandler) toolSendText() mcp.Tool {
 			mcp.Description("Phone number or group ID to send message to"),
 		),
 		mcp.WithString("message",
-			mcp.Required(),
-			mcp.Description("The text message to send"),
+			mcp.Description("The text message to send, not needed with template_name"),
 		),
 		mcp.WithBoolean("is_forwarded",
 			mcp.Description("Whether this message is being forwarded (default: false)"),
@@ -45,6 +45,13 @@ func (s *SendHandler) toolSendText() mcp.Tool {
 		mcp.WithString("reply_message_id",
 			mcp.Description("Message ID to reply to (optional)"),
 		),
+		mcp.WithString("template_name",
+			mcp.Description("Name of a stored message template to render instead of message (optional)"),
+		),
+		mcp.WithObject("variables",
+			mcp.Description("Values of the template placeholders, e.g. {\"name\": \"Budi\"}; {{phone}} is filled in automatically"),
+			mcp.AdditionalProperties(map[string]any{"type": "string"}),
+		),
 	)
 
 	return sendTextTool
@@ -58,7 +65,7 @@ func (s *SendHandler) handleSendText(ctx context.Context, request mcp.CallToolRe
 
 	message, ok := request.GetArguments()["message"].(string)
 	if !ok {
-		return nil, errors.New("message must be a string")
+		message = ""
 	}
 
 	isForwarded, ok := request.GetArguments()["is_forwarded"].(bool)
@@ -71,13 +78,21 @@ func (s *SendHandler) handleSendText(ctx context.Context, request mcp.CallToolRe
 		replyMessageId = ""
 	}
 
+	templateRequest, err := templateArguments(request)
+	if err != nil {
+		return nil, err
+	}
+
 	res, err := s.sendService.SendText(ctx, domainSend.MessageRequest{
 		BaseRequest: domainSend.BaseRequest{
 			Phone:       phone,
 			IsForwarded: isForwarded,
 		},
-		Message:        message,
-		ReplyMessageID: &replyMessageId,
+		TemplateRequest: templateRequest,
+		ReplyRequest: domainSend.ReplyRequest{
+			ReplyMessageID: &replyMessageId,
+		},
+		Message: message,
 	})
 
 	if err != nil {
@@ -159,12 +174,18 @@ func (s *SendHandler) toolSendLink() mcp.Tool {
 			mcp.Description("URL link to send"),
 		),
 		mcp.WithString("caption",
-			mcp.Required(),
-			mcp.Description("Caption or description for the link"),
+			mcp.Description("Caption or description for the link, not needed with template_name"),
 		),
 		mcp.WithBoolean("is_forwarded",
 			mcp.Description("Whether this message is being forwarded (default: false)"),
 		),
+		mcp.WithString("template_name",
+			mcp.Description("Name of a stored message template to render instead of caption (optional)"),
+		),
+		mcp.WithObject("variables",
+			mcp.Description("Values of the template placeholders, e.g. {\"name\": \"Budi\"}; {{phone}} is filled in automatically"),
+			mcp.AdditionalProperties(map[string]any{"type": "string"}),
+		),
 	)
 
 	return sendLinkTool
@@ -191,13 +212,19 @@ func (s *SendHandler) handleSendLink(ctx context.Context, request mcp.CallToolRe
 		isForwarded = false
 	}
 
+	templateRequest, err := templateArguments(request)
+	if err != nil {
+		return nil, err
+	}
+
 	res, err := s.sendService.SendLink(ctx, domainSend.LinkRequest{
 		BaseRequest: domainSend.BaseRequest{
 			Phone:       phone,
 			IsForwarded: isForwarded,
 		},
-		Link:    link,
-		Caption: caption,
+		TemplateRequest: templateRequest,
+		Link:            link,
+		Caption:         caption,
 	})
 
 	if err != nil {
@@ -275,7 +302,7 @@ func (s *SendHandler) toolSendImage() mcp.Tool {
 			mcp.Description("Phone number or group ID to send image to"),
 		),
 		mcp.WithString("image_url",
-			mcp.Description("URL of the image to send"),
+			mcp.Description("URL of the image to send, not needed with a template that has an image"),
 		),
 		mcp.WithString("caption",
 			mcp.Description("Caption or description for the image"),
@@ -289,6 +316,13 @@ func (s *SendHandler) toolSendImage() mcp.Tool {
 		mcp.WithBoolean("is_forwarded",
 			mcp.Description("Whether this message is being forwarded (default: false)"),
 		),
+		mcp.WithString("template_name",
+			mcp.Description("Name of a stored message template to render instead of caption (optional)"),
+		),
+		mcp.WithObject("variables",
+			mcp.Description("Values of the template placeholders, e.g. {\"name\": \"Budi\"}; {{phone}} is filled in automatically"),
+			mcp.AdditionalProperties(map[string]any{"type": "string"}),
+		),
 	)
 
 	return sendImageTool
@@ -301,9 +335,6 @@ func (s *SendHandler) handleSendImage(ctx context.Context, request mcp.CallToolR
 	}
 
 	imageURL, imageURLOk := request.GetArguments()["image_url"].(string)
-	if !imageURLOk {
-		return nil, errors.New("image_url must be a string")
-	}
 
 	caption, ok := request.GetArguments()["caption"].(string)
 	if !ok {
@@ -325,15 +356,21 @@ func (s *SendHandler) handleSendImage(ctx context.Context, request mcp.CallToolR
 		isForwarded = false
 	}
 
+	templateRequest, err := templateArguments(request)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create image request
 	imageRequest := domainSend.ImageRequest{
 		BaseRequest: domainSend.BaseRequest{
 			Phone:       phone,
 			IsForwarded: isForwarded,
 		},
-		Caption:  caption,
-		ViewOnce: viewOnce,
-		Compress: compress,
+		TemplateRequest: templateRequest,
+		Caption:         caption,
+		ViewOnce:        viewOnce,
+		Compress:        compress,
 	}
 
 	if imageURLOk && imageURL != "" {
@@ -346,3 +383,74 @@ func (s *SendHandler) handleSendImage(ctx context.Context, request mcp.CallToolR
 
 	return mcp.NewToolResultText(fmt.Sprintf("Image sent successfully with ID %s", res.MessageID)), nil
 }
+
+func (s *SendHandler) toolSendSticker() mcp.Tool {
+	sendStickerTool := mcp.NewTool("whatsapp_send_sticker",
+		mcp.WithDescription("Send an image as a sticker to a WhatsApp contact or group. The image is converted to a 512x512 WebP sticker."),
+		mcp.WithString("phone",
+			mcp.Required(),
+			mcp.Description("Phone number or group ID to send sticker to"),
+		),
+		mcp.WithString("sticker_url",
+			mcp.Required(),
+			mcp.Description("URL of a JPEG, PNG, GIF or WebP image to send as a sticker"),
+		),
+		mcp.WithBoolean("is_forwarded",
+			mcp.Description("Whether this message is being forwarded (default: false)"),
+		),
+	)
+
+	return sendStickerTool
+}
+
+func (s *SendHandler) handleSendSticker(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	phone, ok := request.GetArguments()["phone"].(string)
+	if !ok {
+		return nil, errors.New("phone must be a string")
+	}
+
+	stickerURL, ok := request.GetArguments()["sticker_url"].(string)
+	if !ok {
+		return nil, errors.New("sticker_url must be a string")
+	}
+
+	isForwarded, ok := request.GetArguments()["is_forwarded"].(bool)
+	if !ok {
+		isForwarded = false
+	}
+
+	res, err := s.sendService.SendSticker(ctx, domainSend.StickerRequest{
+		BaseRequest: domainSend.BaseRequest{
+			Phone:       phone,
+			IsForwarded: isForwarded,
+		},
+		StickerURL: &stickerURL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Sticker sent successfully with ID %s", res.MessageID)), nil
+}
+
+// templateArguments reads the optional template_name and variables arguments shared by the send tools
+func templateArguments(request mcp.CallToolRequest) (domainSend.TemplateRequest, error) {
+	templateName, ok := request.GetArguments()["template_name"].(string)
+	if !ok {
+		templateName = ""
+	}
+
+	var variables map[string]string
+	if raw, exists := request.GetArguments()["variables"]; exists && raw != nil {
+		values, ok := raw.(map[string]any)
+		if !ok {
+			return domainSend.TemplateRequest{}, errors.New("variables must be an object")
+		}
+		variables = make(map[string]string, len(values))
+		for name, value := range values {
+			variables[name] = fmt.Sprint(value)
+		}
+	}
+
+	return domainSend.TemplateRequest{TemplateName: templateName, Variables: variables}, nil
+}
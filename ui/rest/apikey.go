@@ -0,0 +1,94 @@
+package rest
+
+import (
+	"strconv"
+
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+type APIKey struct {
+	Service domainAPIKey.IAPIKeyUsecase
+}
+
+// InitRestAPIKey registers API key management for the authenticated user
+func InitRestAPIKey(app fiber.Router, service domainAPIKey.IAPIKeyUsecase) APIKey {
+	rest := APIKey{Service: service}
+	app.Get("/api-keys", middleware.RequireScope(permission.APIKeyManage), rest.ListKeys)
+	app.Post("/api-keys", middleware.RequireScope(permission.APIKeyManage), rest.CreateKey)
+	app.Delete("/api-keys/:key_id", middleware.RequireScope(permission.APIKeyManage), rest.RevokeKey)
+	return rest
+}
+
+// InitRestAdminAPIKey registers API key management on behalf of any user (admin only)
+func InitRestAdminAPIKey(app fiber.Router, service domainAPIKey.IAPIKeyUsecase) APIKey {
+	rest := APIKey{Service: service}
+	app.Get("/users/:id/api-keys", rest.ListKeys)
+	app.Post("/users/:id/api-keys", rest.CreateKey)
+	app.Delete("/users/:id/api-keys/:key_id", rest.RevokeKey)
+	return rest
+}
+
+func (controller *APIKey) ListKeys(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	response, err := controller.Service.ListKeys(appCtx, userID)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get list API keys",
+		Results: response,
+	})
+}
+
+func (controller *APIKey) CreateKey(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	var request domainAPIKey.CreateAPIKeyRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	// Users can only hand out what they are allowed themselves; admins are not limited
+	grant := permission.Grant{Scopes: request.Scopes, AllowedChats: request.AllowedChats}
+	if c.Params("id") == "" && !middleware.GrantsCover(c, grant) {
+		panic(pkgError.ForbiddenError("API key cannot be given more permissions than the credentials creating it"))
+	}
+
+	response, err := controller.Service.CreateKey(appCtx, userID, request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success create API key, store the key now as it is not shown again",
+		Results: response,
+	})
+}
+
+func (controller *APIKey) RevokeKey(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	response, err := controller.Service.RevokeKey(appCtx, userID, keyIDParam(c))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success revoke API key",
+		Results: response,
+	})
+}
+
+func keyIDParam(c *fiber.Ctx) int {
+	id, err := strconv.Atoi(c.Params("key_id"))
+	if err != nil {
+		panic(pkgError.ValidationError("invalid API key id"))
+	}
+	return id
+}
@@ -2,9 +2,11 @@ package rest
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
@@ -17,12 +19,12 @@ type App struct {
 
 func InitRestApp(app fiber.Router, service domainApp.IAppUsecaseWithContext) App {
 	rest := App{Service: service}
-	app.Get("/app/login", rest.Login)
-	app.Get("/app/login-with-code", rest.LoginWithCode)
-	app.Get("/app/logout", rest.Logout)
-	app.Get("/app/reconnect", rest.Reconnect)
-	app.Get("/app/devices", rest.Devices)
-	app.Get("/app/status", rest.ConnectionStatus)
+	app.Get("/app/login", middleware.RequireScope(permission.AppManage), rest.Login)
+	app.Get("/app/login-with-code", middleware.RequireScope(permission.AppManage), rest.LoginWithCode)
+	app.Get("/app/logout", middleware.RequireScope(permission.AppManage), rest.Logout)
+	app.Get("/app/reconnect", middleware.RequireScope(permission.AppManage), rest.Reconnect)
+	app.Get("/app/devices", middleware.RequireScope(permission.AppRead), rest.Devices)
+	app.Get("/app/status", middleware.RequireScope(permission.AppRead), rest.ConnectionStatus)
 
 	return App{Service: service}
 }
@@ -112,25 +114,35 @@ func (handler *App) ConnectionStatus(c *fiber.Ctx) error {
 	userID, hasUserID := middleware.GetUserIDFromContext(c)
 
 	var isConnected, isLoggedIn bool
-	var deviceID string
+	var deviceID, disconnectReason string
+	var disconnectedAt time.Time
 
 	if hasUserID {
 		// Get status for specific user
 		sessionManager := whatsapp.GetSessionManager()
 		isConnected, isLoggedIn, deviceID = sessionManager.GetUserConnectionStatus(userID)
+		if session := sessionManager.GetUserSession(userID); session != nil {
+			disconnectReason, disconnectedAt = session.DisconnectReason()
+		}
 	} else {
 		// No global client in multi-user mode
 		isConnected, isLoggedIn, deviceID = false, false, "multi-user-mode"
 	}
 
+	results := map[string]any{
+		"is_connected": isConnected,
+		"is_logged_in": isLoggedIn,
+		"device_id":    deviceID,
+	}
+	if disconnectReason != "" {
+		results["disconnect_reason"] = disconnectReason
+		results["disconnected_at"] = disconnectedAt.Format(time.RFC3339)
+	}
+
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
 		Message: "Connection status retrieved",
-		Results: map[string]any{
-			"is_connected": isConnected,
-			"is_logged_in": isLoggedIn,
-			"device_id":    deviceID,
-		},
+		Results: results,
 	})
 }
@@ -0,0 +1,68 @@
+package rest
+
+import (
+	"fmt"
+	"time"
+
+	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	domainAudit "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/audit"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Audit struct {
+	Service domainAudit.IAuditUsecase
+}
+
+// InitRestAdminAudit registers the audit log (admin only)
+func InitRestAdminAudit(app fiber.Router, service domainAudit.IAuditUsecase) Audit {
+	rest := Audit{Service: service}
+	app.Get("/audit", rest.ListEntries)
+	app.Get("/audit/export", rest.ExportEntries)
+	return rest
+}
+
+func (controller *Audit) ListEntries(c *fiber.Ctx) error {
+	request := parseAuditRequest(c)
+	request.Limit = c.QueryInt("limit", 25)
+	request.Offset = c.QueryInt("offset", 0)
+
+	response, err := controller.Service.List(domainApp.NewAppContext(c.UserContext(), c), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get audit log",
+		Results: response,
+	})
+}
+
+func (controller *Audit) ExportEntries(c *fiber.Ctx) error {
+	request := parseAuditRequest(c)
+
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="audit-%s.csv"`, time.Now().UTC().Format("20060102-150405")))
+
+	err := controller.Service.ExportCSV(domainApp.NewAppContext(c.UserContext(), c), request, c)
+	if err != nil {
+		// The error is answered as JSON, which replaces any partially written CSV
+		c.Response().Header.Del(fiber.HeaderContentDisposition)
+	}
+	utils.PanicIfNeeded(err)
+
+	return nil
+}
+
+// parseAuditRequest reads the audit log filters from the query string
+func parseAuditRequest(c *fiber.Ctx) domainAudit.ListRequest {
+	return domainAudit.ListRequest{
+		UserID:    c.QueryInt("user_id", 0),
+		Actor:     c.Query("actor"),
+		Action:    c.Query("action"),
+		TargetJID: c.Query("target_jid"),
+		Result:    c.Query("result"),
+		From:      c.Query("from"),
+		To:        c.Query("to"),
+	}
+}
@@ -0,0 +1,149 @@
+package rest
+
+import (
+	"strconv"
+
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+type AutoReply struct {
+	Service domainAutoReply.IAutoReplyUsecase
+}
+
+// InitRestAutoReply registers auto-reply rule management for the authenticated user
+func InitRestAutoReply(app fiber.Router, service domainAutoReply.IAutoReplyUsecase) AutoReply {
+	rest := AutoReply{Service: service}
+	// The dry-run route is registered first so it is not captured by /auto-replies/:rule_id
+	app.Post("/auto-replies/dry-run", middleware.RequireScope(permission.AutoReplyRead), rest.DryRun)
+	app.Get("/auto-replies", middleware.RequireScope(permission.AutoReplyRead), rest.ListRules)
+	app.Post("/auto-replies", middleware.RequireScope(permission.AutoReplyWrite), rest.CreateRule)
+	app.Get("/auto-replies/:rule_id", middleware.RequireScope(permission.AutoReplyRead), rest.GetRule)
+	app.Put("/auto-replies/:rule_id", middleware.RequireScope(permission.AutoReplyWrite), rest.UpdateRule)
+	app.Delete("/auto-replies/:rule_id", middleware.RequireScope(permission.AutoReplyWrite), rest.DeleteRule)
+	return rest
+}
+
+// InitRestAdminAutoReply registers auto-reply rule management on behalf of any user (admin only)
+func InitRestAdminAutoReply(app fiber.Router, service domainAutoReply.IAutoReplyUsecase) AutoReply {
+	rest := AutoReply{Service: service}
+	app.Post("/users/:id/auto-replies/dry-run", rest.DryRun)
+	app.Get("/users/:id/auto-replies", rest.ListRules)
+	app.Post("/users/:id/auto-replies", rest.CreateRule)
+	app.Get("/users/:id/auto-replies/:rule_id", rest.GetRule)
+	app.Put("/users/:id/auto-replies/:rule_id", rest.UpdateRule)
+	app.Delete("/users/:id/auto-replies/:rule_id", rest.DeleteRule)
+	return rest
+}
+
+func (controller *AutoReply) ListRules(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	response, err := controller.Service.ListRules(appCtx, userID)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get list auto-reply rules",
+		Results: response,
+	})
+}
+
+func (controller *AutoReply) CreateRule(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	var request domainAutoReply.CreateRuleRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.CreateRule(appCtx, userID, request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success create auto-reply rule",
+		Results: response,
+	})
+}
+
+func (controller *AutoReply) GetRule(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	response, err := controller.Service.GetRule(appCtx, userID, ruleIDParam(c))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get auto-reply rule",
+		Results: response,
+	})
+}
+
+func (controller *AutoReply) UpdateRule(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	var request domainAutoReply.UpdateRuleRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.UpdateRule(appCtx, userID, ruleIDParam(c), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success update auto-reply rule",
+		Results: response,
+	})
+}
+
+func (controller *AutoReply) DeleteRule(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	err := controller.Service.DeleteRule(appCtx, userID, ruleIDParam(c))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success delete auto-reply rule",
+	})
+}
+
+func (controller *AutoReply) DryRun(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	var request domainAutoReply.DryRunRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.DryRun(appCtx, userID, request)
+	utils.PanicIfNeeded(err)
+
+	message := "No auto-reply rule matched"
+	if response.Matched {
+		message = "Auto-reply rule matched"
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: message,
+		Results: response,
+	})
+}
+
+func ruleIDParam(c *fiber.Ctx) int {
+	id, err := strconv.Atoi(c.Params("rule_id"))
+	if err != nil {
+		panic(pkgError.ValidationError("invalid auto-reply rule id"))
+	}
+	return id
+}
@@ -0,0 +1,207 @@
+package rest
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"strconv"
+	"strings"
+
+	domainBulk "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/bulk"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Bulk struct {
+	Service domainBulk.IBulkUsecase
+}
+
+// InitRestBulk registers the bulk send jobs of the authenticated user
+func InitRestBulk(app fiber.Router, service domainBulk.IBulkUsecase) Bulk {
+	rest := Bulk{Service: service}
+	app.Post("/send/bulk", middleware.RequireScope(permission.SendText), rest.CreateJob)
+	app.Get("/send/bulk", middleware.RequireScope(permission.QueueRead), rest.ListJobs)
+	app.Get("/send/bulk/:job_id", middleware.RequireScope(permission.QueueRead), rest.GetJob)
+	app.Get("/send/bulk/:job_id/recipients", middleware.RequireScope(permission.QueueRead), rest.ListRecipients)
+	app.Post("/send/bulk/:job_id/cancel", middleware.RequireScope(permission.QueueWrite), rest.CancelJob)
+	return rest
+}
+
+// InitRestAdminBulk registers the bulk send jobs of any user (admin only)
+func InitRestAdminBulk(app fiber.Router, service domainBulk.IBulkUsecase) Bulk {
+	rest := Bulk{Service: service}
+	app.Get("/users/:id/bulk", rest.ListJobs)
+	app.Get("/users/:id/bulk/:job_id", rest.GetJob)
+	app.Get("/users/:id/bulk/:job_id/recipients", rest.ListRecipients)
+	app.Post("/users/:id/bulk/:job_id/cancel", rest.CancelJob)
+	return rest
+}
+
+func (controller *Bulk) CreateJob(c *fiber.Ctx) error {
+	var request domainBulk.CreateJobRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	// Multipart requests may list the recipients in a CSV file instead
+	if file, err := c.FormFile("recipients"); err == nil {
+		request.Recipients, err = parseRecipientsCSV(file)
+		utils.PanicIfNeeded(err)
+	}
+
+	for i := range request.Recipients {
+		utils.SanitizePhone(&request.Recipients[i].Phone)
+		if !middleware.ChatAllowed(c, request.Recipients[i].Phone) {
+			panic(pkgError.ForbiddenError(fmt.Sprintf("Access to chat %s is not allowed", request.Recipients[i].Phone)))
+		}
+	}
+
+	appCtx, userID := resolveOwner(c)
+
+	response, err := controller.Service.CreateJob(appCtx, userID, request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Bulk job %d queued for %d recipients", response.ID, response.Progress.Total),
+		Results: response,
+	})
+}
+
+func (controller *Bulk) ListJobs(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	request := domainBulk.ListJobsRequest{
+		Limit:  c.QueryInt("limit", 25),
+		Offset: c.QueryInt("offset", 0),
+	}
+
+	response, err := controller.Service.ListJobs(appCtx, userID, request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get bulk jobs",
+		Results: response,
+	})
+}
+
+func (controller *Bulk) GetJob(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	response, err := controller.Service.GetJob(appCtx, userID, jobIDParam(c))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get bulk job",
+		Results: response,
+	})
+}
+
+func (controller *Bulk) ListRecipients(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	request := domainBulk.ListRecipientsRequest{
+		Status: c.Query("status"),
+		Limit:  c.QueryInt("limit", 25),
+		Offset: c.QueryInt("offset", 0),
+	}
+
+	response, err := controller.Service.ListRecipients(appCtx, userID, jobIDParam(c), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get bulk job recipients",
+		Results: response,
+	})
+}
+
+func (controller *Bulk) CancelJob(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	response, err := controller.Service.CancelJob(appCtx, userID, jobIDParam(c))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success cancel bulk job",
+		Results: response,
+	})
+}
+
+func jobIDParam(c *fiber.Ctx) int64 {
+	id, err := strconv.ParseInt(c.Params("job_id"), 10, 64)
+	if err != nil {
+		panic(pkgError.ValidationError("invalid job id"))
+	}
+	return id
+}
+
+// parseRecipientsCSV reads recipients from a CSV file whose header row names a phone column,
+// the other columns are the template variables
+func parseRecipientsCSV(upload *multipart.FileHeader) ([]domainBulk.Recipient, error) {
+	file, err := upload.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, pkgError.ValidationError("recipients: CSV file is empty")
+	}
+	if err != nil {
+		return nil, pkgError.ValidationError(fmt.Sprintf("recipients: %v", err))
+	}
+
+	phoneColumn := -1
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		header[i] = name
+		if strings.EqualFold(name, "phone") {
+			phoneColumn = i
+		}
+	}
+	if phoneColumn < 0 {
+		return nil, pkgError.ValidationError("recipients: CSV header must have a phone column")
+	}
+
+	var recipients []domainBulk.Recipient
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, pkgError.ValidationError(fmt.Sprintf("recipients: %v", err))
+		}
+
+		// One more than allowed is enough for the validation to reject the file
+		if len(recipients) > domainBulk.MaxRecipients {
+			break
+		}
+
+		recipient := domainBulk.Recipient{Phone: strings.TrimSpace(record[phoneColumn]), Variables: map[string]string{}}
+		for i, value := range record {
+			if i != phoneColumn {
+				recipient.Variables[header[i]] = value
+			}
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	return recipients, nil
+}
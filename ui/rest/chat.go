@@ -1,8 +1,11 @@
 package rest
 
 import (
+	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
 	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -14,9 +17,9 @@ func InitRestChat(app fiber.Router, service domainChat.IChatUsecase) Chat {
 	rest := Chat{Service: service}
 
 	// Chat endpoints
-	app.Get("/chats", rest.ListChats)
-	app.Get("/chat/:chat_jid/messages", rest.GetChatMessages)
-	app.Post("/chat/:chat_jid/pin", rest.PinChat)
+	app.Get("/chats", middleware.RequireScope(permission.ChatRead), rest.ListChats)
+	app.Get("/chat/:chat_jid/messages", middleware.RequireScope(permission.ChatRead), rest.GetChatMessages)
+	app.Post("/chat/:chat_jid/pin", middleware.RequireScope(permission.ChatWrite), rest.PinChat)
 
 	return rest
 }
@@ -30,7 +33,7 @@ func (controller *Chat) ListChats(c *fiber.Ctx) error {
 	request.Search = c.Query("search", "")
 	request.HasMedia = c.QueryBool("has_media", false)
 
-	response, err := controller.Service.ListChats(c.UserContext(), request)
+	response, err := controller.Service.ListChats(domainApp.NewAppContext(c.UserContext(), c), request)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
@@ -67,7 +70,7 @@ func (controller *Chat) GetChatMessages(c *fiber.Ctx) error {
 		request.IsFromMe = &isFromMe
 	}
 
-	response, err := controller.Service.GetChatMessages(c.UserContext(), request)
+	response, err := controller.Service.GetChatMessages(domainApp.NewAppContext(c.UserContext(), c), request)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
@@ -94,7 +97,7 @@ func (controller *Chat) PinChat(c *fiber.Ctx) error {
 		})
 	}
 
-	response, err := controller.Service.PinChat(c.UserContext(), request)
+	response, err := controller.Service.PinChat(domainApp.NewAppContext(c.UserContext(), c), request)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
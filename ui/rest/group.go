@@ -5,8 +5,11 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
 	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
 	"github.com/gofiber/fiber/v2"
 	"go.mau.fi/whatsmeow"
 )
@@ -17,23 +20,23 @@ type Group struct {
 
 func InitRestGroup(app fiber.Router, service domainGroup.IGroupUsecase) Group {
 	rest := Group{Service: service}
-	app.Post("/group", rest.CreateGroup)
-	app.Post("/group/join-with-link", rest.JoinGroupWithLink)
-	app.Get("/group/info-from-link", rest.GetGroupInfoFromLink)
-	app.Get("/group/info", rest.GroupInfo)
-	app.Post("/group/leave", rest.LeaveGroup)
-	app.Post("/group/participants", rest.AddParticipants)
-	app.Post("/group/participants/remove", rest.DeleteParticipants)
-	app.Post("/group/participants/promote", rest.PromoteParticipants)
-	app.Post("/group/participants/demote", rest.DemoteParticipants)
-	app.Get("/group/participant-requests", rest.ListParticipantRequests)
-	app.Post("/group/participant-requests/approve", rest.ApproveParticipantRequests)
-	app.Post("/group/participant-requests/reject", rest.RejectParticipantRequests)
-	app.Post("/group/photo", rest.SetGroupPhoto)
-	app.Post("/group/name", rest.SetGroupName)
-	app.Post("/group/locked", rest.SetGroupLocked)
-	app.Post("/group/announce", rest.SetGroupAnnounce)
-	app.Post("/group/topic", rest.SetGroupTopic)
+	app.Post("/group", middleware.RequireScope(permission.GroupCreate), rest.CreateGroup)
+	app.Post("/group/join-with-link", middleware.RequireScope(permission.GroupJoin), rest.JoinGroupWithLink)
+	app.Get("/group/info-from-link", middleware.RequireScope(permission.GroupRead), rest.GetGroupInfoFromLink)
+	app.Get("/group/info", middleware.RequireScope(permission.GroupRead), rest.GroupInfo)
+	app.Post("/group/leave", middleware.RequireScope(permission.GroupLeave), rest.LeaveGroup)
+	app.Post("/group/participants", middleware.RequireScope(permission.GroupAdmin), rest.AddParticipants)
+	app.Post("/group/participants/remove", middleware.RequireScope(permission.GroupAdmin), rest.DeleteParticipants)
+	app.Post("/group/participants/promote", middleware.RequireScope(permission.GroupAdmin), rest.PromoteParticipants)
+	app.Post("/group/participants/demote", middleware.RequireScope(permission.GroupAdmin), rest.DemoteParticipants)
+	app.Get("/group/participant-requests", middleware.RequireScope(permission.GroupRead), rest.ListParticipantRequests)
+	app.Post("/group/participant-requests/approve", middleware.RequireScope(permission.GroupAdmin), rest.ApproveParticipantRequests)
+	app.Post("/group/participant-requests/reject", middleware.RequireScope(permission.GroupAdmin), rest.RejectParticipantRequests)
+	app.Post("/group/photo", middleware.RequireScope(permission.GroupAdmin), rest.SetGroupPhoto)
+	app.Post("/group/name", middleware.RequireScope(permission.GroupAdmin), rest.SetGroupName)
+	app.Post("/group/locked", middleware.RequireScope(permission.GroupAdmin), rest.SetGroupLocked)
+	app.Post("/group/announce", middleware.RequireScope(permission.GroupAdmin), rest.SetGroupAnnounce)
+	app.Post("/group/topic", middleware.RequireScope(permission.GroupAdmin), rest.SetGroupTopic)
 	return rest
 }
 
@@ -42,7 +45,7 @@ func (controller *Group) JoinGroupWithLink(c *fiber.Ctx) error {
 	err := c.BodyParser(&request)
 	utils.PanicIfNeeded(err)
 
-	response, err := controller.Service.JoinGroupWithLink(c.UserContext(), request)
+	response, err := controller.Service.JoinGroupWithLink(domainApp.NewAppContext(c.UserContext(), c), request)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
@@ -60,7 +63,7 @@ func (controller *Group) GetGroupInfoFromLink(c *fiber.Ctx) error {
 	err := c.QueryParser(&request)
 	utils.PanicIfNeeded(err)
 
-	response, err := controller.Service.GetGroupInfoFromLink(c.UserContext(), request)
+	response, err := controller.Service.GetGroupInfoFromLink(domainApp.NewAppContext(c.UserContext(), c), request)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
@@ -78,7 +81,7 @@ func (controller *Group) LeaveGroup(c *fiber.Ctx) error {
 
 	utils.SanitizePhone(&request.GroupID)
 
-	err = controller.Service.LeaveGroup(c.UserContext(), request)
+	err = controller.Service.LeaveGroup(domainApp.NewAppContext(c.UserContext(), c), request)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
@@ -93,7 +96,7 @@ func (controller *Group) CreateGroup(c *fiber.Ctx) error {
 	err := c.BodyParser(&request)
 	utils.PanicIfNeeded(err)
 
-	groupID, err := controller.Service.CreateGroup(c.UserContext(), request)
+	groupID, err := controller.Service.CreateGroup(domainApp.NewAppContext(c.UserContext(), c), request)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
@@ -136,7 +139,7 @@ func (controller *Group) ListParticipantRequests(c *fiber.Ctx) error {
 
 	utils.SanitizePhone(&request.GroupID)
 
-	result, err := controller.Service.GetGroupRequestParticipants(c.UserContext(), request)
+	result, err := controller.Service.GetGroupRequestParticipants(domainApp.NewAppContext(c.UserContext(), c), request)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
@@ -162,7 +165,7 @@ func (controller *Group) manageParticipants(c *fiber.Ctx, action whatsmeow.Parti
 	utils.PanicIfNeeded(err)
 	utils.SanitizePhone(&request.GroupID)
 	request.Action = action
-	result, err := controller.Service.ManageParticipant(c.UserContext(), request)
+	result, err := controller.Service.ManageParticipant(domainApp.NewAppContext(c.UserContext(), c), request)
 	utils.PanicIfNeeded(err)
 	return c.JSON(utils.ResponseData{
 		Status:  200,
@@ -179,7 +182,7 @@ func (controller *Group) handleRequestedParticipants(c *fiber.Ctx, action whatsm
 	utils.PanicIfNeeded(err)
 	utils.SanitizePhone(&request.GroupID)
 	request.Action = action
-	result, err := controller.Service.ManageGroupRequestParticipants(c.UserContext(), request)
+	result, err := controller.Service.ManageGroupRequestParticipants(domainApp.NewAppContext(c.UserContext(), c), request)
 	utils.PanicIfNeeded(err)
 	return c.JSON(utils.ResponseData{
 		Status:  200,
@@ -216,7 +219,7 @@ func (controller *Group) SetGroupPhoto(c *fiber.Ctx) error {
 		logrus.Printf("DEBUG: No photo file provided - Error: %v", err)
 	}
 
-	pictureID, err := controller.Service.SetGroupPhoto(c.UserContext(), request)
+	pictureID, err := controller.Service.SetGroupPhoto(domainApp.NewAppContext(c.UserContext(), c), request)
 	if err != nil {
 		logrus.Printf("ERROR: WhatsApp service failed to set group photo - %v", err)
 	}
@@ -245,7 +248,7 @@ func (controller *Group) SetGroupName(c *fiber.Ctx) error {
 
 	utils.SanitizePhone(&request.GroupID)
 
-	err = controller.Service.SetGroupName(c.UserContext(), request)
+	err = controller.Service.SetGroupName(domainApp.NewAppContext(c.UserContext(), c), request)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
@@ -262,7 +265,7 @@ func (controller *Group) SetGroupLocked(c *fiber.Ctx) error {
 
 	utils.SanitizePhone(&request.GroupID)
 
-	err = controller.Service.SetGroupLocked(c.UserContext(), request)
+	err = controller.Service.SetGroupLocked(domainApp.NewAppContext(c.UserContext(), c), request)
 	utils.PanicIfNeeded(err)
 
 	message := "Success set group as unlocked"
@@ -284,7 +287,7 @@ func (controller *Group) SetGroupAnnounce(c *fiber.Ctx) error {
 
 	utils.SanitizePhone(&request.GroupID)
 
-	err = controller.Service.SetGroupAnnounce(c.UserContext(), request)
+	err = controller.Service.SetGroupAnnounce(domainApp.NewAppContext(c.UserContext(), c), request)
 	utils.PanicIfNeeded(err)
 
 	message := "Success disable announce mode"
@@ -306,7 +309,7 @@ func (controller *Group) SetGroupTopic(c *fiber.Ctx) error {
 
 	utils.SanitizePhone(&request.GroupID)
 
-	err = controller.Service.SetGroupTopic(c.UserContext(), request)
+	err = controller.Service.SetGroupTopic(domainApp.NewAppContext(c.UserContext(), c), request)
 	utils.PanicIfNeeded(err)
 
 	message := "Success update group topic"
@@ -329,7 +332,7 @@ func (controller *Group) GroupInfo(c *fiber.Ctx) error {
 
 	utils.SanitizePhone(&request.GroupID)
 
-	response, err := controller.Service.GroupInfo(c.UserContext(), request)
+	response, err := controller.Service.GroupInfo(domainApp.NewAppContext(c.UserContext(), c), request)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
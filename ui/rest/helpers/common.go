@@ -259,6 +259,11 @@ func SetAutoReconnectCheckingForAllUsers() {
 
 			for userID, session := range activeSessions {
 				if session.Client != nil && !session.Client.IsConnected() {
+					// Sessions opened on another device stay disconnected unless the policy reclaims them
+					if !session.ShouldAutoReconnect() {
+						logrus.Debugf("[AUTO-RECONNECT] User %d (%s) was replaced by another device, keeping disconnected", userID, session.Username)
+						continue
+					}
 					// Only reconnect if user is already logged in to WhatsApp
 					if session.Client.IsLoggedIn() {
 						logrus.Infof("[AUTO-RECONNECT] User %d (%s) was logged in but disconnected, attempting reconnection...", userID, session.Username)
@@ -0,0 +1,31 @@
+package rest
+
+import (
+	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
+	domainUserManagement "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/usermanagement"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Media struct {
+	Service domainMessage.IMessageUsecase
+}
+
+func InitRestMedia(app fiber.Router, service domainMessage.IMessageUsecase, userService domainUserManagement.IUserManagementUsecase) Media {
+	rest := Media{Service: service}
+	app.Get(utils.MediaURLPath+"/:user_id/:message_id", middleware.SignedMediaURL(userService), rest.DownloadMedia)
+	return rest
+}
+
+// DownloadMedia serves the media of a stored message to the holder of a signed media URL
+func (controller *Media) DownloadMedia(c *fiber.Ctx) error {
+	var request domainMessage.DownloadMediaRequest
+	request.MessageID = c.Params("message_id")
+
+	response, err := controller.Service.DownloadMedia(domainApp.NewAppContext(c.UserContext(), c), request)
+	utils.PanicIfNeeded(err)
+
+	return sendMediaFile(c, response)
+}
@@ -1,8 +1,11 @@
 package rest
 
 import (
+	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
 	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -14,13 +17,14 @@ func InitRestMessage(app fiber.Router, service domainMessage.IMessageUsecase) Me
 	rest := Message{Service: service}
 
 	// Message action endpoints
-	app.Post("/message/:message_id/reaction", rest.ReactMessage)
-	app.Post("/message/:message_id/revoke", rest.RevokeMessage)
-	app.Post("/message/:message_id/delete", rest.DeleteMessage)
-	app.Post("/message/:message_id/update", rest.UpdateMessage)
-	app.Post("/message/:message_id/read", rest.MarkAsRead)
-	app.Post("/message/:message_id/star", rest.StarMessage)
-	app.Post("/message/:message_id/unstar", rest.UnstarMessage)
+	app.Post("/message/:message_id/reaction", middleware.RequireScope(permission.MessageReact), rest.ReactMessage)
+	app.Post("/message/:message_id/revoke", middleware.RequireScope(permission.MessageRevoke), rest.RevokeMessage)
+	app.Post("/message/:message_id/delete", middleware.RequireScope(permission.MessageDelete), rest.DeleteMessage)
+	app.Post("/message/:message_id/update", middleware.RequireScope(permission.MessageUpdate), rest.UpdateMessage)
+	app.Post("/message/:message_id/read", middleware.RequireScope(permission.MessageRead), rest.MarkAsRead)
+	app.Post("/message/:message_id/star", middleware.RequireScope(permission.MessageStar), rest.StarMessage)
+	app.Post("/message/:message_id/unstar", middleware.RequireScope(permission.MessageStar), rest.UnstarMessage)
+	app.Get("/message/:message_id/download", middleware.RequireScope(permission.MessageDownload), rest.DownloadMedia)
 	return rest
 }
 
@@ -32,7 +36,7 @@ func (controller *Message) RevokeMessage(c *fiber.Ctx) error {
 	request.MessageID = c.Params("message_id")
 	utils.SanitizePhone(&request.Phone)
 
-	response, err := controller.Service.RevokeMessage(c.UserContext(), request)
+	response, err := controller.Service.RevokeMessage(domainApp.NewAppContext(c.UserContext(), c), request)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
@@ -51,7 +55,7 @@ func (controller *Message) DeleteMessage(c *fiber.Ctx) error {
 	request.MessageID = c.Params("message_id")
 	utils.SanitizePhone(&request.Phone)
 
-	err = controller.Service.DeleteMessage(c.UserContext(), request)
+	err = controller.Service.DeleteMessage(domainApp.NewAppContext(c.UserContext(), c), request)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
@@ -70,7 +74,7 @@ func (controller *Message) UpdateMessage(c *fiber.Ctx) error {
 	request.MessageID = c.Params("message_id")
 	utils.SanitizePhone(&request.Phone)
 
-	response, err := controller.Service.UpdateMessage(c.UserContext(), request)
+	response, err := controller.Service.UpdateMessage(domainApp.NewAppContext(c.UserContext(), c), request)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
@@ -89,7 +93,7 @@ func (controller *Message) ReactMessage(c *fiber.Ctx) error {
 	request.MessageID = c.Params("message_id")
 	utils.SanitizePhone(&request.Phone)
 
-	response, err := controller.Service.ReactMessage(c.UserContext(), request)
+	response, err := controller.Service.ReactMessage(domainApp.NewAppContext(c.UserContext(), c), request)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
@@ -108,7 +112,7 @@ func (controller *Message) MarkAsRead(c *fiber.Ctx) error {
 	request.MessageID = c.Params("message_id")
 	utils.SanitizePhone(&request.Phone)
 
-	response, err := controller.Service.MarkAsRead(c.UserContext(), request)
+	response, err := controller.Service.MarkAsRead(domainApp.NewAppContext(c.UserContext(), c), request)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
@@ -128,7 +132,7 @@ func (controller *Message) StarMessage(c *fiber.Ctx) error {
 	utils.SanitizePhone(&request.Phone)
 	request.IsStarred = true
 
-	err = controller.Service.StarMessage(c.UserContext(), request)
+	err = controller.Service.StarMessage(domainApp.NewAppContext(c.UserContext(), c), request)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
@@ -147,7 +151,7 @@ func (controller *Message) UnstarMessage(c *fiber.Ctx) error {
 	request.MessageID = c.Params("message_id")
 	utils.SanitizePhone(&request.Phone)
 	request.IsStarred = false
-	err = controller.Service.StarMessage(c.UserContext(), request)
+	err = controller.Service.StarMessage(domainApp.NewAppContext(c.UserContext(), c), request)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
@@ -157,3 +161,24 @@ func (controller *Message) UnstarMessage(c *fiber.Ctx) error {
 		Results: nil,
 	})
 }
+
+// DownloadMedia serves the media of a stored message. The file is served from disk, which answers range requests.
+func (controller *Message) DownloadMedia(c *fiber.Ctx) error {
+	var request domainMessage.DownloadMediaRequest
+	request.MessageID = c.Params("message_id")
+
+	response, err := controller.Service.DownloadMedia(domainApp.NewAppContext(c.UserContext(), c), request)
+	utils.PanicIfNeeded(err)
+
+	return sendMediaFile(c, response)
+}
+
+// sendMediaFile serves downloaded media with its type and filename
+func sendMediaFile(c *fiber.Ctx, response domainMessage.DownloadMediaResponse) error {
+	if err := c.SendFile(response.FilePath); err != nil {
+		return err
+	}
+	c.Attachment(response.Filename)
+	c.Set(fiber.HeaderContentType, response.MimeType)
+	return nil
+}
@@ -4,136 +4,85 @@ import (
 	"encoding/base64"
 	"strings"
 
-	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
 	domainUserManagement "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/usermanagement"
-	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
 )
 
-// AdminBasicAuth middleware untuk mengamankan admin endpoints
-func AdminBasicAuth() fiber.Handler {
+const (
+	AdminIDKey = "admin_id"
+	// AdminUsernameKey is read back by domainApp.NewAppContext
+	AdminUsernameKey = domainApp.AdminUsernameKey
+)
+
+// AdminBasicAuth middleware untuk mengamankan admin endpoints.
+// Admin accounts are users with the admin role, verified against the database; auditors may only read.
+func AdminBasicAuth(userUsecase domainUserManagement.IUserManagementUsecase) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		logrus.Debugf("AdminBasicAuth middleware called for path: %s", c.Path())
 		auth := c.Get("Authorization")
 		if auth == "" {
 			c.Set("WWW-Authenticate", `Basic realm="Admin Area"`)
-			return c.Status(fiber.StatusUnauthorized).JSON(utils.ResponseData{
-				Status:  fiber.StatusUnauthorized,
-				Code:    "UNAUTHORIZED",
-				Message: "Authorization required",
-			})
+			return unauthorized(c, "Authorization required")
 		}
 
 		if !strings.HasPrefix(auth, "Basic ") {
-			return c.Status(fiber.StatusUnauthorized).JSON(utils.ResponseData{
-				Status:  fiber.StatusUnauthorized,
-				Code:    "UNAUTHORIZED",
-				Message: "Invalid authorization format",
-			})
+			return unauthorized(c, "Invalid authorization format")
 		}
 
 		payload, err := base64.StdEncoding.DecodeString(auth[6:])
 		if err != nil {
-			return c.Status(fiber.StatusUnauthorized).JSON(utils.ResponseData{
-				Status:  fiber.StatusUnauthorized,
-				Code:    "UNAUTHORIZED",
-				Message: "Invalid authorization encoding",
-			})
+			return unauthorized(c, "Invalid authorization encoding")
 		}
 
 		pair := strings.SplitN(string(payload), ":", 2)
 		if len(pair) != 2 {
-			return c.Status(fiber.StatusUnauthorized).JSON(utils.ResponseData{
-				Status:  fiber.StatusUnauthorized,
-				Code:    "UNAUTHORIZED",
-				Message: "Invalid authorization format",
-			})
+			return unauthorized(c, "Invalid authorization format")
 		}
 
 		username, password := pair[0], pair[1]
 
-		// Check admin credentials from config
-		if config.AdminUsername != "" && config.AdminPassword != "" {
-			logrus.Debugf("Admin auth - checking credentials: %s vs %s", username, config.AdminUsername)
-			if username == config.AdminUsername && password == config.AdminPassword {
-				return c.Next()
-			}
+		user, err := userUsecase.GetUserByUsername(username)
+		if err != nil || user == nil || !domainUserManagement.IsAdminRole(user.Role) {
+			return unauthorized(c, "Invalid admin credentials")
+		}
+		if !userUsecase.ValidateUserCredentials(username, password) {
+			return unauthorized(c, "Invalid admin credentials")
 		}
 
-		return c.Status(fiber.StatusUnauthorized).JSON(utils.ResponseData{
-			Status:  fiber.StatusUnauthorized,
-			Code:    "UNAUTHORIZED",
-			Message: "Invalid admin credentials",
-		})
+		if user.Role == domainUserManagement.RoleAuditor && c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+			return forbidden(c, "Auditors have read-only access")
+		}
+
+		c.Locals(AdminIDKey, user.ID)
+		c.Locals(AdminUsernameKey, user.Username)
+
+		logrus.Debugf("AdminBasicAuth: Authenticated %s %s (ID: %d)", user.Role, user.Username, user.ID)
+		return c.Next()
 	}
 }
 
-// UserBasicAuth middleware yang menggunakan database untuk validasi user
-func UserBasicAuth(userUsecase domainUserManagement.IUserManagementUsecase) fiber.Handler {
+// UserBasicAuth middleware yang menggunakan database untuk validasi user,
+// dengan Basic credentials atau API key sebagai Bearer token
+func UserBasicAuth(userUsecase domainUserManagement.IUserManagementUsecase, apiKeyUsecase domainAPIKey.IAPIKeyUsecase) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		logrus.Debugf("UserBasicAuth middleware called for path: %s", c.Path())
-		auth := c.Get("Authorization")
-		if auth == "" {
+		if c.Get("Authorization") == "" {
 			c.Set("WWW-Authenticate", `Basic realm="User Area"`)
-			return c.Status(fiber.StatusUnauthorized).JSON(utils.ResponseData{
-				Status:  fiber.StatusUnauthorized,
-				Code:    "UNAUTHORIZED",
-				Message: "Authorization required",
-			})
 		}
 
-		if !strings.HasPrefix(auth, "Basic ") {
-			return c.Status(fiber.StatusUnauthorized).JSON(utils.ResponseData{
-				Status:  fiber.StatusUnauthorized,
-				Code:    "UNAUTHORIZED",
-				Message: "Invalid authorization format",
-			})
+		user, message := authenticateUser(c, userUsecase, apiKeyUsecase)
+		if user == nil {
+			return unauthorized(c, message)
 		}
 
-		payload, err := base64.StdEncoding.DecodeString(auth[6:])
-		if err != nil {
-			return c.Status(fiber.StatusUnauthorized).JSON(utils.ResponseData{
-				Status:  fiber.StatusUnauthorized,
-				Code:    "UNAUTHORIZED",
-				Message: "Invalid authorization encoding",
-			})
-		}
-
-		pair := strings.SplitN(string(payload), ":", 2)
-		if len(pair) != 2 {
-			return c.Status(fiber.StatusUnauthorized).JSON(utils.ResponseData{
-				Status:  fiber.StatusUnauthorized,
-				Code:    "UNAUTHORIZED",
-				Message: "Invalid authorization format",
-			})
-		}
-
-		username, password := pair[0], pair[1]
-
-		// Validate user credentials from database
-		user, err := userUsecase.GetUserByUsername(username)
-		if err != nil || user == nil {
-			return c.Status(fiber.StatusUnauthorized).JSON(utils.ResponseData{
-				Status:  fiber.StatusUnauthorized,
-				Code:    "UNAUTHORIZED",
-				Message: "Invalid user credentials",
-			})
-		}
-
-		if userUsecase.ValidateUserCredentials(username, password) {
-			// Store user info in context for template rendering
-			c.Locals("user_id", user.ID)
-			c.Locals("username", user.Username)
-
-			logrus.Debugf("UserBasicAuth: Authenticated user %s (ID: %d)", user.Username, user.ID)
-			return c.Next()
-		}
+		// Store user info in context for template rendering
+		c.Locals("user_id", user.ID)
+		c.Locals("username", user.Username)
 
-		return c.Status(fiber.StatusUnauthorized).JSON(utils.ResponseData{
-			Status:  fiber.StatusUnauthorized,
-			Code:    "UNAUTHORIZED",
-			Message: "Invalid user credentials",
-		})
+		logrus.Debugf("UserBasicAuth: Authenticated user %s (ID: %d)", user.Username, user.ID)
+		return c.Next()
 	}
 }
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
+	domainUserManagement "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/usermanagement"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// APIKeyIDKey and GrantsKey are read back by domainApp.NewAppContext
+	APIKeyIDKey = domainApp.APIKeyIDKey
+	GrantsKey   = domainApp.GrantsKey
+	// authenticatedUserKey lets the user and session middlewares share one verification per request
+	authenticatedUserKey = "authenticated_user"
+)
+
+// authenticateUser verifies the Authorization header, either Basic user credentials or a Bearer API key,
+// and returns the user it belongs to. On failure the message to answer with is returned instead.
+func authenticateUser(c *fiber.Ctx, userUsecase domainUserManagement.IUserManagementUsecase, apiKeyUsecase domainAPIKey.IAPIKeyUsecase) (*domainUserManagement.UserResponse, string) {
+	if user, ok := c.Locals(authenticatedUserKey).(*domainUserManagement.UserResponse); ok {
+		return user, ""
+	}
+
+	var (
+		user    *domainUserManagement.UserResponse
+		message string
+	)
+	auth := c.Get("Authorization")
+	switch {
+	case auth == "":
+		return nil, "Authorization required"
+	case strings.HasPrefix(auth, "Basic "):
+		user, message = authenticateBasic(c, auth[6:], userUsecase)
+	case strings.HasPrefix(auth, "Bearer ") && apiKeyUsecase != nil:
+		user, message = authenticateBearer(c, strings.TrimSpace(auth[7:]), userUsecase, apiKeyUsecase)
+	default:
+		return nil, "Invalid authorization format"
+	}
+
+	if user != nil {
+		c.Locals(authenticatedUserKey, user)
+	}
+	return user, message
+}
+
+func authenticateBasic(c *fiber.Ctx, encoded string, userUsecase domainUserManagement.IUserManagementUsecase) (*domainUserManagement.UserResponse, string) {
+	payload, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, "Invalid authorization encoding"
+	}
+
+	pair := strings.SplitN(string(payload), ":", 2)
+	if len(pair) != 2 {
+		return nil, "Invalid authorization format"
+	}
+
+	username, password := pair[0], pair[1]
+
+	// Validate user credentials from database
+	user, err := userUsecase.GetUserByUsername(username)
+	if err != nil || user == nil {
+		return nil, "Invalid user credentials"
+	}
+	if !userUsecase.ValidateUserCredentials(username, password) {
+		return nil, "Invalid user credentials"
+	}
+
+	c.Locals(GrantsKey, []permission.Grant{user.Grant})
+	return user, ""
+}
+
+func authenticateBearer(c *fiber.Ctx, token string, userUsecase domainUserManagement.IUserManagementUsecase, apiKeyUsecase domainAPIKey.IAPIKeyUsecase) (*domainUserManagement.UserResponse, string) {
+	key, err := apiKeyUsecase.Authenticate(c.UserContext(), token)
+	if err != nil {
+		var authErr pkgError.AuthError
+		if errors.As(err, &authErr) {
+			return nil, authErr.Error()
+		}
+		logrus.Errorf("Failed to verify API key: %v", err)
+		return nil, "Invalid API key"
+	}
+
+	// The key stops working as soon as its owner is deactivated or deleted
+	user, err := userUsecase.GetUser(key.UserID)
+	if err != nil || user == nil || !user.IsActive {
+		return nil, "Invalid user credentials"
+	}
+
+	c.Locals(APIKeyIDKey, key.ID)
+	c.Locals(GrantsKey, []permission.Grant{user.Grant, key.Grant})
+	return user, ""
+}
+
+func unauthorized(c *fiber.Ctx, message string) error {
+	return c.Status(fiber.StatusUnauthorized).JSON(utils.ResponseData{
+		Status:  fiber.StatusUnauthorized,
+		Code:    "UNAUTHORIZED",
+		Message: message,
+	})
+}
@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"mime/multipart"
+	"sort"
+	"strings"
+
+	domainIdempotency "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/idempotency"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// Idempotency answers a POST retried with the same Idempotency-Key header with the stored response of the first
+// request instead of handling it again. Only successful responses are stored, so failed requests can be retried.
+func Idempotency(service domainIdempotency.IIdempotencyUsecase) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(IdempotencyKeyHeader)
+		if key == "" || c.Method() != fiber.MethodPost {
+			return c.Next()
+		}
+
+		userID, ok := c.Locals(UserIDKey).(int)
+		if !ok {
+			return c.Next()
+		}
+
+		fingerprint, err := requestFingerprint(c)
+		utils.PanicIfNeeded(err)
+
+		record, err := service.Begin(c.UserContext(), userID, key, fingerprint)
+		utils.PanicIfNeeded(err)
+		if record != nil {
+			c.Set(IdempotentReplayedHeader, "true")
+			c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			return c.Status(record.StatusCode).Send(record.Response)
+		}
+
+		// Panics and failed responses give the key back, the request did not send anything worth replaying
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			if err := service.Release(c.UserContext(), userID, key); err != nil {
+				logrus.Errorf("Failed to release idempotency key %s of user %d: %v", key, userID, err)
+			}
+		}()
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		status := c.Response().StatusCode()
+		if status >= fiber.StatusOK && status < fiber.StatusMultipleChoices {
+			response := append([]byte(nil), c.Response().Body()...)
+			if err := service.Complete(c.UserContext(), userID, key, status, response); err != nil {
+				logrus.Errorf("Failed to store response of idempotency key %s of user %d: %v", key, userID, err)
+				return nil
+			}
+			completed = true
+		}
+		return nil
+	}
+}
+
+// requestFingerprint hashes what makes two requests the same: method, path and payload. Multipart payloads are
+// hashed by their fields and file contents, as the boundary changes between retries.
+func requestFingerprint(c *fiber.Ctx) (string, error) {
+	digest := sha256.New()
+	fmt.Fprintf(digest, "%s %s\n", c.Method(), c.Path())
+
+	if !strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		digest.Write(c.Body())
+		return hex.EncodeToString(digest.Sum(nil)), nil
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		return "", pkgError.ValidationError(fmt.Sprintf("invalid multipart form: %v", err))
+	}
+	for _, name := range sortedKeys(form.Value) {
+		fmt.Fprintf(digest, "%q=%q\n", name, form.Value[name])
+	}
+	for _, name := range sortedKeys(form.File) {
+		for _, file := range form.File[name] {
+			fmt.Fprintf(digest, "%q:%q:%d\n", name, file.Filename, file.Size)
+			if err := hashFile(digest, file); err != nil {
+				return "", err
+			}
+		}
+	}
+	return hex.EncodeToString(digest.Sum(nil)), nil
+}
+
+func hashFile(digest hash.Hash, header *multipart.FileHeader) error {
+	file, err := header.Open()
+	if err != nil {
+		return pkgError.InternalServerError(fmt.Sprintf("failed to read uploaded file: %v", err))
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(digest, file); err != nil {
+		return pkgError.InternalServerError(fmt.Sprintf("failed to read uploaded file: %v", err))
+	}
+	return nil
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
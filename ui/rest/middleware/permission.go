@@ -0,0 +1,186 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	fiberUtils "github.com/gofiber/fiber/v2/utils"
+)
+
+// chatFields are the request fields that name the chat a route acts on
+var chatFields = []string{"phone", "group_id", "chat_jid", "newsletter_id"}
+
+// RequireScope rejects requests whose user or API key lacks the scope, or which target a chat
+// outside of the allowed chats
+func RequireScope(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		grants, ok := GetGrantsFromContext(c)
+		if !ok {
+			return forbidden(c, "Permissions are not available for this request")
+		}
+
+		for _, grant := range grants {
+			if !grant.Allows(scope) {
+				return forbidden(c, fmt.Sprintf("Missing permission %s", scope))
+			}
+		}
+
+		for _, chat := range requestChats(c) {
+			for _, grant := range grants {
+				if !grant.AllowsChat(chat) {
+					return forbidden(c, fmt.Sprintf("Access to chat %s is not allowed", chat))
+				}
+			}
+		}
+
+		return c.Next()
+	}
+}
+
+// GetGrantsFromContext extracts what the authenticated request may do from fiber context
+func GetGrantsFromContext(c *fiber.Ctx) ([]permission.Grant, bool) {
+	grants, ok := c.Locals(GrantsKey).([]permission.Grant)
+	return grants, ok
+}
+
+// ChatAllowed reports whether the request may act on a chat that RequireScope cannot see, such as the
+// recipients of a bulk send
+func ChatAllowed(c *fiber.Ctx, chat string) bool {
+	grants, ok := GetGrantsFromContext(c)
+	if !ok {
+		return false
+	}
+	for _, grant := range grants {
+		if !grant.AllowsChat(chat) {
+			return false
+		}
+	}
+	return true
+}
+
+// GrantsCover reports whether the request may hand out an API key grant, so a key cannot create a broader key.
+// Empty lists of the grant inherit from the user, as they do when the key is used.
+func GrantsCover(c *fiber.Ctx, grant permission.Grant) bool {
+	grants, ok := GetGrantsFromContext(c)
+	if !ok || len(grants) == 0 {
+		return false
+	}
+	grant = grant.Inherit(grants[0])
+	for _, current := range grants {
+		if !current.Covers(grant) {
+			return false
+		}
+	}
+	return true
+}
+
+// requestChats collects the chats named in the path, query and body of a request
+func requestChats(c *fiber.Ctx) []string {
+	var chats []string
+	add := func(value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			chats = append(chats, value)
+		}
+	}
+
+	if chatJID, err := url.PathUnescape(c.Params("chat_jid")); err == nil {
+		add(chatJID)
+	}
+	for _, field := range chatFields {
+		add(c.Query(field))
+	}
+
+	for _, chat := range bodyChats(c) {
+		add(chat)
+	}
+
+	return chats
+}
+
+// bodyChats returns the chat fields of the body. It decides how the body is encoded the way fiber's BodyParser
+// does, so every body the handlers parse is checked. Like the decoders, field names match regardless of case.
+func bodyChats(c *fiber.Ctx) []string {
+	if c.Method() == fiber.MethodGet {
+		return nil
+	}
+
+	ctype := fiberUtils.ParseVendorSpecificContentType(fiberUtils.ToLower(string(c.Request().Header.ContentType())))
+	ctype, _, _ = strings.Cut(ctype, ";")
+
+	var chats []string
+	switch {
+	case strings.HasSuffix(ctype, "json"):
+		var body map[string]any
+		if err := json.Unmarshal(c.Body(), &body); err == nil {
+			for key, value := range body {
+				if text, ok := value.(string); ok && isChatField(key) {
+					chats = append(chats, text)
+				}
+			}
+		}
+	case strings.HasSuffix(ctype, "xml"):
+		chats = xmlChats(c.Body())
+	default:
+		for _, field := range chatFields {
+			chats = append(chats, c.FormValue(field))
+		}
+	}
+	return chats
+}
+
+// xmlChats returns the text of the chat fields directly under the root element of an XML body
+func xmlChats(body []byte) []string {
+	var chats []string
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	depth := 0
+	field := ""
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return chats
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			depth++
+			if depth == 2 && isChatField(token.Name.Local) {
+				field = token.Name.Local
+			}
+		case xml.EndElement:
+			if depth == 2 {
+				field = ""
+			}
+			depth--
+		case xml.CharData:
+			if field != "" {
+				chats = append(chats, string(token))
+			}
+		}
+	}
+}
+
+// isChatField reports whether a body field names a chat; the JSON decoder matches struct fields regardless of case,
+// and the XML decoder matches the Go field names
+func isChatField(name string) bool {
+	name = strings.ReplaceAll(name, "_", "")
+	for _, field := range chatFields {
+		if strings.EqualFold(name, strings.ReplaceAll(field, "_", "")) {
+			return true
+		}
+	}
+	return false
+}
+
+func forbidden(c *fiber.Ctx, message string) error {
+	return c.Status(fiber.StatusForbidden).JSON(utils.ResponseData{
+		Status:  fiber.StatusForbidden,
+		Code:    "FORBIDDEN",
+		Message: message,
+	})
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireScope(t *testing.T) {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals(GrantsKey, []permission.Grant{{Scopes: permission.List{permission.SendText}, AllowedChats: permission.List{"628123456789"}}})
+		return c.Next()
+	})
+	app.All("/send/message", RequireScope(permission.SendText), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Get("/group/info", RequireScope(permission.GroupRead), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	multipartBody := func(phone string) (string, string) {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		require.NoError(t, writer.WriteField("phone", phone))
+		require.NoError(t, writer.Close())
+		return body.String(), writer.FormDataContentType()
+	}
+	allowedMultipart, allowedMultipartType := multipartBody("628123456789")
+	deniedMultipart, deniedMultipartType := multipartBody("628987654321")
+
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		contentType string
+		body        string
+		want        int
+	}{
+		{name: "should deny missing scope", method: http.MethodGet, target: "/group/info", want: fiber.StatusForbidden},
+		{name: "should allow chat in query", method: http.MethodGet, target: "/send/message?phone=628123456789", want: fiber.StatusOK},
+		{name: "should deny chat in query", method: http.MethodGet, target: "/send/message?phone=628987654321", want: fiber.StatusForbidden},
+		{
+			name: "should allow chat in json", method: http.MethodPost, target: "/send/message",
+			contentType: fiber.MIMEApplicationJSON, body: `{"phone": "628123456789@s.whatsapp.net"}`, want: fiber.StatusOK,
+		},
+		{
+			name: "should deny chat in json", method: http.MethodPost, target: "/send/message",
+			contentType: fiber.MIMEApplicationJSONCharsetUTF8, body: `{"phone": "628987654321"}`, want: fiber.StatusForbidden,
+		},
+		{
+			name: "should deny chat in json with other content type", method: http.MethodPost, target: "/send/message",
+			contentType: "text/json", body: `{"phone": "628987654321"}`, want: fiber.StatusForbidden,
+		},
+		{
+			name: "should deny chat in json with upper case content type", method: http.MethodPost, target: "/send/message",
+			contentType: "Application/JSON", body: `{"phone": "628987654321"}`, want: fiber.StatusForbidden,
+		},
+		{
+			name: "should deny chat in json with vendor content type", method: http.MethodPost, target: "/send/message",
+			contentType: "application/vnd.api+json", body: `{"phone": "628987654321"}`, want: fiber.StatusForbidden,
+		},
+		{
+			name: "should deny chat in json field of other case", method: http.MethodPost, target: "/send/message",
+			contentType: fiber.MIMEApplicationJSON, body: `{"PHONE": "628987654321"}`, want: fiber.StatusForbidden,
+		},
+		{
+			name: "should deny chat in xml", method: http.MethodPost, target: "/send/message",
+			contentType: fiber.MIMEApplicationXML, body: `<request><Phone>628987654321</Phone></request>`, want: fiber.StatusForbidden,
+		},
+		{
+			name: "should allow chat in form", method: http.MethodPost, target: "/send/message",
+			contentType: fiber.MIMEApplicationForm, body: "phone=628123456789", want: fiber.StatusOK,
+		},
+		{
+			name: "should deny chat in form", method: http.MethodPost, target: "/send/message",
+			contentType: fiber.MIMEApplicationForm, body: "group_id=120363402106123456%40g.us", want: fiber.StatusForbidden,
+		},
+		{
+			name: "should allow chat in multipart form", method: http.MethodPost, target: "/send/message",
+			contentType: allowedMultipartType, body: allowedMultipart, want: fiber.StatusOK,
+		},
+		{
+			name: "should deny chat in multipart form", method: http.MethodPost, target: "/send/message",
+			contentType: deniedMultipartType, body: deniedMultipart, want: fiber.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set(fiber.HeaderContentType, tt.contentType)
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, resp.StatusCode)
+		})
+	}
+}
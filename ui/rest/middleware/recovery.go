@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"fmt"
+	"strconv"
 
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
@@ -29,6 +30,11 @@ func Recovery() fiber.Handler {
 					res.Message = errValidation.Error()
 				}
 
+				if errRateLimit, ok := err.(pkgError.RateLimitError); ok {
+					ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(errRateLimit.RetryAfter))
+					res.Results = errRateLimit
+				}
+
 				_ = ctx.Status(res.Status).JSON(res)
 			}
 		}()
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
+	domainUserManagement "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/usermanagement"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SignedMediaURL authenticates a media download by the signature of its URL instead of credentials, so that
+// webhook receivers can fetch media with a plain GET. The URL names the user whose media it serves.
+func SignedMediaURL(userUsecase domainUserManagement.IUserManagementUsecase) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, err := c.ParamsInt("user_id")
+		if err != nil || userID <= 0 {
+			return forbidden(c, "invalid media url")
+		}
+
+		err = utils.VerifyMediaURL(config.WhatsappMediaURLSecret, userID, c.Params("message_id"), int64(c.QueryInt("expires")), c.Query("signature"), time.Now())
+		if err != nil {
+			return forbidden(c, err.Error())
+		}
+
+		user, err := userUsecase.GetUser(userID)
+		if err != nil || user == nil || !user.IsActive {
+			return forbidden(c, "invalid media url")
+		}
+
+		c.Locals(UserIDKey, user.ID)
+		c.Locals(UsernameKey, user.Username)
+		// The URL was signed for the user, not for an API key, so the user's own chat limits apply
+		c.Locals(GrantsKey, []permission.Grant{user.Grant})
+		return c.Next()
+	}
+}
@@ -2,9 +2,8 @@ package middleware
 
 import (
 	"context"
-	"encoding/base64"
-	"strings"
 
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	domainUserManagement "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/usermanagement"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
@@ -19,47 +18,13 @@ const (
 	UsernameKey    = "username"
 )
 
-// UserSessionMiddleware extracts user information and ensures user has a WhatsApp session
-func UserSessionMiddleware(userUsecase domainUserManagement.IUserManagementUsecase, chatStorageRepo domainChatStorage.IChatStorageRepository) fiber.Handler {
+// UserSessionMiddleware authenticates the user and ensures user has a WhatsApp session
+func UserSessionMiddleware(userUsecase domainUserManagement.IUserManagementUsecase, apiKeyUsecase domainAPIKey.IAPIKeyUsecase, chatStorageRepo domainChatStorage.IChatStorageRepository) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		// Extract user credentials from Basic Auth
-		auth := c.Get("Authorization")
-		if auth == "" || !strings.HasPrefix(auth, "Basic ") {
-			return c.Status(fiber.StatusUnauthorized).JSON(utils.ResponseData{
-				Status:  fiber.StatusUnauthorized,
-				Code:    "UNAUTHORIZED",
-				Message: "Authorization required",
-			})
-		}
-
-		payload, err := base64.StdEncoding.DecodeString(auth[6:])
-		if err != nil {
-			return c.Status(fiber.StatusUnauthorized).JSON(utils.ResponseData{
-				Status:  fiber.StatusUnauthorized,
-				Code:    "UNAUTHORIZED",
-				Message: "Invalid authorization encoding",
-			})
-		}
-
-		pair := strings.SplitN(string(payload), ":", 2)
-		if len(pair) != 2 {
-			return c.Status(fiber.StatusUnauthorized).JSON(utils.ResponseData{
-				Status:  fiber.StatusUnauthorized,
-				Code:    "UNAUTHORIZED",
-				Message: "Invalid authorization format",
-			})
-		}
-
-		username := pair[0]
-
-		// Get user from database
-		user, err := userUsecase.GetUserByUsername(username)
-		if err != nil || user == nil {
-			return c.Status(fiber.StatusUnauthorized).JSON(utils.ResponseData{
-				Status:  fiber.StatusUnauthorized,
-				Code:    "UNAUTHORIZED",
-				Message: "Invalid user credentials",
-			})
+		// Verify the full credentials, Basic or Bearer, on every request
+		user, message := authenticateUser(c, userUsecase, apiKeyUsecase)
+		if user == nil {
+			return unauthorized(c, message)
 		}
 
 		// Set user information in context
@@ -2,7 +2,9 @@ package rest
 
 import (
 	domainNewsletter "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/newsletter"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -12,7 +14,7 @@ type Newsletter struct {
 
 func InitRestNewsletter(app fiber.Router, service domainNewsletter.INewsletterUsecase) Newsletter {
 	rest := Newsletter{Service: service}
-	app.Post("/newsletter/unfollow", rest.Unfollow)
+	app.Post("/newsletter/unfollow", middleware.RequireScope(permission.NewsletterManage), rest.Unfollow)
 	return rest
 }
 
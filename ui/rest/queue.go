@@ -0,0 +1,90 @@
+package rest
+
+import (
+	"strconv"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
+	domainQueue "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/queue"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Queue struct {
+	Service domainQueue.IQueueUsecase
+}
+
+// InitRestQueue registers the send queue of the authenticated user
+func InitRestQueue(app fiber.Router, service domainQueue.IQueueUsecase) Queue {
+	rest := Queue{Service: service}
+	app.Get("/queue", middleware.RequireScope(permission.QueueRead), rest.ListMessages)
+	app.Get("/queue/:queue_id", middleware.RequireScope(permission.QueueRead), rest.GetMessage)
+	app.Post("/queue/:queue_id/cancel", middleware.RequireScope(permission.QueueWrite), rest.CancelMessage)
+	return rest
+}
+
+// InitRestAdminQueue registers the send queue of any user (admin only)
+func InitRestAdminQueue(app fiber.Router, service domainQueue.IQueueUsecase) Queue {
+	rest := Queue{Service: service}
+	app.Get("/users/:id/queue", rest.ListMessages)
+	app.Get("/users/:id/queue/:queue_id", rest.GetMessage)
+	app.Post("/users/:id/queue/:queue_id/cancel", rest.CancelMessage)
+	return rest
+}
+
+func (controller *Queue) ListMessages(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	request := domainQueue.ListRequest{
+		Status: c.Query("status"),
+		Limit:  c.QueryInt("limit", 25),
+		Offset: c.QueryInt("offset", 0),
+	}
+
+	response, err := controller.Service.ListMessages(appCtx, userID, request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get send queue",
+		Results: response,
+	})
+}
+
+func (controller *Queue) GetMessage(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	response, err := controller.Service.GetMessage(appCtx, userID, queueIDParam(c))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get queued message",
+		Results: response,
+	})
+}
+
+func (controller *Queue) CancelMessage(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	response, err := controller.Service.CancelMessage(appCtx, userID, queueIDParam(c))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success cancel queued message",
+		Results: response,
+	})
+}
+
+func queueIDParam(c *fiber.Ctx) int64 {
+	id, err := strconv.ParseInt(c.Params("queue_id"), 10, 64)
+	if err != nil {
+		panic(pkgError.ValidationError("invalid queue id"))
+	}
+	return id
+}
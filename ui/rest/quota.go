@@ -0,0 +1,66 @@
+package rest
+
+import (
+	domainQuota "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/quota"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Quota struct {
+	Service domainQuota.IQuotaUsecase
+}
+
+// InitRestAdminQuota registers sending limits and usage of any user (admin only)
+func InitRestAdminQuota(app fiber.Router, service domainQuota.IQuotaUsecase) Quota {
+	rest := Quota{Service: service}
+	app.Get("/users/:id/limits", rest.GetLimits)
+	app.Put("/users/:id/limits", rest.SetLimits)
+	app.Get("/users/:id/usage", rest.GetUsage)
+	return rest
+}
+
+func (controller *Quota) GetLimits(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	response, err := controller.Service.GetLimits(appCtx, userID)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get sending limits",
+		Results: response,
+	})
+}
+
+func (controller *Quota) SetLimits(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	var request domainQuota.Limits
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.SetLimits(appCtx, userID, request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success update sending limits",
+		Results: response,
+	})
+}
+
+func (controller *Quota) GetUsage(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	response, err := controller.Service.GetUsage(appCtx, userID)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get sending usage",
+		Results: response,
+	})
+}
@@ -0,0 +1,111 @@
+package rest
+
+import (
+	"strconv"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
+	domainSchedule "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/schedule"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Schedule struct {
+	Service domainSchedule.IScheduleUsecase
+}
+
+// InitRestSchedule registers the scheduled messages of the authenticated user
+func InitRestSchedule(app fiber.Router, service domainSchedule.IScheduleUsecase) Schedule {
+	rest := Schedule{Service: service}
+	app.Get("/schedules", middleware.RequireScope(permission.ScheduleRead), rest.ListSchedules)
+	app.Get("/schedules/:schedule_id", middleware.RequireScope(permission.ScheduleRead), rest.GetSchedule)
+	app.Put("/schedules/:schedule_id", middleware.RequireScope(permission.ScheduleWrite), rest.UpdateSchedule)
+	app.Post("/schedules/:schedule_id/cancel", middleware.RequireScope(permission.ScheduleWrite), rest.CancelSchedule)
+	return rest
+}
+
+// InitRestAdminSchedule registers the scheduled messages of any user (admin only)
+func InitRestAdminSchedule(app fiber.Router, service domainSchedule.IScheduleUsecase) Schedule {
+	rest := Schedule{Service: service}
+	app.Get("/users/:id/schedules", rest.ListSchedules)
+	app.Get("/users/:id/schedules/:schedule_id", rest.GetSchedule)
+	app.Put("/users/:id/schedules/:schedule_id", rest.UpdateSchedule)
+	app.Post("/users/:id/schedules/:schedule_id/cancel", rest.CancelSchedule)
+	return rest
+}
+
+func (controller *Schedule) ListSchedules(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	request := domainSchedule.ListRequest{
+		Status: c.Query("status"),
+		Limit:  c.QueryInt("limit", 25),
+		Offset: c.QueryInt("offset", 0),
+	}
+
+	response, err := controller.Service.ListSchedules(appCtx, userID, request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get schedules",
+		Results: response,
+	})
+}
+
+func (controller *Schedule) GetSchedule(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	response, err := controller.Service.GetSchedule(appCtx, userID, scheduleIDParam(c))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get schedule",
+		Results: response,
+	})
+}
+
+func (controller *Schedule) UpdateSchedule(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+	id := scheduleIDParam(c)
+
+	var request domainSchedule.UpdateRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.UpdateSchedule(appCtx, userID, id, request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success update schedule",
+		Results: response,
+	})
+}
+
+func (controller *Schedule) CancelSchedule(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	response, err := controller.Service.CancelSchedule(appCtx, userID, scheduleIDParam(c))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success cancel schedule",
+		Results: response,
+	})
+}
+
+func scheduleIDParam(c *fiber.Ctx) int64 {
+	id, err := strconv.ParseInt(c.Params("schedule_id"), 10, 64)
+	if err != nil {
+		panic(pkgError.ValidationError("invalid schedule id"))
+	}
+	return id
+}
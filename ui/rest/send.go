@@ -1,9 +1,15 @@
 package rest
 
 import (
+	"encoding/json"
+	"fmt"
+
 	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
 	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -13,17 +19,19 @@ type Send struct {
 
 func InitRestSend(app fiber.Router, service domainSend.ISendUsecase) Send {
 	rest := Send{Service: service}
-	app.Post("/send/message", rest.SendText)
-	app.Post("/send/image", rest.SendImage)
-	app.Post("/send/file", rest.SendFile)
-	app.Post("/send/video", rest.SendVideo)
-	app.Post("/send/contact", rest.SendContact)
-	app.Post("/send/link", rest.SendLink)
-	app.Post("/send/location", rest.SendLocation)
-	app.Post("/send/audio", rest.SendAudio)
-	app.Post("/send/poll", rest.SendPoll)
-	app.Post("/send/presence", rest.SendPresence)
-	app.Post("/send/chat-presence", rest.SendChatPresence)
+	app.Post("/send/message", middleware.RequireScope(permission.SendText), rest.SendText)
+	app.Post("/send/image", middleware.RequireScope(permission.SendImage), rest.SendImage)
+	app.Post("/send/file", middleware.RequireScope(permission.SendFile), rest.SendFile)
+	app.Post("/send/video", middleware.RequireScope(permission.SendVideo), rest.SendVideo)
+	app.Post("/send/contact", middleware.RequireScope(permission.SendContact), rest.SendContact)
+	app.Post("/send/link", middleware.RequireScope(permission.SendLink), rest.SendLink)
+	app.Post("/send/location", middleware.RequireScope(permission.SendLocation), rest.SendLocation)
+	app.Post("/send/audio", middleware.RequireScope(permission.SendAudio), rest.SendAudio)
+	app.Post("/send/poll", middleware.RequireScope(permission.SendPoll), rest.SendPoll)
+	app.Post("/send/sticker", middleware.RequireScope(permission.SendSticker), rest.SendSticker)
+	app.Post("/send/presence", middleware.RequireScope(permission.SendPresence), rest.SendPresence)
+	app.Post("/send/chat-presence", middleware.RequireScope(permission.SendPresence), rest.SendChatPresence)
+	app.Post("/message/:message_id/forward", middleware.RequireScope(permission.MessageForward), rest.ForwardMessage)
 	return rest
 }
 
@@ -31,6 +39,7 @@ func (controller *Send) SendText(c *fiber.Ctx) error {
 	var request domainSend.MessageRequest
 	err := c.BodyParser(&request)
 	utils.PanicIfNeeded(err)
+	parseFormVariables(c, &request.TemplateRequest)
 
 	utils.SanitizePhone(&request.Phone)
 
@@ -54,6 +63,7 @@ func (controller *Send) SendImage(c *fiber.Ctx) error {
 
 	err := c.BodyParser(&request)
 	utils.PanicIfNeeded(err)
+	parseFormVariables(c, &request.TemplateRequest)
 
 	file, err := c.FormFile("image")
 	if err == nil {
@@ -76,15 +86,41 @@ func (controller *Send) SendImage(c *fiber.Ctx) error {
 	})
 }
 
+func (controller *Send) SendSticker(c *fiber.Ctx) error {
+	var request domainSend.StickerRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	file, err := c.FormFile("sticker")
+	if err == nil {
+		request.Sticker = file
+	}
+
+	utils.SanitizePhone(&request.Phone)
+
+	appCtx := domainApp.NewAppContext(c.UserContext(), c)
+	response, err := controller.Service.SendSticker(appCtx, request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Status,
+		Results: response,
+	})
+}
+
 func (controller *Send) SendFile(c *fiber.Ctx) error {
 	var request domainSend.FileRequest
 	err := c.BodyParser(&request)
 	utils.PanicIfNeeded(err)
+	parseFormVariables(c, &request.TemplateRequest)
 
-	file, err := c.FormFile("file")
-	utils.PanicIfNeeded(err)
+	// The file may come from the template instead, validation requires one of them
+	if file, errFile := c.FormFile("file"); errFile == nil {
+		request.File = file
+	}
 
-	request.File = file
 	utils.SanitizePhone(&request.Phone)
 
 	appCtx := domainApp.NewAppContext(c.UserContext(), c)
@@ -103,6 +139,7 @@ func (controller *Send) SendVideo(c *fiber.Ctx) error {
 	var request domainSend.VideoRequest
 	err := c.BodyParser(&request)
 	utils.PanicIfNeeded(err)
+	parseFormVariables(c, &request.TemplateRequest)
 
 	// Try to get file but ignore error if not provided
 	if videoFile, errFile := c.FormFile("video"); errFile == nil {
@@ -146,6 +183,7 @@ func (controller *Send) SendLink(c *fiber.Ctx) error {
 	var request domainSend.LinkRequest
 	err := c.BodyParser(&request)
 	utils.PanicIfNeeded(err)
+	parseFormVariables(c, &request.TemplateRequest)
 
 	utils.SanitizePhone(&request.Phone)
 
@@ -258,3 +296,38 @@ func (controller *Send) SendChatPresence(c *fiber.Ctx) error {
 		Results: response,
 	})
 }
+
+func (controller *Send) ForwardMessage(c *fiber.Ctx) error {
+	var request domainSend.ForwardRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.MessageID = c.Params("message_id")
+	for i := range request.Phones {
+		utils.SanitizePhone(&request.Phones[i])
+		if !middleware.ChatAllowed(c, request.Phones[i]) {
+			panic(pkgError.ForbiddenError(fmt.Sprintf("Access to chat %s is not allowed", request.Phones[i])))
+		}
+	}
+
+	response, err := controller.Service.ForwardMessage(domainApp.NewAppContext(c.UserContext(), c), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Status,
+		Results: response,
+	})
+}
+
+// parseFormVariables reads the template variables of a form request, they are sent as a JSON object in one field
+func parseFormVariables(c *fiber.Ctx, request *domainSend.TemplateRequest) {
+	raw := c.FormValue("variables")
+	if raw == "" || request.Variables != nil {
+		return
+	}
+	if err := json.Unmarshal([]byte(raw), &request.Variables); err != nil {
+		panic(pkgError.ValidationError("variables: must be a JSON object of strings"))
+	}
+}
@@ -0,0 +1,138 @@
+package rest
+
+import (
+	"strconv"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Template struct {
+	Service domainTemplate.ITemplateUsecase
+}
+
+// InitRestTemplate registers message template management for the authenticated user
+func InitRestTemplate(app fiber.Router, service domainTemplate.ITemplateUsecase) Template {
+	rest := Template{Service: service}
+	app.Get("/templates", middleware.RequireScope(permission.TemplateRead), rest.ListTemplates)
+	app.Post("/templates", middleware.RequireScope(permission.TemplateWrite), rest.CreateTemplate)
+	app.Get("/templates/:template_id", middleware.RequireScope(permission.TemplateRead), rest.GetTemplate)
+	app.Put("/templates/:template_id", middleware.RequireScope(permission.TemplateWrite), rest.UpdateTemplate)
+	app.Delete("/templates/:template_id", middleware.RequireScope(permission.TemplateWrite), rest.DeleteTemplate)
+	return rest
+}
+
+// InitRestAdminTemplate registers message template management on behalf of any user (admin only)
+func InitRestAdminTemplate(app fiber.Router, service domainTemplate.ITemplateUsecase) Template {
+	rest := Template{Service: service}
+	app.Get("/users/:id/templates", rest.ListTemplates)
+	app.Post("/users/:id/templates", rest.CreateTemplate)
+	app.Get("/users/:id/templates/:template_id", rest.GetTemplate)
+	app.Put("/users/:id/templates/:template_id", rest.UpdateTemplate)
+	app.Delete("/users/:id/templates/:template_id", rest.DeleteTemplate)
+	return rest
+}
+
+func (controller *Template) ListTemplates(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	request := domainTemplate.ListRequest{
+		Limit:  c.QueryInt("limit", 25),
+		Offset: c.QueryInt("offset", 0),
+	}
+
+	response, err := controller.Service.ListTemplates(appCtx, userID, request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get list templates",
+		Results: response,
+	})
+}
+
+func (controller *Template) CreateTemplate(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	var request domainTemplate.CreateTemplateRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	// The attachment is optional and only sent as multipart/form-data
+	if file, errFile := c.FormFile("attachment"); errFile == nil {
+		request.Attachment = file
+	}
+
+	response, err := controller.Service.CreateTemplate(appCtx, userID, request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success create template",
+		Results: response,
+	})
+}
+
+func (controller *Template) GetTemplate(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	response, err := controller.Service.GetTemplate(appCtx, userID, templateIDParam(c))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get template",
+		Results: response,
+	})
+}
+
+func (controller *Template) UpdateTemplate(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+	id := templateIDParam(c)
+
+	var request domainTemplate.UpdateTemplateRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	if file, errFile := c.FormFile("attachment"); errFile == nil {
+		request.Attachment = file
+	}
+
+	response, err := controller.Service.UpdateTemplate(appCtx, userID, id, request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success update template",
+		Results: response,
+	})
+}
+
+func (controller *Template) DeleteTemplate(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	err := controller.Service.DeleteTemplate(appCtx, userID, templateIDParam(c))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success delete template",
+	})
+}
+
+func templateIDParam(c *fiber.Ctx) int64 {
+	id, err := strconv.ParseInt(c.Params("template_id"), 10, 64)
+	if err != nil {
+		panic(pkgError.ValidationError("invalid template id"))
+	}
+	return id
+}
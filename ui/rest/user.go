@@ -1,8 +1,11 @@
 package rest
 
 import (
+	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
 	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -12,16 +15,16 @@ type User struct {
 
 func InitRestUser(app fiber.Router, service domainUser.IUserUsecase) User {
 	rest := User{Service: service}
-	app.Get("/user/info", rest.UserInfo)
-	app.Get("/user/avatar", rest.UserAvatar)
-	app.Post("/user/avatar", rest.UserChangeAvatar)
-	app.Post("/user/pushname", rest.UserChangePushName)
-	app.Get("/user/my/privacy", rest.UserMyPrivacySetting)
-	app.Get("/user/my/groups", rest.UserMyListGroups)
-	app.Get("/user/my/newsletters", rest.UserMyListNewsletter)
-	app.Get("/user/my/contacts", rest.UserMyListContacts)
-	app.Get("/user/check", rest.UserCheck)
-	app.Get("/user/business-profile", rest.UserBusinessProfile)
+	app.Get("/user/info", middleware.RequireScope(permission.UserRead), rest.UserInfo)
+	app.Get("/user/avatar", middleware.RequireScope(permission.UserRead), rest.UserAvatar)
+	app.Post("/user/avatar", middleware.RequireScope(permission.UserWrite), rest.UserChangeAvatar)
+	app.Post("/user/pushname", middleware.RequireScope(permission.UserWrite), rest.UserChangePushName)
+	app.Get("/user/my/privacy", middleware.RequireScope(permission.UserRead), rest.UserMyPrivacySetting)
+	app.Get("/user/my/groups", middleware.RequireScope(permission.UserRead), rest.UserMyListGroups)
+	app.Get("/user/my/newsletters", middleware.RequireScope(permission.UserRead), rest.UserMyListNewsletter)
+	app.Get("/user/my/contacts", middleware.RequireScope(permission.UserRead), rest.UserMyListContacts)
+	app.Get("/user/check", middleware.RequireScope(permission.UserRead), rest.UserCheck)
+	app.Get("/user/business-profile", middleware.RequireScope(permission.UserRead), rest.UserBusinessProfile)
 
 	return rest
 }
@@ -93,7 +96,7 @@ func (controller *User) UserMyPrivacySetting(c *fiber.Ctx) error {
 }
 
 func (controller *User) UserMyListGroups(c *fiber.Ctx) error {
-	response, err := controller.Service.MyListGroups(c.UserContext())
+	response, err := controller.Service.MyListGroups(domainApp.NewAppContext(c.UserContext(), c))
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
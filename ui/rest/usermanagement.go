@@ -3,6 +3,7 @@ package rest
 import (
 	"strconv"
 
+	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
 	domainUserManagement "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/usermanagement"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/gofiber/fiber/v2"
@@ -40,7 +41,7 @@ func (r *UserManagementRest) CreateUser(c *fiber.Ctx) error {
 		})
 	}
 
-	response, err := r.userUsecase.CreateUser(request)
+	response, err := r.userUsecase.CreateUser(domainApp.NewAppContext(c.UserContext(), c), request)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(utils.ResponseData{
 			Status:  fiber.StatusBadRequest,
@@ -123,7 +124,7 @@ func (r *UserManagementRest) UpdateUser(c *fiber.Ctx) error {
 		})
 	}
 
-	user, err := r.userUsecase.UpdateUser(id, request)
+	user, err := r.userUsecase.UpdateUser(domainApp.NewAppContext(c.UserContext(), c), id, request)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(utils.ResponseData{
 			Status:  fiber.StatusBadRequest,
@@ -151,7 +152,7 @@ func (r *UserManagementRest) DeleteUser(c *fiber.Ctx) error {
 		})
 	}
 
-	err = r.userUsecase.DeleteUser(id)
+	err = r.userUsecase.DeleteUser(domainApp.NewAppContext(c.UserContext(), c), id)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(utils.ResponseData{
 			Status:  fiber.StatusNotFound,
@@ -179,7 +180,7 @@ func (r *UserManagementRest) DisconnectWhatsAppSession(c *fiber.Ctx) error {
 		})
 	}
 
-	err = r.userUsecase.DisconnectWhatsAppSession(id)
+	err = r.userUsecase.DisconnectWhatsAppSession(domainApp.NewAppContext(c.UserContext(), c), id)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(utils.ResponseData{
 			Status:  fiber.StatusBadRequest,
@@ -212,7 +213,7 @@ func (r *UserManagementRest) ReconnectWhatsAppSession(c *fiber.Ctx) error {
 		})
 	}
 
-	err = r.userUsecase.ReconnectWhatsAppSession(id)
+	err = r.userUsecase.ReconnectWhatsAppSession(domainApp.NewAppContext(c.UserContext(), c), id)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(utils.ResponseData{
 			Status:  fiber.StatusBadRequest,
@@ -245,7 +246,7 @@ func (r *UserManagementRest) ClearWhatsAppSession(c *fiber.Ctx) error {
 		})
 	}
 
-	err = r.userUsecase.ClearWhatsAppSession(id)
+	err = r.userUsecase.ClearWhatsAppSession(domainApp.NewAppContext(c.UserContext(), c), id)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(utils.ResponseData{
 			Status:  fiber.StatusBadRequest,
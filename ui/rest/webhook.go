@@ -0,0 +1,247 @@
+package rest
+
+import (
+	"strconv"
+
+	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Webhook struct {
+	Service domainWebhook.IWebhookUsecase
+}
+
+// InitRestWebhook registers webhook management for the authenticated user
+func InitRestWebhook(app fiber.Router, service domainWebhook.IWebhookUsecase) Webhook {
+	rest := Webhook{Service: service}
+	// Delivery and dead letter routes are registered first so they are not captured by /webhooks/:webhook_id
+	app.Get("/webhooks/deliveries", middleware.RequireScope(permission.WebhookRead), rest.ListDeliveries)
+	app.Get("/webhooks/dead-letters", middleware.RequireScope(permission.WebhookRead), rest.ListDeadLetters)
+	app.Post("/webhooks/dead-letters/replay", middleware.RequireScope(permission.WebhookWrite), rest.ReplayDeadLetters)
+	app.Post("/webhooks/dead-letters/:dead_letter_id/replay", middleware.RequireScope(permission.WebhookWrite), rest.ReplayDeadLetter)
+	app.Get("/webhooks", middleware.RequireScope(permission.WebhookRead), rest.ListWebhooks)
+	app.Post("/webhooks", middleware.RequireScope(permission.WebhookWrite), rest.CreateWebhook)
+	app.Get("/webhooks/:webhook_id", middleware.RequireScope(permission.WebhookRead), rest.GetWebhook)
+	app.Put("/webhooks/:webhook_id", middleware.RequireScope(permission.WebhookWrite), rest.UpdateWebhook)
+	app.Delete("/webhooks/:webhook_id", middleware.RequireScope(permission.WebhookWrite), rest.DeleteWebhook)
+	app.Post("/webhooks/:webhook_id/test", middleware.RequireScope(permission.WebhookWrite), rest.TestWebhook)
+	return rest
+}
+
+// InitRestAdminWebhook registers webhook management on behalf of any user (admin only)
+func InitRestAdminWebhook(app fiber.Router, service domainWebhook.IWebhookUsecase) Webhook {
+	rest := Webhook{Service: service}
+	app.Get("/users/:id/webhooks/deliveries", rest.ListDeliveries)
+	app.Get("/users/:id/webhooks/dead-letters", rest.ListDeadLetters)
+	app.Post("/users/:id/webhooks/dead-letters/replay", rest.ReplayDeadLetters)
+	app.Post("/users/:id/webhooks/dead-letters/:dead_letter_id/replay", rest.ReplayDeadLetter)
+	app.Get("/users/:id/webhooks", rest.ListWebhooks)
+	app.Post("/users/:id/webhooks", rest.CreateWebhook)
+	app.Get("/users/:id/webhooks/:webhook_id", rest.GetWebhook)
+	app.Put("/users/:id/webhooks/:webhook_id", rest.UpdateWebhook)
+	app.Delete("/users/:id/webhooks/:webhook_id", rest.DeleteWebhook)
+	app.Post("/users/:id/webhooks/:webhook_id/test", rest.TestWebhook)
+	return rest
+}
+
+func (controller *Webhook) ListWebhooks(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	response, err := controller.Service.ListWebhooks(appCtx, userID)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get list webhooks",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) CreateWebhook(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	var request domainWebhook.CreateWebhookRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.CreateWebhook(appCtx, userID, request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success create webhook",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) GetWebhook(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	response, err := controller.Service.GetWebhook(appCtx, userID, webhookIDParam(c))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get webhook",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) UpdateWebhook(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	var request domainWebhook.UpdateWebhookRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.UpdateWebhook(appCtx, userID, webhookIDParam(c), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success update webhook",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) DeleteWebhook(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	err := controller.Service.DeleteWebhook(appCtx, userID, webhookIDParam(c))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success delete webhook",
+	})
+}
+
+func (controller *Webhook) TestWebhook(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	response, err := controller.Service.TestWebhook(appCtx, userID, webhookIDParam(c))
+	utils.PanicIfNeeded(err)
+
+	message := "Test event delivered"
+	if !response.Delivered {
+		message = "Test event delivery failed"
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: message,
+		Results: response,
+	})
+}
+
+func (controller *Webhook) ListDeliveries(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	var request domainWebhook.ListDeliveriesRequest
+	request.Limit = c.QueryInt("limit", 25)
+	request.Offset = c.QueryInt("offset", 0)
+
+	response, err := controller.Service.ListDeliveries(appCtx, userID, request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get list delivered webhooks",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) ListDeadLetters(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	var request domainWebhook.ListDeadLettersRequest
+	request.Limit = c.QueryInt("limit", 25)
+	request.Offset = c.QueryInt("offset", 0)
+
+	response, err := controller.Service.ListDeadLetters(appCtx, userID, request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get list failed webhook deliveries",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) ReplayDeadLetter(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	id, err := strconv.ParseInt(c.Params("dead_letter_id"), 10, 64)
+	if err != nil {
+		panic(pkgError.ValidationError("invalid dead letter id"))
+	}
+
+	err = controller.Service.ReplayDeadLetter(appCtx, userID, id)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Webhook delivery queued for replay",
+	})
+}
+
+func (controller *Webhook) ReplayDeadLetters(c *fiber.Ctx) error {
+	appCtx, userID := resolveOwner(c)
+
+	var request domainWebhook.ReplayDeadLettersRequest
+	if len(c.Body()) > 0 {
+		err := c.BodyParser(&request)
+		utils.PanicIfNeeded(err)
+	}
+
+	response, err := controller.Service.ReplayDeadLetters(appCtx, userID, request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Webhook deliveries queued for replay",
+		Results: response,
+	})
+}
+
+// resolveOwner returns the user whose resources are managed: the :id path parameter on admin
+// routes, otherwise the authenticated user
+func resolveOwner(c *fiber.Ctx) (*domainApp.AppContext, int) {
+	appCtx := domainApp.NewAppContext(c.UserContext(), c)
+
+	if idParam := c.Params("id"); idParam != "" {
+		userID, err := strconv.Atoi(idParam)
+		if err != nil {
+			panic(pkgError.ValidationError("invalid user id"))
+		}
+		return appCtx, userID
+	}
+
+	if appCtx.UserID == 0 {
+		panic(pkgError.ErrNotLoggedIn)
+	}
+	return appCtx, appCtx.UserID
+}
+
+func webhookIDParam(c *fiber.Ctx) int {
+	id, err := strconv.Atoi(c.Params("webhook_id"))
+	if err != nil {
+		panic(pkgError.ValidationError("invalid webhook id"))
+	}
+	return id
+}
@@ -3,10 +3,12 @@ package websocket
 import (
 	"context"
 	"encoding/json"
+	"sync/atomic"
 
 	"github.com/sirupsen/logrus"
 
 	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/websocket/v2"
 )
@@ -14,6 +16,7 @@ import (
 const (
 	UserIDKey   = "user_id"
 	UsernameKey = "username"
+	GrantsKey   = "grants"
 )
 
 // Helper functions to extract user info from fiber context
@@ -27,15 +30,33 @@ func getUsernameFromContext(c *fiber.Ctx) (string, bool) {
 	return username, ok
 }
 
+func getGrantsFromContext(c *fiber.Ctx) []permission.Grant {
+	grants, _ := c.Locals(GrantsKey).([]permission.Grant)
+	return grants
+}
+
 type client struct {
 	userID   int
 	username string
+	grants   []permission.Grant // Limit which chats the connection receives events about
+}
+
+// allowsChat reports whether the connection may receive events about a chat
+func (c client) allowsChat(chat string) bool {
+	for _, grant := range c.grants {
+		if !grant.AllowsChat(chat) {
+			return false
+		}
+	}
+	return true
 }
 
 type BroadcastMessage struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
 	Result  any    `json:"result"`
+	UserID  int    `json:"-"` // Deliver only to this user's connections; 0 delivers to everyone
+	ChatJID string `json:"-"` // Deliver only to connections allowed to see this chat; empty for events about no chat
 }
 
 var (
@@ -43,8 +64,33 @@ var (
 	Register   = make(chan *websocket.Conn)
 	Broadcast  = make(chan BroadcastMessage)
 	Unregister = make(chan *websocket.Conn)
+
+	// hubRunning guards Broadcast against blocking forever when RunHub was never started (e.g. MCP mode)
+	hubRunning atomic.Bool
 )
 
+// BroadcastToUser sends a message to the websocket connections of a user; 0 sends to everyone.
+// It is a no-op when the hub is not running.
+func BroadcastToUser(userID int, code string, message string, result any) {
+	BroadcastChatToUser(userID, "", code, message, result)
+}
+
+// BroadcastChatToUser sends a message about a chat to the websocket connections of a user that are allowed to see
+// the chat. It is a no-op when the hub is not running.
+func BroadcastChatToUser(userID int, chatJID string, code string, message string, result any) {
+	if !hubRunning.Load() {
+		return
+	}
+
+	Broadcast <- BroadcastMessage{
+		Code:    code,
+		Message: message,
+		Result:  result,
+		UserID:  userID,
+		ChatJID: chatJID,
+	}
+}
+
 func handleRegister(conn *websocket.Conn) {
 	// Extract user info from connection
 	var userID int
@@ -60,10 +106,12 @@ func handleRegister(conn *websocket.Conn) {
 			username = name
 		}
 	}
+	grants, _ := conn.Locals(GrantsKey).([]permission.Grant)
 
 	Clients[conn] = client{
 		userID:   userID,
 		username: username,
+		grants:   grants,
 	}
 	logrus.Printf("WebSocket connection registered for user %s (ID: %d)", username, userID)
 }
@@ -82,7 +130,13 @@ func broadcastMessage(message BroadcastMessage) {
 		return
 	}
 
-	for conn := range Clients {
+	for conn, c := range Clients {
+		if message.UserID != 0 && c.userID != message.UserID {
+			continue
+		}
+		if message.ChatJID != "" && !c.allowsChat(message.ChatJID) {
+			continue
+		}
 		if err := conn.WriteMessage(websocket.TextMessage, marshalMessage); err != nil {
 			logrus.Println("write error:", err)
 			closeConnection(conn)
@@ -101,6 +155,9 @@ func closeConnection(conn *websocket.Conn) {
 }
 
 func RunHub() {
+	hubRunning.Store(true)
+	defer hubRunning.Store(false)
+
 	for {
 		select {
 		case conn := <-Register:
@@ -122,6 +179,7 @@ func RegisterRoutes(app fiber.Router, service domainApp.IAppUsecaseWithContext)
 		// Extract user information before upgrading to WebSocket
 		userID, hasUserID := getUserIDFromContext(c)
 		username, _ := getUsernameFromContext(c)
+		grants := getGrantsFromContext(c)
 
 		if !websocket.IsWebSocketUpgrade(c) {
 			return c.SendStatus(fiber.StatusUpgradeRequired)
@@ -132,6 +190,7 @@ func RegisterRoutes(app fiber.Router, service domainApp.IAppUsecaseWithContext)
 			Clients[conn] = client{
 				userID:   userID,
 				username: username,
+				grants:   grants,
 			}
 
 			defer func() {
@@ -188,6 +247,7 @@ func RegisterRoutes(app fiber.Router, service domainApp.IAppUsecaseWithContext)
 								Code:    "FETCH_DEVICES_ERROR",
 								Message: "Failed to fetch devices: " + err.Error(),
 								Result:  nil,
+								UserID:  userID,
 							}
 						} else {
 							logrus.Infof("[WebSocket] Successfully fetched %d devices for user %s", len(devices), username)
@@ -195,6 +255,7 @@ func RegisterRoutes(app fiber.Router, service domainApp.IAppUsecaseWithContext)
 								Code:    "LIST_DEVICES",
 								Message: "Device found",
 								Result:  devices,
+								UserID:  userID,
 							}
 						}
 					}
@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// apiKeySecretBytes is the amount of randomness in a key
+	apiKeySecretBytes = 24
+	// apiKeyVisiblePrefix is how many characters of a key are stored in clear to tell keys apart
+	apiKeyVisiblePrefix = len(domainAPIKey.KeyPrefix) + 8
+	// apiKeyLastUsedInterval limits how often last_used_at is written for a busy key
+	apiKeyLastUsedInterval = time.Minute
+)
+
+type serviceAPIKey struct {
+	apiKeyRepo domainAPIKey.IAPIKeyRepository
+}
+
+func NewAPIKeyService(apiKeyRepo domainAPIKey.IAPIKeyRepository) domainAPIKey.IAPIKeyUsecase {
+	return &serviceAPIKey{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+func (service serviceAPIKey) CreateKey(ctx context.Context, userID int, request domainAPIKey.CreateAPIKeyRequest) (response domainAPIKey.CreateAPIKeyResponse, err error) {
+	request.Name = strings.TrimSpace(request.Name)
+	if err = validations.ValidateCreateAPIKey(ctx, request); err != nil {
+		return response, err
+	}
+
+	secret := make([]byte, apiKeySecretBytes)
+	if _, err = rand.Read(secret); err != nil {
+		return response, fmt.Errorf("failed to generate api key: %w", err)
+	}
+	token := domainAPIKey.KeyPrefix + hex.EncodeToString(secret)
+
+	key := domainAPIKey.APIKey{
+		UserID:  userID,
+		Name:    request.Name,
+		Prefix:  token[:apiKeyVisiblePrefix],
+		KeyHash: hashAPIKey(token),
+		Grant: permission.Grant{
+			Scopes:       request.Scopes,
+			AllowedChats: request.AllowedChats,
+		},
+	}
+	if request.ExpiresAt != "" {
+		expiresAt, err := time.Parse(time.RFC3339, request.ExpiresAt)
+		if err != nil {
+			return response, pkgError.ValidationError("expires_at: must be a valid date.")
+		}
+		key.ExpiresAt = &expiresAt
+	}
+
+	if err = service.apiKeyRepo.Create(&key); err != nil {
+		return response, err
+	}
+
+	logrus.Infof("API key %d (%s) created for user %d", key.ID, key.Prefix, userID)
+	response.APIKey = withKeyStatus(key)
+	response.Key = token
+	return response, nil
+}
+
+func (service serviceAPIKey) ListKeys(_ context.Context, userID int) ([]domainAPIKey.APIKey, error) {
+	keys, err := service.apiKeyRepo.GetByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range keys {
+		keys[i] = withKeyStatus(keys[i])
+	}
+	return keys, nil
+}
+
+func (service serviceAPIKey) RevokeKey(_ context.Context, userID, id int) (key domainAPIKey.APIKey, err error) {
+	found, err := service.apiKeyRepo.GetByID(userID, id)
+	if err != nil {
+		return key, err
+	}
+	if found == nil {
+		return key, pkgError.NotFoundError(fmt.Sprintf("api key %d not found", id))
+	}
+
+	// Revoking twice keeps the original revocation time
+	if found.RevokedAt == nil {
+		now := time.Now()
+		if err = service.apiKeyRepo.Revoke(userID, id, now); err != nil {
+			return key, err
+		}
+		found.RevokedAt = &now
+		logrus.Infof("API key %d (%s) of user %d revoked", found.ID, found.Prefix, userID)
+	}
+
+	return withKeyStatus(*found), nil
+}
+
+func (service serviceAPIKey) Authenticate(_ context.Context, token string) (*domainAPIKey.APIKey, error) {
+	if !strings.HasPrefix(token, domainAPIKey.KeyPrefix) {
+		return nil, pkgError.AuthError("invalid API key")
+	}
+
+	key, err := service.apiKeyRepo.GetByHash(hashAPIKey(token))
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, pkgError.AuthError("invalid API key")
+	}
+
+	now := time.Now()
+	switch key.StatusAt(now) {
+	case domainAPIKey.StatusRevoked:
+		return nil, pkgError.AuthError("API key has been revoked")
+	case domainAPIKey.StatusExpired:
+		return nil, pkgError.AuthError("API key has expired")
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyLastUsedInterval {
+		if err := service.apiKeyRepo.UpdateLastUsed(key.ID, now); err != nil {
+			logrus.Warnf("Failed to update last use of API key %d: %v", key.ID, err)
+		} else {
+			key.LastUsedAt = &now
+		}
+	}
+
+	result := withKeyStatus(*key)
+	return &result, nil
+}
+
+// hashAPIKey returns the stored form of a key; keys are random enough that a fast hash is sufficient
+func hashAPIKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func withKeyStatus(key domainAPIKey.APIKey) domainAPIKey.APIKey {
+	key.Status = key.StatusAt(time.Now())
+	return key
+}
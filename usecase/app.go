@@ -9,6 +9,7 @@ import (
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	domainAudit "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/audit"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
@@ -23,11 +24,13 @@ import (
 
 type serviceApp struct {
 	chatStorageRepo domainChatStorage.IChatStorageRepository
+	auditRepo       domainAudit.IAuditRepository
 }
 
-func NewAppService(chatStorageRepo domainChatStorage.IChatStorageRepository) domainApp.IAppUsecaseWithContext {
+func NewAppService(chatStorageRepo domainChatStorage.IChatStorageRepository, auditRepo domainAudit.IAuditRepository) domainApp.IAppUsecaseWithContext {
 	return &serviceApp{
 		chatStorageRepo: chatStorageRepo,
+		auditRepo:       auditRepo,
 	}
 }
 
@@ -50,6 +53,10 @@ func (service serviceApp) getClientFromContext(ctx context.Context) (*whatsmeow.
 // Context-aware methods for multi-user support
 
 func (service *serviceApp) LoginWithContext(appCtx *domainApp.AppContext) (response domainApp.LoginResponse, err error) {
+	defer func() {
+		recordAudit(appCtx, service.auditRepo, domainAudit.Entry{Action: domainAudit.ActionAppLogin}, err)
+	}()
+
 	if appCtx.UserID == 0 {
 		return response, fmt.Errorf("user ID required for login in multi-user system")
 	}
@@ -106,6 +113,7 @@ func (service *serviceApp) LoginWithContext(appCtx *domainApp.AppContext) (respo
 					if err != nil {
 						logrus.Error("Error when write qr code to file: ", err)
 					}
+					whatsapp.EmitQRCode(appCtx.UserID, evt.Code, qrPath, response.Duration*time.Second)
 					go func() {
 						time.Sleep(response.Duration * time.Second)
 						err := os.Remove(qrPath)
@@ -141,6 +149,10 @@ func (service *serviceApp) LoginWithContext(appCtx *domainApp.AppContext) (respo
 }
 
 func (service *serviceApp) LoginWithCodeAndContext(appCtx *domainApp.AppContext, phoneNumber string) (loginCode string, err error) {
+	defer func() {
+		recordAudit(appCtx, service.auditRepo, domainAudit.Entry{Action: domainAudit.ActionAppLoginWithCode, TargetJID: phoneNumber}, err)
+	}()
+
 	if appCtx.UserID == 0 {
 		return loginCode, fmt.Errorf("user ID required for login with code in multi-user system")
 	}
@@ -177,6 +189,9 @@ func (service *serviceApp) LoginWithCodeAndContext(appCtx *domainApp.AppContext,
 }
 
 func (service *serviceApp) LogoutWithContext(appCtx *domainApp.AppContext) (err error) {
+	entry := domainAudit.Entry{Action: domainAudit.ActionAppLogout, TargetJID: sessionJID(appCtx.UserID)}
+	defer func() { recordAudit(appCtx, service.auditRepo, entry, err) }()
+
 	if appCtx.UserID == 0 {
 		return fmt.Errorf("user ID required for logout in multi-user system")
 	}
@@ -207,6 +222,9 @@ func (service *serviceApp) LogoutWithContext(appCtx *domainApp.AppContext) (err
 }
 
 func (service *serviceApp) ReconnectWithContext(appCtx *domainApp.AppContext) (err error) {
+	entry := domainAudit.Entry{Action: domainAudit.ActionAppReconnect, TargetJID: sessionJID(appCtx.UserID)}
+	defer func() { recordAudit(appCtx, service.auditRepo, entry, err) }()
+
 	if appCtx.UserID == 0 {
 		return fmt.Errorf("user ID required for reconnect in multi-user system")
 	}
@@ -218,6 +236,10 @@ func (service *serviceApp) ReconnectWithContext(appCtx *domainApp.AppContext) (e
 
 	logrus.Infof("[DEBUG] Starting reconnect process for user %d...", appCtx.UserID)
 
+	// Reconnecting by hand takes the session back, so a later replacement may be reclaimed again
+	if session := whatsapp.GetSessionManager().GetUserSession(appCtx.UserID); session != nil {
+		session.ResetReclaimAttempts()
+	}
 	client.Disconnect()
 	err = client.Connect()
 
@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	domainAudit "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/audit"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/gofiber/fiber/v2"
+	fiberUtils "github.com/gofiber/fiber/v2/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// auditExportPageSize is how many entries an export reads from the database at once
+const auditExportPageSize = 500
+
+var auditCSVHeader = []string{"id", "created_at", "actor", "user_id", "action", "target_jid", "reference", "result", "error", "ip", "user_agent", "method", "path", "api_key_id"}
+
+type serviceAudit struct {
+	auditRepo domainAudit.IAuditRepository
+}
+
+func NewAuditService(auditRepo domainAudit.IAuditRepository) domainAudit.IAuditUsecase {
+	return &serviceAudit{
+		auditRepo: auditRepo,
+	}
+}
+
+func (service serviceAudit) List(ctx context.Context, request domainAudit.ListRequest) (response domainAudit.ListResponse, err error) {
+	if err = validations.ValidateListAudit(ctx, &request); err != nil {
+		return response, err
+	}
+
+	filter := toAuditFilter(request)
+	entries, err := service.auditRepo.List(filter)
+	if err != nil {
+		return response, err
+	}
+
+	total, err := service.auditRepo.Count(filter)
+	if err != nil {
+		return response, err
+	}
+
+	response.Data = entries
+	response.Pagination = domainAudit.PaginationResponse{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+		Total:  total,
+	}
+
+	return response, nil
+}
+
+func (service serviceAudit) ExportCSV(ctx context.Context, request domainAudit.ListRequest, w io.Writer) error {
+	if err := validations.ValidateListAudit(ctx, &request); err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(auditCSVHeader); err != nil {
+		return err
+	}
+
+	// Page by id instead of offset so entries appended during the export do not shift the pages
+	filter := toAuditFilter(request)
+	filter.Limit = auditExportPageSize
+	filter.Offset = 0
+	for {
+		entries, err := service.auditRepo.List(filter)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			if err := writer.Write(auditCSVRecord(entry)); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return err
+		}
+
+		if len(entries) < auditExportPageSize {
+			return nil
+		}
+		filter.BeforeID = entries[len(entries)-1].ID
+	}
+}
+
+// toAuditFilter converts a validated list request into a repository filter
+func toAuditFilter(request domainAudit.ListRequest) domainAudit.Filter {
+	filter := domainAudit.Filter{
+		UserID:    request.UserID,
+		Actor:     request.Actor,
+		Action:    request.Action,
+		TargetJID: auditJID(request.TargetJID),
+		Result:    request.Result,
+		Limit:     request.Limit,
+		Offset:    request.Offset,
+	}
+	if from, err := time.Parse(time.RFC3339, request.From); err == nil {
+		filter.From = &from
+	}
+	if to, err := time.Parse(time.RFC3339, request.To); err == nil {
+		filter.To = &to
+	}
+	return filter
+}
+
+// auditJID stores phone numbers as JIDs, so filtering by either finds the same entries
+func auditJID(target string) string {
+	if target == "" {
+		return ""
+	}
+	jid, err := utils.ParseJID(target)
+	if err != nil {
+		return target
+	}
+	return jid.String()
+}
+
+func auditCSVRecord(entry domainAudit.Entry) []string {
+	apiKeyID := ""
+	if entry.Metadata.APIKeyID > 0 {
+		apiKeyID = strconv.Itoa(entry.Metadata.APIKeyID)
+	}
+
+	return []string{
+		strconv.FormatInt(entry.ID, 10),
+		entry.CreatedAt.UTC().Format(time.RFC3339),
+		entry.Actor,
+		strconv.Itoa(entry.UserID),
+		entry.Action,
+		entry.TargetJID,
+		entry.Reference,
+		entry.Result,
+		entry.Error,
+		entry.Metadata.IP,
+		entry.Metadata.UserAgent,
+		entry.Metadata.Method,
+		entry.Metadata.Path,
+		apiKeyID,
+	}
+}
+
+// recordAudit appends an entry for an action to the audit log. The actor, the user and the request metadata are
+// taken from the app context; without one the action is attributed to the system. A failing audit write is logged
+// and never fails the action itself.
+func recordAudit(ctx context.Context, auditRepo domainAudit.IAuditRepository, entry domainAudit.Entry, err error) {
+	if auditRepo == nil {
+		return
+	}
+
+	entry.Actor = domainAudit.ActorSystem
+	entry.TargetJID = auditJID(entry.TargetJID)
+	entry.Result = domainAudit.ResultSuccess
+	if err != nil {
+		entry.Result = domainAudit.ResultFailure
+		entry.Error = err.Error()
+	}
+
+	if appCtx, ok := ctx.(*domainApp.AppContext); ok {
+		if entry.UserID == 0 {
+			entry.UserID = appCtx.UserID
+		}
+		if appCtx.Username != "" {
+			entry.Actor = appCtx.Username
+		}
+		// Admin routes act on other users, the admin is the actor
+		if appCtx.AdminUsername != "" {
+			entry.Actor = appCtx.AdminUsername
+		}
+		if c := appCtx.FiberCtx; c != nil {
+			// Fiber reuses these buffers once the handler returns, copy them
+			entry.Metadata = domainAudit.Metadata{
+				IP:        fiberUtils.CopyString(c.IP()),
+				UserAgent: fiberUtils.CopyString(c.Get(fiber.HeaderUserAgent)),
+				Method:    fiberUtils.CopyString(c.Method()),
+				Path:      fiberUtils.CopyString(c.Path()),
+				APIKeyID:  appCtx.APIKeyID,
+			}
+		}
+	}
+
+	if appendErr := auditRepo.Append(&entry); appendErr != nil {
+		logrus.Warnf("Failed to write audit entry %s by %s: %v", entry.Action, entry.Actor, appendErr)
+	}
+}
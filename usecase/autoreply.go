@@ -0,0 +1,321 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	fiberUtils "github.com/gofiber/fiber/v2/utils"
+	"github.com/sirupsen/logrus"
+)
+
+type serviceAutoReply struct {
+	autoReplyRepo domainAutoReply.IAutoReplyRepository
+}
+
+func NewAutoReplyService(autoReplyRepo domainAutoReply.IAutoReplyRepository) domainAutoReply.IAutoReplyUsecase {
+	return &serviceAutoReply{
+		autoReplyRepo: autoReplyRepo,
+	}
+}
+
+func (service serviceAutoReply) CreateRule(ctx context.Context, userID int, request domainAutoReply.CreateRuleRequest) (rule domainAutoReply.Rule, err error) {
+	rule = domainAutoReply.Rule{
+		UserID:          userID,
+		Name:            request.Name,
+		Priority:        request.Priority,
+		MatchType:       request.MatchType,
+		Keywords:        request.Keywords,
+		Pattern:         request.Pattern,
+		ChatType:        request.ChatType,
+		AllowSenders:    request.AllowSenders,
+		DenySenders:     request.DenySenders,
+		BusinessHours:   request.BusinessHours,
+		CooldownSeconds: request.CooldownSeconds,
+		ReplyType:       request.ReplyType,
+		ReplyText:       request.ReplyText,
+		MediaURL:        request.MediaURL,
+		IsActive:        true,
+	}
+	if rule.MatchType == "" {
+		rule.MatchType = domainAutoReply.MatchAny
+	}
+	if rule.ChatType == "" {
+		rule.ChatType = domainAutoReply.ChatTypeAll
+	}
+	if rule.ReplyType == "" {
+		rule.ReplyType = domainAutoReply.ReplyText
+	}
+	if request.IsActive != nil {
+		rule.IsActive = *request.IsActive
+	}
+
+	if err = validations.ValidateAutoReplyRule(ctx, rule); err != nil {
+		return rule, err
+	}
+	if err = storeReplyMedia(&rule); err != nil {
+		return rule, err
+	}
+
+	if err = service.autoReplyRepo.Create(&rule); err != nil {
+		removeReplyMedia(rule.MediaPath)
+		return rule, err
+	}
+	compileRulePattern(rule)
+
+	logrus.Infof("Auto-reply rule %d created for user %d", rule.ID, userID)
+	return normalizeRule(rule), nil
+}
+
+func (service serviceAutoReply) ListRules(_ context.Context, userID int) ([]domainAutoReply.Rule, error) {
+	rules, err := service.autoReplyRepo.GetByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range rules {
+		rules[i] = normalizeRule(rules[i])
+	}
+	return rules, nil
+}
+
+func (service serviceAutoReply) GetRule(_ context.Context, userID, id int) (rule domainAutoReply.Rule, err error) {
+	found, err := service.getRule(userID, id)
+	if err != nil {
+		return rule, err
+	}
+	return normalizeRule(*found), nil
+}
+
+func (service serviceAutoReply) UpdateRule(ctx context.Context, userID, id int, request domainAutoReply.UpdateRuleRequest) (rule domainAutoReply.Rule, err error) {
+	found, err := service.getRule(userID, id)
+	if err != nil {
+		return rule, err
+	}
+	rule = *found
+
+	if request.Name != "" {
+		rule.Name = request.Name
+	}
+	if request.Priority != nil {
+		rule.Priority = *request.Priority
+	}
+	if request.MatchType != "" {
+		rule.MatchType = request.MatchType
+	}
+	if request.Keywords != nil {
+		rule.Keywords = request.Keywords
+	}
+	if request.Pattern != nil {
+		rule.Pattern = *request.Pattern
+	}
+	if request.ChatType != "" {
+		rule.ChatType = request.ChatType
+	}
+	if request.AllowSenders != nil {
+		rule.AllowSenders = request.AllowSenders
+	}
+	if request.DenySenders != nil {
+		rule.DenySenders = request.DenySenders
+	}
+	if request.BusinessHours != nil {
+		rule.BusinessHours = request.BusinessHours
+	}
+	if request.ClearHours {
+		rule.BusinessHours = nil
+	}
+	if request.CooldownSeconds != nil {
+		rule.CooldownSeconds = *request.CooldownSeconds
+	}
+	if request.ReplyType != "" {
+		rule.ReplyType = request.ReplyType
+	}
+	if request.ReplyText != nil {
+		rule.ReplyText = *request.ReplyText
+	}
+	if request.MediaURL != nil {
+		rule.MediaURL = *request.MediaURL
+	}
+	if request.IsActive != nil {
+		rule.IsActive = *request.IsActive
+	}
+
+	if err = validations.ValidateAutoReplyRule(ctx, rule); err != nil {
+		return rule, err
+	}
+
+	// The media is only downloaded again when the update names it or changes the reply type
+	if request.MediaURL != nil || rule.ReplyType != found.ReplyType || rule.MediaPath == "" {
+		if err = storeReplyMedia(&rule); err != nil {
+			return rule, err
+		}
+	}
+
+	if err = service.autoReplyRepo.Update(&rule); err != nil {
+		if rule.MediaPath != found.MediaPath {
+			removeReplyMedia(rule.MediaPath)
+		}
+		return rule, err
+	}
+
+	if rule.MediaPath != found.MediaPath {
+		removeReplyMedia(found.MediaPath)
+	}
+	if rule.Pattern != found.Pattern {
+		domainAutoReply.ForgetPattern(found.Pattern)
+	}
+	compileRulePattern(rule)
+
+	return normalizeRule(rule), nil
+}
+
+func (service serviceAutoReply) DeleteRule(_ context.Context, userID, id int) error {
+	rule, err := service.getRule(userID, id)
+	if err != nil {
+		return err
+	}
+	if err := service.autoReplyRepo.Delete(userID, id); err != nil {
+		return err
+	}
+
+	removeReplyMedia(rule.MediaPath)
+	domainAutoReply.ForgetPattern(rule.Pattern)
+	return nil
+}
+
+// DryRun evaluates the user's rules against a made-up message without sending anything
+func (service serviceAutoReply) DryRun(ctx context.Context, userID int, request domainAutoReply.DryRunRequest) (response domainAutoReply.DryRunResponse, err error) {
+	if err = validations.ValidateAutoReplyDryRun(ctx, request); err != nil {
+		return response, err
+	}
+
+	message := domainAutoReply.IncomingMessage{
+		Text:    request.Message,
+		ChatJID: request.ChatJID,
+		Sender:  request.Sender,
+		IsGroup: request.IsGroup || strings.HasSuffix(request.ChatJID, "@g.us"),
+		Time:    time.Now(),
+	}
+	if message.ChatJID == "" {
+		message.ChatJID = request.Sender
+	}
+	if request.Timestamp != "" {
+		message.Time, _ = time.Parse(time.RFC3339, request.Timestamp)
+	}
+
+	rules, err := service.autoReplyRepo.GetActiveByUser(userID)
+	if err != nil {
+		return response, err
+	}
+
+	rule, evaluations := domainAutoReply.FirstMatch(rules, message)
+	response.Evaluations = evaluations
+	if rule == nil {
+		return response, nil
+	}
+
+	matched := normalizeRule(*rule)
+	response.Matched = true
+	response.Rule = &matched
+	response.ReplyType = rule.ReplyType
+	response.ReplyText = rule.ReplyText
+	response.MediaURL = rule.MediaURL
+
+	remaining := whatsapp.AutoReplyCooldownRemaining(userID, *rule, request.Sender)
+	response.WouldReply = remaining == 0
+	if remaining > 0 {
+		// Round up so a cooldown about to expire is not reported as zero
+		response.CooldownRemainingSeconds = int((remaining + time.Second - 1) / time.Second)
+	}
+
+	return response, nil
+}
+
+// getRule loads a rule owned by the user or returns a not found error
+func (service serviceAutoReply) getRule(userID, id int) (*domainAutoReply.Rule, error) {
+	rule, err := service.autoReplyRepo.GetByID(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		return nil, pkgError.NotFoundError(fmt.Sprintf("auto-reply rule %d not found", id))
+	}
+	return rule, nil
+}
+
+// storeReplyMedia downloads the media of a media reply once, so replies do not fetch it again every time the rule
+// fires. Text replies keep no media.
+func storeReplyMedia(rule *domainAutoReply.Rule) error {
+	var (
+		data     []byte
+		fileName string
+		err      error
+	)
+	switch rule.ReplyType {
+	case domainAutoReply.ReplyImage:
+		data, fileName, err = utils.DownloadImageFromURL(rule.MediaURL)
+	case domainAutoReply.ReplyVideo:
+		data, fileName, err = utils.DownloadVideoFromURL(rule.MediaURL)
+	case domainAutoReply.ReplyAudio:
+		data, fileName, err = utils.DownloadAudioFromURL(rule.MediaURL)
+	case domainAutoReply.ReplyDocument:
+		data, fileName, err = utils.DownloadFileFromURL(rule.MediaURL)
+	default:
+		rule.MediaPath, rule.MediaName = "", ""
+		return nil
+	}
+	if err != nil {
+		return pkgError.ValidationError(fmt.Sprintf("failed to download media_url: %v", err))
+	}
+
+	path := fmt.Sprintf("%s/%s-%s", config.PathAutoReply, fiberUtils.UUIDv4(), filepath.Base(fileName))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return pkgError.InternalServerError(fmt.Sprintf("failed to store reply media: %v", err))
+	}
+
+	rule.MediaPath, rule.MediaName = path, fileName
+	return nil
+}
+
+// removeReplyMedia deletes media no rule replies with anymore
+func removeReplyMedia(path string) {
+	if path == "" {
+		return
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		logrus.Warnf("Failed to remove auto-reply media %s: %v", path, err)
+	}
+}
+
+// compileRulePattern compiles the pattern of a saved regex rule, so incoming messages do not compile it again
+func compileRulePattern(rule domainAutoReply.Rule) {
+	if rule.MatchType != domainAutoReply.MatchRegex {
+		return
+	}
+	if _, err := domainAutoReply.CompilePattern(rule.Pattern); err != nil {
+		logrus.Warnf("Failed to compile pattern of auto-reply rule %d: %v", rule.ID, err)
+	}
+}
+
+// normalizeRule returns empty lists instead of null in responses
+func normalizeRule(rule domainAutoReply.Rule) domainAutoReply.Rule {
+	if rule.Keywords == nil {
+		rule.Keywords = []string{}
+	}
+	if rule.AllowSenders == nil {
+		rule.AllowSenders = []string{}
+	}
+	if rule.DenySenders == nil {
+		rule.DenySenders = []string{}
+	}
+	return rule
+}
@@ -0,0 +1,259 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	domainBulk "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/bulk"
+	domainQueue "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/queue"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+)
+
+type serviceBulk struct {
+	bulkRepo  domainBulk.IBulkRepository
+	queueRepo domainQueue.IQueueRepository
+}
+
+func NewBulkService(bulkRepo domainBulk.IBulkRepository, queueRepo domainQueue.IQueueRepository) domainBulk.IBulkUsecase {
+	return &serviceBulk{
+		bulkRepo:  bulkRepo,
+		queueRepo: queueRepo,
+	}
+}
+
+// CreateJob renders the message for every recipient and queues the messages, the send queue worker paces them
+// and checks each recipient is on WhatsApp right before sending
+func (service serviceBulk) CreateJob(ctx context.Context, userID int, request domainBulk.CreateJobRequest) (domainBulk.Job, error) {
+	if err := validations.ValidateCreateBulkJob(ctx, request); err != nil {
+		return domainBulk.Job{}, err
+	}
+
+	var username string
+	if appCtx, ok := ctx.(*domainApp.AppContext); ok && appCtx.UserID == userID {
+		username = appCtx.Username
+	}
+
+	messages := make([]*domainQueue.Message, 0, len(request.Recipients))
+	for i, recipient := range request.Recipients {
+		variables := map[string]string{"phone": strings.SplitN(recipient.Phone, "@", 2)[0]}
+		for name, value := range recipient.Variables {
+			variables[name] = value
+		}
+
+		sendRequest := domainSend.MessageRequest{
+			BaseRequest: domainSend.BaseRequest{Phone: recipient.Phone},
+			Message:     utils.RenderTemplate(request.Message, variables),
+		}
+		if err := validations.ValidateSendMessage(ctx, sendRequest); err != nil {
+			return domainBulk.Job{}, pkgError.ValidationError(fmt.Sprintf("recipient %d: %s", i+1, err.Error()))
+		}
+
+		payload, err := json.Marshal(sendRequest)
+		if err != nil {
+			return domainBulk.Job{}, err
+		}
+		messages = append(messages, &domainQueue.Message{
+			UserID:   userID,
+			Username: username,
+			Type:     domainQueue.TypeText,
+			Phone:    recipient.Phone,
+			Payload:  string(payload),
+		})
+	}
+
+	job := &domainBulk.Job{UserID: userID, Message: request.Message, Status: domainBulk.StatusRunning}
+	if err := service.bulkRepo.Create(job); err != nil {
+		return domainBulk.Job{}, err
+	}
+
+	for _, message := range messages {
+		message.JobID = job.ID
+	}
+	if err := service.queueRepo.EnqueueBatch(messages); err != nil {
+		if updateErr := service.bulkRepo.UpdateStatus(job.ID, domainBulk.StatusCancelled); updateErr != nil {
+			logrus.Errorf("Failed to cancel bulk job %d: %v", job.ID, updateErr)
+		}
+		return domainBulk.Job{}, err
+	}
+
+	logrus.Infof("Queued bulk job %d of user %d for %d recipients", job.ID, userID, len(messages))
+	return service.withProgress(*job)
+}
+
+func (service serviceBulk) ListJobs(ctx context.Context, userID int, request domainBulk.ListJobsRequest) (response domainBulk.ListJobsResponse, err error) {
+	if err = validations.ValidateListBulkJobs(ctx, &request); err != nil {
+		return response, err
+	}
+
+	// Requests limited to some chats only list the jobs with a recipient in those
+	chatKeys, restricted := allowedChatKeys(ctx)
+	if restricted && len(chatKeys) == 0 {
+		response.Data = []domainBulk.Job{}
+		response.Pagination = domainBulk.PaginationResponse{Limit: request.Limit, Offset: request.Offset}
+		return response, nil
+	}
+
+	jobs, err := service.bulkRepo.List(userID, chatKeys, request.Limit, request.Offset)
+	if err != nil {
+		return response, err
+	}
+
+	total, err := service.bulkRepo.Count(userID, chatKeys)
+	if err != nil {
+		return response, err
+	}
+
+	for i := range jobs {
+		if jobs[i], err = service.withProgress(jobs[i]); err != nil {
+			return response, err
+		}
+	}
+
+	response.Data = jobs
+	response.Pagination = domainBulk.PaginationResponse{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+		Total:  total,
+	}
+	return response, nil
+}
+
+func (service serviceBulk) GetJob(ctx context.Context, userID int, id int64) (domainBulk.Job, error) {
+	job, err := service.bulkRepo.GetByID(userID, id)
+	if err != nil {
+		return domainBulk.Job{}, err
+	}
+	if job == nil {
+		return domainBulk.Job{}, pkgError.NotFoundError("bulk job not found")
+	}
+
+	// Requests limited to some chats only see the jobs with a recipient in those, as ListJobs does
+	if chatKeys, restricted := allowedChatKeys(ctx); restricted {
+		allowed := 0
+		if len(chatKeys) > 0 {
+			if allowed, err = service.queueRepo.CountByJob(id, "", chatKeys); err != nil {
+				return domainBulk.Job{}, err
+			}
+		}
+		if allowed == 0 {
+			return domainBulk.Job{}, pkgError.NotFoundError("bulk job not found")
+		}
+	}
+	return service.withProgress(*job)
+}
+
+func (service serviceBulk) ListRecipients(ctx context.Context, userID int, id int64, request domainBulk.ListRecipientsRequest) (response domainBulk.ListRecipientsResponse, err error) {
+	if err = validations.ValidateListBulkRecipients(ctx, &request); err != nil {
+		return response, err
+	}
+
+	if _, err = service.GetJob(ctx, userID, id); err != nil {
+		return response, err
+	}
+
+	// GetJob found a recipient in the allowed chats, so a restricted request has chat keys here
+	chatKeys, _ := allowedChatKeys(ctx)
+	messages, err := service.queueRepo.ListByJob(id, request.Status, chatKeys, request.Limit, request.Offset)
+	if err != nil {
+		return response, err
+	}
+
+	total, err := service.queueRepo.CountByJob(id, request.Status, chatKeys)
+	if err != nil {
+		return response, err
+	}
+
+	response.Data = make([]domainBulk.JobRecipient, 0, len(messages))
+	for _, message := range messages {
+		response.Data = append(response.Data, domainBulk.JobRecipient{
+			QueueID:   message.ID,
+			Phone:     message.Phone,
+			Status:    message.Status,
+			Attempts:  message.Attempts,
+			Error:     message.LastError,
+			MessageID: message.MessageID,
+			SentAt:    message.SentAt,
+		})
+	}
+	response.Pagination = domainBulk.PaginationResponse{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+		Total:  total,
+	}
+	return response, nil
+}
+
+// CancelJob cancels the recipients that were not sent yet, a message being sent right now still goes out
+func (service serviceBulk) CancelJob(ctx context.Context, userID int, id int64) (domainBulk.Job, error) {
+	job, err := service.GetJob(ctx, userID, id)
+	if err != nil {
+		return job, err
+	}
+	if job.Status != domainBulk.StatusRunning {
+		return job, pkgError.ValidationError(fmt.Sprintf("only running jobs can be cancelled, this one is %s", job.Status))
+	}
+
+	// Requests limited to some chats may only cancel the jobs they could have created
+	if chatKeys, restricted := allowedChatKeys(ctx); restricted {
+		allowed, err := service.queueRepo.CountByJob(id, "", chatKeys)
+		if err != nil {
+			return job, err
+		}
+		if allowed < job.Progress.Total {
+			return job, pkgError.ForbiddenError("bulk job has recipients in chats that are not allowed")
+		}
+	}
+
+	if err := service.bulkRepo.UpdateStatus(id, domainBulk.StatusCancelled); err != nil {
+		return job, err
+	}
+	cancelled, err := service.queueRepo.CancelJob(id)
+	if err != nil {
+		return job, err
+	}
+
+	logrus.Infof("Cancelled bulk job %d of user %d, %d recipients were not sent", id, userID, cancelled)
+	return service.GetJob(ctx, userID, id)
+}
+
+// withProgress counts the recipients of a job by status and marks a running job completed once none are left to send
+func (service serviceBulk) withProgress(job domainBulk.Job) (domainBulk.Job, error) {
+	counts, err := service.queueRepo.CountJobStatuses(job.ID)
+	if err != nil {
+		return job, err
+	}
+
+	job.Progress = domainBulk.Progress{
+		Pending:       counts[domainQueue.StatusPending] + counts[domainQueue.StatusSending],
+		Sent:          counts[domainQueue.StatusSent],
+		Failed:        counts[domainQueue.StatusFailed],
+		NotOnWhatsApp: counts[domainQueue.StatusNotOnWhatsApp],
+		Cancelled:     counts[domainQueue.StatusCancelled],
+	}
+	for _, count := range counts {
+		job.Progress.Total += count
+	}
+
+	if job.Status == domainBulk.StatusRunning && job.Progress.Pending == 0 && job.Progress.Total > 0 {
+		if err := service.bulkRepo.UpdateStatus(job.ID, domainBulk.StatusCompleted); err != nil {
+			return job, err
+		}
+		job.Status = domainBulk.StatusCompleted
+	}
+	return job, nil
+}
+
+// allowedChatKeys returns the chat keys a request is limited to, and false when it may see every chat
+func allowedChatKeys(ctx context.Context) ([]string, bool) {
+	if appCtx, ok := ctx.(*domainApp.AppContext); ok {
+		return appCtx.AllowedChatKeys()
+	}
+	return nil, false
+}
@@ -43,11 +43,24 @@ func (service serviceChat) getClientFromContext(ctx context.Context) (*whatsmeow
 	return nil, pkgError.ErrNotLoggedIn
 }
 
+// getChatStorageFromContext scopes chat storage to the user in the app context
+func (service serviceChat) getChatStorageFromContext(ctx context.Context) (domainChatStorage.IChatStorageRepository, error) {
+	if appCtx, ok := ctx.(*domainApp.AppContext); ok && appCtx.UserID != 0 {
+		return service.chatStorageRepo.ForUser(appCtx.UserID), nil
+	}
+	return nil, pkgError.ErrNotLoggedIn
+}
+
 func (service serviceChat) ListChats(ctx context.Context, request domainChat.ListChatsRequest) (response domainChat.ListChatsResponse, err error) {
 	if err = validations.ValidateListChats(ctx, &request); err != nil {
 		return response, err
 	}
 
+	chatStorageRepo, err := service.getChatStorageFromContext(ctx)
+	if err != nil {
+		return response, err
+	}
+
 	// Create filter from request
 	filter := &domainChatStorage.ChatFilter{
 		Limit:      request.Limit,
@@ -56,15 +69,26 @@ func (service serviceChat) ListChats(ctx context.Context, request domainChat.Lis
 		HasMedia:   request.HasMedia,
 	}
 
+	// Requests limited to some chats only list those
+	if appCtx, ok := ctx.(*domainApp.AppContext); ok {
+		keys, restricted := appCtx.AllowedChatKeys()
+		if restricted && len(keys) == 0 {
+			response.Data = []domainChat.ChatInfo{}
+			response.Pagination = domainChat.PaginationResponse{Limit: request.Limit, Offset: request.Offset}
+			return response, nil
+		}
+		filter.ChatKeys = keys
+	}
+
 	// Get chats from storage
-	chats, err := service.chatStorageRepo.GetChats(filter)
+	chats, err := chatStorageRepo.GetChats(filter)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to get chats from storage")
 		return response, err
 	}
 
 	// Get total count for pagination
-	totalCount, err := service.chatStorageRepo.GetTotalChatCount()
+	totalCount, err := chatStorageRepo.CountChats(filter)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to get total chat count")
 		// Continue with partial data
@@ -109,8 +133,13 @@ func (service serviceChat) GetChatMessages(ctx context.Context, request domainCh
 		return response, err
 	}
 
+	chatStorageRepo, err := service.getChatStorageFromContext(ctx)
+	if err != nil {
+		return response, err
+	}
+
 	// Get chat info first
-	chat, err := service.chatStorageRepo.GetChat(request.ChatJID)
+	chat, err := chatStorageRepo.GetChat(request.ChatJID)
 	if err != nil {
 		logrus.WithError(err).WithField("chat_jid", request.ChatJID).Error("Failed to get chat info")
 		return response, err
@@ -149,14 +178,14 @@ func (service serviceChat) GetChatMessages(ctx context.Context, request domainCh
 	var messages []*domainChatStorage.Message
 	if request.Search != "" {
 		// Use search functionality if search query is provided
-		messages, err = service.chatStorageRepo.SearchMessages(request.ChatJID, request.Search, request.Limit)
+		messages, err = chatStorageRepo.SearchMessages(request.ChatJID, request.Search, request.Limit)
 		if err != nil {
 			logrus.WithError(err).WithField("chat_jid", request.ChatJID).Error("Failed to search messages")
 			return response, err
 		}
 	} else {
 		// Use regular filter
-		messages, err = service.chatStorageRepo.GetMessages(filter)
+		messages, err = chatStorageRepo.GetMessages(filter)
 		if err != nil {
 			logrus.WithError(err).WithField("chat_jid", request.ChatJID).Error("Failed to get messages")
 			return response, err
@@ -164,7 +193,7 @@ func (service serviceChat) GetChatMessages(ctx context.Context, request domainCh
 	}
 
 	// Get total message count for pagination
-	totalCount, err := service.chatStorageRepo.GetChatMessageCount(request.ChatJID)
+	totalCount, err := chatStorageRepo.GetChatMessageCount(request.ChatJID)
 	if err != nil {
 		logrus.WithError(err).WithField("chat_jid", request.ChatJID).Error("Failed to get message count")
 		// Continue with partial data
@@ -3,11 +3,14 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	domainAudit "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/audit"
 	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
@@ -17,10 +20,19 @@ import (
 	"go.mau.fi/whatsmeow/types"
 )
 
-type serviceGroup struct{}
+type serviceGroup struct {
+	auditRepo domainAudit.IAuditRepository
+}
+
+func NewGroupService(auditRepo domainAudit.IAuditRepository) domainGroup.IGroupUsecase {
+	return &serviceGroup{
+		auditRepo: auditRepo,
+	}
+}
 
-func NewGroupService() domainGroup.IGroupUsecase {
-	return &serviceGroup{}
+// audit records an action on a group
+func (service serviceGroup) audit(ctx context.Context, action, groupID, reference string, err error) {
+	recordAudit(ctx, service.auditRepo, domainAudit.Entry{Action: action, TargetJID: groupID, Reference: reference}, err)
 }
 
 // getClientFromContext extracts WhatsApp client from app context for user-specific operations
@@ -40,6 +52,8 @@ func (service serviceGroup) getClientFromContext(ctx context.Context) (*whatsmeo
 }
 
 func (service serviceGroup) JoinGroupWithLink(ctx context.Context, request domainGroup.JoinGroupWithLinkRequest) (groupID string, err error) {
+	defer func() { service.audit(ctx, domainAudit.ActionGroupJoin, groupID, "", err) }()
+
 	if err = validations.ValidateJoinGroupWithLink(ctx, request); err != nil {
 		return groupID, err
 	}
@@ -58,6 +72,8 @@ func (service serviceGroup) JoinGroupWithLink(ctx context.Context, request domai
 }
 
 func (service serviceGroup) LeaveGroup(ctx context.Context, request domainGroup.LeaveGroupRequest) (err error) {
+	defer func() { service.audit(ctx, domainAudit.ActionGroupLeave, request.GroupID, "", err) }()
+
 	if err = validations.ValidateLeaveGroup(ctx, request); err != nil {
 		return err
 	}
@@ -76,6 +92,8 @@ func (service serviceGroup) LeaveGroup(ctx context.Context, request domainGroup.
 }
 
 func (service serviceGroup) CreateGroup(ctx context.Context, request domainGroup.CreateGroupRequest) (groupID string, err error) {
+	defer func() { service.audit(ctx, domainAudit.ActionGroupCreate, groupID, request.Title, err) }()
+
 	if err = validations.ValidateCreateGroup(ctx, request); err != nil {
 		return groupID, err
 	}
@@ -138,6 +156,10 @@ func (service serviceGroup) GetGroupInfoFromLink(ctx context.Context, request do
 }
 
 func (service serviceGroup) ManageParticipant(ctx context.Context, request domainGroup.ParticipantRequest) (result []domainGroup.ParticipantStatus, err error) {
+	defer func() {
+		service.audit(ctx, domainAudit.ActionGroupParticipants, request.GroupID, string(request.Action)+" "+strings.Join(request.Participants, ","), err)
+	}()
+
 	if err = validations.ValidateParticipant(ctx, request); err != nil {
 		return result, err
 	}
@@ -213,6 +235,10 @@ func (service serviceGroup) GetGroupRequestParticipants(ctx context.Context, req
 }
 
 func (service serviceGroup) ManageGroupRequestParticipants(ctx context.Context, request domainGroup.GroupRequestParticipantsRequest) (result []domainGroup.ParticipantStatus, err error) {
+	defer func() {
+		service.audit(ctx, domainAudit.ActionGroupRequests, request.GroupID, string(request.Action)+" "+strings.Join(request.Participants, ","), err)
+	}()
+
 	if err = validations.ValidateManageGroupRequestParticipants(ctx, request); err != nil {
 		return result, err
 	}
@@ -278,6 +304,8 @@ func (service serviceGroup) participantToJID(ctx context.Context, participants [
 }
 
 func (service serviceGroup) SetGroupPhoto(ctx context.Context, request domainGroup.SetGroupPhotoRequest) (pictureID string, err error) {
+	defer func() { service.audit(ctx, domainAudit.ActionGroupPhoto, request.GroupID, pictureID, err) }()
+
 	if err = validations.ValidateSetGroupPhoto(ctx, request); err != nil {
 		return pictureID, err
 	}
@@ -320,6 +348,8 @@ func (service serviceGroup) SetGroupPhoto(ctx context.Context, request domainGro
 }
 
 func (service serviceGroup) SetGroupName(ctx context.Context, request domainGroup.SetGroupNameRequest) (err error) {
+	defer func() { service.audit(ctx, domainAudit.ActionGroupName, request.GroupID, request.Name, err) }()
+
 	if err = validations.ValidateSetGroupName(ctx, request); err != nil {
 		return err
 	}
@@ -338,6 +368,10 @@ func (service serviceGroup) SetGroupName(ctx context.Context, request domainGrou
 }
 
 func (service serviceGroup) SetGroupLocked(ctx context.Context, request domainGroup.SetGroupLockedRequest) (err error) {
+	defer func() {
+		service.audit(ctx, domainAudit.ActionGroupLocked, request.GroupID, strconv.FormatBool(request.Locked), err)
+	}()
+
 	if err = validations.ValidateSetGroupLocked(ctx, request); err != nil {
 		return err
 	}
@@ -356,6 +390,10 @@ func (service serviceGroup) SetGroupLocked(ctx context.Context, request domainGr
 }
 
 func (service serviceGroup) SetGroupAnnounce(ctx context.Context, request domainGroup.SetGroupAnnounceRequest) (err error) {
+	defer func() {
+		service.audit(ctx, domainAudit.ActionGroupAnnounce, request.GroupID, strconv.FormatBool(request.Announce), err)
+	}()
+
 	if err = validations.ValidateSetGroupAnnounce(ctx, request); err != nil {
 		return err
 	}
@@ -374,6 +412,8 @@ func (service serviceGroup) SetGroupAnnounce(ctx context.Context, request domain
 }
 
 func (service serviceGroup) SetGroupTopic(ctx context.Context, request domainGroup.SetGroupTopicRequest) (err error) {
+	defer func() { service.audit(ctx, domainAudit.ActionGroupTopic, request.GroupID, "", err) }()
+
 	if err = validations.ValidateSetGroupTopic(ctx, request); err != nil {
 		return err
 	}
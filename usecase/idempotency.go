@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainIdempotency "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/idempotency"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+)
+
+// idempotencyStaleAfter frees keys of requests that never finished, e.g. because the process stopped
+const idempotencyStaleAfter = 10 * time.Minute
+
+type serviceIdempotency struct {
+	idempotencyRepo domainIdempotency.IIdempotencyRepository
+}
+
+func NewIdempotencyService(idempotencyRepo domainIdempotency.IIdempotencyRepository) domainIdempotency.IIdempotencyUsecase {
+	return &serviceIdempotency{
+		idempotencyRepo: idempotencyRepo,
+	}
+}
+
+func (service serviceIdempotency) Begin(_ context.Context, userID int, key, fingerprint string) (*domainIdempotency.Record, error) {
+	if err := validations.ValidateIdempotencyKey(key); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	existing, err := service.idempotencyRepo.Reserve(&domainIdempotency.Record{
+		UserID:      userID,
+		Key:         key,
+		Fingerprint: fingerprint,
+		Status:      domainIdempotency.StatusProcessing,
+		ExpiresAt:   now.Add(time.Duration(config.WhatsappIdempotencyWindow) * time.Hour),
+	}, now.Add(-idempotencyStaleAfter))
+	if err != nil || existing == nil {
+		return nil, err
+	}
+
+	if existing.Fingerprint != fingerprint {
+		return nil, pkgError.UnprocessableError(fmt.Sprintf("Idempotency-Key %s was already used for a different request", key))
+	}
+	if existing.Status == domainIdempotency.StatusProcessing {
+		return nil, pkgError.ConflictError(fmt.Sprintf("a request with Idempotency-Key %s is still being processed", key))
+	}
+	return existing, nil
+}
+
+func (service serviceIdempotency) Complete(_ context.Context, userID int, key string, statusCode int, response []byte) error {
+	return service.idempotencyRepo.Complete(userID, key, statusCode, response)
+}
+
+func (service serviceIdempotency) Release(_ context.Context, userID int, key string) error {
+	return service.idempotencyRepo.Release(userID, key)
+}
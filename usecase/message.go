@@ -2,10 +2,22 @@ package usecase
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	domainAudit "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/audit"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
@@ -24,14 +36,21 @@ import (
 
 type serviceMessage struct {
 	chatStorageRepo domainChatStorage.IChatStorageRepository
+	auditRepo       domainAudit.IAuditRepository
 }
 
-func NewMessageService(chatStorageRepo domainChatStorage.IChatStorageRepository) domainMessage.IMessageUsecase {
+func NewMessageService(chatStorageRepo domainChatStorage.IChatStorageRepository, auditRepo domainAudit.IAuditRepository) domainMessage.IMessageUsecase {
 	return &serviceMessage{
 		chatStorageRepo: chatStorageRepo,
+		auditRepo:       auditRepo,
 	}
 }
 
+// audit records an action on an existing message, referencing that message
+func (service serviceMessage) audit(ctx context.Context, action, phone, messageID string, err error) {
+	recordAudit(ctx, service.auditRepo, domainAudit.Entry{Action: action, TargetJID: phone, Reference: messageID}, err)
+}
+
 // getClientFromContext extracts WhatsApp client from app context for user-specific operations
 func (service serviceMessage) getClientFromContext(ctx context.Context) (*whatsmeow.Client, error) {
 	if appCtx, ok := ctx.(*domainApp.AppContext); ok {
@@ -49,6 +68,8 @@ func (service serviceMessage) getClientFromContext(ctx context.Context) (*whatsm
 }
 
 func (service serviceMessage) MarkAsRead(ctx context.Context, request domainMessage.MarkAsReadRequest) (response domainMessage.GenericResponse, err error) {
+	defer func() { service.audit(ctx, domainAudit.ActionMessageRead, request.Phone, request.MessageID, err) }()
+
 	if err = validations.ValidateMarkAsRead(ctx, request); err != nil {
 		return response, err
 	}
@@ -81,6 +102,8 @@ func (service serviceMessage) MarkAsRead(ctx context.Context, request domainMess
 }
 
 func (service serviceMessage) ReactMessage(ctx context.Context, request domainMessage.ReactionRequest) (response domainMessage.GenericResponse, err error) {
+	defer func() { service.audit(ctx, domainAudit.ActionMessageReact, request.Phone, request.MessageID, err) }()
+
 	if err = validations.ValidateReactMessage(ctx, request); err != nil {
 		return response, err
 	}
@@ -117,6 +140,8 @@ func (service serviceMessage) ReactMessage(ctx context.Context, request domainMe
 }
 
 func (service serviceMessage) RevokeMessage(ctx context.Context, request domainMessage.RevokeRequest) (response domainMessage.GenericResponse, err error) {
+	defer func() { service.audit(ctx, domainAudit.ActionMessageRevoke, request.Phone, request.MessageID, err) }()
+
 	if err = validations.ValidateRevokeMessage(ctx, request); err != nil {
 		return response, err
 	}
@@ -142,6 +167,8 @@ func (service serviceMessage) RevokeMessage(ctx context.Context, request domainM
 }
 
 func (service serviceMessage) DeleteMessage(ctx context.Context, request domainMessage.DeleteRequest) (err error) {
+	defer func() { service.audit(ctx, domainAudit.ActionMessageDelete, request.Phone, request.MessageID, err) }()
+
 	if err = validations.ValidateDeleteMessage(ctx, request); err != nil {
 		return err
 	}
@@ -182,6 +209,8 @@ func (service serviceMessage) DeleteMessage(ctx context.Context, request domainM
 }
 
 func (service serviceMessage) UpdateMessage(ctx context.Context, request domainMessage.UpdateMessageRequest) (response domainMessage.GenericResponse, err error) {
+	defer func() { service.audit(ctx, domainAudit.ActionMessageUpdate, request.Phone, request.MessageID, err) }()
+
 	if err = validations.ValidateUpdateMessage(ctx, request); err != nil {
 		return response, err
 	}
@@ -209,6 +238,8 @@ func (service serviceMessage) UpdateMessage(ctx context.Context, request domainM
 
 // StarMessage implements message.IMessageService.
 func (service serviceMessage) StarMessage(ctx context.Context, request domainMessage.StarRequest) (err error) {
+	defer func() { service.audit(ctx, domainAudit.ActionMessageStar, request.Phone, request.MessageID, err) }()
+
 	if err = validations.ValidateStarMessage(ctx, request); err != nil {
 		return err
 	}
@@ -235,3 +266,176 @@ func (service serviceMessage) StarMessage(ctx context.Context, request domainMes
 	}
 	return nil
 }
+
+// DownloadMedia decrypts the media of a stored message into the media cache of the user. A cached file is reused
+// until it is older than the media cache window, so repeated and ranged downloads do not fetch it again.
+func (service serviceMessage) DownloadMedia(ctx context.Context, request domainMessage.DownloadMediaRequest) (response domainMessage.DownloadMediaResponse, err error) {
+	if err = validations.ValidateDownloadMedia(ctx, request); err != nil {
+		return response, err
+	}
+
+	appCtx, ok := ctx.(*domainApp.AppContext)
+	if !ok || appCtx.UserID == 0 {
+		return response, pkgError.ErrNotLoggedIn
+	}
+
+	message, err := service.chatStorageRepo.ForUser(appCtx.UserID).GetMessageByID(request.MessageID)
+	if err != nil {
+		return response, err
+	}
+	if message == nil {
+		return response, pkgError.NotFoundError(fmt.Sprintf("message %s not found", request.MessageID))
+	}
+	if !appCtx.AllowsChat(message.ChatJID) {
+		return response, pkgError.ForbiddenError(fmt.Sprintf("Access to chat %s is not allowed", message.ChatJID))
+	}
+
+	mediaMessage := utils.BuildMediaMessage(message.MediaType, message.Filename, message.Mimetype, message.URL, message.MediaKey, message.FileSHA256, message.FileEncSHA256, message.FileLength, "")
+	if mediaMessage == nil || len(message.MediaKey) == 0 || message.URL == "" {
+		return response, pkgError.NotFoundError(fmt.Sprintf("message %s has no downloadable media", request.MessageID))
+	}
+	if int64(message.FileLength) > config.WhatsappSettingMaxDownloadSize {
+		return response, pkgError.ValidationError(fmt.Sprintf("media exceeds the maximum download size of %d bytes", config.WhatsappSettingMaxDownloadSize))
+	}
+
+	response = domainMessage.DownloadMediaResponse{
+		MessageID: message.ID,
+		MediaType: message.MediaType,
+		MimeType:  utils.ExtractMediaMimetype(mediaMessage),
+	}
+
+	cacheDir := filepath.Join(config.PathMediaCache, strconv.Itoa(appCtx.UserID))
+	response.FilePath = filepath.Join(cacheDir, mediaCacheName(message))
+	cacheWindow := time.Duration(config.WhatsappMediaCacheHours) * time.Hour
+	if info, err := os.Stat(response.FilePath); err == nil && time.Since(info.ModTime()) < cacheWindow {
+		response.FileLength = info.Size()
+		if message.Mimetype == "" {
+			response.MimeType = sniffMediaMimetype(response.FilePath, response.MimeType)
+		}
+		response.Filename = mediaFilename(message, response.MimeType)
+		return response, nil
+	}
+
+	client, err := service.getClientFromContext(ctx)
+	if err != nil {
+		return response, err
+	}
+
+	data, err := client.DownloadAny(ctx, mediaMessage)
+	if err != nil {
+		if errors.Is(err, whatsmeow.ErrMediaDownloadFailedWith403) || errors.Is(err, whatsmeow.ErrMediaDownloadFailedWith404) || errors.Is(err, whatsmeow.ErrMediaDownloadFailedWith410) {
+			return response, pkgError.MediaExpiredError(fmt.Sprintf("media of message %s is no longer available on the WhatsApp servers", request.MessageID))
+		}
+		return response, err
+	}
+
+	if err = writeMediaCache(cacheDir, response.FilePath, data); err != nil {
+		return response, err
+	}
+	go pruneMediaCache(cacheDir, cacheWindow)
+
+	response.FileLength = int64(len(data))
+	if message.Mimetype == "" {
+		response.MimeType = sniffMediaMimetype(response.FilePath, response.MimeType)
+	}
+	response.Filename = mediaFilename(message, response.MimeType)
+	return response, nil
+}
+
+// mediaFilename returns the filename of a document, other media have none and are named after the message ID with
+// the extension of their mimetype
+func mediaFilename(message *domainChatStorage.Message, mimetype string) string {
+	if message.Filename != "" {
+		return message.Filename
+	}
+
+	mediaType, _, _ := strings.Cut(mimetype, ";")
+	mediaType = strings.TrimSpace(mediaType)
+	if extension, ok := mediaExtensions[mediaType]; ok {
+		return message.ID + extension
+	}
+	if extensions, err := mime.ExtensionsByType(mediaType); err == nil && len(extensions) > 0 {
+		return message.ID + extensions[0]
+	}
+	return message.ID
+}
+
+// mediaExtensions are the usual extensions of WhatsApp media, mime.ExtensionsByType sorts its own so .jfif comes before .jpg
+var mediaExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+	"video/mp4":  ".mp4",
+	"audio/ogg":  ".ogg",
+	"audio/mpeg": ".mp3",
+	"audio/mp4":  ".m4a",
+}
+
+// sniffMediaMimetype detects the mimetype of media stored before its mimetype was kept. The detected type is only
+// used when it is of the same kind as the fallback, the usual type of the media, as audio/ogg is detected as
+// application/ogg for example.
+func sniffMediaMimetype(path, fallback string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return fallback
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(file, head)
+	detected, _, _ := strings.Cut(http.DetectContentType(head[:n]), ";")
+	kind, _, _ := strings.Cut(detected, "/")
+	fallbackKind, _, _ := strings.Cut(fallback, "/")
+	if detected == "application/octet-stream" || kind != fallbackKind {
+		return fallback
+	}
+	return detected
+}
+
+// mediaCacheName names the cached file after the hash of the media, so copies of the same media share it
+func mediaCacheName(message *domainChatStorage.Message) string {
+	if len(message.FileSHA256) > 0 {
+		return hex.EncodeToString(message.FileSHA256)
+	}
+	sum := sha256.Sum256([]byte(message.ID))
+	return hex.EncodeToString(sum[:])
+}
+
+// writeMediaCache writes the file through a temporary file, so concurrent downloads never serve a partial file
+func writeMediaCache(cacheDir, path string, data []byte) error {
+	if err := os.MkdirAll(cacheDir, 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(cacheDir, ".download-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// pruneMediaCache removes the files of a media cache directory that are older than the cache window
+func pruneMediaCache(cacheDir string, cacheWindow time.Duration) {
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		logrus.Warnf("Failed to read media cache %s: %v", cacheDir, err)
+		return
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < cacheWindow {
+			continue
+		}
+		if err = os.Remove(filepath.Join(cacheDir, entry.Name())); err != nil && !os.IsNotExist(err) {
+			logrus.Warnf("Failed to remove cached media %s: %v", entry.Name(), err)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"testing"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMediaFilename(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  domainChatStorage.Message
+		mimetype string
+		want     string
+	}{
+		{name: "should keep document filename", message: domainChatStorage.Message{ID: "3EB0A1", Filename: "invoice.pdf"}, mimetype: "application/pdf", want: "invoice.pdf"},
+		{name: "should name image after message", message: domainChatStorage.Message{ID: "3EB0A1"}, mimetype: "image/jpeg", want: "3EB0A1.jpg"},
+		{name: "should ignore mimetype parameters", message: domainChatStorage.Message{ID: "3EB0A1"}, mimetype: "audio/ogg; codecs=opus", want: "3EB0A1.ogg"},
+		{name: "should name sticker after message", message: domainChatStorage.Message{ID: "3EB0A1"}, mimetype: "image/webp", want: "3EB0A1.webp"},
+		{name: "should fall back to known extension", message: domainChatStorage.Message{ID: "3EB0A1"}, mimetype: "image/gif", want: "3EB0A1.gif"},
+		{name: "should omit unknown extension", message: domainChatStorage.Message{ID: "3EB0A1"}, mimetype: "application/x-unknown", want: "3EB0A1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, mediaFilename(&tt.message, tt.mimetype))
+		})
+	}
+}
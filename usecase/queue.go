@@ -0,0 +1,480 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	domainQueue "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/queue"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+)
+
+const (
+	queuePollInterval    = 1 * time.Second
+	queueRetryBaseDelay  = 10 * time.Second
+	queueRetryMaxDelay   = 10 * time.Minute
+	queueTypingPerChar   = 50 * time.Millisecond
+	queueTypingMinimum   = 1 * time.Second
+	queueTypingMaximum   = 6 * time.Second
+	queueAttachmentInRAM = 10 << 20 // Larger attachments are buffered in a temporary file while sending
+)
+
+type serviceQueue struct {
+	queueRepo   domainQueue.IQueueRepository
+	sendService domainSend.ISendUsecase
+
+	// workers holds the users whose queue is being drained, so each user has a single worker
+	workers *sync.Map
+	// nextSendAt holds when each user may send their next queued message
+	nextSendAt *sync.Map
+}
+
+func NewQueueService(queueRepo domainQueue.IQueueRepository, sendService domainSend.ISendUsecase) domainQueue.IQueueUsecase {
+	return &serviceQueue{
+		queueRepo:   queueRepo,
+		sendService: sendService,
+		workers:     &sync.Map{},
+		nextSendAt:  &sync.Map{},
+	}
+}
+
+func (service serviceQueue) ListMessages(ctx context.Context, userID int, request domainQueue.ListRequest) (response domainQueue.ListResponse, err error) {
+	if err = validations.ValidateListQueue(ctx, &request); err != nil {
+		return response, err
+	}
+
+	// Requests limited to some chats only list the messages to those
+	var chatKeys []string
+	if appCtx, ok := ctx.(*domainApp.AppContext); ok {
+		keys, restricted := appCtx.AllowedChatKeys()
+		if restricted && len(keys) == 0 {
+			response.Data = []domainQueue.Message{}
+			response.Pagination = domainQueue.PaginationResponse{Limit: request.Limit, Offset: request.Offset}
+			return response, nil
+		}
+		chatKeys = keys
+	}
+
+	messages, err := service.queueRepo.List(userID, request.Status, chatKeys, request.Limit, request.Offset)
+	if err != nil {
+		return response, err
+	}
+
+	total, err := service.queueRepo.Count(userID, request.Status, chatKeys)
+	if err != nil {
+		return response, err
+	}
+
+	response.Data = messages
+	response.Pagination = domainQueue.PaginationResponse{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+		Total:  total,
+	}
+	return response, nil
+}
+
+func (service serviceQueue) GetMessage(ctx context.Context, userID int, id int64) (domainQueue.Message, error) {
+	message, err := service.queueRepo.GetByID(userID, id)
+	if err != nil {
+		return domainQueue.Message{}, err
+	}
+	if message == nil {
+		return domainQueue.Message{}, pkgError.NotFoundError("queued message not found")
+	}
+	if appCtx, ok := ctx.(*domainApp.AppContext); ok && !appCtx.AllowsChat(message.Phone) {
+		return domainQueue.Message{}, pkgError.ForbiddenError(fmt.Sprintf("Access to chat %s is not allowed", message.Phone))
+	}
+	return *message, nil
+}
+
+func (service serviceQueue) CancelMessage(ctx context.Context, userID int, id int64) (domainQueue.Message, error) {
+	message, err := service.GetMessage(ctx, userID, id)
+	if err != nil {
+		return message, err
+	}
+
+	cancelled, err := service.queueRepo.Cancel(userID, id)
+	if err != nil {
+		return message, err
+	}
+	if !cancelled {
+		return message, pkgError.ValidationError(fmt.Sprintf("only pending messages can be cancelled, this one is %s", message.Status))
+	}
+
+	removeAttachment(message)
+	return service.GetMessage(ctx, userID, id)
+}
+
+func (service serviceQueue) RunWorker(ctx context.Context) {
+	// Messages claimed by a previous process may or may not have been sent; sending them again is preferred to losing them
+	if reset, err := service.queueRepo.ResetSending(); err != nil {
+		logrus.Errorf("Failed to reset interrupted queued messages: %v", err)
+	} else if reset > 0 {
+		logrus.Warnf("Resuming %d queued messages that were being sent when the process stopped", reset)
+	}
+
+	ticker := time.NewTicker(queuePollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			service.dispatch(ctx)
+		}
+	}
+}
+
+// dispatch starts a worker for every user with due messages that has none running
+func (service serviceQueue) dispatch(ctx context.Context) {
+	userIDs, err := service.queueRepo.GetDueUsers(time.Now())
+	if err != nil {
+		logrus.Errorf("Failed to load send queue: %v", err)
+		return
+	}
+
+	for _, userID := range userIDs {
+		if _, running := service.workers.LoadOrStore(userID, true); running {
+			continue
+		}
+		go func(userID int) {
+			defer service.workers.Delete(userID)
+			service.drain(ctx, userID)
+		}(userID)
+	}
+}
+
+// drain sends the due messages of a user one after the other, pausing a random delay between them
+func (service serviceQueue) drain(ctx context.Context, userID int) {
+	for {
+		// Queued messages wait for the session to come back instead of using up their attempts
+		client := whatsapp.GetClientForUser(userID)
+		if client == nil || !client.IsConnected() || !client.IsLoggedIn() {
+			return
+		}
+
+		if next, ok := service.nextSendAt.Load(userID); ok {
+			if !sleepContext(ctx, time.Until(next.(time.Time))) {
+				return
+			}
+		}
+
+		message, err := service.queueRepo.ClaimNext(userID, time.Now())
+		if err != nil {
+			logrus.Errorf("Failed to claim queued message of user %d: %v", userID, err)
+			return
+		}
+		if message == nil {
+			return
+		}
+
+		service.deliver(ctx, *message)
+		service.nextSendAt.Store(userID, time.Now().Add(queueDelay()))
+	}
+}
+
+// deliver sends a claimed message and records the outcome
+func (service serviceQueue) deliver(ctx context.Context, message domainQueue.Message) {
+	appCtx := &domainApp.AppContext{Context: ctx, UserID: message.UserID, Username: message.Username}
+
+	if config.WhatsappSendQueueTyping {
+		service.simulateTyping(appCtx, message)
+	}
+
+	response, err := sendStoredRequest(appCtx, service.sendService, storedRequest{
+		Type:           message.Type,
+		Payload:        message.Payload,
+		AttachmentPath: message.AttachmentPath,
+		AttachmentName: message.AttachmentName,
+		AttachmentType: message.AttachmentType,
+	})
+	attempts := message.Attempts + 1
+
+	if err == nil {
+		logrus.Infof("Sent queued message %d of user %d as %s", message.ID, message.UserID, response.MessageID)
+		if err := service.queueRepo.MarkSent(message.ID, attempts, response.MessageID); err != nil {
+			logrus.Errorf("Failed to mark queued message %d as sent: %v", message.ID, err)
+		}
+		removeAttachment(message)
+		return
+	}
+
+	// Waiting out a sending limit is not a failed attempt
+	var rateLimit pkgError.RateLimitError
+	if errors.As(err, &rateLimit) {
+		retryAt := time.Now().Add(time.Duration(rateLimit.RetryAfter) * time.Second)
+		if err := service.queueRepo.Reschedule(message.ID, message.Attempts, rateLimit.Error(), retryAt); err != nil {
+			logrus.Errorf("Failed to reschedule queued message %d: %v", message.ID, err)
+		}
+		return
+	}
+
+	if isTransientSendError(err) && attempts < config.WhatsappSendQueueMaxAttempts {
+		delay := queueRetryDelay(attempts)
+		logrus.Warnf("Attempt %d to send queued message %d of user %d failed, retrying in %s: %v", attempts, message.ID, message.UserID, delay, err)
+		if err := service.queueRepo.Reschedule(message.ID, attempts, err.Error(), time.Now().Add(delay)); err != nil {
+			logrus.Errorf("Failed to reschedule queued message %d: %v", message.ID, err)
+		}
+		return
+	}
+
+	// Sending only returns an invalid JID error when the account validation finds no WhatsApp account
+	status := domainQueue.StatusFailed
+	var invalidJID pkgError.InvalidJID
+	if errors.As(err, &invalidJID) {
+		status = domainQueue.StatusNotOnWhatsApp
+	}
+
+	logrus.Errorf("Queued message %d of user %d failed after %d attempts: %v", message.ID, message.UserID, attempts, err)
+	if err := service.queueRepo.MarkFailed(message.ID, attempts, status, err.Error()); err != nil {
+		logrus.Errorf("Failed to mark queued message %d as failed: %v", message.ID, err)
+	}
+	removeAttachment(message)
+}
+
+// storedRequest is a send request kept to be sent later, by the send queue or the scheduler
+type storedRequest struct {
+	Type           string
+	Payload        string
+	AttachmentPath string
+	AttachmentName string
+	AttachmentType string
+}
+
+// sendStoredRequest decodes a stored request and hands it to the matching sender
+func sendStoredRequest(ctx context.Context, sendService domainSend.ISendUsecase, stored storedRequest) (response domainSend.GenericResponse, err error) {
+	// The senders panic when the session drops, which the REST recovery middleware would otherwise turn into an error
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			if recoveredErr, ok := recovered.(error); ok {
+				err = recoveredErr
+			} else {
+				err = fmt.Errorf("%v", recovered)
+			}
+		}
+	}()
+
+	var upload *multipart.FileHeader
+	if stored.AttachmentPath != "" {
+		form, err := attachmentForm(stored)
+		if err != nil {
+			return domainSend.GenericResponse{}, err
+		}
+		defer form.RemoveAll()
+		upload = form.File["attachment"][0]
+	}
+
+	payload := []byte(stored.Payload)
+	switch stored.Type {
+	case domainQueue.TypeText:
+		var request domainSend.MessageRequest
+		if err := json.Unmarshal(payload, &request); err != nil {
+			return domainSend.GenericResponse{}, err
+		}
+		return sendService.SendText(ctx, request)
+	case domainQueue.TypeImage:
+		var request domainSend.ImageRequest
+		if err := json.Unmarshal(payload, &request); err != nil {
+			return domainSend.GenericResponse{}, err
+		}
+		request.Image = upload
+		return sendService.SendImage(ctx, request)
+	case domainQueue.TypeFile:
+		var request domainSend.FileRequest
+		if err := json.Unmarshal(payload, &request); err != nil {
+			return domainSend.GenericResponse{}, err
+		}
+		request.File = upload
+		return sendService.SendFile(ctx, request)
+	case domainQueue.TypeVideo:
+		var request domainSend.VideoRequest
+		if err := json.Unmarshal(payload, &request); err != nil {
+			return domainSend.GenericResponse{}, err
+		}
+		request.Video = upload
+		return sendService.SendVideo(ctx, request)
+	case domainQueue.TypeAudio:
+		var request domainSend.AudioRequest
+		if err := json.Unmarshal(payload, &request); err != nil {
+			return domainSend.GenericResponse{}, err
+		}
+		request.Audio = upload
+		return sendService.SendAudio(ctx, request)
+	case domainQueue.TypeContact:
+		var request domainSend.ContactRequest
+		if err := json.Unmarshal(payload, &request); err != nil {
+			return domainSend.GenericResponse{}, err
+		}
+		return sendService.SendContact(ctx, request)
+	case domainQueue.TypeLink:
+		var request domainSend.LinkRequest
+		if err := json.Unmarshal(payload, &request); err != nil {
+			return domainSend.GenericResponse{}, err
+		}
+		return sendService.SendLink(ctx, request)
+	case domainQueue.TypeLocation:
+		var request domainSend.LocationRequest
+		if err := json.Unmarshal(payload, &request); err != nil {
+			return domainSend.GenericResponse{}, err
+		}
+		return sendService.SendLocation(ctx, request)
+	case domainQueue.TypePoll:
+		var request domainSend.PollRequest
+		if err := json.Unmarshal(payload, &request); err != nil {
+			return domainSend.GenericResponse{}, err
+		}
+		return sendService.SendPoll(ctx, request)
+	case domainQueue.TypeSticker:
+		var request domainSend.StickerRequest
+		if err := json.Unmarshal(payload, &request); err != nil {
+			return domainSend.GenericResponse{}, err
+		}
+		request.Sticker = upload
+		return sendService.SendSticker(ctx, request)
+	}
+
+	return domainSend.GenericResponse{}, fmt.Errorf("unknown message type %q", stored.Type)
+}
+
+// simulateTyping shows the recipient a typing indicator for about as long as typing the text would take
+func (service serviceQueue) simulateTyping(ctx context.Context, message domainQueue.Message) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			logrus.Debugf("Failed to show typing for queued message %d: %v", message.ID, recovered)
+		}
+	}()
+
+	var text struct {
+		Message string `json:"message"`
+		Caption string `json:"caption"`
+	}
+	_ = json.Unmarshal([]byte(message.Payload), &text)
+
+	duration := time.Duration(len(text.Message)+len(text.Caption)) * queueTypingPerChar
+	duration = min(max(duration, queueTypingMinimum), queueTypingMaximum)
+
+	if _, err := service.sendService.SendChatPresence(ctx, domainSend.ChatPresenceRequest{Phone: message.Phone, Action: "start"}); err != nil {
+		logrus.Debugf("Failed to show typing for queued message %d: %v", message.ID, err)
+		return
+	}
+	sleepContext(ctx, duration)
+	if _, err := service.sendService.SendChatPresence(ctx, domainSend.ChatPresenceRequest{Phone: message.Phone, Action: "stop"}); err != nil {
+		logrus.Debugf("Failed to stop typing for queued message %d: %v", message.ID, err)
+	}
+}
+
+// attachmentForm turns a stored attachment back into the multipart upload the senders expect
+func attachmentForm(stored storedRequest) (*multipart.Form, error) {
+	file, err := os.Open(stored.AttachmentPath)
+	if err != nil {
+		return nil, fmt.Errorf("stored attachment is missing: %w", err)
+	}
+
+	body, pipe := io.Pipe()
+	writer := multipart.NewWriter(pipe)
+	go func() {
+		defer file.Close()
+
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{"name": "attachment", "filename": stored.AttachmentName}))
+		header.Set("Content-Type", stored.AttachmentType)
+
+		part, err := writer.CreatePart(header)
+		if err == nil {
+			_, err = io.Copy(part, file)
+		}
+		if err == nil {
+			err = writer.Close()
+		}
+		pipe.CloseWithError(err)
+	}()
+
+	form, err := multipart.NewReader(body, writer.Boundary()).ReadForm(queueAttachmentInRAM)
+	body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if len(form.File["attachment"]) == 0 {
+		form.RemoveAll()
+		return nil, fmt.Errorf("stored attachment could not be read")
+	}
+	return form, nil
+}
+
+func removeAttachment(message domainQueue.Message) {
+	if message.AttachmentPath == "" {
+		return
+	}
+	if err := os.Remove(message.AttachmentPath); err != nil && !os.IsNotExist(err) {
+		logrus.Warnf("Failed to remove attachment of queued message %d: %v", message.ID, err)
+	}
+}
+
+// isTransientSendError reports whether sending may succeed when tried again later
+func isTransientSendError(err error) bool {
+	var disconnected *whatsmeow.DisconnectedError
+	return errors.Is(err, pkgError.ErrNotConnected) ||
+		errors.Is(err, pkgError.ErrNotLoggedIn) ||
+		errors.Is(err, whatsmeow.ErrNotConnected) ||
+		errors.Is(err, whatsmeow.ErrIQTimedOut) ||
+		errors.Is(err, whatsmeow.ErrMessageTimedOut) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.As(err, &disconnected)
+}
+
+// queueDelay picks the pause before the next queued message of a user
+func queueDelay() time.Duration {
+	minDelay := time.Duration(config.WhatsappSendQueueMinDelay) * time.Second
+	maxDelay := time.Duration(config.WhatsappSendQueueMaxDelay) * time.Second
+	if maxDelay <= minDelay {
+		return max(minDelay, 0)
+	}
+	return minDelay + rand.N(maxDelay-minDelay)
+}
+
+// queueRetryDelay doubles the wait after every failed attempt up to queueRetryMaxDelay
+func queueRetryDelay(attempts int) time.Duration {
+	delay := queueRetryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= queueRetryMaxDelay {
+			return queueRetryMaxDelay
+		}
+	}
+	return delay
+}
+
+// sleepContext waits for the duration, it returns false when ctx is cancelled first
+func sleepContext(ctx context.Context, duration time.Duration) bool {
+	if duration <= 0 {
+		return ctx.Err() == nil
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
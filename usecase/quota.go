@@ -0,0 +1,279 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	domainQuota "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/quota"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types"
+)
+
+const (
+	// quotaWindow is the longest sliding window, sends older than this no longer count
+	quotaWindow = 24 * time.Hour
+	// quotaPruneInterval is how often sends that left every window are deleted
+	quotaPruneInterval = time.Hour
+)
+
+type serviceQuota struct {
+	quotaRepo domainQuota.IQuotaRepository
+
+	// mu serializes reservations so concurrent sends cannot both take the last slot of a limit
+	mu         *sync.Mutex
+	lastPruned *time.Time
+}
+
+func NewQuotaService(quotaRepo domainQuota.IQuotaRepository) domainQuota.IQuotaUsecase {
+	return &serviceQuota{
+		quotaRepo:  quotaRepo,
+		mu:         &sync.Mutex{},
+		lastPruned: &time.Time{},
+	}
+}
+
+func (service serviceQuota) GetLimits(_ context.Context, userID int) (domainQuota.Limits, error) {
+	limits, err := service.quotaRepo.GetLimits(userID)
+	if err != nil || limits == nil {
+		return domainQuota.Limits{}, err
+	}
+	return *limits, nil
+}
+
+func (service serviceQuota) SetLimits(ctx context.Context, userID int, limits domainQuota.Limits) (domainQuota.Limits, error) {
+	if err := validations.ValidateLimits(ctx, limits); err != nil {
+		return limits, err
+	}
+
+	if err := service.quotaRepo.SaveLimits(userID, limits); err != nil {
+		return limits, err
+	}
+	return limits, nil
+}
+
+func (service serviceQuota) GetUsage(ctx context.Context, userID int) (response domainQuota.UsageResponse, err error) {
+	response.UserID = userID
+	if response.Limits, err = service.GetLimits(ctx, userID); err != nil {
+		return response, err
+	}
+
+	now := time.Now()
+	lastMinute, err := service.quotaRepo.GetTotals(userID, now.Add(-time.Minute))
+	if err != nil {
+		return response, err
+	}
+	lastHour, err := service.quotaRepo.GetTotals(userID, now.Add(-time.Hour))
+	if err != nil {
+		return response, err
+	}
+	lastDay, err := service.quotaRepo.GetTotals(userID, now.Add(-quotaWindow))
+	if err != nil {
+		return response, err
+	}
+
+	response.Usage = domainQuota.Usage{
+		MessagesLastMinute: lastMinute.Messages,
+		MessagesLastHour:   lastHour.Messages,
+		MessagesLastDay:    lastDay.Messages,
+		NewContactsLastDay: lastDay.NewContacts,
+		MediaBytesLastDay:  lastDay.MediaBytes,
+	}
+	return response, nil
+}
+
+func (service serviceQuota) Reserve(_ context.Context, send domainQuota.Send) (event domainQuota.SendEvent, err error) {
+	if send.UserID == 0 {
+		return event, nil
+	}
+
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	now := time.Now()
+	service.pruneIfDue(now)
+
+	limits, err := service.quotaRepo.GetLimits(send.UserID)
+	if err != nil {
+		return event, err
+	}
+	if limits == nil {
+		limits = &domainQuota.Limits{}
+	}
+
+	if limits.MediaBytesPerDay > 0 && send.MediaBytes > limits.MediaBytesPerDay {
+		return event, pkgError.ValidationError(fmt.Sprintf("media of %d bytes is larger than the daily limit of %d bytes", send.MediaBytes, limits.MediaBytesPerDay))
+	}
+
+	newContact, err := service.isNewContact(send)
+	if err != nil {
+		return event, err
+	}
+
+	// Sends are still recorded without limits so admins can see the usage before setting any
+	if !limits.IsUnlimited() {
+		sends, err := service.quotaRepo.GetSends(send.UserID, now.Add(-quotaWindow))
+		if err != nil {
+			return event, err
+		}
+		if err := checkQuota(*limits, sends, send.MediaBytes, newContact, now); err != nil {
+			return event, err
+		}
+	}
+
+	event = domainQuota.SendEvent{
+		UserID:     send.UserID,
+		Recipient:  send.Recipient,
+		MediaBytes: send.MediaBytes,
+		NewContact: newContact,
+		SentAt:     now,
+	}
+	if err := service.quotaRepo.RecordSend(&event); err != nil {
+		return domainQuota.SendEvent{}, err
+	}
+	return event, nil
+}
+
+func (service serviceQuota) Check(_ context.Context, send domainQuota.Send) error {
+	if send.UserID == 0 {
+		return nil
+	}
+
+	limits, err := service.quotaRepo.GetLimits(send.UserID)
+	if err != nil || limits == nil || limits.IsUnlimited() {
+		return err
+	}
+
+	newContact, err := service.isNewContact(send)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	sends, err := service.quotaRepo.GetSends(send.UserID, now.Add(-quotaWindow))
+	if err != nil {
+		return err
+	}
+	// The media is not processed yet, its size is only known to Reserve
+	return checkQuota(*limits, sends, 0, newContact, now)
+}
+
+func (service serviceQuota) Release(_ context.Context, event domainQuota.SendEvent) error {
+	if event.ID == 0 {
+		return nil
+	}
+	return service.quotaRepo.DeleteSend(event)
+}
+
+// isNewContact reports whether a send goes to a person the user had no chat with before
+func (service serviceQuota) isNewContact(send domainQuota.Send) (bool, error) {
+	if send.KnownChat || !isContactJID(send.Recipient) {
+		return false, nil
+	}
+	known, err := service.quotaRepo.IsKnownContact(send.UserID, send.Recipient)
+	return !known, err
+}
+
+// pruneIfDue deletes the sends that left every window, at most once per prune interval
+func (service serviceQuota) pruneIfDue(now time.Time) {
+	if now.Sub(*service.lastPruned) < quotaPruneInterval {
+		return
+	}
+	*service.lastPruned = now
+
+	if err := service.quotaRepo.PruneSends(now.Add(-quotaWindow)); err != nil {
+		logrus.Warnf("Failed to prune send usage: %v", err)
+	}
+}
+
+// checkQuota returns a rate limit error for the first limit one more send would exceed.
+// The sends of the last day must be ordered oldest first.
+func checkQuota(limits domainQuota.Limits, sends []domainQuota.SendEvent, mediaBytes int64, newContact bool, now time.Time) error {
+	windows := []struct {
+		limit  string
+		max    int
+		window time.Duration
+	}{
+		{domainQuota.LimitMessagesPerMinute, limits.MessagesPerMinute, time.Minute},
+		{domainQuota.LimitMessagesPerHour, limits.MessagesPerHour, time.Hour},
+		{domainQuota.LimitMessagesPerDay, limits.MessagesPerDay, quotaWindow},
+	}
+	for _, w := range windows {
+		if w.max <= 0 {
+			continue
+		}
+		if retryAt, exceeded := countExceeded(sendsSince(sends, now.Add(-w.window)), w.max, w.window); exceeded {
+			return rateLimitError(w.limit, int64(w.max), retryAt, now)
+		}
+	}
+
+	if newContact && limits.NewContactsPerDay > 0 {
+		var contacts []domainQuota.SendEvent
+		for _, send := range sends {
+			if send.NewContact {
+				contacts = append(contacts, send)
+			}
+		}
+		if retryAt, exceeded := countExceeded(contacts, limits.NewContactsPerDay, quotaWindow); exceeded {
+			return rateLimitError(domainQuota.LimitNewContactsPerDay, int64(limits.NewContactsPerDay), retryAt, now)
+		}
+	}
+
+	if mediaBytes > 0 && limits.MediaBytesPerDay > 0 {
+		var total int64
+		for _, send := range sends {
+			total += send.MediaBytes
+		}
+		if total+mediaBytes > limits.MediaBytesPerDay {
+			// Wait until enough of the oldest media has left the window for this one to fit
+			for _, send := range sends {
+				total -= send.MediaBytes
+				if total+mediaBytes <= limits.MediaBytesPerDay {
+					return rateLimitError(domainQuota.LimitMediaBytesPerDay, limits.MediaBytesPerDay, send.SentAt.Add(quotaWindow), now)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// countExceeded reports whether another send would exceed max within the window,
+// and when the oldest send that has to leave the window does so
+func countExceeded(sends []domainQuota.SendEvent, max int, window time.Duration) (time.Time, bool) {
+	if len(sends) < max {
+		return time.Time{}, false
+	}
+	return sends[len(sends)-max].SentAt.Add(window), true
+}
+
+// sendsSince returns the tail of the ordered sends that happened after a point in time
+func sendsSince(sends []domainQuota.SendEvent, since time.Time) []domainQuota.SendEvent {
+	for i, send := range sends {
+		if send.SentAt.After(since) {
+			return sends[i:]
+		}
+	}
+	return nil
+}
+
+func rateLimitError(limit string, max int64, retryAt time.Time, now time.Time) pkgError.RateLimitError {
+	retryAfter := int(math.Ceil(retryAt.Sub(now).Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	return pkgError.RateLimitError{Limit: limit, Max: max, RetryAfter: retryAfter}
+}
+
+// isContactJID reports whether a recipient is a person, groups and newsletters never count as new contacts
+func isContactJID(recipient string) bool {
+	jid, err := types.ParseJID(recipient)
+	if err != nil {
+		return false
+	}
+	return jid.Server == types.DefaultUserServer || jid.Server == types.HiddenUserServer
+}
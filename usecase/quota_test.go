@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	domainQuota "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/quota"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+// sendsAgo builds sends ordered oldest first, each the given time before now
+func sendsAgo(now time.Time, ago ...time.Duration) []domainQuota.SendEvent {
+	sends := make([]domainQuota.SendEvent, 0, len(ago))
+	for _, d := range ago {
+		sends = append(sends, domainQuota.SendEvent{SentAt: now.Add(-d)})
+	}
+	return sends
+}
+
+func TestCheckQuota(t *testing.T) {
+	now := time.Date(2025, 8, 1, 9, 30, 0, 0, time.UTC)
+	newContacts := sendsAgo(now, 20*time.Hour, 2*time.Hour)
+	for i := range newContacts {
+		newContacts[i].NewContact = true
+	}
+	media := sendsAgo(now, 10*time.Hour, 5*time.Hour, time.Hour)
+	for i := range media {
+		media[i].MediaBytes = 400
+	}
+
+	type args struct {
+		limits     domainQuota.Limits
+		sends      []domainQuota.SendEvent
+		mediaBytes int64
+		newContact bool
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success without limits",
+			args: args{sends: sendsAgo(now, 3*time.Second, 2*time.Second, time.Second)},
+			err:  nil,
+		},
+		{
+			name: "should success one send below the limit",
+			args: args{
+				limits: domainQuota.Limits{MessagesPerMinute: 3},
+				sends:  sendsAgo(now, 20*time.Second, 10*time.Second),
+			},
+			err: nil,
+		},
+		{
+			name: "should error at the limit",
+			args: args{
+				limits: domainQuota.Limits{MessagesPerMinute: 3},
+				sends:  sendsAgo(now, 30*time.Second, 20*time.Second, 10*time.Second),
+			},
+			err: pkgError.RateLimitError{Limit: domainQuota.LimitMessagesPerMinute, Max: 3, RetryAfter: 30},
+		},
+		{
+			name: "should success once the oldest send left the window",
+			args: args{
+				limits: domainQuota.Limits{MessagesPerMinute: 3},
+				sends:  sendsAgo(now, time.Minute, 20*time.Second, 10*time.Second),
+			},
+			err: nil,
+		},
+		{
+			name: "should error with retry after the send that has to leave the window",
+			args: args{
+				limits: domainQuota.Limits{MessagesPerHour: 2},
+				sends:  sendsAgo(now, 50*time.Minute, 40*time.Minute, 15*time.Minute),
+			},
+			err: pkgError.RateLimitError{Limit: domainQuota.LimitMessagesPerHour, Max: 2, RetryAfter: 20 * 60},
+		},
+		{
+			name: "should error with retry after rounded up to whole seconds",
+			args: args{
+				limits: domainQuota.Limits{MessagesPerMinute: 1},
+				sends:  sendsAgo(now, 59*time.Second+500*time.Millisecond),
+			},
+			err: pkgError.RateLimitError{Limit: domainQuota.LimitMessagesPerMinute, Max: 1, RetryAfter: 1},
+		},
+		{
+			name: "should error with the day limit",
+			args: args{
+				limits: domainQuota.Limits{MessagesPerMinute: 5, MessagesPerDay: 2},
+				sends:  sendsAgo(now, 23*time.Hour, 6*time.Hour),
+			},
+			err: pkgError.RateLimitError{Limit: domainQuota.LimitMessagesPerDay, Max: 2, RetryAfter: 60 * 60},
+		},
+		{
+			name: "should success known contact at the new contact limit",
+			args: args{
+				limits: domainQuota.Limits{NewContactsPerDay: 2},
+				sends:  newContacts,
+			},
+			err: nil,
+		},
+		{
+			name: "should error new contact at the new contact limit",
+			args: args{
+				limits:     domainQuota.Limits{NewContactsPerDay: 2},
+				sends:      newContacts,
+				newContact: true,
+			},
+			err: pkgError.RateLimitError{Limit: domainQuota.LimitNewContactsPerDay, Max: 2, RetryAfter: 4 * 60 * 60},
+		},
+		{
+			name: "should success media that fits the daily limit",
+			args: args{
+				limits:     domainQuota.Limits{MediaBytesPerDay: 1500},
+				sends:      media,
+				mediaBytes: 300,
+			},
+			err: nil,
+		},
+		{
+			name: "should error with retry after enough media left the window",
+			args: args{
+				limits:     domainQuota.Limits{MediaBytesPerDay: 1500},
+				sends:      media,
+				mediaBytes: 1000,
+			},
+			err: pkgError.RateLimitError{Limit: domainQuota.LimitMediaBytesPerDay, Max: 1500, RetryAfter: 19 * 60 * 60},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkQuota(tt.args.limits, tt.args.sends, tt.args.mediaBytes, tt.args.newContact, now)
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.err, err)
+			}
+		})
+	}
+}
+
+func TestCountExceeded(t *testing.T) {
+	now := time.Date(2025, 8, 1, 9, 30, 0, 0, time.UTC)
+	sends := sendsAgo(now, 40*time.Second, 30*time.Second, 10*time.Second)
+
+	tests := []struct {
+		name         string
+		max          int
+		wantExceeded bool
+		wantRetryAt  time.Time
+	}{
+		{name: "should not exceed below max", max: 4},
+		{name: "should exceed at max", max: 3, wantExceeded: true, wantRetryAt: now.Add(20 * time.Second)},
+		{name: "should wait for the oldest sends over max", max: 2, wantExceeded: true, wantRetryAt: now.Add(30 * time.Second)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retryAt, exceeded := countExceeded(sends, tt.max, time.Minute)
+			assert.Equal(t, tt.wantExceeded, exceeded)
+			assert.Equal(t, tt.wantRetryAt, retryAt)
+		})
+	}
+}
@@ -0,0 +1,329 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	domainSchedule "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/schedule"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	schedulePollInterval = 1 * time.Second
+	scheduleRetryDelay   = 1 * time.Minute
+)
+
+type serviceSchedule struct {
+	scheduleRepo domainSchedule.IScheduleRepository
+	sendService  domainSend.ISendUsecase
+}
+
+func NewScheduleService(scheduleRepo domainSchedule.IScheduleRepository, sendService domainSend.ISendUsecase) domainSchedule.IScheduleUsecase {
+	return &serviceSchedule{
+		scheduleRepo: scheduleRepo,
+		sendService:  sendService,
+	}
+}
+
+func (service serviceSchedule) ListSchedules(ctx context.Context, userID int, request domainSchedule.ListRequest) (response domainSchedule.ListResponse, err error) {
+	if err = validations.ValidateListSchedules(ctx, &request); err != nil {
+		return response, err
+	}
+
+	// Requests limited to some chats only list the schedules to those
+	var chatKeys []string
+	if appCtx, ok := ctx.(*domainApp.AppContext); ok {
+		keys, restricted := appCtx.AllowedChatKeys()
+		if restricted && len(keys) == 0 {
+			response.Data = []domainSchedule.Schedule{}
+			response.Pagination = domainSchedule.PaginationResponse{Limit: request.Limit, Offset: request.Offset}
+			return response, nil
+		}
+		chatKeys = keys
+	}
+
+	schedules, err := service.scheduleRepo.List(userID, request.Status, chatKeys, request.Limit, request.Offset)
+	if err != nil {
+		return response, err
+	}
+
+	total, err := service.scheduleRepo.Count(userID, request.Status, chatKeys)
+	if err != nil {
+		return response, err
+	}
+
+	response.Data = schedules
+	response.Pagination = domainSchedule.PaginationResponse{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+		Total:  total,
+	}
+	return response, nil
+}
+
+func (service serviceSchedule) GetSchedule(ctx context.Context, userID int, id int64) (domainSchedule.Schedule, error) {
+	schedule, err := service.scheduleRepo.GetByID(userID, id)
+	if err != nil {
+		return domainSchedule.Schedule{}, err
+	}
+	if schedule == nil {
+		return domainSchedule.Schedule{}, pkgError.NotFoundError("schedule not found")
+	}
+	if appCtx, ok := ctx.(*domainApp.AppContext); ok && !appCtx.AllowsChat(schedule.Phone) {
+		return domainSchedule.Schedule{}, pkgError.ForbiddenError(fmt.Sprintf("Access to chat %s is not allowed", schedule.Phone))
+	}
+	return *schedule, nil
+}
+
+func (service serviceSchedule) UpdateSchedule(ctx context.Context, userID int, id int64, request domainSchedule.UpdateRequest) (domainSchedule.Schedule, error) {
+	if err := validations.ValidateUpdateSchedule(ctx, request); err != nil {
+		return domainSchedule.Schedule{}, err
+	}
+
+	schedule, err := service.GetSchedule(ctx, userID, id)
+	if err != nil {
+		return schedule, err
+	}
+
+	if err := setScheduleTiming(&schedule, request.SendAt, request.Cron, request.Timezone, time.Now()); err != nil {
+		return schedule, err
+	}
+
+	updated, err := service.scheduleRepo.UpdateTiming(&schedule)
+	if err != nil {
+		return schedule, err
+	}
+	if !updated {
+		return schedule, pkgError.ValidationError(fmt.Sprintf("only active schedules can be updated, this one is %s", schedule.Status))
+	}
+
+	return service.GetSchedule(ctx, userID, id)
+}
+
+func (service serviceSchedule) CancelSchedule(ctx context.Context, userID int, id int64) (domainSchedule.Schedule, error) {
+	schedule, err := service.GetSchedule(ctx, userID, id)
+	if err != nil {
+		return schedule, err
+	}
+
+	cancelled, running, err := service.scheduleRepo.Cancel(userID, id)
+	if err != nil {
+		return schedule, err
+	}
+	if !cancelled {
+		return schedule, pkgError.ValidationError(fmt.Sprintf("only active schedules can be cancelled, this one is %s", schedule.Status))
+	}
+
+	// A run in progress still sends the attachment, it is removed when the run finishes
+	if !running {
+		removeScheduleAttachment(schedule)
+	}
+	return service.GetSchedule(ctx, userID, id)
+}
+
+func (service serviceSchedule) RunScheduler(ctx context.Context) {
+	// Schedules running when the process stopped may or may not have been sent; sending them again is preferred to losing them
+	if reset, err := service.scheduleRepo.ResetRunning(); err != nil {
+		logrus.Errorf("Failed to reset interrupted schedules: %v", err)
+	} else if reset > 0 {
+		logrus.Warnf("Resuming %d schedules that were running when the process stopped", reset)
+	}
+
+	ticker := time.NewTicker(schedulePollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			service.runDue(ctx)
+		}
+	}
+}
+
+// runDue starts every due schedule whose user is connected, the others wait for their session to come back
+func (service serviceSchedule) runDue(ctx context.Context) {
+	schedules, err := service.scheduleRepo.GetDue(time.Now())
+	if err != nil {
+		logrus.Errorf("Failed to load due schedules: %v", err)
+		return
+	}
+
+	for _, schedule := range schedules {
+		client := whatsapp.GetClientForUser(schedule.UserID)
+		if client == nil || !client.IsConnected() || !client.IsLoggedIn() {
+			continue
+		}
+		// Claiming marks the schedule as running, so a slow send is not started twice
+		claimed, err := service.scheduleRepo.Claim(schedule.ID, *schedule.NextRunAt)
+		if err != nil {
+			logrus.Errorf("Failed to claim schedule %d: %v", schedule.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		go service.run(ctx, schedule)
+	}
+}
+
+// run sends a due schedule, records the outcome and notifies the user
+func (service serviceSchedule) run(ctx context.Context, schedule domainSchedule.Schedule) {
+	appCtx := &domainApp.AppContext{Context: ctx, UserID: schedule.UserID, Username: schedule.Username}
+
+	response, err := sendStoredRequest(appCtx, service.sendService, storedRequest{
+		Type:           schedule.Type,
+		Payload:        schedule.Payload,
+		AttachmentPath: schedule.AttachmentPath,
+		AttachmentName: schedule.AttachmentName,
+		AttachmentType: schedule.AttachmentType,
+	})
+	now := time.Now()
+
+	// Waiting out a sending limit or a dropped connection is not a run
+	var rateLimit pkgError.RateLimitError
+	if errors.As(err, &rateLimit) || isTransientSendError(err) {
+		retryAt := now.Add(scheduleRetryDelay)
+		if errors.As(err, &rateLimit) {
+			retryAt = now.Add(time.Duration(rateLimit.RetryAfter) * time.Second)
+		}
+		logrus.Warnf("Schedule %d of user %d could not be sent, retrying at %s: %v", schedule.ID, schedule.UserID, retryAt.Format(time.RFC3339), err)
+		postponed, err := service.scheduleRepo.Postpone(schedule.ID, retryAt, err.Error())
+		if err != nil {
+			logrus.Errorf("Failed to postpone schedule %d: %v", schedule.ID, err)
+		} else if !postponed {
+			// Cancelled during the run
+			removeScheduleAttachment(schedule)
+		}
+		return
+	}
+
+	schedule.Runs++
+	schedule.LastRunAt = &now
+	schedule.LastMessageID = response.MessageID
+	schedule.LastError = ""
+	switch {
+	case err != nil:
+		schedule.LastResult = domainSchedule.ResultFailed
+		schedule.LastError = err.Error()
+	case response.QueueID != 0:
+		schedule.LastResult = domainSchedule.ResultQueued
+	default:
+		schedule.LastResult = domainSchedule.ResultSent
+	}
+
+	// A cron schedule keeps running after a failed run, a one-off schedule is done either way
+	schedule.NextRunAt = nil
+	schedule.Status = domainSchedule.StatusCompleted
+	if schedule.Cron != "" {
+		if next := nextCronRun(schedule.Cron, schedule.Timezone, now); !next.IsZero() {
+			schedule.NextRunAt = &next
+			schedule.Status = domainSchedule.StatusActive
+		}
+	} else if err != nil {
+		schedule.Status = domainSchedule.StatusFailed
+	}
+
+	if err != nil {
+		logrus.Errorf("Schedule %d of user %d failed: %v", schedule.ID, schedule.UserID, err)
+	} else {
+		logrus.Infof("Schedule %d of user %d ran, message %s", schedule.ID, schedule.UserID, schedule.LastResult)
+	}
+
+	if err := service.scheduleRepo.SaveRun(&schedule); err != nil {
+		logrus.Errorf("Failed to save run of schedule %d: %v", schedule.ID, err)
+	}
+	if schedule.Status != domainSchedule.StatusActive {
+		removeScheduleAttachment(schedule)
+	}
+
+	payload := map[string]any{
+		"schedule_id": schedule.ID,
+		"type":        schedule.Type,
+		"phone":       schedule.Phone,
+		"result":      schedule.LastResult,
+		"status":      schedule.Status,
+		"runs":        schedule.Runs,
+	}
+	if response.MessageID != "" {
+		payload["message_id"] = response.MessageID
+	}
+	if response.QueueID != 0 {
+		payload["queue_id"] = response.QueueID
+	}
+	if schedule.LastError != "" {
+		payload["error"] = schedule.LastError
+	}
+	if schedule.NextRunAt != nil {
+		payload["next_run_at"] = schedule.NextRunAt.UTC().Format(time.RFC3339)
+	}
+	whatsapp.EmitScheduleExecuted(schedule.UserID, utils.FormatJID(schedule.Phone).String(), payload)
+}
+
+// setScheduleTiming fills the timing fields of a schedule from validated values
+func setScheduleTiming(schedule *domainSchedule.Schedule, sendAt, cron, timezone string, now time.Time) error {
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return pkgError.ValidationError(fmt.Sprintf("timezone: unknown time zone %s", timezone))
+	}
+
+	schedule.Timezone = timezone
+	schedule.Cron = cron
+	schedule.SendAt = nil
+	if sendAt != "" {
+		at, err := utils.ParseLocalTime(sendAt, location)
+		if err != nil {
+			return pkgError.ValidationError(fmt.Sprintf("send_at: %v", err))
+		}
+		at = at.UTC()
+		schedule.SendAt = &at
+		schedule.NextRunAt = &at
+		return nil
+	}
+
+	next := nextCronRun(cron, timezone, now)
+	if next.IsZero() {
+		return pkgError.ValidationError("cron: never matches")
+	}
+	schedule.NextRunAt = &next
+	return nil
+}
+
+// nextCronRun returns the next run of a cron expression after the given time, evaluated in timezone
+func nextCronRun(cron, timezone string, after time.Time) time.Time {
+	schedule, err := utils.ParseCron(cron)
+	if err != nil {
+		return time.Time{}
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	next := schedule.Next(after.In(location))
+	if next.IsZero() {
+		return next
+	}
+	return next.UTC()
+}
+
+func removeScheduleAttachment(schedule domainSchedule.Schedule) {
+	if schedule.AttachmentPath == "" {
+		return
+	}
+	if err := os.Remove(schedule.AttachmentPath); err != nil && !os.IsNotExist(err) {
+		logrus.Warnf("Failed to remove attachment of schedule %d: %v", schedule.ID, err)
+	}
+}
@@ -3,18 +3,27 @@ package usecase
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	domainAudit "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/audit"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainQueue "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/queue"
+	domainQuota "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/quota"
+	domainSchedule "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/schedule"
 	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
@@ -33,12 +42,22 @@ import (
 type serviceSend struct {
 	appService      app.IAppUsecaseWithContext
 	chatStorageRepo domainChatStorage.IChatStorageRepository
+	auditRepo       domainAudit.IAuditRepository
+	quotaService    domainQuota.IQuotaUsecase
+	queueRepo       domainQueue.IQueueRepository
+	scheduleRepo    domainSchedule.IScheduleRepository
+	templateRepo    domainTemplate.ITemplateRepository
 }
 
-func NewSendService(appService app.IAppUsecaseWithContext, chatStorageRepo domainChatStorage.IChatStorageRepository) domainSend.ISendUsecase {
+func NewSendService(appService app.IAppUsecaseWithContext, chatStorageRepo domainChatStorage.IChatStorageRepository, auditRepo domainAudit.IAuditRepository, quotaService domainQuota.IQuotaUsecase, queueRepo domainQueue.IQueueRepository, scheduleRepo domainSchedule.IScheduleRepository, templateRepo domainTemplate.ITemplateRepository) domainSend.ISendUsecase {
 	return &serviceSend{
 		appService:      appService,
 		chatStorageRepo: chatStorageRepo,
+		auditRepo:       auditRepo,
+		quotaService:    quotaService,
+		queueRepo:       queueRepo,
+		scheduleRepo:    scheduleRepo,
+		templateRepo:    templateRepo,
 	}
 }
 
@@ -52,10 +71,262 @@ func (service serviceSend) getClientFromContext(ctx context.Context) *whatsmeow.
 	return nil
 }
 
-// wrapSendMessage wraps the message sending process with message ID saving
+// getChatStorageFromContext scopes chat storage to the user in the app context
+func (service serviceSend) getChatStorageFromContext(ctx context.Context) domainChatStorage.IChatStorageRepository {
+	if appCtx, ok := ctx.(*app.AppContext); ok && appCtx.UserID > 0 {
+		return service.chatStorageRepo.ForUser(appCtx.UserID)
+	}
+	return service.chatStorageRepo
+}
+
+// audit records a send action, referencing the ID of the message that was sent
+func (service serviceSend) audit(ctx context.Context, action, phone string, response domainSend.GenericResponse, err error) {
+	if response.QueueID != 0 || response.ScheduleID != 0 {
+		// Queued and scheduled messages are audited when they are sent
+		return
+	}
+	recordAudit(ctx, service.auditRepo, domainAudit.Entry{Action: action, TargetJID: phone, Reference: response.MessageID}, err)
+}
+
+// enqueue persists a validated request for the send queue worker instead of sending it now.
+// An uploaded file is kept on disk next to the queue until the message is final.
+func (service serviceSend) enqueue(ctx context.Context, messageType, phone string, request any, upload *multipart.FileHeader) (response domainSend.GenericResponse, err error) {
+	appCtx, ok := ctx.(*app.AppContext)
+	if !ok || appCtx.UserID == 0 {
+		return response, pkgError.ErrNotLoggedIn
+	}
+	if service.queueRepo == nil {
+		return response, pkgError.InternalServerError("send queue is not available")
+	}
+
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return response, err
+	}
+
+	message := &domainQueue.Message{
+		UserID:   appCtx.UserID,
+		Username: appCtx.Username,
+		Type:     messageType,
+		Phone:    phone,
+		Payload:  string(payload),
+	}
+	if upload != nil {
+		message.AttachmentName = upload.Filename
+		message.AttachmentType = upload.Header.Get("Content-Type")
+		if message.AttachmentPath, err = storeUpload(upload, config.PathQueue); err != nil {
+			return response, err
+		}
+	}
+
+	if err = service.queueRepo.Enqueue(message); err != nil {
+		if message.AttachmentPath != "" {
+			_ = os.Remove(message.AttachmentPath)
+		}
+		return response, err
+	}
+
+	response.QueueID = message.ID
+	response.Status = fmt.Sprintf("Message to %s queued (queue id: %d)", phone, message.ID)
+	return response, nil
+}
+
+// schedule persists a validated request to be sent by the scheduler at the requested time instead of now.
+// An uploaded file is kept on disk until the schedule is final.
+func (service serviceSend) schedule(ctx context.Context, messageType, phone string, when domainSend.Schedule, request any, upload *multipart.FileHeader) (response domainSend.GenericResponse, err error) {
+	appCtx, ok := ctx.(*app.AppContext)
+	if !ok || appCtx.UserID == 0 {
+		return response, pkgError.ErrNotLoggedIn
+	}
+	if service.scheduleRepo == nil {
+		return response, pkgError.InternalServerError("scheduler is not available")
+	}
+
+	if err = validations.ValidateSendSchedule(ctx, when); err != nil {
+		return response, err
+	}
+
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return response, err
+	}
+
+	schedule := &domainSchedule.Schedule{
+		UserID:   appCtx.UserID,
+		Username: appCtx.Username,
+		Type:     messageType,
+		Phone:    phone,
+		Payload:  string(payload),
+	}
+	if err = setScheduleTiming(schedule, when.SendAt, when.Cron, when.Timezone, time.Now()); err != nil {
+		return response, err
+	}
+	if upload != nil {
+		schedule.AttachmentName = upload.Filename
+		schedule.AttachmentType = upload.Header.Get("Content-Type")
+		if schedule.AttachmentPath, err = storeUpload(upload, config.PathSchedule); err != nil {
+			return response, err
+		}
+	}
+
+	if err = service.scheduleRepo.Create(schedule); err != nil {
+		if schedule.AttachmentPath != "" {
+			_ = os.Remove(schedule.AttachmentPath)
+		}
+		return response, err
+	}
+
+	response.ScheduleID = schedule.ID
+	response.Status = fmt.Sprintf("Message to %s scheduled for %s (schedule id: %d)", phone, schedule.NextRunAt.Format(time.RFC3339), schedule.ID)
+	return response, nil
+}
+
+// storeUpload keeps an uploaded file in folder for a request that is sent later
+func storeUpload(upload *multipart.FileHeader, folder string) (string, error) {
+	path := fmt.Sprintf("%s/%s-%s", folder, fiberUtils.UUIDv4(), filepath.Base(upload.Filename))
+	return path, fasthttp.SaveMultipartFile(upload, path)
+}
+
+// renderedTemplate is a template of the user filled in for one send request
+type renderedTemplate struct {
+	text       string
+	duration   *int
+	attachment *multipart.FileHeader // Only set for media requests without media of their own
+	form       *multipart.Form
+}
+
+// close releases the copy of the template attachment once the request was sent or stored
+func (rendered renderedTemplate) close() {
+	if rendered.form != nil {
+		_ = rendered.form.RemoveAll()
+	}
+}
+
+// renderTemplate loads the template named by a request from the user in the app context and fills in its variables.
+// text is the message or caption the request brings itself, which cannot be combined with a template.
+// Templates with an attachment can only be sent by media requests, their attachment is loaded when needsMedia is set.
+func (service serviceSend) renderTemplate(ctx context.Context, request domainSend.TemplateRequest, phone, text string, acceptsMedia, needsMedia bool) (rendered renderedTemplate, err error) {
+	appCtx, ok := ctx.(*app.AppContext)
+	if !ok || appCtx.UserID == 0 {
+		return rendered, pkgError.ErrNotLoggedIn
+	}
+	if service.templateRepo == nil {
+		return rendered, pkgError.InternalServerError("templates are not available")
+	}
+	if text != "" {
+		return rendered, pkgError.ValidationError("template_name cannot be combined with a message or caption")
+	}
+
+	template, err := service.templateRepo.GetByName(appCtx.UserID, request.TemplateName)
+	if err != nil {
+		return rendered, err
+	}
+	if template == nil {
+		return rendered, pkgError.NotFoundError(fmt.Sprintf("template %s not found", request.TemplateName))
+	}
+	if template.AttachmentPath != "" && !acceptsMedia {
+		return rendered, pkgError.ValidationError(fmt.Sprintf("template %s has an attachment, send it as an image, video or file", template.Name))
+	}
+	if err = validations.ValidateTemplateVariables(template.Body, request.Variables); err != nil {
+		return rendered, err
+	}
+
+	variables := map[string]string{"phone": strings.SplitN(phone, "@", 2)[0]}
+	for name, value := range request.Variables {
+		variables[name] = value
+	}
+	rendered.text = utils.RenderTemplate(template.Body, variables)
+	rendered.duration = template.Duration
+
+	if template.AttachmentPath != "" && needsMedia {
+		rendered.form, err = attachmentForm(storedRequest{
+			AttachmentPath: template.AttachmentPath,
+			AttachmentName: template.AttachmentName,
+			AttachmentType: template.AttachmentType,
+		})
+		if err != nil {
+			return rendered, err
+		}
+		rendered.attachment = rendered.form.File["attachment"][0]
+	}
+	return rendered, nil
+}
+
+// precheckQuota refuses a message to the recipient when the user in the app context is over a message or new contact
+// limit. Senders call it before processing and uploading media; reserveQuota still counts the message once it is built.
+func (service serviceSend) precheckQuota(ctx context.Context, recipient types.JID) error {
+	send, ok := service.quotaSend(ctx, recipient, 0)
+	if !ok {
+		return nil
+	}
+	return service.quotaService.Check(ctx, send)
+}
+
+// reserveQuota counts a message against the sending limits of the user in the app context
+func (service serviceSend) reserveQuota(ctx context.Context, recipient types.JID, msg *waE2E.Message) (domainQuota.SendEvent, error) {
+	send, ok := service.quotaSend(ctx, recipient, mediaBytes(msg))
+	if !ok {
+		return domainQuota.SendEvent{}, nil
+	}
+	return service.quotaService.Reserve(ctx, send)
+}
+
+// quotaSend describes a message for the sending limits, it returns false when no user's limits apply
+func (service serviceSend) quotaSend(ctx context.Context, recipient types.JID, mediaBytes int64) (domainQuota.Send, bool) {
+	appCtx, ok := ctx.(*app.AppContext)
+	if service.quotaService == nil || !ok || appCtx.UserID == 0 {
+		return domainQuota.Send{}, false
+	}
+
+	chat, err := service.getChatStorageFromContext(ctx).GetChat(recipient.String())
+	if err != nil {
+		logrus.Warnf("Failed to look up chat %s for quota: %v", recipient.String(), err)
+	}
+
+	return domainQuota.Send{
+		UserID:     appCtx.UserID,
+		Recipient:  recipient.ToNonAD().String(),
+		MediaBytes: mediaBytes,
+		KnownChat:  chat != nil,
+	}, true
+}
+
+// mediaBytes is the size of the media a message carries, zero for messages without media
+func mediaBytes(msg *waE2E.Message) int64 {
+	switch {
+	case msg.GetImageMessage() != nil:
+		return int64(msg.GetImageMessage().GetFileLength())
+	case msg.GetVideoMessage() != nil:
+		return int64(msg.GetVideoMessage().GetFileLength())
+	case msg.GetAudioMessage() != nil:
+		return int64(msg.GetAudioMessage().GetFileLength())
+	case msg.GetDocumentMessage() != nil:
+		return int64(msg.GetDocumentMessage().GetFileLength())
+	case msg.GetStickerMessage() != nil:
+		return int64(msg.GetStickerMessage().GetFileLength())
+	}
+	return 0
+}
+
+// wrapSendMessage wraps the message sending process with the allowed chats, quota enforcement and message ID saving
 func (service serviceSend) wrapSendMessage(ctx context.Context, client *whatsmeow.Client, recipient types.JID, msg *waE2E.Message, content string) (whatsmeow.SendResponse, error) {
+	if appCtx, ok := ctx.(*app.AppContext); ok && !appCtx.AllowsChat(recipient.String()) {
+		return whatsmeow.SendResponse{}, pkgError.ForbiddenError(fmt.Sprintf("Access to chat %s is not allowed", recipient.String()))
+	}
+
+	reservation, err := service.reserveQuota(ctx, recipient, msg)
+	if err != nil {
+		return whatsmeow.SendResponse{}, err
+	}
+
 	ts, err := client.SendMessage(ctx, recipient, msg)
 	if err != nil {
+		// The message never left, it does not count against the limits
+		if reservation.ID != 0 {
+			if releaseErr := service.quotaService.Release(ctx, reservation); releaseErr != nil {
+				logrus.Warnf("Failed to release quota reservation: %v", releaseErr)
+			}
+		}
 		return whatsmeow.SendResponse{}, err
 	}
 
@@ -67,11 +338,12 @@ func (service serviceSend) wrapSendMessage(ctx context.Context, client *whatsmeo
 
 	// Store message asynchronously with timeout
 	// Use a goroutine to avoid blocking the send operation
+	chatStorageRepo := service.getChatStorageFromContext(ctx)
 	go func() {
 		storeCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 		defer cancel()
 
-		if err := service.chatStorageRepo.StoreSentMessageWithContext(storeCtx, ts.ID, senderJID, recipient.String(), content, ts.Timestamp); err != nil {
+		if err := chatStorageRepo.StoreSentMessageWithContext(storeCtx, ts.ID, senderJID, recipient.String(), msg, content, ts.Timestamp); err != nil {
 			if errors.Is(err, context.DeadlineExceeded) {
 				logrus.Warn("Timeout storing sent message")
 			} else {
@@ -84,11 +356,38 @@ func (service serviceSend) wrapSendMessage(ctx context.Context, client *whatsmeo
 }
 
 func (service serviceSend) SendText(ctx context.Context, request domainSend.MessageRequest) (response domainSend.GenericResponse, err error) {
+	defer func() { service.audit(ctx, domainAudit.ActionSendText, request.Phone, response, err) }()
+
+	if request.TemplateName != "" {
+		rendered, err := service.renderTemplate(ctx, request.TemplateRequest, request.Phone, request.Message, false, false)
+		if err != nil {
+			return response, err
+		}
+		defer rendered.close()
+
+		request.Message = rendered.text
+		if request.Duration == nil {
+			request.Duration = rendered.duration
+		}
+		request.TemplateRequest = domainSend.TemplateRequest{}
+	}
+
 	err = validations.ValidateSendMessage(ctx, request)
 	if err != nil {
 		return response, err
 	}
 
+	if request.IsScheduled() {
+		when := request.Schedule
+		request.Schedule = domainSend.Schedule{}
+		return service.schedule(ctx, domainQueue.TypeText, request.Phone, when, request, nil)
+	}
+
+	if request.Queue {
+		request.Queue = false
+		return service.enqueue(ctx, domainQueue.TypeText, request.Phone, request, nil)
+	}
+
 	// Get the appropriate WhatsApp client from context
 	client := service.getClientFromContext(ctx)
 	if client == nil {
@@ -100,6 +399,10 @@ func (service serviceSend) SendText(ctx context.Context, request domainSend.Mess
 		return response, err
 	}
 
+	if err = service.precheckQuota(ctx, dataWaRecipient); err != nil {
+		return response, err
+	}
+
 	// Create base message
 	msg := &waE2E.Message{
 		ExtendedTextMessage: &waE2E.ExtendedTextMessage{
@@ -118,60 +421,11 @@ func (service serviceSend) SendText(ctx context.Context, request domainSend.Mess
 	if request.BaseRequest.Duration != nil && *request.BaseRequest.Duration > 0 {
 		msg.ExtendedTextMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
 	} else {
-		msg.ExtendedTextMessage.ContextInfo.Expiration = proto.Uint32(service.getDefaultEphemeralExpiration(request.BaseRequest.Phone))
+		msg.ExtendedTextMessage.ContextInfo.Expiration = proto.Uint32(service.getDefaultEphemeralExpiration(ctx, request.BaseRequest.Phone))
 	}
 
-	parsedMentions := service.getMentionFromText(ctx, client, request.Message)
-	if len(parsedMentions) > 0 {
-		msg.ExtendedTextMessage.ContextInfo.MentionedJID = parsedMentions
-	}
-
-	// Reply message
-	if request.ReplyMessageID != nil && *request.ReplyMessageID != "" {
-		message, err := service.chatStorageRepo.GetMessageByID(*request.ReplyMessageID)
-		if err != nil {
-			logrus.Warnf("Error retrieving reply message ID %s: %v, continuing without reply context", *request.ReplyMessageID, err)
-		} else if message != nil { // Only set reply context if we found the message
-			// Ensure we use a full JID (user@server) for the Participant field
-			// Use the sender JID from storage as-is. Modern storage should already provide
-			// fully-qualified JIDs (e.g., user@s.whatsapp.net or group@g.us). Avoid mutating
-			// the JID here to prevent corrupting valid group or special JIDs.
-			participantJID := message.Sender
-
-			// Build base ContextInfo with reply details
-			ctxInfo := &waE2E.ContextInfo{
-				StanzaID:    request.ReplyMessageID,
-				Participant: proto.String(participantJID),
-				QuotedMessage: &waE2E.Message{
-					Conversation: proto.String(message.Content),
-				},
-			}
-
-			// Preserve forwarding flag if set
-			if request.BaseRequest.IsForwarded {
-				ctxInfo.IsForwarded = proto.Bool(true)
-				ctxInfo.ForwardingScore = proto.Uint32(100)
-			}
-
-			// Preserve disappearing message duration if provided
-			if request.BaseRequest.Duration != nil && *request.BaseRequest.Duration > 0 {
-				ctxInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
-			} else {
-				ctxInfo.Expiration = proto.Uint32(service.getDefaultEphemeralExpiration(participantJID))
-			}
-
-			// Preserve mentions
-			if len(parsedMentions) > 0 {
-				ctxInfo.MentionedJID = parsedMentions
-			}
-
-			msg.ExtendedTextMessage = &waE2E.ExtendedTextMessage{
-				Text:        proto.String(request.Message),
-				ContextInfo: ctxInfo,
-			}
-		} else {
-			logrus.Warnf("Reply message ID %s not found in storage, continuing without reply context", *request.ReplyMessageID)
-		}
+	if msg.ExtendedTextMessage.ContextInfo, err = service.withReply(ctx, client, dataWaRecipient, msg.ExtendedTextMessage.ContextInfo, request.ReplyRequest, request.Message); err != nil {
+		return response, err
 	}
 
 	ts, err := service.wrapSendMessage(ctx, client, dataWaRecipient, msg, request.Message)
@@ -185,11 +439,42 @@ func (service serviceSend) SendText(ctx context.Context, request domainSend.Mess
 }
 
 func (service serviceSend) SendImage(ctx context.Context, request domainSend.ImageRequest) (response domainSend.GenericResponse, err error) {
+	defer func() { service.audit(ctx, domainAudit.ActionSendImage, request.Phone, response, err) }()
+
+	if request.TemplateName != "" {
+		rendered, err := service.renderTemplate(ctx, request.TemplateRequest, request.Phone, request.Caption, true, request.Image == nil && (request.ImageURL == nil || *request.ImageURL == ""))
+		if err != nil {
+			return response, err
+		}
+		defer rendered.close()
+
+		request.Caption = rendered.text
+		if rendered.attachment != nil {
+			request.Image = rendered.attachment
+		}
+		if request.Duration == nil {
+			request.Duration = rendered.duration
+		}
+		request.TemplateRequest = domainSend.TemplateRequest{}
+	}
+
 	err = validations.ValidateSendImage(ctx, request)
 	if err != nil {
 		return response, err
 	}
 
+	if request.IsScheduled() {
+		when, upload := request.Schedule, request.Image
+		request.Schedule, request.Image = domainSend.Schedule{}, nil
+		return service.schedule(ctx, domainQueue.TypeImage, request.Phone, when, request, upload)
+	}
+
+	if request.Queue {
+		upload := request.Image
+		request.Queue, request.Image = false, nil
+		return service.enqueue(ctx, domainQueue.TypeImage, request.Phone, request, upload)
+	}
+
 	// Get the appropriate WhatsApp client from context
 	client := service.getClientFromContext(ctx)
 	if client == nil {
@@ -201,6 +486,10 @@ func (service serviceSend) SendImage(ctx context.Context, request domainSend.Ima
 		return response, err
 	}
 
+	if err = service.precheckQuota(ctx, dataWaRecipient); err != nil {
+		return response, err
+	}
+
 	var (
 		imagePath      string
 		imageThumbnail string
@@ -333,6 +622,10 @@ func (service serviceSend) SendImage(ctx context.Context, request domainSend.Ima
 		msg.ImageMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
 	}
 
+	if msg.ImageMessage.ContextInfo, err = service.withReply(ctx, client, dataWaRecipient, msg.ImageMessage.ContextInfo, request.ReplyRequest, request.Caption); err != nil {
+		return response, err
+	}
+
 	caption := "🖼️ Image"
 	if request.Caption != "" {
 		caption = "🖼️ " + request.Caption
@@ -353,12 +646,151 @@ func (service serviceSend) SendImage(ctx context.Context, request domainSend.Ima
 	return response, nil
 }
 
+func (service serviceSend) SendSticker(ctx context.Context, request domainSend.StickerRequest) (response domainSend.GenericResponse, err error) {
+	defer func() { service.audit(ctx, domainAudit.ActionSendSticker, request.Phone, response, err) }()
+
+	err = validations.ValidateSendSticker(ctx, request)
+	if err != nil {
+		return response, err
+	}
+
+	if request.IsScheduled() {
+		when, upload := request.Schedule, request.Sticker
+		request.Schedule, request.Sticker = domainSend.Schedule{}, nil
+		return service.schedule(ctx, domainQueue.TypeSticker, request.Phone, when, request, upload)
+	}
+
+	if request.Queue {
+		upload := request.Sticker
+		request.Queue, request.Sticker = false, nil
+		return service.enqueue(ctx, domainQueue.TypeSticker, request.Phone, request, upload)
+	}
+
+	// Get the appropriate WhatsApp client from context
+	client := service.getClientFromContext(ctx)
+	if client == nil {
+		return response, pkgError.InternalServerError("WhatsApp client not available")
+	}
+
+	dataWaRecipient, err := utils.ValidateJidWithLogin(client, request.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	if err = service.precheckQuota(ctx, dataWaRecipient); err != nil {
+		return response, err
+	}
+
+	var source io.Reader
+	if request.StickerURL != nil && *request.StickerURL != "" {
+		stickerData, _, err := utils.DownloadStickerFromURL(*request.StickerURL)
+		if err != nil {
+			return response, pkgError.InternalServerError(fmt.Sprintf("failed to download sticker from URL %v", err))
+		}
+		source = bytes.NewReader(stickerData)
+	} else {
+		file, err := request.Sticker.Open()
+		if err != nil {
+			return response, pkgError.InternalServerError(fmt.Sprintf("failed to open sticker %v", err))
+		}
+		defer file.Close()
+		source = file
+	}
+
+	// Convert to a 512x512 WebP, the only format WhatsApp shows as a sticker
+	if _, err = exec.LookPath("ffmpeg"); err != nil {
+		return response, pkgError.InternalServerError("ffmpeg not installed")
+	}
+	sticker, err := utils.ProcessSticker(source)
+	if err != nil {
+		return response, pkgError.ValidationError(fmt.Sprintf("failed to convert sticker: %v", err))
+	}
+	dataWaSticker := sticker.Bytes()
+
+	uploadedSticker, err := service.uploadMedia(ctx, client, whatsmeow.MediaImage, dataWaSticker, dataWaRecipient)
+	if err != nil {
+		return response, err
+	}
+
+	msg := &waE2E.Message{StickerMessage: &waE2E.StickerMessage{
+		URL:           proto.String(uploadedSticker.URL),
+		DirectPath:    proto.String(uploadedSticker.DirectPath),
+		MediaKey:      uploadedSticker.MediaKey,
+		Mimetype:      proto.String("image/webp"),
+		FileEncSHA256: uploadedSticker.FileEncSHA256,
+		FileSHA256:    uploadedSticker.FileSHA256,
+		FileLength:    proto.Uint64(uint64(len(dataWaSticker))),
+		Width:         proto.Uint32(utils.StickerDimension),
+		Height:        proto.Uint32(utils.StickerDimension),
+		IsAnimated:    proto.Bool(false),
+	}}
+
+	if request.BaseRequest.IsForwarded {
+		msg.StickerMessage.ContextInfo = &waE2E.ContextInfo{
+			IsForwarded:     proto.Bool(true),
+			ForwardingScore: proto.Uint32(100),
+		}
+	}
+
+	// Set duration expiration
+	if request.BaseRequest.Duration != nil && *request.BaseRequest.Duration > 0 {
+		if msg.StickerMessage.ContextInfo == nil {
+			msg.StickerMessage.ContextInfo = &waE2E.ContextInfo{}
+		}
+		msg.StickerMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
+	}
+
+	if msg.StickerMessage.ContextInfo, err = service.withReply(ctx, client, dataWaRecipient, msg.StickerMessage.ContextInfo, request.ReplyRequest, ""); err != nil {
+		return response, err
+	}
+
+	ts, err := service.wrapSendMessage(ctx, client, dataWaRecipient, msg, "🎨 Sticker")
+	if err != nil {
+		return response, err
+	}
+
+	response.MessageID = ts.ID
+	response.Status = fmt.Sprintf("Sticker sent to %s (server timestamp: %s)", request.BaseRequest.Phone, ts.Timestamp.String())
+	return response, nil
+}
+
 func (service serviceSend) SendFile(ctx context.Context, request domainSend.FileRequest) (response domainSend.GenericResponse, err error) {
+	defer func() { service.audit(ctx, domainAudit.ActionSendFile, request.Phone, response, err) }()
+
+	if request.TemplateName != "" {
+		rendered, err := service.renderTemplate(ctx, request.TemplateRequest, request.Phone, request.Caption, true, request.File == nil)
+		if err != nil {
+			return response, err
+		}
+		defer rendered.close()
+
+		request.Caption = rendered.text
+		if rendered.attachment != nil {
+			request.File = rendered.attachment
+		}
+		if request.Duration == nil {
+			request.Duration = rendered.duration
+		}
+		request.TemplateRequest = domainSend.TemplateRequest{}
+	}
+
 	err = validations.ValidateSendFile(ctx, request)
 	if err != nil {
 		return response, err
 	}
 
+	if request.IsScheduled() {
+		when, upload := request.Schedule, request.File
+		request.Schedule, request.File = domainSend.Schedule{}, nil
+		return service.schedule(ctx, domainQueue.TypeFile, request.Phone, when, request, upload)
+	}
+
+	if request.Queue {
+		upload := request.File
+		request.Queue, request.File = false, nil
+		return service.enqueue(ctx, domainQueue.TypeFile, request.Phone, request, upload)
+	}
+
 	// Get the appropriate WhatsApp client from context
 	client := service.getClientFromContext(ctx)
 	if client == nil {
@@ -370,6 +802,10 @@ func (service serviceSend) SendFile(ctx context.Context, request domainSend.File
 		return response, err
 	}
 
+	if err = service.precheckQuota(ctx, dataWaRecipient); err != nil {
+		return response, err
+	}
+
 	fileBytes := helpers.MultipartFormFileHeaderToBytes(request.File)
 	fileMimeType := http.DetectContentType(fileBytes)
 
@@ -407,6 +843,10 @@ func (service serviceSend) SendFile(ctx context.Context, request domainSend.File
 		msg.DocumentMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
 	}
 
+	if msg.DocumentMessage.ContextInfo, err = service.withReply(ctx, client, dataWaRecipient, msg.DocumentMessage.ContextInfo, request.ReplyRequest, request.Caption); err != nil {
+		return response, err
+	}
+
 	caption := "📄 Document"
 	if request.Caption != "" {
 		caption = "📄 " + request.Caption
@@ -422,11 +862,42 @@ func (service serviceSend) SendFile(ctx context.Context, request domainSend.File
 }
 
 func (service serviceSend) SendVideo(ctx context.Context, request domainSend.VideoRequest) (response domainSend.GenericResponse, err error) {
+	defer func() { service.audit(ctx, domainAudit.ActionSendVideo, request.Phone, response, err) }()
+
+	if request.TemplateName != "" {
+		rendered, err := service.renderTemplate(ctx, request.TemplateRequest, request.Phone, request.Caption, true, request.Video == nil && (request.VideoURL == nil || *request.VideoURL == ""))
+		if err != nil {
+			return response, err
+		}
+		defer rendered.close()
+
+		request.Caption = rendered.text
+		if rendered.attachment != nil {
+			request.Video = rendered.attachment
+		}
+		if request.Duration == nil {
+			request.Duration = rendered.duration
+		}
+		request.TemplateRequest = domainSend.TemplateRequest{}
+	}
+
 	err = validations.ValidateSendVideo(ctx, request)
 	if err != nil {
 		return response, err
 	}
 
+	if request.IsScheduled() {
+		when, upload := request.Schedule, request.Video
+		request.Schedule, request.Video = domainSend.Schedule{}, nil
+		return service.schedule(ctx, domainQueue.TypeVideo, request.Phone, when, request, upload)
+	}
+
+	if request.Queue {
+		upload := request.Video
+		request.Queue, request.Video = false, nil
+		return service.enqueue(ctx, domainQueue.TypeVideo, request.Phone, request, upload)
+	}
+
 	// Get the appropriate WhatsApp client from context
 	client := service.getClientFromContext(ctx)
 	if client == nil {
@@ -438,6 +909,10 @@ func (service serviceSend) SendVideo(ctx context.Context, request domainSend.Vid
 		return response, err
 	}
 
+	if err = service.precheckQuota(ctx, dataWaRecipient); err != nil {
+		return response, err
+	}
+
 	var (
 		videoPath      string
 		videoThumbnail string
@@ -589,6 +1064,10 @@ func (service serviceSend) SendVideo(ctx context.Context, request domainSend.Vid
 		msg.VideoMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
 	}
 
+	if msg.VideoMessage.ContextInfo, err = service.withReply(ctx, client, dataWaRecipient, msg.VideoMessage.ContextInfo, request.ReplyRequest, request.Caption); err != nil {
+		return response, err
+	}
+
 	caption := "🎥 Video"
 	if request.Caption != "" {
 		caption = "🎥 " + request.Caption
@@ -604,11 +1083,24 @@ func (service serviceSend) SendVideo(ctx context.Context, request domainSend.Vid
 }
 
 func (service serviceSend) SendContact(ctx context.Context, request domainSend.ContactRequest) (response domainSend.GenericResponse, err error) {
+	defer func() { service.audit(ctx, domainAudit.ActionSendContact, request.Phone, response, err) }()
+
 	err = validations.ValidateSendContact(ctx, request)
 	if err != nil {
 		return response, err
 	}
 
+	if request.IsScheduled() {
+		when := request.Schedule
+		request.Schedule = domainSend.Schedule{}
+		return service.schedule(ctx, domainQueue.TypeContact, request.Phone, when, request, nil)
+	}
+
+	if request.Queue {
+		request.Queue = false
+		return service.enqueue(ctx, domainQueue.TypeContact, request.Phone, request, nil)
+	}
+
 	// Get the appropriate WhatsApp client from context
 	client := service.getClientFromContext(ctx)
 	if client == nil {
@@ -620,6 +1112,10 @@ func (service serviceSend) SendContact(ctx context.Context, request domainSend.C
 		return response, err
 	}
 
+	if err = service.precheckQuota(ctx, dataWaRecipient); err != nil {
+		return response, err
+	}
+
 	msgVCard := fmt.Sprintf("BEGIN:VCARD\nVERSION:3.0\nN:;%v;;;\nFN:%v\nTEL;type=CELL;waid=%v:+%v\nEND:VCARD",
 		request.ContactName, request.ContactName, request.ContactPhone, request.ContactPhone)
 	msg := &waE2E.Message{ContactMessage: &waE2E.ContactMessage{
@@ -641,6 +1137,10 @@ func (service serviceSend) SendContact(ctx context.Context, request domainSend.C
 		msg.ContactMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
 	}
 
+	if msg.ContactMessage.ContextInfo, err = service.withReply(ctx, client, dataWaRecipient, msg.ContactMessage.ContextInfo, request.ReplyRequest, ""); err != nil {
+		return response, err
+	}
+
 	content := "👤 " + request.ContactName
 
 	ts, err := service.wrapSendMessage(ctx, client, dataWaRecipient, msg, content)
@@ -654,11 +1154,38 @@ func (service serviceSend) SendContact(ctx context.Context, request domainSend.C
 }
 
 func (service serviceSend) SendLink(ctx context.Context, request domainSend.LinkRequest) (response domainSend.GenericResponse, err error) {
+	defer func() { service.audit(ctx, domainAudit.ActionSendLink, request.Phone, response, err) }()
+
+	if request.TemplateName != "" {
+		rendered, err := service.renderTemplate(ctx, request.TemplateRequest, request.Phone, request.Caption, false, false)
+		if err != nil {
+			return response, err
+		}
+		defer rendered.close()
+
+		request.Caption = rendered.text
+		if request.Duration == nil {
+			request.Duration = rendered.duration
+		}
+		request.TemplateRequest = domainSend.TemplateRequest{}
+	}
+
 	err = validations.ValidateSendLink(ctx, request)
 	if err != nil {
 		return response, err
 	}
 
+	if request.IsScheduled() {
+		when := request.Schedule
+		request.Schedule = domainSend.Schedule{}
+		return service.schedule(ctx, domainQueue.TypeLink, request.Phone, when, request, nil)
+	}
+
+	if request.Queue {
+		request.Queue = false
+		return service.enqueue(ctx, domainQueue.TypeLink, request.Phone, request, nil)
+	}
+
 	// Get the appropriate WhatsApp client from context
 	client := service.getClientFromContext(ctx)
 	if client == nil {
@@ -670,6 +1197,10 @@ func (service serviceSend) SendLink(ctx context.Context, request domainSend.Link
 		return response, err
 	}
 
+	if err = service.precheckQuota(ctx, dataWaRecipient); err != nil {
+		return response, err
+	}
+
 	metadata, err := utils.GetMetaDataFromURL(request.Link)
 	if err != nil {
 		return response, err
@@ -705,6 +1236,10 @@ func (service serviceSend) SendLink(ctx context.Context, request domainSend.Link
 		msg.ExtendedTextMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
 	}
 
+	if msg.ExtendedTextMessage.ContextInfo, err = service.withReply(ctx, client, dataWaRecipient, msg.ExtendedTextMessage.ContextInfo, request.ReplyRequest, request.Caption); err != nil {
+		return response, err
+	}
+
 	// If we have a thumbnail image, upload it to WhatsApp's servers
 	if len(metadata.ImageThumb) > 0 && metadata.Height != nil && metadata.Width != nil {
 		uploadedThumb, err := service.uploadMedia(ctx, client, whatsmeow.MediaLinkThumbnail, metadata.ImageThumb, dataWaRecipient)
@@ -736,11 +1271,24 @@ func (service serviceSend) SendLink(ctx context.Context, request domainSend.Link
 }
 
 func (service serviceSend) SendLocation(ctx context.Context, request domainSend.LocationRequest) (response domainSend.GenericResponse, err error) {
+	defer func() { service.audit(ctx, domainAudit.ActionSendLocation, request.Phone, response, err) }()
+
 	err = validations.ValidateSendLocation(ctx, request)
 	if err != nil {
 		return response, err
 	}
 
+	if request.IsScheduled() {
+		when := request.Schedule
+		request.Schedule = domainSend.Schedule{}
+		return service.schedule(ctx, domainQueue.TypeLocation, request.Phone, when, request, nil)
+	}
+
+	if request.Queue {
+		request.Queue = false
+		return service.enqueue(ctx, domainQueue.TypeLocation, request.Phone, request, nil)
+	}
+
 	// Get the appropriate WhatsApp client from context
 	client := service.getClientFromContext(ctx)
 	if client == nil {
@@ -752,6 +1300,10 @@ func (service serviceSend) SendLocation(ctx context.Context, request domainSend.
 		return response, err
 	}
 
+	if err = service.precheckQuota(ctx, dataWaRecipient); err != nil {
+		return response, err
+	}
+
 	// Compose WhatsApp Proto
 	msg := &waE2E.Message{
 		LocationMessage: &waE2E.LocationMessage{
@@ -774,6 +1326,10 @@ func (service serviceSend) SendLocation(ctx context.Context, request domainSend.
 		msg.LocationMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
 	}
 
+	if msg.LocationMessage.ContextInfo, err = service.withReply(ctx, client, dataWaRecipient, msg.LocationMessage.ContextInfo, request.ReplyRequest, ""); err != nil {
+		return response, err
+	}
+
 	content := "📍 " + request.Latitude + ", " + request.Longitude
 
 	// Send WhatsApp Message Proto
@@ -788,12 +1344,26 @@ func (service serviceSend) SendLocation(ctx context.Context, request domainSend.
 }
 
 func (service serviceSend) SendAudio(ctx context.Context, request domainSend.AudioRequest) (response domainSend.GenericResponse, err error) {
+	defer func() { service.audit(ctx, domainAudit.ActionSendAudio, request.Phone, response, err) }()
+
 	// Validate request
 	err = validations.ValidateSendAudio(ctx, request)
 	if err != nil {
 		return response, err
 	}
 
+	if request.IsScheduled() {
+		when, upload := request.Schedule, request.Audio
+		request.Schedule, request.Audio = domainSend.Schedule{}, nil
+		return service.schedule(ctx, domainQueue.TypeAudio, request.Phone, when, request, upload)
+	}
+
+	if request.Queue {
+		upload := request.Audio
+		request.Queue, request.Audio = false, nil
+		return service.enqueue(ctx, domainQueue.TypeAudio, request.Phone, request, upload)
+	}
+
 	// Get the appropriate WhatsApp client from context
 	client := service.getClientFromContext(ctx)
 	if client == nil {
@@ -805,6 +1375,10 @@ func (service serviceSend) SendAudio(ctx context.Context, request domainSend.Aud
 		return response, err
 	}
 
+	if err = service.precheckQuota(ctx, dataWaRecipient); err != nil {
+		return response, err
+	}
+
 	var (
 		audioBytes    []byte
 		audioMimeType string
@@ -855,6 +1429,10 @@ func (service serviceSend) SendAudio(ctx context.Context, request domainSend.Aud
 		msg.AudioMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
 	}
 
+	if msg.AudioMessage.ContextInfo, err = service.withReply(ctx, client, dataWaRecipient, msg.AudioMessage.ContextInfo, request.ReplyRequest, ""); err != nil {
+		return response, err
+	}
+
 	content := "🎵 Audio"
 
 	ts, err := service.wrapSendMessage(ctx, client, dataWaRecipient, msg, content)
@@ -868,11 +1446,24 @@ func (service serviceSend) SendAudio(ctx context.Context, request domainSend.Aud
 }
 
 func (service serviceSend) SendPoll(ctx context.Context, request domainSend.PollRequest) (response domainSend.GenericResponse, err error) {
+	defer func() { service.audit(ctx, domainAudit.ActionSendPoll, request.Phone, response, err) }()
+
 	err = validations.ValidateSendPoll(ctx, request)
 	if err != nil {
 		return response, err
 	}
 
+	if request.IsScheduled() {
+		when := request.Schedule
+		request.Schedule = domainSend.Schedule{}
+		return service.schedule(ctx, domainQueue.TypePoll, request.Phone, when, request, nil)
+	}
+
+	if request.Queue {
+		request.Queue = false
+		return service.enqueue(ctx, domainQueue.TypePoll, request.Phone, request, nil)
+	}
+
 	// Get the appropriate WhatsApp client from context
 	client := service.getClientFromContext(ctx)
 	if client == nil {
@@ -884,6 +1475,10 @@ func (service serviceSend) SendPoll(ctx context.Context, request domainSend.Poll
 		return response, err
 	}
 
+	if err = service.precheckQuota(ctx, dataWaRecipient); err != nil {
+		return response, err
+	}
+
 	content := "📊 " + request.Question
 
 	msg := client.BuildPollCreation(request.Question, request.Options, request.MaxAnswer)
@@ -895,6 +1490,10 @@ func (service serviceSend) SendPoll(ctx context.Context, request domainSend.Poll
 		msg.PollCreationMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
 	}
 
+	if msg.PollCreationMessage.ContextInfo, err = service.withReply(ctx, client, dataWaRecipient, msg.PollCreationMessage.ContextInfo, request.ReplyRequest, ""); err != nil {
+		return response, err
+	}
+
 	ts, err := service.wrapSendMessage(ctx, client, dataWaRecipient, msg, content)
 	if err != nil {
 		return response, err
@@ -905,6 +1504,128 @@ func (service serviceSend) SendPoll(ctx context.Context, request domainSend.Poll
 	return response, nil
 }
 
+// ForwardMessage re-sends a stored message to every target with the forwarded flag. Media are sent with the keys
+// from the chat storage, so they are not downloaded and uploaded again. The request only fails when no target got
+// the message, the outcome of every target is in the results.
+func (service serviceSend) ForwardMessage(ctx context.Context, request domainSend.ForwardRequest) (response domainSend.ForwardResponse, err error) {
+	if err = validations.ValidateForwardMessage(ctx, request); err != nil {
+		return response, err
+	}
+
+	client := service.getClientFromContext(ctx)
+	if client == nil {
+		return response, pkgError.InternalServerError("WhatsApp client not available")
+	}
+
+	message, err := service.getChatStorageFromContext(ctx).GetMessageByID(request.MessageID)
+	if err != nil {
+		return response, err
+	}
+	if message == nil {
+		return response, pkgError.NotFoundError(fmt.Sprintf("message %s not found", request.MessageID))
+	}
+	if appCtx, ok := ctx.(*app.AppContext); ok && !appCtx.AllowsChat(message.ChatJID) {
+		return response, pkgError.ForbiddenError(fmt.Sprintf("Access to chat %s is not allowed", message.ChatJID))
+	}
+	if forwardedMessage(message) == nil {
+		return response, pkgError.ValidationError(fmt.Sprintf("message %s is neither text nor media that can be forwarded", request.MessageID))
+	}
+
+	// Media without a caption are stored by their type, like the other senders do
+	content := message.Content
+	if content == "" {
+		content = "↪️ " + message.MediaType
+	}
+
+	var firstErr error
+	sent := 0
+	for _, phone := range request.Phones {
+		result := domainSend.ForwardResult{Phone: phone}
+		result.MessageID, err = service.forwardTo(ctx, client, message, phone, request.Duration, content)
+		if err != nil {
+			result.Error = err.Error()
+			if firstErr == nil {
+				firstErr = err
+			}
+		} else {
+			sent++
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	if sent == 0 {
+		return response, firstErr
+	}
+
+	response.MessageID = request.MessageID
+	response.Status = fmt.Sprintf("Message %s forwarded to %d of %d chats", request.MessageID, sent, len(request.Phones))
+	return response, nil
+}
+
+// forwardTo sends one forwarded copy of a stored message and returns its ID
+func (service serviceSend) forwardTo(ctx context.Context, client *whatsmeow.Client, message *domainChatStorage.Message, phone string, duration *int, content string) (messageID string, err error) {
+	defer func() {
+		service.audit(ctx, domainAudit.ActionMessageForward, phone, domainSend.GenericResponse{MessageID: messageID}, err)
+	}()
+
+	dataWaRecipient, err := utils.ValidateJidWithLogin(client, phone)
+	if err != nil {
+		return "", err
+	}
+
+	if err = service.precheckQuota(ctx, dataWaRecipient); err != nil {
+		return "", err
+	}
+
+	msg := forwardedMessage(message)
+	contextInfo := &waE2E.ContextInfo{
+		IsForwarded:     proto.Bool(true),
+		ForwardingScore: proto.Uint32(100),
+	}
+	if duration != nil && *duration > 0 {
+		contextInfo.Expiration = proto.Uint32(uint32(*duration))
+	} else if expiration := service.getDefaultEphemeralExpiration(ctx, dataWaRecipient.String()); expiration > 0 {
+		contextInfo.Expiration = proto.Uint32(expiration)
+	}
+
+	switch {
+	case msg.ImageMessage != nil:
+		msg.ImageMessage.ContextInfo = contextInfo
+	case msg.VideoMessage != nil:
+		msg.VideoMessage.ContextInfo = contextInfo
+	case msg.AudioMessage != nil:
+		msg.AudioMessage.ContextInfo = contextInfo
+	case msg.DocumentMessage != nil:
+		msg.DocumentMessage.ContextInfo = contextInfo
+	case msg.StickerMessage != nil:
+		msg.StickerMessage.ContextInfo = contextInfo
+	default:
+		msg.ExtendedTextMessage.ContextInfo = contextInfo
+	}
+
+	ts, err := service.wrapSendMessage(ctx, client, dataWaRecipient, msg, content)
+	if err != nil {
+		return "", err
+	}
+	return ts.ID, nil
+}
+
+// forwardedMessage rebuilds a stored message for forwarding, nil when it is neither text nor media with stored keys.
+// Contacts, locations and polls sent through this API are stored as a summary with their kind as media type, and
+// are not forwarded as that summary.
+func forwardedMessage(message *domainChatStorage.Message) *waE2E.Message {
+	if message.MediaType != "" {
+		if len(message.MediaKey) == 0 || message.URL == "" {
+			return nil
+		}
+		return utils.BuildMediaMessage(message.MediaType, message.Filename, message.Mimetype, message.URL, message.MediaKey, message.FileSHA256, message.FileEncSHA256, message.FileLength, message.Content)
+	}
+	if message.Content == "" {
+		return nil
+	}
+	return &waE2E.Message{ExtendedTextMessage: &waE2E.ExtendedTextMessage{Text: proto.String(message.Content)}}
+}
+
 func (service serviceSend) SendPresence(ctx context.Context, request domainSend.PresenceRequest) (response domainSend.GenericResponse, err error) {
 	err = validations.ValidateSendPresence(ctx, request)
 	if err != nil {
@@ -981,6 +1702,95 @@ func (service serviceSend) getMentionFromText(ctx context.Context, client *whats
 	return result
 }
 
+// withReply adds the mentions of the text and the request, and the quoted message of the request, to contextInfo.
+// A reply message that cannot be found is skipped so the message is still sent, one from another chat is rejected.
+func (service serviceSend) withReply(ctx context.Context, client *whatsmeow.Client, recipient types.JID, contextInfo *waE2E.ContextInfo, request domainSend.ReplyRequest, text string) (*waE2E.ContextInfo, error) {
+	mentions := service.getMentionFromText(ctx, client, text)
+	for _, mention := range request.Mentions {
+		if dataWaRecipient, err := utils.ValidateJidWithLogin(client, mention); err == nil {
+			mentions = append(mentions, dataWaRecipient.String())
+		}
+	}
+
+	var quoted *domainChatStorage.Message
+	if request.ReplyMessageID != nil && *request.ReplyMessageID != "" {
+		message, err := service.getChatStorageFromContext(ctx).GetMessageByID(*request.ReplyMessageID)
+		if err != nil {
+			logrus.Warnf("Error retrieving reply message ID %s: %v, continuing without reply context", *request.ReplyMessageID, err)
+		} else if message == nil {
+			logrus.Warnf("Reply message ID %s not found in storage, continuing without reply context", *request.ReplyMessageID)
+		} else if !sameChat(ctx, client, message.ChatJID, recipient) {
+			return contextInfo, pkgError.ValidationError(fmt.Sprintf("reply message %s belongs to another chat", message.ID))
+		} else {
+			quoted = message
+		}
+	}
+
+	if len(mentions) == 0 && quoted == nil {
+		return contextInfo, nil
+	}
+	if contextInfo == nil {
+		contextInfo = &waE2E.ContextInfo{}
+	}
+
+	if len(mentions) > 0 {
+		seen := make(map[string]bool, len(mentions))
+		contextInfo.MentionedJID = nil
+		for _, mention := range mentions {
+			if !seen[mention] {
+				seen[mention] = true
+				contextInfo.MentionedJID = append(contextInfo.MentionedJID, mention)
+			}
+		}
+	}
+
+	if quoted != nil {
+		// Use the sender JID from storage as-is. Modern storage should already provide
+		// fully-qualified JIDs (e.g., user@s.whatsapp.net or group@g.us). Avoid mutating
+		// the JID here to prevent corrupting valid group or special JIDs.
+		contextInfo.StanzaID = proto.String(quoted.ID)
+		contextInfo.Participant = proto.String(quoted.Sender)
+		contextInfo.QuotedMessage = quotedMessage(quoted)
+	}
+	return contextInfo, nil
+}
+
+// quotedMessage rebuilds a stored message for the quote of a reply. Media keep their type so the client shows them
+// as such, images and videos with the preview kept in the chat storage.
+func quotedMessage(message *domainChatStorage.Message) *waE2E.Message {
+	quoted := utils.BuildMediaMessage(message.MediaType, message.Filename, message.Mimetype, message.URL, message.MediaKey, message.FileSHA256, message.FileEncSHA256, message.FileLength, message.Content)
+	if quoted == nil {
+		return &waE2E.Message{Conversation: proto.String(message.Content)}
+	}
+
+	switch {
+	case quoted.ImageMessage != nil:
+		quoted.ImageMessage.JPEGThumbnail = message.Thumbnail
+	case quoted.VideoMessage != nil:
+		quoted.VideoMessage.JPEGThumbnail = message.Thumbnail
+	}
+	return quoted
+}
+
+// sameChat reports whether a stored chat JID is the chat of the recipient, resolving hidden user ids to phone numbers
+func sameChat(ctx context.Context, client *whatsmeow.Client, chatJID string, recipient types.JID) bool {
+	chat, err := types.ParseJID(chatJID)
+	if err != nil {
+		return false
+	}
+
+	resolve := func(jid types.JID) types.JID {
+		jid = jid.ToNonAD()
+		if jid.Server == types.HiddenUserServer {
+			if pn, err := client.Store.LIDs.GetPNForLID(ctx, jid); err == nil && !pn.IsEmpty() {
+				return pn
+			}
+		}
+		return jid
+	}
+	return resolve(chat) == resolve(recipient)
+}
+
 func (service serviceSend) uploadMedia(ctx context.Context, client *whatsmeow.Client, mediaType whatsmeow.MediaType, media []byte, recipient types.JID) (uploaded whatsmeow.UploadResponse, err error) {
 	if recipient.Server == types.NewsletterServer {
 		uploaded, err = client.UploadNewsletter(ctx, media, mediaType)
@@ -990,13 +1800,13 @@ func (service serviceSend) uploadMedia(ctx context.Context, client *whatsmeow.Cl
 	return uploaded, err
 }
 
-func (service serviceSend) getDefaultEphemeralExpiration(jid string) (expiration uint32) {
+func (service serviceSend) getDefaultEphemeralExpiration(ctx context.Context, jid string) (expiration uint32) {
 	expiration = 0
 	if jid == "" {
 		return expiration
 	}
 
-	chat, err := service.chatStorageRepo.GetChat(jid)
+	chat, err := service.getChatStorageFromContext(ctx).GetChat(jid)
 	if err != nil {
 		return expiration
 	}
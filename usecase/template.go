@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+)
+
+type serviceTemplate struct {
+	templateRepo domainTemplate.ITemplateRepository
+}
+
+func NewTemplateService(templateRepo domainTemplate.ITemplateRepository) domainTemplate.ITemplateUsecase {
+	return &serviceTemplate{
+		templateRepo: templateRepo,
+	}
+}
+
+func (service serviceTemplate) CreateTemplate(ctx context.Context, userID int, request domainTemplate.CreateTemplateRequest) (template domainTemplate.Template, err error) {
+	template = domainTemplate.Template{
+		UserID:   userID,
+		Name:     request.Name,
+		Body:     request.Body,
+		Duration: request.Duration,
+	}
+	if request.Attachment != nil {
+		template.AttachmentName = request.Attachment.Filename
+		template.AttachmentType = request.Attachment.Header.Get("Content-Type")
+	}
+
+	if err = validations.ValidateTemplate(ctx, template, request.Attachment); err != nil {
+		return template, err
+	}
+	if err = service.ensureNameAvailable(userID, 0, template.Name); err != nil {
+		return template, err
+	}
+
+	if request.Attachment != nil {
+		if template.AttachmentPath, err = storeUpload(request.Attachment, config.PathTemplate); err != nil {
+			return template, err
+		}
+	}
+
+	if err = service.templateRepo.Create(&template); err != nil {
+		removeTemplateAttachment(template.AttachmentPath)
+		return template, err
+	}
+
+	logrus.Infof("Template %d (%s) created for user %d", template.ID, template.Name, userID)
+	return template, nil
+}
+
+func (service serviceTemplate) ListTemplates(ctx context.Context, userID int, request domainTemplate.ListRequest) (response domainTemplate.ListResponse, err error) {
+	if err = validations.ValidateListTemplates(ctx, &request); err != nil {
+		return response, err
+	}
+
+	templates, err := service.templateRepo.List(userID, request.Limit, request.Offset)
+	if err != nil {
+		return response, err
+	}
+
+	total, err := service.templateRepo.Count(userID)
+	if err != nil {
+		return response, err
+	}
+
+	response.Data = templates
+	response.Pagination = domainTemplate.PaginationResponse{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+		Total:  total,
+	}
+	return response, nil
+}
+
+func (service serviceTemplate) GetTemplate(_ context.Context, userID int, id int64) (domainTemplate.Template, error) {
+	template, err := service.templateRepo.GetByID(userID, id)
+	if err != nil {
+		return domainTemplate.Template{}, err
+	}
+	if template == nil {
+		return domainTemplate.Template{}, pkgError.NotFoundError("template not found")
+	}
+	return *template, nil
+}
+
+func (service serviceTemplate) UpdateTemplate(ctx context.Context, userID int, id int64, request domainTemplate.UpdateTemplateRequest) (template domainTemplate.Template, err error) {
+	template, err = service.GetTemplate(ctx, userID, id)
+	if err != nil {
+		return template, err
+	}
+	previousAttachment := template.AttachmentPath
+
+	if request.Name != "" {
+		template.Name = request.Name
+	}
+	if request.Body != nil {
+		template.Body = *request.Body
+	}
+	if request.Duration != nil {
+		template.Duration = request.Duration
+	}
+	if request.ClearDuration {
+		template.Duration = nil
+	}
+	if request.RemoveAttachment {
+		template.AttachmentPath, template.AttachmentName, template.AttachmentType = "", "", ""
+	}
+	if request.Attachment != nil {
+		template.AttachmentName = request.Attachment.Filename
+		template.AttachmentType = request.Attachment.Header.Get("Content-Type")
+	}
+
+	if err = validations.ValidateTemplate(ctx, template, request.Attachment); err != nil {
+		return template, err
+	}
+	if err = service.ensureNameAvailable(userID, id, template.Name); err != nil {
+		return template, err
+	}
+
+	if request.Attachment != nil {
+		if template.AttachmentPath, err = storeUpload(request.Attachment, config.PathTemplate); err != nil {
+			return template, err
+		}
+	}
+
+	if err = service.templateRepo.Update(&template); err != nil {
+		if template.AttachmentPath != previousAttachment {
+			removeTemplateAttachment(template.AttachmentPath)
+		}
+		return template, err
+	}
+
+	if template.AttachmentPath != previousAttachment {
+		removeTemplateAttachment(previousAttachment)
+	}
+	return template, nil
+}
+
+func (service serviceTemplate) DeleteTemplate(ctx context.Context, userID int, id int64) error {
+	template, err := service.GetTemplate(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := service.templateRepo.Delete(userID, id); err != nil {
+		return err
+	}
+
+	removeTemplateAttachment(template.AttachmentPath)
+	return nil
+}
+
+// ensureNameAvailable rejects a name already used by another template of the user
+func (service serviceTemplate) ensureNameAvailable(userID int, id int64, name string) error {
+	existing, err := service.templateRepo.GetByName(userID, name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != id {
+		return pkgError.ValidationError(fmt.Sprintf("name: template %s already exists", name))
+	}
+	return nil
+}
+
+func removeTemplateAttachment(path string) {
+	if path == "" {
+		return
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		logrus.Warnf("Failed to remove template attachment %s: %v", path, err)
+	}
+}
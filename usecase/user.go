@@ -159,7 +159,12 @@ func (service serviceUser) MyListGroups(ctx context.Context) (response domainUse
 		return
 	}
 
+	appCtx, _ := ctx.(*domainApp.AppContext)
 	for _, group := range groups {
+		// Requests limited to some chats only list those groups
+		if appCtx != nil && !appCtx.AllowsChat(group.JID.String()) {
+			continue
+		}
 		response.Data = append(response.Data, *group)
 	}
 	return response, nil
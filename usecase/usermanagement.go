@@ -2,26 +2,37 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	domainAudit "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/audit"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
 	domainUserManagement "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/usermanagement"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow"
 )
 
+// exampleAdminPassword is the bootstrap password earlier versions of .env.example shipped with
+const exampleAdminPassword = "admin123"
+
 type userManagementUsecase struct {
 	userRepo        domainUserManagement.IUserManagementRepository
 	chatStorageRepo domainChatStorage.IChatStorageRepository
+	auditRepo       domainAudit.IAuditRepository
 }
 
-func NewUserManagementUsecase(userRepo domainUserManagement.IUserManagementRepository, chatStorageRepo domainChatStorage.IChatStorageRepository) domainUserManagement.IUserManagementUsecase {
+func NewUserManagementUsecase(userRepo domainUserManagement.IUserManagementRepository, chatStorageRepo domainChatStorage.IChatStorageRepository, auditRepo domainAudit.IAuditRepository) domainUserManagement.IUserManagementUsecase {
 	return &userManagementUsecase{
 		userRepo:        userRepo,
 		chatStorageRepo: chatStorageRepo,
+		auditRepo:       auditRepo,
 	}
 }
 
@@ -41,7 +52,10 @@ func (u *userManagementUsecase) getClientFromContext(ctx context.Context) (*what
 	return nil, pkgError.ErrNotLoggedIn
 }
 
-func (u *userManagementUsecase) CreateUser(request domainUserManagement.CreateUserRequest) (*domainUserManagement.UserResponse, error) {
+func (u *userManagementUsecase) CreateUser(ctx context.Context, request domainUserManagement.CreateUserRequest) (response *domainUserManagement.UserResponse, err error) {
+	entry := domainAudit.Entry{Action: domainAudit.ActionUserCreate, Reference: request.Username}
+	defer func() { recordAudit(ctx, u.auditRepo, entry, err) }()
+
 	// Basic validation
 	if request.Username == "" {
 		return nil, fmt.Errorf("username is required")
@@ -55,6 +69,16 @@ func (u *userManagementUsecase) CreateUser(request domainUserManagement.CreateUs
 	if len(request.Password) < 6 {
 		return nil, fmt.Errorf("password must be at least 6 characters")
 	}
+	if request.Role == "" {
+		request.Role = domainUserManagement.RoleUser
+	}
+	if !slices.Contains(domainUserManagement.Roles, request.Role) {
+		return nil, fmt.Errorf("role must be one of %s", strings.Join(domainUserManagement.Roles, ", "))
+	}
+	grant := permission.Grant{Scopes: request.Scopes, AllowedChats: request.AllowedChats}
+	if err := validations.ValidateGrant(ctx, grant); err != nil {
+		return nil, err
+	}
 
 	// Check if username already exists
 	existingUser, err := u.userRepo.GetByUsername(request.Username)
@@ -70,11 +94,14 @@ func (u *userManagementUsecase) CreateUser(request domainUserManagement.CreateUs
 		Username: request.Username,
 		Password: request.Password,
 		IsActive: true,
+		Role:     request.Role,
+		Grant:    grant,
 	}
 
 	if err := u.userRepo.Create(user); err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
+	entry.UserID = user.ID
 
 	// Get WhatsApp connection status for new user (will be false initially)
 	sessionManager := whatsapp.GetSessionManager()
@@ -84,10 +111,12 @@ func (u *userManagementUsecase) CreateUser(request domainUserManagement.CreateUs
 		ID:          user.ID,
 		Username:    user.Username,
 		IsActive:    user.IsActive,
+		Role:        user.Role,
 		IsConnected: isConnected,
 		IsLoggedIn:  isLoggedIn,
 		CreatedAt:   user.CreatedAt,
 		UpdatedAt:   user.UpdatedAt,
+		Grant:       user.Grant,
 	}, nil
 }
 
@@ -108,10 +137,12 @@ func (u *userManagementUsecase) GetUser(id int) (*domainUserManagement.UserRespo
 		ID:          user.ID,
 		Username:    user.Username,
 		IsActive:    user.IsActive,
+		Role:        user.Role,
 		IsConnected: isConnected,
 		IsLoggedIn:  isLoggedIn,
 		CreatedAt:   user.CreatedAt,
 		UpdatedAt:   user.UpdatedAt,
+		Grant:       user.Grant,
 	}, nil
 }
 
@@ -132,10 +163,12 @@ func (u *userManagementUsecase) GetUserByUsername(username string) (*domainUserM
 		ID:          user.ID,
 		Username:    user.Username,
 		IsActive:    user.IsActive,
+		Role:        user.Role,
 		IsConnected: isConnected,
 		IsLoggedIn:  isLoggedIn,
 		CreatedAt:   user.CreatedAt,
 		UpdatedAt:   user.UpdatedAt,
+		Grant:       user.Grant,
 	}, nil
 }
 
@@ -157,17 +190,22 @@ func (u *userManagementUsecase) GetAllUsers() ([]domainUserManagement.UserRespon
 			ID:          user.ID,
 			Username:    user.Username,
 			IsActive:    user.IsActive,
+			Role:        user.Role,
 			IsConnected: isConnected,
 			IsLoggedIn:  isLoggedIn,
 			CreatedAt:   user.CreatedAt,
 			UpdatedAt:   user.UpdatedAt,
+			Grant:       user.Grant,
 		})
 	}
 
 	return responses, nil
 }
 
-func (u *userManagementUsecase) UpdateUser(id int, request domainUserManagement.UpdateUserRequest) (*domainUserManagement.UserResponse, error) {
+func (u *userManagementUsecase) UpdateUser(ctx context.Context, id int, request domainUserManagement.UpdateUserRequest) (response *domainUserManagement.UserResponse, err error) {
+	entry := domainAudit.Entry{Action: domainAudit.ActionUserUpdate, UserID: id}
+	defer func() { recordAudit(ctx, u.auditRepo, entry, err) }()
+
 	// Basic validation
 	if request.Username != "" && (len(request.Username) < 3 || len(request.Username) > 50) {
 		return nil, fmt.Errorf("username must be between 3 and 50 characters")
@@ -175,6 +213,12 @@ func (u *userManagementUsecase) UpdateUser(id int, request domainUserManagement.
 	if request.Password != "" && len(request.Password) < 6 {
 		return nil, fmt.Errorf("password must be at least 6 characters")
 	}
+	if request.Role != "" && !slices.Contains(domainUserManagement.Roles, request.Role) {
+		return nil, fmt.Errorf("role must be one of %s", strings.Join(domainUserManagement.Roles, ", "))
+	}
+	if err := validations.ValidateGrant(ctx, permission.Grant{Scopes: request.Scopes, AllowedChats: request.AllowedChats}); err != nil {
+		return nil, err
+	}
 
 	// Check if user exists
 	existingUser, err := u.userRepo.GetByID(id)
@@ -184,6 +228,7 @@ func (u *userManagementUsecase) UpdateUser(id int, request domainUserManagement.
 	if existingUser == nil {
 		return nil, fmt.Errorf("user not found")
 	}
+	entry.Reference = existingUser.Username
 
 	// Check if username already exists (if updating username)
 	if request.Username != "" && request.Username != existingUser.Username {
@@ -196,8 +241,11 @@ func (u *userManagementUsecase) UpdateUser(id int, request domainUserManagement.
 		}
 	}
 
-	// Update user
+	// Update user, the repository refuses to remove the last active admin
 	if err := u.userRepo.Update(id, &request); err != nil {
+		if errors.Is(err, domainUserManagement.ErrLastActiveAdmin) {
+			return nil, err
+		}
 		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
 
@@ -215,14 +263,19 @@ func (u *userManagementUsecase) UpdateUser(id int, request domainUserManagement.
 		ID:          updatedUser.ID,
 		Username:    updatedUser.Username,
 		IsActive:    updatedUser.IsActive,
+		Role:        updatedUser.Role,
 		IsConnected: isConnected,
 		IsLoggedIn:  isLoggedIn,
 		CreatedAt:   updatedUser.CreatedAt,
 		UpdatedAt:   updatedUser.UpdatedAt,
+		Grant:       updatedUser.Grant,
 	}, nil
 }
 
-func (u *userManagementUsecase) DeleteUser(id int) error {
+func (u *userManagementUsecase) DeleteUser(ctx context.Context, id int) (err error) {
+	entry := domainAudit.Entry{Action: domainAudit.ActionUserDelete, UserID: id, TargetJID: sessionJID(id)}
+	defer func() { recordAudit(ctx, u.auditRepo, entry, err) }()
+
 	// Check if user exists
 	existingUser, err := u.userRepo.GetByID(id)
 	if err != nil {
@@ -231,12 +284,71 @@ func (u *userManagementUsecase) DeleteUser(id int) error {
 	if existingUser == nil {
 		return fmt.Errorf("user not found")
 	}
+	entry.Reference = existingUser.Username
 
-	// Delete user
+	// Delete user, the repository refuses to remove the last active admin
 	if err := u.userRepo.Delete(id); err != nil {
+		if errors.Is(err, domainUserManagement.ErrLastActiveAdmin) {
+			return err
+		}
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
 
+	// Drop the user's chats and messages; other users' storage is untouched
+	if u.chatStorageRepo != nil {
+		if err := u.chatStorageRepo.ForUser(id).TruncateAllChats(); err != nil {
+			logrus.Warnf("Failed to clear chat storage for deleted user %s (ID: %d): %v", existingUser.Username, id, err)
+		}
+	}
+
+	return nil
+}
+
+// sessionJID returns the WhatsApp account the user's session is logged in as, if any
+func sessionJID(userID int) string {
+	client := whatsapp.GetSessionManager().GetUserClient(userID)
+	if client == nil || client.Store == nil || client.Store.ID == nil {
+		return ""
+	}
+	return client.Store.ID.ToNonAD().String()
+}
+
+func (u *userManagementUsecase) BootstrapAdmin(username, password string) error {
+	if username == "" || password == "" {
+		return nil
+	}
+
+	count, err := u.userRepo.CountActiveAdmins()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		if password == exampleAdminPassword {
+			logrus.Warnf("ADMIN_PASSWORD is set to the example password, make sure no admin still uses it")
+		}
+		return nil
+	}
+	if password == exampleAdminPassword {
+		return fmt.Errorf("cannot bootstrap admin %s with the example password, set ADMIN_PASSWORD to a password of your own", username)
+	}
+
+	existingUser, err := u.userRepo.GetByUsername(username)
+	if err != nil {
+		return fmt.Errorf("failed to check existing username: %w", err)
+	}
+	if existingUser != nil {
+		return fmt.Errorf("cannot bootstrap admin %s: a user with that username already exists, rename it or choose another ADMIN_USERNAME", username)
+	}
+
+	if _, err := u.CreateUser(context.Background(), domainUserManagement.CreateUserRequest{
+		Username: username,
+		Password: password,
+		Role:     domainUserManagement.RoleAdmin,
+	}); err != nil {
+		return fmt.Errorf("failed to bootstrap admin %s: %w", username, err)
+	}
+
+	logrus.Infof("Created admin %s from ADMIN_USERNAME and ADMIN_PASSWORD, manage admins through /admin/users from now on", username)
 	return nil
 }
 
@@ -263,7 +375,10 @@ func (u *userManagementUsecase) GetActiveUserCredentials() map[string]string {
 // DisconnectWhatsAppSession disconnects WhatsApp session for specific user (admin only)
 // Similar to user logout - clears all local data but keeps device registered with WhatsApp server
 // Device remains active on server, user just needs to login again
-func (u *userManagementUsecase) DisconnectWhatsAppSession(userID int) error {
+func (u *userManagementUsecase) DisconnectWhatsAppSession(ctx context.Context, userID int) (err error) {
+	entry := domainAudit.Entry{Action: domainAudit.ActionSessionDisconnect, UserID: userID, TargetJID: sessionJID(userID)}
+	defer func() { recordAudit(ctx, u.auditRepo, entry, err) }()
+
 	// Validate user exists
 	user, err := u.userRepo.GetByID(userID)
 	if err != nil {
@@ -272,6 +387,7 @@ func (u *userManagementUsecase) DisconnectWhatsAppSession(userID int) error {
 	if user == nil {
 		return fmt.Errorf("user with ID %d not found", userID)
 	}
+	entry.Reference = user.Username
 
 	// Get user-specific WhatsApp client for the user being disconnected
 	sessionManager := whatsapp.GetSessionManager()
@@ -285,7 +401,10 @@ func (u *userManagementUsecase) DisconnectWhatsAppSession(userID int) error {
 }
 
 // ReconnectWhatsAppSession reconnects WhatsApp session for specific user (admin only)
-func (u *userManagementUsecase) ReconnectWhatsAppSession(userID int) error {
+func (u *userManagementUsecase) ReconnectWhatsAppSession(ctx context.Context, userID int) (err error) {
+	entry := domainAudit.Entry{Action: domainAudit.ActionSessionReconnect, UserID: userID, TargetJID: sessionJID(userID)}
+	defer func() { recordAudit(ctx, u.auditRepo, entry, err) }()
+
 	// Validate user exists
 	user, err := u.userRepo.GetByID(userID)
 	if err != nil {
@@ -294,6 +413,7 @@ func (u *userManagementUsecase) ReconnectWhatsAppSession(userID int) error {
 	if user == nil {
 		return fmt.Errorf("user with ID %d not found", userID)
 	}
+	entry.Reference = user.Username
 
 	// Get user-specific WhatsApp client for reconnection
 	sessionManager := whatsapp.GetSessionManager()
@@ -318,7 +438,10 @@ func (u *userManagementUsecase) ReconnectWhatsAppSession(userID int) error {
 
 // ClearWhatsAppSession completely clears WhatsApp session and forces logout (admin only)
 // Like disconnect but also removes device from WhatsApp server - clears RAM completely
-func (u *userManagementUsecase) ClearWhatsAppSession(userID int) error {
+func (u *userManagementUsecase) ClearWhatsAppSession(ctx context.Context, userID int) (err error) {
+	entry := domainAudit.Entry{Action: domainAudit.ActionSessionClear, UserID: userID, TargetJID: sessionJID(userID)}
+	defer func() { recordAudit(ctx, u.auditRepo, entry, err) }()
+
 	// Validate user exists
 	user, err := u.userRepo.GetByID(userID)
 	if err != nil {
@@ -327,6 +450,7 @@ func (u *userManagementUsecase) ClearWhatsAppSession(userID int) error {
 	if user == nil {
 		return fmt.Errorf("user with ID %d not found", userID)
 	}
+	entry.Reference = user.Username
 
 	// Get user-specific WhatsApp client for logout
 	sessionManager := whatsapp.GetSessionManager()
@@ -335,8 +459,6 @@ func (u *userManagementUsecase) ClearWhatsAppSession(userID int) error {
 		return fmt.Errorf("WhatsApp client not found for user %s (ID: %d). User may not have active session.", user.Username, userID)
 	}
 
-	ctx := context.Background()
-
 	// Logout from WhatsApp server first to remove device registration
 	if client.IsLoggedIn() {
 		logrus.Infof("Logging out WhatsApp for user %s (ID: %d) from server (removing device)...", user.Username, userID)
@@ -0,0 +1,311 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+)
+
+type serviceWebhook struct {
+	webhookRepo   domainWebhook.IWebhookRepository
+	webhookOutbox domainWebhook.IWebhookOutboxRepository
+}
+
+func NewWebhookService(webhookRepo domainWebhook.IWebhookRepository, webhookOutbox domainWebhook.IWebhookOutboxRepository) domainWebhook.IWebhookUsecase {
+	return &serviceWebhook{
+		webhookRepo:   webhookRepo,
+		webhookOutbox: webhookOutbox,
+	}
+}
+
+func (service serviceWebhook) CreateWebhook(ctx context.Context, userID int, request domainWebhook.CreateWebhookRequest) (response domainWebhook.WebhookResponse, err error) {
+	if err = validations.ValidateCreateWebhook(ctx, request); err != nil {
+		return response, err
+	}
+
+	webhook := &domainWebhook.Webhook{
+		UserID:   userID,
+		URL:      request.URL,
+		Secret:   request.Secret,
+		Events:   request.Events,
+		Chats:    request.Chats,
+		Format:   request.Format,
+		IsActive: true,
+	}
+	if webhook.Format == "" {
+		webhook.Format = domainWebhook.FormatLegacy
+	}
+	if request.IsActive != nil {
+		webhook.IsActive = *request.IsActive
+	}
+
+	// Generate a signing secret when the caller did not provide one
+	if webhook.Secret == "" {
+		if webhook.Secret, err = generateWebhookSecret(); err != nil {
+			return response, pkgError.InternalServerError(fmt.Sprintf("failed to generate webhook secret: %v", err))
+		}
+	}
+
+	if err = service.webhookRepo.Create(webhook); err != nil {
+		return response, err
+	}
+
+	logrus.Infof("Webhook %d created for user %d: %s", webhook.ID, userID, webhook.URL)
+
+	response = toWebhookResponse(*webhook)
+	response.Secret = webhook.Secret
+	return response, nil
+}
+
+func (service serviceWebhook) ListWebhooks(_ context.Context, userID int) ([]domainWebhook.WebhookResponse, error) {
+	webhooks, err := service.webhookRepo.GetByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]domainWebhook.WebhookResponse, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		responses = append(responses, toWebhookResponse(webhook))
+	}
+	return responses, nil
+}
+
+func (service serviceWebhook) GetWebhook(_ context.Context, userID, id int) (response domainWebhook.WebhookResponse, err error) {
+	webhook, err := service.getWebhook(userID, id)
+	if err != nil {
+		return response, err
+	}
+	return toWebhookResponse(*webhook), nil
+}
+
+func (service serviceWebhook) UpdateWebhook(ctx context.Context, userID, id int, request domainWebhook.UpdateWebhookRequest) (response domainWebhook.WebhookResponse, err error) {
+	if err = validations.ValidateUpdateWebhook(ctx, request); err != nil {
+		return response, err
+	}
+
+	webhook, err := service.getWebhook(userID, id)
+	if err != nil {
+		return response, err
+	}
+
+	if request.URL != "" {
+		webhook.URL = request.URL
+	}
+	if request.Secret != nil {
+		webhook.Secret = *request.Secret
+	}
+	if request.Events != nil {
+		webhook.Events = request.Events
+	}
+	if request.Chats != nil {
+		webhook.Chats = request.Chats
+	}
+	if request.Format != "" {
+		webhook.Format = request.Format
+	}
+	if request.IsActive != nil {
+		webhook.IsActive = *request.IsActive
+	}
+
+	if err = service.webhookRepo.Update(webhook); err != nil {
+		return response, err
+	}
+
+	return toWebhookResponse(*webhook), nil
+}
+
+func (service serviceWebhook) DeleteWebhook(_ context.Context, userID, id int) error {
+	if _, err := service.getWebhook(userID, id); err != nil {
+		return err
+	}
+	return service.webhookRepo.Delete(userID, id)
+}
+
+func (service serviceWebhook) TestWebhook(ctx context.Context, userID, id int) (response domainWebhook.TestWebhookResponse, err error) {
+	webhook, err := service.getWebhook(userID, id)
+	if err != nil {
+		return response, err
+	}
+
+	start := time.Now()
+	statusCode, err := whatsapp.SendTestWebhook(ctx, *webhook)
+
+	response = domainWebhook.TestWebhookResponse{
+		WebhookID:  webhook.ID,
+		Delivered:  err == nil,
+		StatusCode: statusCode,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		response.Error = err.Error()
+	}
+	return response, nil
+}
+
+func (service serviceWebhook) ListDeliveries(ctx context.Context, userID int, request domainWebhook.ListDeliveriesRequest) (response domainWebhook.ListDeliveriesResponse, err error) {
+	if err = validations.ValidateListDeliveries(ctx, &request); err != nil {
+		return response, err
+	}
+
+	deliveries, err := service.webhookOutbox.ListDelivered(userID, request.Limit, request.Offset)
+	if err != nil {
+		return response, err
+	}
+
+	total, err := service.webhookOutbox.CountDelivered(userID)
+	if err != nil {
+		return response, err
+	}
+
+	response.Data = make([]domainWebhook.DeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		response.Data = append(response.Data, toDeliveryResponse(delivery))
+	}
+	response.Pagination = domainWebhook.PaginationResponse{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+		Total:  total,
+	}
+
+	return response, nil
+}
+
+func (service serviceWebhook) ListDeadLetters(ctx context.Context, userID int, request domainWebhook.ListDeadLettersRequest) (response domainWebhook.ListDeadLettersResponse, err error) {
+	if err = validations.ValidateListDeadLetters(ctx, &request); err != nil {
+		return response, err
+	}
+
+	deadLetters, err := service.webhookOutbox.ListDeadLetters(userID, request.Limit, request.Offset)
+	if err != nil {
+		return response, err
+	}
+
+	total, err := service.webhookOutbox.CountDeadLetters(userID)
+	if err != nil {
+		return response, err
+	}
+
+	response.Data = make([]domainWebhook.DeadLetterResponse, 0, len(deadLetters))
+	for _, deadLetter := range deadLetters {
+		response.Data = append(response.Data, toDeadLetterResponse(deadLetter))
+	}
+	response.Pagination = domainWebhook.PaginationResponse{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+		Total:  total,
+	}
+
+	return response, nil
+}
+
+func (service serviceWebhook) ReplayDeadLetter(_ context.Context, userID int, id int64) error {
+	replayed, err := service.webhookOutbox.ReplayDeadLetter(userID, id)
+	if err != nil {
+		return err
+	}
+	if !replayed {
+		return pkgError.NotFoundError(fmt.Sprintf("dead letter %d not found", id))
+	}
+
+	logrus.Infof("Dead letter %d of user %d queued for redelivery", id, userID)
+	return nil
+}
+
+func (service serviceWebhook) ReplayDeadLetters(ctx context.Context, userID int, request domainWebhook.ReplayDeadLettersRequest) (response domainWebhook.ReplayDeadLettersResponse, err error) {
+	if err = validations.ValidateReplayDeadLetters(ctx, request); err != nil {
+		return response, err
+	}
+
+	response.Replayed, err = service.webhookOutbox.ReplayDeadLetters(userID, request.IDs)
+	if err != nil {
+		return response, err
+	}
+
+	logrus.Infof("%d dead letters of user %d queued for redelivery", response.Replayed, userID)
+	return response, nil
+}
+
+// getWebhook loads a webhook owned by the user or returns a not found error
+func (service serviceWebhook) getWebhook(userID, id int) (*domainWebhook.Webhook, error) {
+	webhook, err := service.webhookRepo.GetByID(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if webhook == nil {
+		return nil, pkgError.NotFoundError(fmt.Sprintf("webhook %d not found", id))
+	}
+	return webhook, nil
+}
+
+func toWebhookResponse(webhook domainWebhook.Webhook) domainWebhook.WebhookResponse {
+	events := webhook.Events
+	if events == nil {
+		events = []string{}
+	}
+	chats := webhook.Chats
+	if chats == nil {
+		chats = []string{}
+	}
+
+	return domainWebhook.WebhookResponse{
+		ID:        webhook.ID,
+		UserID:    webhook.UserID,
+		URL:       webhook.URL,
+		Events:    events,
+		Chats:     chats,
+		Format:    webhook.Format,
+		IsActive:  webhook.IsActive,
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+	}
+}
+
+func toDeliveryResponse(delivery domainWebhook.Delivery) domainWebhook.DeliveryResponse {
+	response := domainWebhook.DeliveryResponse{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookID,
+		Event:          delivery.Event,
+		URL:            delivery.URL,
+		Payload:        json.RawMessage(delivery.Payload),
+		LastStatusCode: delivery.LastStatusCode,
+		CreatedAt:      delivery.CreatedAt,
+		History:        delivery.History,
+	}
+	if delivery.DeliveredAt != nil {
+		response.DeliveredAt = *delivery.DeliveredAt
+	}
+	return response
+}
+
+func toDeadLetterResponse(deadLetter domainWebhook.DeadLetter) domainWebhook.DeadLetterResponse {
+	return domainWebhook.DeadLetterResponse{
+		ID:             deadLetter.ID,
+		WebhookID:      deadLetter.WebhookID,
+		Event:          deadLetter.Event,
+		URL:            deadLetter.URL,
+		Payload:        json.RawMessage(deadLetter.Payload),
+		Attempts:       deadLetter.Attempts,
+		LastError:      deadLetter.LastError,
+		LastStatusCode: deadLetter.LastStatusCode,
+		CreatedAt:      deadLetter.CreatedAt,
+		FailedAt:       deadLetter.FailedAt,
+		History:        deadLetter.History,
+	}
+}
+
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,39 @@
+package validations
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// validateFutureTime rejects RFC3339 timestamps that already passed; malformed ones are reported by validation.Date
+func validateFutureTime(value any) error {
+	timestamp, _ := value.(string)
+	parsed, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return nil
+	}
+	if !parsed.After(time.Now()) {
+		return errors.New("must be in the future")
+	}
+	return nil
+}
+
+func ValidateCreateAPIKey(ctx context.Context, request domainAPIKey.CreateAPIKeyRequest) error {
+	rules := append([]*validation.FieldRules{
+		validation.Field(&request.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&request.ExpiresAt, validation.Date(time.RFC3339), validation.By(validateFutureTime)),
+	}, grantRules(&request.Scopes, &request.AllowedChats)...)
+
+	err := validation.ValidateStructWithContext(ctx, &request, rules...)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
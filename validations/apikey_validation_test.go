@@ -0,0 +1,98 @@
+package validations
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCreateAPIKey(t *testing.T) {
+	type args struct {
+		request domainAPIKey.CreateAPIKeyRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success without expiry",
+			args: args{request: domainAPIKey.CreateAPIKeyRequest{
+				Name: "crm integration",
+			}},
+			err: nil,
+		},
+		{
+			name: "should success with expiry in the future",
+			args: args{request: domainAPIKey.CreateAPIKeyRequest{
+				Name:      "temporary",
+				ExpiresAt: time.Now().Add(24 * time.Hour).Format(time.RFC3339),
+			}},
+			err: nil,
+		},
+		{
+			name: "should success with scopes and allowed chats",
+			args: args{request: domainAPIKey.CreateAPIKeyRequest{
+				Name:         "group bot",
+				Scopes:       []string{"send:text", "group:read"},
+				AllowedChats: []string{"120363402106123456@g.us"},
+			}},
+			err: nil,
+		},
+		{
+			name: "should error without name",
+			args: args{request: domainAPIKey.CreateAPIKeyRequest{}},
+			err:  pkgError.ValidationError("name: cannot be blank."),
+		},
+		{
+			name: "should error with too long name",
+			args: args{request: domainAPIKey.CreateAPIKeyRequest{
+				Name: strings.Repeat("a", 101),
+			}},
+			err: pkgError.ValidationError("name: the length must be between 1 and 100."),
+		},
+		{
+			name: "should error with invalid expiry",
+			args: args{request: domainAPIKey.CreateAPIKeyRequest{
+				Name:      "crm integration",
+				ExpiresAt: "next week",
+			}},
+			err: pkgError.ValidationError("expires_at: must be a valid date."),
+		},
+		{
+			name: "should error with expiry without timezone",
+			args: args{request: domainAPIKey.CreateAPIKeyRequest{
+				Name:      "crm integration",
+				ExpiresAt: "2030-01-01T00:00:00",
+			}},
+			err: pkgError.ValidationError("expires_at: must be a valid date."),
+		},
+		{
+			name: "should error with expiry in the past",
+			args: args{request: domainAPIKey.CreateAPIKeyRequest{
+				Name:      "crm integration",
+				ExpiresAt: "2020-01-01T00:00:00Z",
+			}},
+			err: pkgError.ValidationError("expires_at: must be in the future."),
+		},
+		{
+			name: "should error with unknown scope",
+			args: args{request: domainAPIKey.CreateAPIKeyRequest{
+				Name:   "crm integration",
+				Scopes: []string{"send:everything"},
+			}},
+			err: pkgError.ValidationError("scopes: (0: must be a known scope.)."),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCreateAPIKey(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
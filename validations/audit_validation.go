@@ -0,0 +1,37 @@
+package validations
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	domainAudit "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/audit"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// auditAction accepts a full action like "send.text" or only its area like "send"
+var auditAction = regexp.MustCompile(`^[a-z_]+(\.[a-z_]+)?$`)
+
+func ValidateListAudit(ctx context.Context, request *domainAudit.ListRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 25
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.UserID, validation.Min(0)),
+		validation.Field(&request.Action, validation.Match(auditAction).Error("must be an action like send.text or an area like send")),
+		validation.Field(&request.Result, validation.In(domainAudit.ResultSuccess, domainAudit.ResultFailure)),
+		validation.Field(&request.From, validation.Date(time.RFC3339)),
+		validation.Field(&request.To, validation.Date(time.RFC3339)),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(100)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
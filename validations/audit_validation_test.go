@@ -0,0 +1,73 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainAudit "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/audit"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateListAudit(t *testing.T) {
+	type args struct {
+		request domainAudit.ListRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success without filters",
+			args: args{request: domainAudit.ListRequest{}},
+			err:  nil,
+		},
+		{
+			name: "should success with every filter",
+			args: args{request: domainAudit.ListRequest{
+				UserID:    3,
+				Actor:     "admin",
+				Action:    "send.text",
+				TargetJID: "628123456789@s.whatsapp.net",
+				Result:    domainAudit.ResultFailure,
+				From:      "2025-07-01T00:00:00Z",
+				To:        "2025-08-01T00:00:00+07:00",
+				Limit:     100,
+				Offset:    200,
+			}},
+			err: nil,
+		},
+		{
+			name: "should success with action area",
+			args: args{request: domainAudit.ListRequest{Action: "group"}},
+			err:  nil,
+		},
+		{
+			name: "should error with malformed action",
+			args: args{request: domainAudit.ListRequest{Action: "send.text.%"}},
+			err:  pkgError.ValidationError("action: must be an action like send.text or an area like send."),
+		},
+		{
+			name: "should error with unknown result",
+			args: args{request: domainAudit.ListRequest{Result: "pending"}},
+			err:  pkgError.ValidationError("result: must be a valid value."),
+		},
+		{
+			name: "should error with invalid date range",
+			args: args{request: domainAudit.ListRequest{From: "2025-07-01"}},
+			err:  pkgError.ValidationError("from: must be a valid date."),
+		},
+		{
+			name: "should error with limit above maximum",
+			args: args{request: domainAudit.ListRequest{Limit: 500}},
+			err:  pkgError.ValidationError("limit: must be no greater than 100."),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateListAudit(context.Background(), &tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
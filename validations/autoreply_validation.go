@@ -0,0 +1,94 @@
+package validations
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+// autoReplySender accepts a JID or the user part of a JID such as a phone number
+var autoReplySender = regexp.MustCompile(`^\+?[\w.\-:]+(@[\w.]+)?$`)
+
+var autoReplyClock = regexp.MustCompile(`^([01]\d|2[0-3]):[0-5]\d$`)
+
+var autoReplyMediaTypes = []any{domainAutoReply.ReplyImage, domainAutoReply.ReplyVideo, domainAutoReply.ReplyAudio, domainAutoReply.ReplyDocument}
+
+func validateRegexPattern(value any) error {
+	pattern, _ := value.(string)
+	if _, err := regexp.Compile(pattern); err != nil {
+		return fmt.Errorf("invalid regular expression: %v", err)
+	}
+	return nil
+}
+
+func validateTimezone(value any) error {
+	timezone, _ := value.(string)
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q", timezone)
+	}
+	return nil
+}
+
+// ValidateAutoReplyRule validates a rule after defaults are applied, or after an update is merged,
+// so conditions that depend on each other are always checked together
+func ValidateAutoReplyRule(ctx context.Context, rule domainAutoReply.Rule) error {
+	needsKeywords := rule.MatchType == domainAutoReply.MatchKeyword || rule.MatchType == domainAutoReply.MatchExact
+	isMediaReply := rule.ReplyType != domainAutoReply.ReplyText
+
+	err := validation.ValidateStructWithContext(ctx, &rule,
+		validation.Field(&rule.Name, validation.Length(0, 100)),
+		validation.Field(&rule.Priority, validation.Min(0), validation.Max(1000)),
+		validation.Field(&rule.MatchType, validation.Required, validation.In(domainAutoReply.MatchAny, domainAutoReply.MatchKeyword, domainAutoReply.MatchExact, domainAutoReply.MatchRegex)),
+		validation.Field(&rule.Keywords, validation.When(needsKeywords, validation.Required), validation.Length(0, 100), validation.Each(validation.Required, validation.Length(1, 100))),
+		validation.Field(&rule.Pattern, validation.When(rule.MatchType == domainAutoReply.MatchRegex, validation.Required), validation.Length(0, 500), validation.By(validateRegexPattern)),
+		validation.Field(&rule.ChatType, validation.Required, validation.In(domainAutoReply.ChatTypeAll, domainAutoReply.ChatTypePrivate, domainAutoReply.ChatTypeGroup)),
+		validation.Field(&rule.AllowSenders, validation.Length(0, 500), validation.Each(validation.Required, validation.Match(autoReplySender).Error("must be a JID or a phone number"))),
+		validation.Field(&rule.DenySenders, validation.Length(0, 500), validation.Each(validation.Required, validation.Match(autoReplySender).Error("must be a JID or a phone number"))),
+		validation.Field(&rule.BusinessHours, validation.By(validateBusinessHours)),
+		validation.Field(&rule.CooldownSeconds, validation.Min(0), validation.Max(7*24*60*60)),
+		validation.Field(&rule.ReplyType, validation.Required, validation.In(append([]any{domainAutoReply.ReplyText}, autoReplyMediaTypes...)...)),
+		validation.Field(&rule.ReplyText, validation.When(!isMediaReply, validation.Required), validation.Length(0, 4096)),
+		validation.Field(&rule.MediaURL, validation.When(isMediaReply, validation.Required), is.URL),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func validateBusinessHours(value any) error {
+	hours, _ := value.(*domainAutoReply.BusinessHours)
+	if hours == nil {
+		return nil
+	}
+
+	return validation.ValidateStruct(hours,
+		validation.Field(&hours.Timezone, validation.By(validateTimezone)),
+		validation.Field(&hours.Days, validation.Length(0, 7), validation.Each(validation.Min(0), validation.Max(6))),
+		validation.Field(&hours.Start, validation.Required, validation.Match(autoReplyClock).Error("must be a time in HH:MM format")),
+		validation.Field(&hours.End, validation.Required, validation.Match(autoReplyClock).Error("must be a time in HH:MM format")),
+	)
+}
+
+func ValidateAutoReplyDryRun(ctx context.Context, request domainAutoReply.DryRunRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Message, validation.Length(0, 4096)),
+		validation.Field(&request.Sender, validation.Required, validation.Match(autoReplySender).Error("must be a JID or a phone number")),
+		validation.Field(&request.ChatJID, validation.Match(autoReplySender).Error("must be a JID or a phone number")),
+		validation.Field(&request.Timestamp, validation.Date(time.RFC3339)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
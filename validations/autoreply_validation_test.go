@@ -0,0 +1,196 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAutoReplyRule(t *testing.T) {
+	type args struct {
+		rule domainAutoReply.Rule
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with text reply to every message",
+			args: args{rule: domainAutoReply.Rule{
+				MatchType: domainAutoReply.MatchAny,
+				ChatType:  domainAutoReply.ChatTypeAll,
+				ReplyType: domainAutoReply.ReplyText,
+				ReplyText: "Thanks, we will get back to you soon",
+			}},
+			err: nil,
+		},
+		{
+			name: "should success with keywords, sender lists and business hours",
+			args: args{rule: domainAutoReply.Rule{
+				MatchType:    domainAutoReply.MatchKeyword,
+				Keywords:     []string{"price", "opening hours"},
+				ChatType:     domainAutoReply.ChatTypePrivate,
+				AllowSenders: []string{"628123456789", "+628987654321", "628111111111@s.whatsapp.net"},
+				BusinessHours: &domainAutoReply.BusinessHours{
+					Timezone: "Asia/Jakarta",
+					Days:     []int{1, 2, 3, 4, 5},
+					Start:    "09:00",
+					End:      "17:00",
+					Outside:  true,
+				},
+				CooldownSeconds: 3600,
+				ReplyType:       domainAutoReply.ReplyImage,
+				MediaURL:        "https://example.com/price-list.png",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with keyword match without keywords",
+			args: args{rule: domainAutoReply.Rule{
+				MatchType: domainAutoReply.MatchKeyword,
+				ChatType:  domainAutoReply.ChatTypeAll,
+				ReplyType: domainAutoReply.ReplyText,
+				ReplyText: "hello",
+			}},
+			err: pkgError.ValidationError("keywords: cannot be blank."),
+		},
+		{
+			name: "should error with invalid regex",
+			args: args{rule: domainAutoReply.Rule{
+				MatchType: domainAutoReply.MatchRegex,
+				Pattern:   "(order",
+				ChatType:  domainAutoReply.ChatTypeAll,
+				ReplyType: domainAutoReply.ReplyText,
+				ReplyText: "hello",
+			}},
+			err: pkgError.ValidationError("pattern: invalid regular expression: error parsing regexp: missing closing ): `(order`."),
+		},
+		{
+			name: "should error with unknown chat type",
+			args: args{rule: domainAutoReply.Rule{
+				MatchType: domainAutoReply.MatchAny,
+				ChatType:  "channel",
+				ReplyType: domainAutoReply.ReplyText,
+				ReplyText: "hello",
+			}},
+			err: pkgError.ValidationError("chat_type: must be a valid value."),
+		},
+		{
+			name: "should error with text reply without text",
+			args: args{rule: domainAutoReply.Rule{
+				MatchType: domainAutoReply.MatchAny,
+				ChatType:  domainAutoReply.ChatTypeAll,
+				ReplyType: domainAutoReply.ReplyText,
+			}},
+			err: pkgError.ValidationError("reply_text: cannot be blank."),
+		},
+		{
+			name: "should error with media reply without url",
+			args: args{rule: domainAutoReply.Rule{
+				MatchType: domainAutoReply.MatchAny,
+				ChatType:  domainAutoReply.ChatTypeAll,
+				ReplyType: domainAutoReply.ReplyDocument,
+			}},
+			err: pkgError.ValidationError("media_url: cannot be blank."),
+		},
+		{
+			name: "should error with invalid sender",
+			args: args{rule: domainAutoReply.Rule{
+				MatchType:   domainAutoReply.MatchAny,
+				ChatType:    domainAutoReply.ChatTypeAll,
+				DenySenders: []string{"628123456789", "a,b"},
+				ReplyType:   domainAutoReply.ReplyText,
+				ReplyText:   "hello",
+			}},
+			err: pkgError.ValidationError("deny_senders: (1: must be a JID or a phone number.)."),
+		},
+		{
+			name: "should error with invalid business hours",
+			args: args{rule: domainAutoReply.Rule{
+				MatchType: domainAutoReply.MatchAny,
+				ChatType:  domainAutoReply.ChatTypeAll,
+				BusinessHours: &domainAutoReply.BusinessHours{
+					Timezone: "Mars/Olympus",
+					Start:    "9am",
+					End:      "17:00",
+				},
+				ReplyType: domainAutoReply.ReplyText,
+				ReplyText: "hello",
+			}},
+			err: pkgError.ValidationError(`business_hours: (start: must be a time in HH:MM format; timezone: unknown timezone "Mars/Olympus".).`),
+		},
+		{
+			name: "should error with negative cooldown",
+			args: args{rule: domainAutoReply.Rule{
+				MatchType:       domainAutoReply.MatchAny,
+				ChatType:        domainAutoReply.ChatTypeAll,
+				CooldownSeconds: -1,
+				ReplyType:       domainAutoReply.ReplyText,
+				ReplyText:       "hello",
+			}},
+			err: pkgError.ValidationError("cooldown_seconds: must be no less than 0."),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAutoReplyRule(context.Background(), tt.args.rule)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateAutoReplyDryRun(t *testing.T) {
+	type args struct {
+		request domainAutoReply.DryRunRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with message and sender",
+			args: args{request: domainAutoReply.DryRunRequest{
+				Message: "what is the price?",
+				Sender:  "628123456789",
+			}},
+			err: nil,
+		},
+		{
+			name: "should success with group chat and timestamp",
+			args: args{request: domainAutoReply.DryRunRequest{
+				Message:   "hello",
+				Sender:    "628123456789@s.whatsapp.net",
+				ChatJID:   "120363402106123456@g.us",
+				Timestamp: "2025-07-28T20:00:00+07:00",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error without sender",
+			args: args{request: domainAutoReply.DryRunRequest{
+				Message: "hello",
+			}},
+			err: pkgError.ValidationError("sender: cannot be blank."),
+		},
+		{
+			name: "should error with invalid timestamp",
+			args: args{request: domainAutoReply.DryRunRequest{
+				Message:   "hello",
+				Sender:    "628123456789",
+				Timestamp: "yesterday",
+			}},
+			err: pkgError.ValidationError("timestamp: must be a valid date."),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAutoReplyDryRun(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
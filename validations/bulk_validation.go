@@ -0,0 +1,81 @@
+package validations
+
+import (
+	"context"
+	"fmt"
+
+	domainBulk "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/bulk"
+	domainQueue "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/queue"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+func ValidateCreateBulkJob(ctx context.Context, request domainBulk.CreateJobRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Message, validation.Required),
+		validation.Field(&request.Recipients, validation.Required, validation.Length(1, domainBulk.MaxRecipients)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	variables := utils.TemplateVariables(request.Message)
+	seen := make(map[string]bool, len(request.Recipients))
+	for i, recipient := range request.Recipients {
+		if err := validatePhoneNumber(recipient.Phone); err != nil {
+			return pkgError.ValidationError(fmt.Sprintf("recipient %d: %s", i+1, err.Error()))
+		}
+		if seen[recipient.Phone] {
+			return pkgError.ValidationError(fmt.Sprintf("recipient %d: phone %s is listed more than once", i+1, recipient.Phone))
+		}
+		seen[recipient.Phone] = true
+
+		for _, name := range variables {
+			if _, ok := recipient.Variables[name]; !ok && name != "phone" {
+				return pkgError.ValidationError(fmt.Sprintf("recipient %d: missing variable %s", i+1, name))
+			}
+		}
+	}
+
+	return nil
+}
+
+func ValidateListBulkJobs(ctx context.Context, request *domainBulk.ListJobsRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 25
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(100)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateListBulkRecipients(ctx context.Context, request *domainBulk.ListRecipientsRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 25
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Status, validation.In(domainQueue.StatusPending, domainQueue.StatusSending,
+			domainQueue.StatusSent, domainQueue.StatusFailed, domainQueue.StatusNotOnWhatsApp, domainQueue.StatusCancelled)),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(100)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainBulk "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/bulk"
+	domainQueue "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/queue"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCreateBulkJob(t *testing.T) {
+	type args struct {
+		request domainBulk.CreateJobRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with recipients and variables",
+			args: args{request: domainBulk.CreateJobRequest{
+				Message: "Hi {{name}}, this message went to {{phone}}",
+				Recipients: []domainBulk.Recipient{
+					{Phone: "6289685028129@s.whatsapp.net", Variables: map[string]string{"name": "Budi"}},
+					{Phone: "6289685028130@s.whatsapp.net", Variables: map[string]string{"name": "Sari"}},
+				},
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty message",
+			args: args{request: domainBulk.CreateJobRequest{
+				Recipients: []domainBulk.Recipient{{Phone: "6289685028129@s.whatsapp.net"}},
+			}},
+			err: pkgError.ValidationError("message: cannot be blank."),
+		},
+		{
+			name: "should error without recipients",
+			args: args{request: domainBulk.CreateJobRequest{Message: "Hello"}},
+			err:  pkgError.ValidationError("recipients: cannot be blank."),
+		},
+		{
+			name: "should error with too many recipients",
+			args: args{request: domainBulk.CreateJobRequest{
+				Message:    "Hello",
+				Recipients: make([]domainBulk.Recipient, domainBulk.MaxRecipients+1),
+			}},
+			err: pkgError.ValidationError("recipients: the length must be between 1 and 1000."),
+		},
+		{
+			name: "should error with local phone format",
+			args: args{request: domainBulk.CreateJobRequest{
+				Message:    "Hello",
+				Recipients: []domainBulk.Recipient{{Phone: "6289685028129@s.whatsapp.net"}, {Phone: "089685028129@s.whatsapp.net"}},
+			}},
+			err: pkgError.ValidationError("recipient 2: phone number must be in international format (should not start with 0). For Indonesian numbers, use 62xxx format instead of 08xxx"),
+		},
+		{
+			name: "should error with duplicate phone",
+			args: args{request: domainBulk.CreateJobRequest{
+				Message:    "Hello",
+				Recipients: []domainBulk.Recipient{{Phone: "6289685028129@s.whatsapp.net"}, {Phone: "6289685028129@s.whatsapp.net"}},
+			}},
+			err: pkgError.ValidationError("recipient 2: phone 6289685028129@s.whatsapp.net is listed more than once"),
+		},
+		{
+			name: "should error with missing variable",
+			args: args{request: domainBulk.CreateJobRequest{
+				Message:    "Hi {{name}}",
+				Recipients: []domainBulk.Recipient{{Phone: "6289685028129@s.whatsapp.net", Variables: map[string]string{"nama": "Budi"}}},
+			}},
+			err: pkgError.ValidationError("recipient 1: missing variable name"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCreateBulkJob(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateListBulkRecipients(t *testing.T) {
+	type args struct {
+		request domainBulk.ListRecipientsRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success without filters",
+			args: args{request: domainBulk.ListRecipientsRequest{}},
+			err:  nil,
+		},
+		{
+			name: "should success with not on whatsapp filter",
+			args: args{request: domainBulk.ListRecipientsRequest{Status: domainQueue.StatusNotOnWhatsApp}},
+			err:  nil,
+		},
+		{
+			name: "should error with unknown status",
+			args: args{request: domainBulk.ListRecipientsRequest{Status: "delivered"}},
+			err:  pkgError.ValidationError("status: must be a valid value."),
+		},
+		{
+			name: "should error with limit above maximum",
+			args: args{request: domainBulk.ListRecipientsRequest{Limit: 101}},
+			err:  pkgError.ValidationError("limit: must be no greater than 100."),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateListBulkRecipients(context.Background(), &tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
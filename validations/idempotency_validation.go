@@ -0,0 +1,24 @@
+package validations
+
+import (
+	"fmt"
+
+	domainIdempotency "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/idempotency"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+// ValidateIdempotencyKey accepts up to MaxKeyLength visible ASCII characters, such as a UUID
+func ValidateIdempotencyKey(key string) error {
+	if key == "" {
+		return pkgError.ValidationError("Idempotency-Key: cannot be blank.")
+	}
+	if len(key) > domainIdempotency.MaxKeyLength {
+		return pkgError.ValidationError(fmt.Sprintf("Idempotency-Key: the length must be no more than %d.", domainIdempotency.MaxKeyLength))
+	}
+	for _, char := range key {
+		if char < '!' || char > '~' {
+			return pkgError.ValidationError("Idempotency-Key: must contain only visible ASCII characters.")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package validations
+
+import (
+	"strings"
+	"testing"
+
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateIdempotencyKey(t *testing.T) {
+	type args struct {
+		key string
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with uuid",
+			args: args{key: "5f0c3a52-8d7e-4c1b-9a63-1f2e4b7d9c10"},
+			err:  nil,
+		},
+		{
+			name: "should success with maximum length",
+			args: args{key: strings.Repeat("k", 255)},
+			err:  nil,
+		},
+		{
+			name: "should error with blank key",
+			args: args{key: ""},
+			err:  pkgError.ValidationError("Idempotency-Key: cannot be blank."),
+		},
+		{
+			name: "should error with key above maximum length",
+			args: args{key: strings.Repeat("k", 256)},
+			err:  pkgError.ValidationError("Idempotency-Key: the length must be no more than 255."),
+		},
+		{
+			name: "should error with spaces",
+			args: args{key: "order 12"},
+			err:  pkgError.ValidationError("Idempotency-Key: must contain only visible ASCII characters."),
+		},
+		{
+			name: "should error with non ascii characters",
+			args: args{key: "pesanan-é"},
+			err:  pkgError.ValidationError("Idempotency-Key: must contain only visible ASCII characters."),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateIdempotencyKey(tt.args.key)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
@@ -89,3 +89,15 @@ func ValidateStarMessage(ctx context.Context, request domainMessage.StarRequest)
 
 	return nil
 }
+
+func ValidateDownloadMedia(ctx context.Context, request domainMessage.DownloadMediaRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.MessageID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
@@ -292,3 +292,36 @@ func TestValidateStarMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateDownloadMedia(t *testing.T) {
+	type args struct {
+		request domainMessage.DownloadMediaRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with message id",
+			args: args{request: domainMessage.DownloadMediaRequest{
+				MessageID: "3EB0B430B6F8F1D0E053AC120E0A9E5C",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty message id",
+			args: args{request: domainMessage.DownloadMediaRequest{
+				MessageID: "",
+			}},
+			err: pkgError.ValidationError("message_id: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDownloadMedia(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
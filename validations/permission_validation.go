@@ -0,0 +1,40 @@
+package validations
+
+import (
+	"context"
+	"errors"
+	"regexp"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// grantChat accepts a JID or the user part of a JID such as a phone number
+var grantChat = regexp.MustCompile(`^\+?[\w.\-:]+(@[\w.]+)?$`)
+
+func validateScope(value any) error {
+	scope, _ := value.(string)
+	if !permission.IsValid(scope) {
+		return errors.New("must be a known scope")
+	}
+	return nil
+}
+
+// grantRules validates scopes and allowed chats wherever a grant is accepted
+func grantRules(scopes, allowedChats any) []*validation.FieldRules {
+	return []*validation.FieldRules{
+		validation.Field(scopes, validation.Length(0, 100), validation.Each(validation.Required, validation.By(validateScope))),
+		validation.Field(allowedChats, validation.Length(0, 500), validation.Each(validation.Required, validation.Match(grantChat).Error("must be a JID or a phone number"))),
+	}
+}
+
+// ValidateGrant validates the scopes and allowed chats of a user
+func ValidateGrant(ctx context.Context, grant permission.Grant) error {
+	err := validation.ValidateStructWithContext(ctx, &grant, grantRules(&grant.Scopes, &grant.AllowedChats)...)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/permission"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateGrant(t *testing.T) {
+	type args struct {
+		grant permission.Grant
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success without restrictions",
+			args: args{grant: permission.Grant{}},
+			err:  nil,
+		},
+		{
+			name: "should success with scopes, wildcards and chats",
+			args: args{grant: permission.Grant{
+				Scopes:       permission.List{permission.SendText, "group:*", permission.All},
+				AllowedChats: permission.List{"628123456789", "120363402106123456@g.us"},
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with unknown scope",
+			args: args{grant: permission.Grant{
+				Scopes: permission.List{permission.ChatRead, "chat:delete"},
+			}},
+			err: pkgError.ValidationError("scopes: (1: must be a known scope.)."),
+		},
+		{
+			name: "should error with wildcard of unknown resource",
+			args: args{grant: permission.Grant{
+				Scopes: permission.List{"contact:*"},
+			}},
+			err: pkgError.ValidationError("scopes: (0: must be a known scope.)."),
+		},
+		{
+			name: "should error with invalid chat",
+			args: args{grant: permission.Grant{
+				AllowedChats: permission.List{"a,b"},
+			}},
+			err: pkgError.ValidationError("allowed_chats: (0: must be a JID or a phone number.)."),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGrant(context.Background(), tt.args.grant)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
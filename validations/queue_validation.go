@@ -0,0 +1,29 @@
+package validations
+
+import (
+	"context"
+
+	domainQueue "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/queue"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+func ValidateListQueue(ctx context.Context, request *domainQueue.ListRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 25
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Status, validation.In(domainQueue.StatusPending, domainQueue.StatusSending,
+			domainQueue.StatusSent, domainQueue.StatusFailed, domainQueue.StatusNotOnWhatsApp, domainQueue.StatusCancelled)),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(100)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
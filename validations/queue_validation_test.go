@@ -0,0 +1,53 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainQueue "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/queue"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateListQueue(t *testing.T) {
+	type args struct {
+		request domainQueue.ListRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success without filters",
+			args: args{request: domainQueue.ListRequest{}},
+			err:  nil,
+		},
+		{
+			name: "should success with status filter",
+			args: args{request: domainQueue.ListRequest{Status: domainQueue.StatusPending, Limit: 100, Offset: 50}},
+			err:  nil,
+		},
+		{
+			name: "should error with unknown status",
+			args: args{request: domainQueue.ListRequest{Status: "delivered"}},
+			err:  pkgError.ValidationError("status: must be a valid value."),
+		},
+		{
+			name: "should error with limit above maximum",
+			args: args{request: domainQueue.ListRequest{Limit: 101}},
+			err:  pkgError.ValidationError("limit: must be no greater than 100."),
+		},
+		{
+			name: "should error with negative offset",
+			args: args{request: domainQueue.ListRequest{Offset: -1}},
+			err:  pkgError.ValidationError("offset: must be no less than 0."),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateListQueue(context.Background(), &tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
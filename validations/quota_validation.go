@@ -0,0 +1,25 @@
+package validations
+
+import (
+	"context"
+
+	domainQuota "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/quota"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+func ValidateLimits(ctx context.Context, request domainQuota.Limits) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.MessagesPerMinute, validation.Min(0)),
+		validation.Field(&request.MessagesPerHour, validation.Min(0)),
+		validation.Field(&request.MessagesPerDay, validation.Min(0)),
+		validation.Field(&request.NewContactsPerDay, validation.Min(0)),
+		validation.Field(&request.MediaBytesPerDay, validation.Min(int64(0))),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
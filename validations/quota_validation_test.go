@@ -0,0 +1,54 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainQuota "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/quota"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateLimits(t *testing.T) {
+	type args struct {
+		request domainQuota.Limits
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success without limits",
+			args: args{request: domainQuota.Limits{}},
+			err:  nil,
+		},
+		{
+			name: "should success with every limit",
+			args: args{request: domainQuota.Limits{
+				MessagesPerMinute: 10,
+				MessagesPerHour:   200,
+				MessagesPerDay:    1000,
+				NewContactsPerDay: 50,
+				MediaBytesPerDay:  100 * 1024 * 1024,
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with negative message limit",
+			args: args{request: domainQuota.Limits{MessagesPerHour: -1}},
+			err:  pkgError.ValidationError("messages_per_hour: must be no less than 0."),
+		},
+		{
+			name: "should error with negative media limit",
+			args: args{request: domainQuota.Limits{MediaBytesPerDay: -1024}},
+			err:  pkgError.ValidationError("media_bytes_per_day: must be no less than 0."),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLimits(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package validations
+
+import (
+	"context"
+	"fmt"
+	"time"
+	_ "time/tzdata" // Time zones must resolve on hosts without a zoneinfo database
+
+	domainSchedule "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/schedule"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// validateScheduleTiming requires either a future send_at or a cron expression, in a known time zone
+func validateScheduleTiming(sendAt, cron, timezone string) error {
+	if sendAt != "" && cron != "" {
+		return pkgError.ValidationError("send_at and cron cannot be used together")
+	}
+	if sendAt == "" && cron == "" {
+		return pkgError.ValidationError("send_at or cron is required")
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return pkgError.ValidationError(fmt.Sprintf("timezone: unknown time zone %s", timezone))
+	}
+
+	if sendAt != "" {
+		at, err := utils.ParseLocalTime(sendAt, location)
+		if err != nil {
+			return pkgError.ValidationError(fmt.Sprintf("send_at: %v", err))
+		}
+		if !at.After(time.Now()) {
+			return pkgError.ValidationError("send_at: must be in the future")
+		}
+		return nil
+	}
+
+	schedule, err := utils.ParseCron(cron)
+	if err != nil {
+		return pkgError.ValidationError(fmt.Sprintf("cron: %v", err))
+	}
+	if schedule.Next(time.Now().In(location)).IsZero() {
+		return pkgError.ValidationError("cron: never matches")
+	}
+	return nil
+}
+
+func ValidateSendSchedule(_ context.Context, request domainSend.Schedule) error {
+	return validateScheduleTiming(request.SendAt, request.Cron, request.Timezone)
+}
+
+func ValidateUpdateSchedule(_ context.Context, request domainSchedule.UpdateRequest) error {
+	return validateScheduleTiming(request.SendAt, request.Cron, request.Timezone)
+}
+
+func ValidateListSchedules(ctx context.Context, request *domainSchedule.ListRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 25
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Status, validation.In(domainSchedule.StatusActive, domainSchedule.StatusRunning, domainSchedule.StatusCompleted,
+			domainSchedule.StatusFailed, domainSchedule.StatusCancelled)),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(100)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
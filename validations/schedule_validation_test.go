@@ -0,0 +1,108 @@
+package validations
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domainSchedule "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/schedule"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSendSchedule(t *testing.T) {
+	type args struct {
+		request domainSend.Schedule
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with future send_at",
+			args: args{request: domainSend.Schedule{SendAt: time.Now().Add(time.Hour).Format(time.RFC3339)}},
+			err:  nil,
+		},
+		{
+			name: "should success with cron and timezone",
+			args: args{request: domainSend.Schedule{Cron: "0 9 * * mon-fri", Timezone: "Asia/Jakarta"}},
+			err:  nil,
+		},
+		{
+			name: "should error with both send_at and cron",
+			args: args{request: domainSend.Schedule{SendAt: "2099-01-01 09:00", Cron: "0 9 * * *"}},
+			err:  pkgError.ValidationError("send_at and cron cannot be used together"),
+		},
+		{
+			name: "should error with only timezone",
+			args: args{request: domainSend.Schedule{Timezone: "Asia/Jakarta"}},
+			err:  pkgError.ValidationError("send_at or cron is required"),
+		},
+		{
+			name: "should error with unknown timezone",
+			args: args{request: domainSend.Schedule{Cron: "0 9 * * *", Timezone: "Mars/Olympus"}},
+			err:  pkgError.ValidationError("timezone: unknown time zone Mars/Olympus"),
+		},
+		{
+			name: "should error with past send_at",
+			args: args{request: domainSend.Schedule{SendAt: "2020-01-01T09:00:00Z"}},
+			err:  pkgError.ValidationError("send_at: must be in the future"),
+		},
+		{
+			name: "should error with malformed send_at",
+			args: args{request: domainSend.Schedule{SendAt: "tomorrow"}},
+			err:  pkgError.ValidationError(`send_at: invalid time "tomorrow", use RFC3339 like 2025-08-01T09:30:00+07:00 or 2025-08-01 09:30`),
+		},
+		{
+			name: "should error with malformed cron",
+			args: args{request: domainSend.Schedule{Cron: "0 25 * * *"}},
+			err:  pkgError.ValidationError("cron: hour: value 25 out of range 0-23"),
+		},
+		{
+			name: "should error with cron that never matches",
+			args: args{request: domainSend.Schedule{Cron: "0 0 31 2 *"}},
+			err:  pkgError.ValidationError("cron: never matches"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSendSchedule(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateListSchedules(t *testing.T) {
+	type args struct {
+		request domainSchedule.ListRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with status filter",
+			args: args{request: domainSchedule.ListRequest{Status: domainSchedule.StatusActive}},
+			err:  nil,
+		},
+		{
+			name: "should error with unknown status",
+			args: args{request: domainSchedule.ListRequest{Status: "pending"}},
+			err:  pkgError.ValidationError("status: must be a valid value."),
+		},
+		{
+			name: "should error with negative offset",
+			args: args{request: domainSchedule.ListRequest{Offset: -1}},
+			err:  pkgError.ValidationError("offset: must be no less than 0."),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateListSchedules(context.Background(), &tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
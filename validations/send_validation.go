@@ -49,6 +49,19 @@ func validatePhoneNumber(phone string) error {
 	return nil
 }
 
+// validateReply validates the mentions of a send request. An empty reply message ID means no reply.
+func validateReply(request domainSend.ReplyRequest) error {
+	for _, mention := range request.Mentions {
+		if mention == "" {
+			return pkgError.ValidationError("mentions: cannot contain blank values")
+		}
+		if err := validatePhoneNumber(mention); err != nil {
+			return pkgError.ValidationError(fmt.Sprintf("mentions: %s", err.Error()))
+		}
+	}
+	return nil
+}
+
 func ValidateSendMessage(ctx context.Context, request domainSend.MessageRequest) error {
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.Phone, validation.Required),
@@ -64,6 +77,10 @@ func ValidateSendMessage(ctx context.Context, request domainSend.MessageRequest)
 		return err
 	}
 
+	if err := validateReply(request.ReplyRequest); err != nil {
+		return err
+	}
+
 	// Custom validation for optional Duration
 	if err := validateDuration(request.Duration); err != nil {
 		return err
@@ -85,6 +102,10 @@ func ValidateSendImage(ctx context.Context, request domainSend.ImageRequest) err
 		return err
 	}
 
+	if err := validateReply(request.ReplyRequest); err != nil {
+		return err
+	}
+
 	if request.Image == nil && (request.ImageURL == nil || *request.ImageURL == "") {
 		return pkgError.ValidationError("either Image or ImageURL must be provided")
 	}
@@ -120,6 +141,57 @@ func ValidateSendImage(ctx context.Context, request domainSend.ImageRequest) err
 	return nil
 }
 
+func ValidateSendSticker(ctx context.Context, request domainSend.StickerRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Phone, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	// Custom validation for phone number format
+	if err := validatePhoneNumber(request.Phone); err != nil {
+		return err
+	}
+
+	if err := validateReply(request.ReplyRequest); err != nil {
+		return err
+	}
+
+	if request.Sticker == nil && (request.StickerURL == nil || *request.StickerURL == "") {
+		return pkgError.ValidationError("either Sticker or StickerURL must be provided")
+	}
+
+	if request.Sticker != nil {
+		availableMimes := map[string]bool{
+			"image/jpeg": true,
+			"image/jpg":  true,
+			"image/png":  true,
+			"image/webp": true,
+			"image/gif":  true,
+		}
+
+		if !availableMimes[request.Sticker.Header.Get("Content-Type")] {
+			return pkgError.ValidationError("your sticker is not allowed. please use jpg/jpeg/png/webp/gif")
+		}
+	}
+
+	if request.StickerURL != nil && *request.StickerURL != "" {
+		err := validation.Validate(*request.StickerURL, is.URL)
+		if err != nil {
+			return pkgError.ValidationError("StickerURL must be a valid URL")
+		}
+	}
+
+	// Validate duration
+	if err := validateDuration(request.Duration); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func ValidateSendFile(ctx context.Context, request domainSend.FileRequest) error {
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.Phone, validation.Required),
@@ -135,6 +207,10 @@ func ValidateSendFile(ctx context.Context, request domainSend.FileRequest) error
 		return err
 	}
 
+	if err := validateReply(request.ReplyRequest); err != nil {
+		return err
+	}
+
 	if request.File.Size > config.WhatsappSettingMaxFileSize { // 10MB
 		maxSizeString := humanize.Bytes(uint64(config.WhatsappSettingMaxFileSize))
 		return pkgError.ValidationError(fmt.Sprintf("max file upload is %s, please upload in cloud and send via text if your file is higher than %s", maxSizeString, maxSizeString))
@@ -162,6 +238,10 @@ func ValidateSendVideo(ctx context.Context, request domainSend.VideoRequest) err
 		return err
 	}
 
+	if err := validateReply(request.ReplyRequest); err != nil {
+		return err
+	}
+
 	// Ensure at least one of Video or VideoURL is provided
 	if request.Video == nil && (request.VideoURL == nil || *request.VideoURL == "") {
 		return pkgError.ValidationError("either Video or VideoURL must be provided")
@@ -220,6 +300,10 @@ func ValidateSendContact(ctx context.Context, request domainSend.ContactRequest)
 		return err
 	}
 
+	if err := validateReply(request.ReplyRequest); err != nil {
+		return err
+	}
+
 	// Custom validation for contact phone number format
 	if err := validatePhoneNumber(request.ContactPhone); err != nil {
 		return pkgError.ValidationError("contact " + err.Error())
@@ -248,6 +332,10 @@ func ValidateSendLink(ctx context.Context, request domainSend.LinkRequest) error
 		return err
 	}
 
+	if err := validateReply(request.ReplyRequest); err != nil {
+		return err
+	}
+
 	if err := validateDuration(request.Duration); err != nil {
 		return err
 	}
@@ -271,6 +359,10 @@ func ValidateSendLocation(ctx context.Context, request domainSend.LocationReques
 		return err
 	}
 
+	if err := validateReply(request.ReplyRequest); err != nil {
+		return err
+	}
+
 	if err := validateDuration(request.Duration); err != nil {
 		return err
 	}
@@ -292,6 +384,10 @@ func ValidateSendAudio(ctx context.Context, request domainSend.AudioRequest) err
 		return err
 	}
 
+	if err := validateReply(request.ReplyRequest); err != nil {
+		return err
+	}
+
 	// Ensure at least one of Audio or AudioURL is provided
 	if request.Audio == nil && (request.AudioURL == nil || *request.AudioURL == "") {
 		return pkgError.ValidationError("either Audio or AudioURL must be provided")
@@ -379,6 +475,10 @@ func ValidateSendPoll(ctx context.Context, request domainSend.PollRequest) error
 		return err
 	}
 
+	if err := validateReply(request.ReplyRequest); err != nil {
+		return err
+	}
+
 	if err := validateDuration(request.Duration); err != nil {
 		return err
 	}
@@ -395,6 +495,34 @@ func ValidateSendPoll(ctx context.Context, request domainSend.PollRequest) error
 	return nil
 }
 
+func ValidateForwardMessage(ctx context.Context, request domainSend.ForwardRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.MessageID, validation.Required),
+		validation.Field(&request.Phones, validation.Required, validation.Length(1, domainSend.MaxForwardTargets)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	seen := make(map[string]bool, len(request.Phones))
+	for _, phone := range request.Phones {
+		if err := validatePhoneNumber(phone); err != nil {
+			return err
+		}
+		if seen[phone] {
+			return pkgError.ValidationError(fmt.Sprintf("phones: %s is listed twice", phone))
+		}
+		seen[phone] = true
+	}
+
+	if err := validateDuration(request.Duration); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func ValidateSendPresence(ctx context.Context, request domainSend.PresenceRequest) error {
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.Type, validation.In("available", "unavailable")),
@@ -2,6 +2,7 @@ package validations
 
 import (
 	"context"
+	"fmt"
 	"mime/multipart"
 	"testing"
 
@@ -130,6 +131,103 @@ func TestValidateSendImage(t *testing.T) {
 	}
 }
 
+func TestValidateSendSticker(t *testing.T) {
+	sticker := &multipart.FileHeader{
+		Filename: "sample-sticker.gif",
+		Size:     100,
+		Header:   map[string][]string{"Content-Type": {"image/gif"}},
+	}
+
+	type args struct {
+		request domainSend.StickerRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with normal condition",
+			args: args{request: domainSend.StickerRequest{
+				BaseRequest: domainSend.BaseRequest{
+					Phone: "1728937129312@s.whatsapp.net",
+				},
+				Sticker: sticker,
+			}},
+			err: nil,
+		},
+		{
+			name: "should success with sticker URL",
+			args: args{request: domainSend.StickerRequest{
+				BaseRequest: domainSend.BaseRequest{
+					Phone: "1728937129312@s.whatsapp.net",
+				},
+				StickerURL: func() *string { s := "https://example.com/sticker.webp"; return &s }(),
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty phone",
+			args: args{request: domainSend.StickerRequest{
+				Sticker: sticker,
+			}},
+			err: pkgError.ValidationError("phone: cannot be blank."),
+		},
+		{
+			name: "should error without sticker",
+			args: args{request: domainSend.StickerRequest{
+				BaseRequest: domainSend.BaseRequest{
+					Phone: "1728937129312@s.whatsapp.net",
+				},
+				StickerURL: func() *string { s := ""; return &s }(),
+			}},
+			err: pkgError.ValidationError("either Sticker or StickerURL must be provided"),
+		},
+		{
+			name: "should error with invalid sticker type",
+			args: args{request: domainSend.StickerRequest{
+				BaseRequest: domainSend.BaseRequest{
+					Phone: "1728937129312@s.whatsapp.net",
+				},
+				Sticker: &multipart.FileHeader{
+					Filename: "sample-video.mp4",
+					Size:     100,
+					Header:   map[string][]string{"Content-Type": {"video/mp4"}},
+				},
+			}},
+			err: pkgError.ValidationError("your sticker is not allowed. please use jpg/jpeg/png/webp/gif"),
+		},
+		{
+			name: "should error with invalid sticker URL",
+			args: args{request: domainSend.StickerRequest{
+				BaseRequest: domainSend.BaseRequest{
+					Phone: "1728937129312@s.whatsapp.net",
+				},
+				StickerURL: func() *string { s := "not a url"; return &s }(),
+			}},
+			err: pkgError.ValidationError("StickerURL must be a valid URL"),
+		},
+		{
+			name: "should error with negative duration",
+			args: args{request: domainSend.StickerRequest{
+				BaseRequest: domainSend.BaseRequest{
+					Phone:    "1728937129312@s.whatsapp.net",
+					Duration: func() *int { d := -1; return &d }(),
+				},
+				Sticker: sticker,
+			}},
+			err: pkgError.ValidationError("duration must be between 0 and 4294967295 seconds (0 means no expiry)"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSendSticker(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
 func TestValidateSendFile(t *testing.T) {
 	file := &multipart.FileHeader{
 		Filename: "sample-image.png",
@@ -1106,3 +1204,158 @@ func TestValidateSendAudio_WithDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateSendLocation_WithReply(t *testing.T) {
+	type args struct {
+		request domainSend.LocationRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with reply message ID and mentions",
+			args: args{request: domainSend.LocationRequest{
+				BaseRequest: domainSend.BaseRequest{
+					Phone: "120363024512399999@g.us",
+				},
+				ReplyRequest: domainSend.ReplyRequest{
+					ReplyMessageID: func() *string { s := "3EB0B430B6F8F1D0E053AC120E0A9E5C"; return &s }(),
+					Mentions:       []string{"6289685028129", "+6289685028130", "6289685028131@s.whatsapp.net"},
+				},
+				Latitude:  "-7.797068",
+				Longitude: "110.370529",
+			}},
+			err: nil,
+		},
+		{
+			name: "should success with empty reply message ID",
+			args: args{request: domainSend.LocationRequest{
+				BaseRequest: domainSend.BaseRequest{
+					Phone: "1728937129312@s.whatsapp.net",
+				},
+				ReplyRequest: domainSend.ReplyRequest{
+					ReplyMessageID: func() *string { s := ""; return &s }(),
+				},
+				Latitude:  "-7.797068",
+				Longitude: "110.370529",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with blank mention",
+			args: args{request: domainSend.LocationRequest{
+				BaseRequest: domainSend.BaseRequest{
+					Phone: "1728937129312@s.whatsapp.net",
+				},
+				ReplyRequest: domainSend.ReplyRequest{
+					Mentions: []string{"6289685028129", ""},
+				},
+				Latitude:  "-7.797068",
+				Longitude: "110.370529",
+			}},
+			err: pkgError.ValidationError("mentions: cannot contain blank values"),
+		},
+		{
+			name: "should error with local format mention",
+			args: args{request: domainSend.LocationRequest{
+				BaseRequest: domainSend.BaseRequest{
+					Phone: "1728937129312@s.whatsapp.net",
+				},
+				ReplyRequest: domainSend.ReplyRequest{
+					Mentions: []string{"089685028129"},
+				},
+				Latitude:  "-7.797068",
+				Longitude: "110.370529",
+			}},
+			err: pkgError.ValidationError("mentions: phone number must be in international format (should not start with 0). For Indonesian numbers, use 62xxx format instead of 08xxx"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSendLocation(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateForwardMessage(t *testing.T) {
+	tooMany := make([]string, domainSend.MaxForwardTargets+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("62896850%05d", i)
+	}
+
+	type args struct {
+		request domainSend.ForwardRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with one phone and one group",
+			args: args{request: domainSend.ForwardRequest{
+				MessageID: "3EB0B430B6F8F1D0E053AC120E0A9E5C",
+				Phones:    []string{"6289685028129", "120363024512399999@g.us"},
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty message ID",
+			args: args{request: domainSend.ForwardRequest{
+				Phones: []string{"6289685028129"},
+			}},
+			err: pkgError.ValidationError("message_id: cannot be blank."),
+		},
+		{
+			name: "should error without phones",
+			args: args{request: domainSend.ForwardRequest{
+				MessageID: "3EB0B430B6F8F1D0E053AC120E0A9E5C",
+			}},
+			err: pkgError.ValidationError("phones: cannot be blank."),
+		},
+		{
+			name: "should error with too many phones",
+			args: args{request: domainSend.ForwardRequest{
+				MessageID: "3EB0B430B6F8F1D0E053AC120E0A9E5C",
+				Phones:    tooMany,
+			}},
+			err: pkgError.ValidationError("phones: the length must be between 1 and 50."),
+		},
+		{
+			name: "should error with local format phone",
+			args: args{request: domainSend.ForwardRequest{
+				MessageID: "3EB0B430B6F8F1D0E053AC120E0A9E5C",
+				Phones:    []string{"6289685028129", "089685028129"},
+			}},
+			err: pkgError.ValidationError("phone number must be in international format (should not start with 0). For Indonesian numbers, use 62xxx format instead of 08xxx"),
+		},
+		{
+			name: "should error with duplicate phone",
+			args: args{request: domainSend.ForwardRequest{
+				MessageID: "3EB0B430B6F8F1D0E053AC120E0A9E5C",
+				Phones:    []string{"6289685028129", "6289685028129"},
+			}},
+			err: pkgError.ValidationError("phones: 6289685028129 is listed twice"),
+		},
+		{
+			name: "should error with invalid duration",
+			args: args{request: domainSend.ForwardRequest{
+				MessageID: "3EB0B430B6F8F1D0E053AC120E0A9E5C",
+				Phones:    []string{"6289685028129"},
+				Duration:  func() *int { d := -1; return &d }(),
+			}},
+			err: pkgError.ValidationError("duration must be between 0 and 4294967295 seconds (0 means no expiry)"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateForwardMessage(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package validations
+
+import (
+	"context"
+	"fmt"
+	"mime/multipart"
+	"regexp"
+	"strings"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/dustin/go-humanize"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+var templateName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// ValidateTemplate checks a template about to be stored, with the attachment uploaded for it if any
+func ValidateTemplate(ctx context.Context, template domainTemplate.Template, attachment *multipart.FileHeader) error {
+	err := validation.ValidateStructWithContext(ctx, &template,
+		validation.Field(&template.Name, validation.Required, validation.Length(1, domainTemplate.MaxNameLength),
+			validation.Match(templateName).Error("must start with a letter or digit and contain only letters, digits, _, . and -")),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	if strings.TrimSpace(template.Body) == "" && template.AttachmentName == "" {
+		return pkgError.ValidationError("body or attachment is required")
+	}
+
+	if attachment != nil && attachment.Size > config.WhatsappSettingMaxVideoSize {
+		maxSizeString := humanize.Bytes(uint64(config.WhatsappSettingMaxVideoSize))
+		return pkgError.ValidationError(fmt.Sprintf("attachment: max upload is %s", maxSizeString))
+	}
+
+	return validateDuration(template.Duration)
+}
+
+// ValidateTemplateVariables requires a value for every placeholder of a template body, phone is filled in by the server
+func ValidateTemplateVariables(body string, variables map[string]string) error {
+	var missing []string
+	for _, name := range utils.TemplateVariables(body) {
+		if _, ok := variables[name]; !ok && name != "phone" {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return pkgError.ValidationError(fmt.Sprintf("variables: missing %s", strings.Join(missing, ", ")))
+	}
+
+	return nil
+}
+
+func ValidateListTemplates(ctx context.Context, request *domainTemplate.ListRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 25
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(100)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
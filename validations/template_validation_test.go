@@ -0,0 +1,139 @@
+package validations
+
+import (
+	"context"
+	"mime/multipart"
+	"testing"
+
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTemplate(t *testing.T) {
+	negative := -1
+	type args struct {
+		template   domainTemplate.Template
+		attachment *multipart.FileHeader
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with body",
+			args: args{template: domainTemplate.Template{Name: "order_shipped", Body: "Hi {{name}}, order {{order}} is on its way"}},
+			err:  nil,
+		},
+		{
+			name: "should success with attachment only",
+			args: args{
+				template:   domainTemplate.Template{Name: "catalog.v2", AttachmentName: "catalog.pdf"},
+				attachment: &multipart.FileHeader{Filename: "catalog.pdf", Size: 1024},
+			},
+			err: nil,
+		},
+		{
+			name: "should error with empty name",
+			args: args{template: domainTemplate.Template{Body: "Hello"}},
+			err:  pkgError.ValidationError("name: cannot be blank."),
+		},
+		{
+			name: "should error with invalid name",
+			args: args{template: domainTemplate.Template{Name: "order shipped", Body: "Hello"}},
+			err:  pkgError.ValidationError("name: must start with a letter or digit and contain only letters, digits, _, . and -."),
+		},
+		{
+			name: "should error without body and attachment",
+			args: args{template: domainTemplate.Template{Name: "empty", Body: "  "}},
+			err:  pkgError.ValidationError("body or attachment is required"),
+		},
+		{
+			name: "should error with too large attachment",
+			args: args{
+				template:   domainTemplate.Template{Name: "video", AttachmentName: "video.mp4"},
+				attachment: &multipart.FileHeader{Filename: "video.mp4", Size: 200000000},
+			},
+			err: pkgError.ValidationError("attachment: max upload is 100 MB"),
+		},
+		{
+			name: "should error with negative duration",
+			args: args{template: domainTemplate.Template{Name: "expiring", Body: "Hello", Duration: &negative}},
+			err:  pkgError.ValidationError("duration must be between 0 and 4294967295 seconds (0 means no expiry)"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTemplate(context.Background(), tt.args.template, tt.args.attachment)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateTemplateVariables(t *testing.T) {
+	type args struct {
+		body      string
+		variables map[string]string
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with every variable",
+			args: args{body: "Hi {{name}}, order {{order}} is on its way", variables: map[string]string{"name": "Budi", "order": "A-12"}},
+			err:  nil,
+		},
+		{
+			name: "should success with phone filled in by the server",
+			args: args{body: "This message went to {{phone}}"},
+			err:  nil,
+		},
+		{
+			name: "should error with missing variables",
+			args: args{body: "Hi {{name}}, order {{order}} is on its way, {{name}}", variables: map[string]string{"city": "Bandung"}},
+			err:  pkgError.ValidationError("variables: missing name, order"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTemplateVariables(tt.args.body, tt.args.variables)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateListTemplates(t *testing.T) {
+	type args struct {
+		request domainTemplate.ListRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success without pagination",
+			args: args{request: domainTemplate.ListRequest{}},
+			err:  nil,
+		},
+		{
+			name: "should error with limit above maximum",
+			args: args{request: domainTemplate.ListRequest{Limit: 101}},
+			err:  pkgError.ValidationError("limit: must be no greater than 100."),
+		},
+		{
+			name: "should error with negative offset",
+			args: args{request: domainTemplate.ListRequest{Offset: -1}},
+			err:  pkgError.ValidationError("offset: must be no less than 0."),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateListTemplates(context.Background(), &tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package validations
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+var webhookURLScheme = regexp.MustCompile(`^https?://`)
+
+// webhookChatFilter accepts a JID, the user part of a JID or a server wildcard such as "*@g.us"
+var webhookChatFilter = regexp.MustCompile(`^(\*@[\w.]+|[\w.\-:]+(@[\w.]+)?)$`)
+
+var webhookChatFilterRule = validation.Match(webhookChatFilter).Error("must be a JID, a phone number or a server wildcard such as *@g.us")
+
+// validateWebhookEvents accepts known event names, "*" and prefix wildcards such as "group.*"
+func validateWebhookEvents(value any) error {
+	events, _ := value.([]string)
+	for _, event := range events {
+		if event == "*" || slices.Contains(domainWebhook.SupportedEvents, event) {
+			continue
+		}
+		if prefix, ok := strings.CutSuffix(event, ".*"); ok && slices.ContainsFunc(domainWebhook.SupportedEvents, func(supported string) bool {
+			return strings.HasPrefix(supported, prefix+".")
+		}) {
+			continue
+		}
+		return fmt.Errorf("unsupported event %q, supported events are %s", event, strings.Join(domainWebhook.SupportedEvents, ", "))
+	}
+	return nil
+}
+
+func ValidateCreateWebhook(ctx context.Context, request domainWebhook.CreateWebhookRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.URL, validation.Required, is.URL, validation.Match(webhookURLScheme).Error("must start with http:// or https://")),
+		validation.Field(&request.Secret, validation.Length(8, 256)),
+		validation.Field(&request.Events, validation.By(validateWebhookEvents)),
+		validation.Field(&request.Chats, validation.Length(0, 100), validation.Each(validation.Required, webhookChatFilterRule)),
+		validation.Field(&request.Format, validation.In(domainWebhook.FormatLegacy, domainWebhook.FormatV1)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateUpdateWebhook(ctx context.Context, request domainWebhook.UpdateWebhookRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.URL, is.URL, validation.Match(webhookURLScheme).Error("must start with http:// or https://")),
+		validation.Field(&request.Secret, validation.NilOrNotEmpty, validation.Length(8, 256)),
+		validation.Field(&request.Events, validation.By(validateWebhookEvents)),
+		validation.Field(&request.Chats, validation.Length(0, 100), validation.Each(validation.Required, webhookChatFilterRule)),
+		validation.Field(&request.Format, validation.In(domainWebhook.FormatLegacy, domainWebhook.FormatV1)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateListDeliveries(ctx context.Context, request *domainWebhook.ListDeliveriesRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 25
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(100)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateListDeadLetters(ctx context.Context, request *domainWebhook.ListDeadLettersRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 25
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(100)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateReplayDeadLetters(ctx context.Context, request domainWebhook.ReplayDeadLettersRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.IDs, validation.Length(0, 500), validation.Each(validation.Required, validation.Min(int64(1)))),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,264 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCreateWebhook(t *testing.T) {
+	type args struct {
+		request domainWebhook.CreateWebhookRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with url only",
+			args: args{request: domainWebhook.CreateWebhookRequest{
+				URL: "https://example.com/webhook",
+			}},
+			err: nil,
+		},
+		{
+			name: "should success with secret and events",
+			args: args{request: domainWebhook.CreateWebhookRequest{
+				URL:    "http://localhost:8080/hook",
+				Secret: "super-secret",
+				Events: []string{"message", "message.ack", "group.*"},
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty url",
+			args: args{request: domainWebhook.CreateWebhookRequest{
+				URL: "",
+			}},
+			err: pkgError.ValidationError("url: cannot be blank."),
+		},
+		{
+			name: "should error with url without scheme",
+			args: args{request: domainWebhook.CreateWebhookRequest{
+				URL: "example.com/webhook",
+			}},
+			err: pkgError.ValidationError("url: must start with http:// or https://."),
+		},
+		{
+			name: "should error with short secret",
+			args: args{request: domainWebhook.CreateWebhookRequest{
+				URL:    "https://example.com/webhook",
+				Secret: "short",
+			}},
+			err: pkgError.ValidationError("secret: the length must be between 8 and 256."),
+		},
+		{
+			name: "should error with unsupported event",
+			args: args{request: domainWebhook.CreateWebhookRequest{
+				URL:    "https://example.com/webhook",
+				Events: []string{"message", "call.offer"},
+			}},
+			err: pkgError.ValidationError(`events: unsupported event "call.offer", supported events are message, message.ack, message.deleted, message.revoked, message.edited, group.participants, presence, connection.connected, connection.disconnected, connection.logged_out, pair.success, stream.replaced, stream.reclaim_stopped, qr.code, schedule.executed.`),
+		},
+		{
+			name: "should success with chat filters",
+			args: args{request: domainWebhook.CreateWebhookRequest{
+				URL:   "https://example.com/webhook",
+				Chats: []string{"628123456789", "628123456789@s.whatsapp.net", "120363402106123456@g.us", "*@g.us"},
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with invalid chat filter",
+			args: args{request: domainWebhook.CreateWebhookRequest{
+				URL:   "https://example.com/webhook",
+				Chats: []string{"628123456789", "a,b"},
+			}},
+			err: pkgError.ValidationError("chats: (1: must be a JID, a phone number or a server wildcard such as *@g.us.)."),
+		},
+		{
+			name: "should success with v1 format",
+			args: args{request: domainWebhook.CreateWebhookRequest{
+				URL:    "https://example.com/webhook",
+				Format: "v1",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with unknown format",
+			args: args{request: domainWebhook.CreateWebhookRequest{
+				URL:    "https://example.com/webhook",
+				Format: "v2",
+			}},
+			err: pkgError.ValidationError("format: must be a valid value."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCreateWebhook(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateUpdateWebhook(t *testing.T) {
+	emptySecret := ""
+	type args struct {
+		request domainWebhook.UpdateWebhookRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with empty request",
+			args: args{request: domainWebhook.UpdateWebhookRequest{}},
+			err:  nil,
+		},
+		{
+			name: "should success with wildcard event",
+			args: args{request: domainWebhook.UpdateWebhookRequest{
+				Events: []string{"*"},
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with invalid url",
+			args: args{request: domainWebhook.UpdateWebhookRequest{
+				URL: "ftp://example.com",
+			}},
+			err: pkgError.ValidationError("url: must start with http:// or https://."),
+		},
+		{
+			name: "should error with empty secret",
+			args: args{request: domainWebhook.UpdateWebhookRequest{
+				Secret: &emptySecret,
+			}},
+			err: pkgError.ValidationError("secret: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateUpdateWebhook(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateListDeliveries(t *testing.T) {
+	type args struct {
+		request domainWebhook.ListDeliveriesRequest
+	}
+	tests := []struct {
+		name  string
+		args  args
+		limit int
+		err   any
+	}{
+		{
+			name:  "should default limit",
+			args:  args{request: domainWebhook.ListDeliveriesRequest{}},
+			limit: 25,
+			err:   nil,
+		},
+		{
+			name:  "should error with limit above maximum",
+			args:  args{request: domainWebhook.ListDeliveriesRequest{Limit: 101}},
+			limit: 101,
+			err:   pkgError.ValidationError("limit: must be no greater than 100."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateListDeliveries(context.Background(), &tt.args.request)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.limit, tt.args.request.Limit)
+		})
+	}
+}
+
+func TestValidateListDeadLetters(t *testing.T) {
+	type args struct {
+		request domainWebhook.ListDeadLettersRequest
+	}
+	tests := []struct {
+		name  string
+		args  args
+		limit int
+		err   any
+	}{
+		{
+			name:  "should default limit",
+			args:  args{request: domainWebhook.ListDeadLettersRequest{}},
+			limit: 25,
+			err:   nil,
+		},
+		{
+			name:  "should error with limit above maximum",
+			args:  args{request: domainWebhook.ListDeadLettersRequest{Limit: 101}},
+			limit: 101,
+			err:   pkgError.ValidationError("limit: must be no greater than 100."),
+		},
+		{
+			name:  "should error with negative offset",
+			args:  args{request: domainWebhook.ListDeadLettersRequest{Limit: 10, Offset: -1}},
+			limit: 10,
+			err:   pkgError.ValidationError("offset: must be no less than 0."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateListDeadLetters(context.Background(), &tt.args.request)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.limit, tt.args.request.Limit)
+		})
+	}
+}
+
+func TestValidateReplayDeadLetters(t *testing.T) {
+	type args struct {
+		request domainWebhook.ReplayDeadLettersRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with empty ids",
+			args: args{request: domainWebhook.ReplayDeadLettersRequest{}},
+			err:  nil,
+		},
+		{
+			name: "should success with ids",
+			args: args{request: domainWebhook.ReplayDeadLettersRequest{IDs: []int64{1, 2, 3}}},
+			err:  nil,
+		},
+		{
+			name: "should error with invalid id",
+			args: args{request: domainWebhook.ReplayDeadLettersRequest{IDs: []int64{1, 0}}},
+			err:  pkgError.ValidationError("ids: (1: cannot be blank.)."),
+		},
+		{
+			name: "should error with negative id",
+			args: args{request: domainWebhook.ReplayDeadLettersRequest{IDs: []int64{-5}}},
+			err:  pkgError.ValidationError("ids: (0: must be no less than 1.)."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateReplayDeadLetters(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}